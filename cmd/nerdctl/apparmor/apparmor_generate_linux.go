@@ -0,0 +1,69 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apparmor
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/apparmor"
+)
+
+func generateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "generate [flags] RULES",
+		Short:         "Generate an AppArmor profile from a template and rules, and load it. Requires root.",
+		Long:          "Generate an AppArmor profile from a template and rules (YAML), and load it. Requires root.\nThe name of the loaded profile is printed, to be used as `--security-opt apparmor=NAME`.",
+		Args:          cobra.ExactArgs(1),
+		RunE:          generateAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().String("name", "", "Name of the profile, starting with \"nerdctl-generated-\" (overrides the name in the rules)")
+	cmd.Flags().String("template", "", "Path of the profile template (Go template with .Name and .Rules). Defaults to the template of the default profile")
+	cmd.Flags().Bool("dry-run", false, "Print the generated profile instead of loading it")
+	return cmd
+}
+
+func generateOptions(cmd *cobra.Command) (types.ApparmorGenerateOptions, error) {
+	name, err := cmd.Flags().GetString("name")
+	if err != nil {
+		return types.ApparmorGenerateOptions{}, err
+	}
+	tmpl, err := cmd.Flags().GetString("template")
+	if err != nil {
+		return types.ApparmorGenerateOptions{}, err
+	}
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return types.ApparmorGenerateOptions{}, err
+	}
+	return types.ApparmorGenerateOptions{
+		Stdout:   cmd.OutOrStdout(),
+		Name:     name,
+		Template: tmpl,
+		DryRun:   dryRun,
+	}, nil
+}
+
+func generateAction(cmd *cobra.Command, args []string) error {
+	options, err := generateOptions(cmd)
+	if err != nil {
+		return err
+	}
+	return apparmor.Generate(args[0], options)
+}
@@ -0,0 +1,148 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apparmor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/containerd/nerdctl/v2/pkg/apparmorutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+const testRules = `denyWrite:
+  - /etc/**
+denyNetwork:
+  - raw
+denyCapabilities:
+  - NET_RAW
+`
+
+var canLoadNewProfile = &test.Requirement{
+	Check: func(data test.Data, helpers test.Helpers) (bool, string) {
+		if !apparmorutil.CanLoadNewProfile() {
+			return false, "cannot load AppArmor profiles"
+		}
+		return true, "can load AppArmor profiles"
+	},
+}
+
+func TestApparmorGenerateDryRun(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// `apparmor generate` is not supported by Docker
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		data.Temp().Save(testRules, "rules.yaml")
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("apparmor", "generate", "--dry-run", "--name", apparmorutil.GeneratedProfilePrefix+data.Identifier(), data.Temp().Path("rules.yaml"))
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			Output: expect.Contains(
+				"profile "+apparmorutil.GeneratedProfilePrefix+data.Identifier()+" ",
+				"  deny /etc/** w,\n",
+				"  deny network raw,\n",
+				"  deny capability net_raw,\n",
+			),
+		}
+	}
+
+	testCase.Run(t)
+}
+
+func TestApparmorGenerateReservedName(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		data.Temp().Save(testRules, "rules.yaml")
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("apparmor", "generate", "--dry-run", "--name", "nerdctl-default", data.Temp().Path("rules.yaml"))
+	}
+
+	testCase.Expected = test.Expects(expect.ExitCodeGenericFail, []error{errors.New("must start with")}, nil)
+
+	testCase.Run(t)
+}
+
+func TestApparmorGenerateRun(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.All(
+		require.Not(nerdtest.Docker),
+		require.Not(nerdtest.Rootless),
+		canLoadNewProfile,
+	)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		data.Temp().Save(testRules, "rules.yaml")
+		// subtests have their own temporary directory
+		data.Labels().Set("rules", data.Temp().Path("rules.yaml"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "generate loads the profile",
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("apparmor", "unload", apparmorutil.GeneratedProfilePrefix+data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("apparmor", "generate", "--name", apparmorutil.GeneratedProfilePrefix+data.Identifier(), data.Labels().Get("rules"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, t tig.T) {
+						expect.Equals(apparmorutil.GeneratedProfilePrefix+data.Identifier()+"\n")(stdout, t)
+						expect.Contains(apparmorutil.GeneratedProfilePrefix+data.Identifier())(helpers.Capture("apparmor", "ls", "-q"), t)
+					},
+				}
+			},
+		},
+		{
+			Description: "apparmor=generate: denies writing /etc",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--security-opt", "apparmor=generate:"+data.Labels().Get("rules"),
+					testutil.CommonImage, "touch", "/etc/nerdctl-test")
+			},
+			Expected: test.Expects(1, nil, nil),
+		},
+		{
+			Description: "apparmor=generate: allows writing /tmp",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--security-opt", "apparmor=generate:"+data.Labels().Get("rules"),
+					testutil.CommonImage, "touch", "/tmp/nerdctl-test")
+			},
+			Expected: test.Expects(0, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -36,6 +36,7 @@ func Command() *cobra.Command {
 		inspectCommand(),
 		loadCommand(),
 		unloadCommand(),
+		generateCommand(),
 	)
 	return cmd
 }
@@ -32,7 +32,7 @@ func createCommand() *cobra.Command {
 	var cmd = &cobra.Command{
 		Use:               "create [OPTIONS] CONTAINER CHECKPOINT",
 		Short:             "Create a checkpoint from a running container",
-		Args:              cobra.ExactArgs(2),
+		Args:              createArgs,
 		RunE:              createAction,
 		ValidArgsFunction: createShellComplete,
 		SilenceUsage:      true,
@@ -40,9 +40,18 @@ func createCommand() *cobra.Command {
 	}
 	cmd.Flags().Bool("leave-running", false, "Leave the container running after checkpointing")
 	cmd.Flags().String("checkpoint-dir", "", "Checkpoint directory")
+	cmd.Flags().String("export", "", "Write a portable checkpoint archive (.tar, .tar.gz or .tar.zst) to restore with 'nerdctl container restore', instead of the checkpoint directory")
 	return cmd
 }
 
+// createArgs requires CONTAINER and CHECKPOINT, or only CONTAINER with --export.
+func createArgs(cmd *cobra.Command, args []string) error {
+	if export, _ := cmd.Flags().GetString("export"); export != "" {
+		return cobra.RangeArgs(1, 2)(cmd, args)
+	}
+	return cobra.ExactArgs(2)(cmd, args)
+}
+
 func processCreateFlags(cmd *cobra.Command) (types.CheckpointCreateOptions, error) {
 	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
 	if err != nil {
@@ -60,12 +69,17 @@ func processCreateFlags(cmd *cobra.Command) (types.CheckpointCreateOptions, erro
 	if checkpointDir == "" {
 		checkpointDir = filepath.Join(globalOptions.DataRoot, "checkpoints")
 	}
+	export, err := cmd.Flags().GetString("export")
+	if err != nil {
+		return types.CheckpointCreateOptions{}, err
+	}
 
 	return types.CheckpointCreateOptions{
 		Stdout:        cmd.OutOrStdout(),
 		GOptions:      globalOptions,
 		LeaveRunning:  leaveRunning,
 		CheckpointDir: checkpointDir,
+		Export:        export,
 	}, nil
 }
 
@@ -80,7 +94,11 @@ func createAction(cmd *cobra.Command, args []string) error {
 	}
 	defer cancel()
 
-	err = checkpoint.Create(ctx, client, args[0], args[1], createOptions)
+	var checkpointName string
+	if len(args) > 1 {
+		checkpointName = args[1]
+	}
+	err = checkpoint.Create(ctx, client, args[0], checkpointName, createOptions)
 	if err != nil {
 		return err
 	}
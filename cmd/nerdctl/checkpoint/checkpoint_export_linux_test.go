@@ -0,0 +1,94 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestCheckpointExportRestore(t *testing.T) {
+	testCase := nerdtest.Setup()
+	// `checkpoint create --export` and `container restore` are not supported by Docker
+	testCase.Require = require.All(
+		require.Not(nerdtest.Rootless),
+		require.Not(nerdtest.Docker),
+	)
+	testCase.NoParallel = true
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "-d", "--name", data.Identifier(), testutil.CommonImage,
+			"sh", "-c", "echo restored > /rootfs-diff; sleep infinity")
+		nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+		archive := filepath.Join(data.Temp().Path(), "checkpoint.tar.zst")
+		helpers.Ensure("checkpoint", "create", "--export", archive, data.Identifier())
+		helpers.Ensure("rm", "-f", data.Identifier())
+		data.Labels().Set("archive", archive)
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+		helpers.Anyhow("rm", "-f", data.Identifier("renamed"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "restore the container and its rootfs",
+			NoParallel:  true,
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				helpers.Ensure("container", "restore", data.Labels().Get("archive"))
+				return helpers.Command("exec", data.Identifier(), "cat", "/rootfs-diff")
+			},
+			Expected: test.Expects(0, nil, expect.Equals("restored\n")),
+		},
+		{
+			Description: "restore under another name",
+			NoParallel:  true,
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier("renamed"))
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("container", "restore", "--name", data.Identifier("renamed"), data.Labels().Get("archive"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Equals(data.Identifier("renamed") + "\n"),
+				}
+			},
+		},
+		{
+			Description: "not a checkpoint archive",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("container", "restore", "/etc/hostname")
+			},
+			Expected: test.Expects(1, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
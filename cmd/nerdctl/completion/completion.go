@@ -31,6 +31,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/netutil"
+	"github.com/containerd/nerdctl/v2/pkg/podutil"
 )
 
 func ImageNames(cmd *cobra.Command) ([]string, cobra.ShellCompDirective) {
@@ -104,6 +105,32 @@ func ContainerNames(cmd *cobra.Command, filterFunc func(containerd.ProcessStatus
 	return candidates, cobra.ShellCompDirectiveNoFileComp
 }
 
+// PodNames returns the names of the pods.
+func PodNames(cmd *cobra.Command) ([]string, cobra.ShellCompDirective) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), globalOptions.Namespace, globalOptions.Address)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	defer cancel()
+	infras, err := podutil.Infras(ctx, client)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	candidates := []string{}
+	for _, infra := range infras {
+		lab, err := infra.Labels(ctx)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, lab[labels.Pod])
+	}
+	return candidates, cobra.ShellCompDirectiveNoFileComp
+}
+
 // NetworkNames includes {"bridge","host","none"}
 func NetworkNames(cmd *cobra.Command, exclude []string) ([]string, cobra.ShellCompDirective) {
 	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
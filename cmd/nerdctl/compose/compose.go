@@ -64,6 +64,13 @@ func Command() *cobra.Command {
 		unpauseCommand(),
 		topCommand(),
 		createCommand(),
+		watchCommand(),
+		lsCommand(),
+		eventsCommand(),
+		statsCommand(),
+		waitCommand(),
+		attachCommand(),
+		scaleCommand(),
 	)
 
 	return cmd
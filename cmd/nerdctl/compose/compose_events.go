@@ -0,0 +1,143 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/compose"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/system"
+	"github.com/containerd/nerdctl/v2/pkg/composer"
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+func eventsCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:           "events [flags] [SERVICE...]",
+		Short:         "Receive real time events from containers of services",
+		RunE:          eventsAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().Bool("json", false, "Output events as a stream of json objects")
+	return cmd
+}
+
+// composeEvent is the JSON output of `compose events --json`, compatible with Docker Compose.
+type composeEvent struct {
+	Time       time.Time         `json:"time"`
+	Type       string            `json:"type"`
+	Action     string            `json:"action"`
+	ID         string            `json:"id"`
+	Service    string            `json:"service"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+func eventsAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	jsonOut, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), globalOptions.Namespace, globalOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	options, err := getComposeOptions(cmd, globalOptions.DebugFull, globalOptions.Experimental)
+	if err != nil {
+		return err
+	}
+	c, err := compose.New(client, globalOptions, options, cmd.OutOrStdout(), cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	serviceNames, err := c.ServiceNames(args...)
+	if err != nil {
+		return err
+	}
+
+	// events does not need to lock, and runs until interrupted
+	if err := composer.Unlock(); err != nil {
+		return err
+	}
+
+	stdout := cmd.OutOrStdout()
+	eventsOptions := types.SystemEventsOptions{
+		GOptions: globalOptions,
+		Filters: []string{
+			"type=" + eventutil.TypeContainer,
+			fmt.Sprintf("label=%s=%s", labels.ComposeProject, c.ProjectName()),
+			"namespace=" + globalOptions.Namespace,
+		},
+	}
+	return system.WatchEvents(ctx, client, eventsOptions, func(e *system.EventOut) error {
+		service := e.Labels[labels.ComposeService]
+		if !slices.Contains(serviceNames, service) {
+			return nil
+		}
+		event := composeEvent{
+			Time:       e.Timestamp,
+			Type:       e.Type,
+			Action:     string(e.Action),
+			ID:         e.ID,
+			Service:    service,
+			Attributes: e.Attributes,
+		}
+		if jsonOut {
+			b, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(stdout, string(b))
+			return err
+		}
+		return printComposeEvent(stdout, event)
+	})
+}
+
+// printComposeEvent prints the event in the format of Docker Compose, e.g.,
+// "2006-01-02 15:04:05.000000 container start ID (image=alpine, name=foo)".
+func printComposeEvent(w io.Writer, e composeEvent) error {
+	line := fmt.Sprintf("%s %s %s %s", e.Time.Format("2006-01-02 15:04:05.000000"), e.Type, e.Action, e.ID)
+	if len(e.Attributes) > 0 {
+		attrs := make([]string, 0, len(e.Attributes))
+		for k, v := range e.Attributes {
+			attrs = append(attrs, k+"="+v)
+		}
+		sort.Strings(attrs)
+		line += " (" + strings.Join(attrs, ", ") + ")"
+	}
+	_, err := fmt.Fprintln(w, line)
+	return err
+}
@@ -1463,3 +1463,159 @@ services:
 
 	testCase.Run(t)
 }
+
+func TestComposeUpDependsOnCompletedSuccessfully(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "dependency exiting with 0 unblocks the dependent service",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				composeYAML := fmt.Sprintf(`
+services:
+  init:
+    image: %s
+    command: sh -c "sleep 2; exit 0"
+  app:
+    image: %s
+    command: sleep infinity
+    depends_on:
+      init:
+        condition: service_completed_successfully
+`, testutil.CommonImage, testutil.CommonImage)
+				composePath := data.Temp().Save(composeYAML, "compose.yaml")
+				projectName := filepath.Base(filepath.Dir(composePath))
+				data.Labels().Set("composePath", composePath)
+				data.Labels().Set("appContainerName", serviceparser.DefaultContainerName(projectName, "app", "1"))
+				helpers.Ensure("compose", "-f", composePath, "up", "-d")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("inspect", "--format", "{{.State.Running}}", data.Labels().Get("appContainerName"))
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("true\n")),
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				if data.Labels().Get("composePath") != "" {
+					helpers.Anyhow("compose", "-f", data.Labels().Get("composePath"), "down", "-v")
+				}
+			},
+		},
+		{
+			Description: "dependency exiting with non-zero fails up",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				composeYAML := fmt.Sprintf(`
+services:
+  init:
+    image: %s
+    command: sh -c "exit 1"
+  app:
+    image: %s
+    command: sleep infinity
+    depends_on:
+      init:
+        condition: service_completed_successfully
+`, testutil.CommonImage, testutil.CommonImage)
+				data.Labels().Set("composePath", data.Temp().Save(composeYAML, "compose.yaml"))
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("compose", "-f", data.Labels().Get("composePath"), "up", "-d")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				if data.Labels().Get("composePath") != "" {
+					helpers.Anyhow("compose", "-f", data.Labels().Get("composePath"), "down", "-v")
+				}
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestComposeUpDependsOnHealthy(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "dependency becoming healthy unblocks the dependent service",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				composeYAML := fmt.Sprintf(`
+services:
+  db:
+    image: %s
+    command: sh -c "sleep 2; touch /tmp/ready; sleep infinity"
+    healthcheck:
+      test: ["CMD", "test", "-f", "/tmp/ready"]
+      interval: 1s
+      timeout: 1s
+      retries: 30
+  app:
+    image: %s
+    command: sleep infinity
+    depends_on:
+      db:
+        condition: service_healthy
+`, testutil.CommonImage, testutil.CommonImage)
+				composePath := data.Temp().Save(composeYAML, "compose.yaml")
+				projectName := filepath.Base(filepath.Dir(composePath))
+				data.Labels().Set("composePath", composePath)
+				data.Labels().Set("dbContainerName", serviceparser.DefaultContainerName(projectName, "db", "1"))
+				data.Labels().Set("appContainerName", serviceparser.DefaultContainerName(projectName, "app", "1"))
+				helpers.Ensure("compose", "-f", composePath, "up", "-d")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("inspect", "--format", "{{.State.Running}}", data.Labels().Get("appContainerName"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: expect.ExitCodeSuccess,
+					Output: expect.All(
+						expect.Equals("true\n"),
+						func(stdout string, t tig.T) {
+							health := helpers.Capture("inspect", "--format", "{{.State.Health.Status}}", data.Labels().Get("dbContainerName"))
+							assert.Equal(t, health, "healthy\n")
+						},
+					),
+				}
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				if data.Labels().Get("composePath") != "" {
+					helpers.Anyhow("compose", "-f", data.Labels().Get("composePath"), "down", "-v")
+				}
+			},
+		},
+		{
+			Description: "dependency that never becomes healthy fails up",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				composeYAML := fmt.Sprintf(`
+services:
+  db:
+    image: %s
+    command: sleep infinity
+    healthcheck:
+      test: ["CMD", "false"]
+      interval: 1s
+      timeout: 1s
+      retries: 2
+  app:
+    image: %s
+    command: sleep infinity
+    depends_on:
+      db:
+        condition: service_healthy
+`, testutil.CommonImage, testutil.CommonImage)
+				data.Labels().Set("composePath", data.Temp().Save(composeYAML, "compose.yaml"))
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("compose", "-f", data.Labels().Get("composePath"), "up", "-d")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				if data.Labels().Get("composePath") != "" {
+					helpers.Anyhow("compose", "-f", data.Labels().Get("composePath"), "down", "-v")
+				}
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -0,0 +1,81 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestComposeWatchSync(t *testing.T) {
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc0:
+    image: %s
+    command: "sleep infinity"
+    develop:
+      watch:
+        - action: sync
+          path: ./src
+          target: /tmp/app
+`, testutil.CommonImage)
+
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		data.Temp().Save(dockerComposeYAML, "compose.yaml")
+		data.Temp().Save("initial", "src", "initial.txt")
+		data.Labels().Set("YAMLPath", data.Temp().Path("compose.yaml"))
+		helpers.Ensure("compose", "-f", data.Labels().Get("YAMLPath"), "up", "-d")
+		helpers.Ensure("compose", "-f", data.Labels().Get("YAMLPath"), "exec", "svc0", "mkdir", "-p", "/tmp/app")
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("compose", "-f", data.Temp().Path("compose.yaml"), "down")
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		cmd := helpers.Command("compose", "-f", data.Labels().Get("YAMLPath"), "watch", "--no-up")
+		cmd.WithTimeout(15 * time.Second)
+		cmd.Background()
+		// Leave time to the command to watch the files
+		time.Sleep(3 * time.Second)
+		data.Temp().Save("hello-watch", "src", "hello.txt")
+		return cmd
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			ExitCode: expect.ExitCodeTimeout,
+			Output: func(stdout string, t tig.T) {
+				// The file changed on the host is synced into the container
+				out := helpers.Capture("compose", "-f", data.Labels().Get("YAMLPath"), "exec", "svc0", "cat", "/tmp/app/hello.txt")
+				expect.Equals("hello-watch")(out, t)
+			},
+		}
+	}
+
+	testCase.Run(t)
+}
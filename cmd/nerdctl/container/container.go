@@ -56,6 +56,7 @@ func Command() *cobra.Command {
 		AttachCommand(),
 		HealthCheckCommand(),
 		ExportCommand(),
+		RestoreCommand(),
 	)
 	AddCpCommand(cmd)
 	return cmd
@@ -17,6 +17,7 @@
 package container
 
 import (
+	"errors"
 	"fmt"
 	"runtime"
 
@@ -28,6 +29,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/sdnotify"
 )
 
 func CreateCommand() *cobra.Command {
@@ -97,6 +99,24 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 	if err != nil {
 		return opt, err
 	}
+	opt.Pod, err = cmd.Flags().GetString("pod")
+	if err != nil {
+		return opt, err
+	}
+	opt.PodInfra, err = cmd.Flags().GetBool("pod-infra")
+	if err != nil {
+		return opt, err
+	}
+	if opt.PodInfra && opt.Pod == "" {
+		return opt, errors.New("--pod-infra requires --pod")
+	}
+	if opt.Pod != "" && !opt.PodInfra {
+		for _, name := range []string{"network", "net", "ipc", "uts"} {
+			if cmd.Flags().Changed(name) {
+				return opt, fmt.Errorf("conflicting options: --%s cannot be used with --pod", name)
+			}
+		}
+	}
 	opt.StopSignal, err = cmd.Flags().GetString("stop-signal")
 	if err != nil {
 		return opt, err
@@ -279,6 +299,14 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 	if err != nil {
 		return opt, err
 	}
+	opt.HealthStartInterval, err = cmd.Flags().GetDuration("health-start-interval")
+	if err != nil {
+		return opt, err
+	}
+	opt.HealthOnFailure, err = cmd.Flags().GetString("health-on-failure")
+	if err != nil {
+		return opt, err
+	}
 	opt.NoHealthcheck, err = cmd.Flags().GetBool("no-healthcheck")
 	if err != nil {
 		return opt, err
@@ -428,6 +456,13 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 			return opt, err
 		}
 	}
+	opt.SdNotify, err = cmd.Flags().GetString("sdnotify")
+	if err != nil {
+		return opt, err
+	}
+	if err := sdnotify.Validate(opt.SdNotify); err != nil {
+		return opt, err
+	}
 	// #endregion
 
 	// #region for logging flags
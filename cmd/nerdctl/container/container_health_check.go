@@ -26,6 +26,7 @@ import (
 
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
@@ -42,6 +43,9 @@ func HealthCheckCommand() *cobra.Command {
 		SilenceUsage:      true,
 		SilenceErrors:     true,
 	}
+	// Used by the systemd timer of the health checks, to skip the checks that are not due
+	healthCheckCommand.Flags().Bool("timer", false, "Skip the health check if it is not due")
+	healthCheckCommand.Flags().MarkHidden("timer")
 
 	return healthCheckCommand
 }
@@ -51,6 +55,17 @@ func healthCheckAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	timer, err := cmd.Flags().GetBool("timer")
+	if err != nil {
+		return err
+	}
+	nerdctlCmd, nerdctlArgs := helpers.GlobalFlags(cmd)
+	options := types.ContainerHealthCheckOptions{
+		GOptions:    globalOptions,
+		Timer:       timer,
+		NerdctlCmd:  nerdctlCmd,
+		NerdctlArgs: nerdctlArgs,
+	}
 
 	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), globalOptions.Namespace, globalOptions.Address)
 	if err != nil {
@@ -65,7 +80,7 @@ func healthCheckAction(cmd *cobra.Command, args []string) error {
 			if found.MatchCount > 1 {
 				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
 			}
-			return container.HealthCheck(ctx, client, found.Container)
+			return container.HealthCheck(ctx, client, found.Container, options)
 		},
 	}
 
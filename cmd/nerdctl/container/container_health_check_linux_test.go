@@ -1298,3 +1298,104 @@ func countShimPipeFDs(shimPID int) (int, error) {
 	}
 	return count, nil
 }
+
+func TestContainerHealthCheckOnFailure(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// Docker CLI does not provide a standalone healthcheck command, nor --health-on-failure.
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	runUnhealthy := func(data test.Data, helpers test.Helpers, action string) {
+		helpers.Ensure("run", "-d", "--name", data.Identifier(),
+			"--health-cmd", "exit 1",
+			"--health-interval", "1h",
+			"--health-retries", "1",
+			"--health-on-failure", action,
+			testutil.CommonImage, "sleep", nerdtest.Infinity)
+		nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "stop action stops the unhealthy container",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				runUnhealthy(data, helpers, healthcheck.OnFailureStop)
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				// The health check timer may have already run the check
+				helpers.Anyhow("container", "healthcheck", data.Identifier())
+				return helpers.Command("inspect", data.Identifier())
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: 0,
+					Output: expect.All(func(stdout string, t tig.T) {
+						inspect := nerdtest.InspectContainer(helpers, data.Identifier())
+						assert.Equal(t, inspect.State.Running, false)
+						assert.Equal(t, inspect.State.Health.Status, healthcheck.Unhealthy)
+					}),
+				}
+			},
+		},
+		{
+			Description: "kill action kills the unhealthy container",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				runUnhealthy(data, helpers, healthcheck.OnFailureKill)
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				helpers.Anyhow("container", "healthcheck", data.Identifier())
+				return helpers.Command("inspect", data.Identifier())
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: 0,
+					Output: expect.All(func(stdout string, t tig.T) {
+						inspect := nerdtest.InspectContainer(helpers, data.Identifier())
+						assert.Equal(t, inspect.State.Running, false)
+						assert.Equal(t, inspect.State.ExitCode, 137)
+					}),
+				}
+			},
+		},
+		{
+			Description: "restart action restarts the unhealthy container",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				runUnhealthy(data, helpers, healthcheck.OnFailureRestart)
+				data.Labels().Set("pid", strconv.Itoa(nerdtest.InspectContainer(helpers, data.Identifier()).State.Pid))
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				helpers.Anyhow("container", "healthcheck", data.Identifier())
+				return helpers.Command("inspect", data.Identifier())
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: 0,
+					Output: expect.All(func(stdout string, t tig.T) {
+						inspect := nerdtest.InspectContainer(helpers, data.Identifier())
+						assert.Equal(t, inspect.State.Running, true)
+						assert.Assert(t, strconv.Itoa(inspect.State.Pid) != data.Labels().Get("pid"), "expected the container to be restarted")
+					}),
+				}
+			},
+		},
+		{
+			Description: "invalid action is rejected",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--health-cmd", "true", "--health-on-failure", "invalid",
+					testutil.CommonImage, "true")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("invalid health check failure action")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
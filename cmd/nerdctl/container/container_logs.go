@@ -30,17 +30,20 @@ import (
 )
 
 func LogsCommand() *cobra.Command {
-	const shortUsage = "Fetch the logs of a container. Expected to be used with 'nerdctl run -d'."
-	const longUsage = `Fetch the logs of a container.
+	const shortUsage = "Fetch the logs of one or more containers. Expected to be used with 'nerdctl run -d'."
+	const longUsage = `Fetch the logs of one or more containers.
 
 The following containers are supported:
 - Containers created with 'nerdctl run -d'. The log is currently empty for containers created without '-d'.
 - Containers created with 'nerdctl compose'.
 - Containers created with Kubernetes (EXPERIMENTAL).
+
+When multiple containers are specified, or selected with '--filter', their logs are
+interleaved in timestamp order, and each line is prefixed with the container name.
 `
 	var cmd = &cobra.Command{
-		Use:               "logs [flags] CONTAINER",
-		Args:              helpers.IsExactArgs(1),
+		Use:               "logs [flags] CONTAINER [CONTAINER...]",
+		Args:              logsArgs,
 		Short:             shortUsage,
 		Long:              longUsage,
 		RunE:              logsAction,
@@ -54,9 +57,25 @@ The following containers are supported:
 	cmd.Flags().String("since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37Z) or relative (e.g. 42m for 42 minutes)")
 	cmd.Flags().String("until", "", "Show logs before a timestamp (e.g. 2013-01-02T13:23:37Z) or relative (e.g. 42m for 42 minutes)")
 	cmd.Flags().Bool("details", false, "Show extra details provided to logs")
+	cmd.Flags().StringSlice("filter", nil, "Also show the logs of the containers matching the given conditions (e.g. 'label=com.example.app=web')")
+	cmd.Flags().String("format", "", "Format the output using the given format (\"json\" prints one JSON record per line)")
+	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().String("grep", "", "Only show the lines matching the given regular expression")
+	cmd.Flags().Bool("no-color", false, "Produce monochrome output")
+	cmd.Flags().Bool("no-log-prefix", false, "Don't print prefix in logs of multiple containers")
 	return cmd
 }
 
+// logsArgs requires at least one container, unless containers are selected with `--filter`.
+func logsArgs(cmd *cobra.Command, args []string) error {
+	if cmd.Flags().Changed("filter") {
+		return nil
+	}
+	return cobra.MinimumNArgs(1)(cmd, args)
+}
+
 func logsOptions(cmd *cobra.Command) (types.ContainerLogsOptions, error) {
 	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
 	if err != nil {
@@ -93,16 +112,41 @@ func logsOptions(cmd *cobra.Command) (types.ContainerLogsOptions, error) {
 	if err != nil {
 		return types.ContainerLogsOptions{}, err
 	}
+	filters, err := cmd.Flags().GetStringSlice("filter")
+	if err != nil {
+		return types.ContainerLogsOptions{}, err
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return types.ContainerLogsOptions{}, err
+	}
+	grep, err := cmd.Flags().GetString("grep")
+	if err != nil {
+		return types.ContainerLogsOptions{}, err
+	}
+	noColor, err := cmd.Flags().GetBool("no-color")
+	if err != nil {
+		return types.ContainerLogsOptions{}, err
+	}
+	noLogPrefix, err := cmd.Flags().GetBool("no-log-prefix")
+	if err != nil {
+		return types.ContainerLogsOptions{}, err
+	}
 	return types.ContainerLogsOptions{
-		Stdout:     cmd.OutOrStdout(),
-		Stderr:     cmd.OutOrStderr(),
-		GOptions:   globalOptions,
-		Follow:     follow,
-		Timestamps: timestamps,
-		Tail:       tail,
-		Since:      since,
-		Until:      until,
-		Details:    details,
+		Stdout:      cmd.OutOrStdout(),
+		Stderr:      cmd.OutOrStderr(),
+		GOptions:    globalOptions,
+		Follow:      follow,
+		Timestamps:  timestamps,
+		Tail:        tail,
+		Since:       since,
+		Until:       until,
+		Details:     details,
+		Filters:     filters,
+		Format:      format,
+		Grep:        grep,
+		NoColor:     noColor,
+		NoLogPrefix: noLogPrefix,
 	}, nil
 }
 
@@ -118,7 +162,7 @@ func logsAction(cmd *cobra.Command, args []string) error {
 	}
 	defer cancel()
 
-	return container.Logs(ctx, client, args[0], options)
+	return container.Logs(ctx, client, args, options)
 }
 
 func logsShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -17,6 +17,7 @@
 package container
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
@@ -626,3 +627,98 @@ func TestLogsWithStartContainer(t *testing.T) {
 	}
 	testCase.Run(t)
 }
+
+func TestLogsMultipleContainers(t *testing.T) {
+	testCase := nerdtest.Setup()
+	testCase.Require = require.Not(require.Windows)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		label := "com.example.logs=" + data.Identifier()
+		// Lines are interleaved between the containers: "one" (a), "two" (b), "three" (a).
+		helpers.Ensure("run", "-d", "--label", label, "--name", data.Identifier("a"), testutil.CommonImage,
+			"sh", "-c", "echo one; sleep 2; echo three")
+		helpers.Ensure("run", "-d", "--label", label, "--name", data.Identifier("b"), testutil.CommonImage,
+			"sh", "-c", "sleep 1; echo two")
+		helpers.Ensure("wait", data.Identifier("a"), data.Identifier("b"))
+		data.Labels().Set("a", data.Identifier("a"))
+		data.Labels().Set("b", data.Identifier("b"))
+		data.Labels().Set("label", label)
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier("a"), data.Identifier("b"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "logs are merged in timestamp order with name prefixes",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--no-color", data.Labels().Get("a"), data.Labels().Get("b"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				a, b := data.Labels().Get("a"), data.Labels().Get("b")
+				return test.Expects(0, nil, expect.Equals(fmt.Sprintf("%s |one\n%s |two\n%s |three\n", a, b, a)))(data, helpers)
+			},
+		},
+		{
+			Description: "logs are selected with --filter and formatted as json",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--filter", "label="+data.Labels().Get("label"), "--format", "json")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, t tig.T) {
+						type record struct {
+							Container string    `json:"container"`
+							ID        string    `json:"id"`
+							Stream    string    `json:"stream"`
+							Time      time.Time `json:"time"`
+							Log       string    `json:"log"`
+						}
+						var records []record
+						for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+							var r record
+							assert.NilError(t, json.Unmarshal([]byte(line), &r), line)
+							records = append(records, r)
+						}
+						assert.Equal(t, len(records), 3, stdout)
+						a, b := data.Labels().Get("a"), data.Labels().Get("b")
+						for i, want := range []struct{ container, log string }{{a, "one"}, {b, "two"}, {a, "three"}} {
+							assert.Equal(t, records[i].Container, want.container)
+							assert.Equal(t, records[i].Log, want.log)
+							assert.Equal(t, records[i].Stream, "stdout")
+							assert.Assert(t, records[i].ID != "")
+							assert.Assert(t, !records[i].Time.IsZero())
+						}
+					},
+				}
+			},
+		},
+		{
+			Description: "logs are filtered with --grep",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--no-color", "--grep", "^t", data.Labels().Get("a"), data.Labels().Get("b"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				a, b := data.Labels().Get("a"), data.Labels().Get("b")
+				return test.Expects(0, nil, expect.Equals(fmt.Sprintf("%s |two\n%s |three\n", b, a)))(data, helpers)
+			},
+		},
+		{
+			Description: "logs of a single container are not prefixed",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--grep", "one|three", data.Labels().Get("a"))
+			},
+			Expected: test.Expects(0, nil, expect.Equals("one\nthree\n")),
+		},
+		{
+			Description: "invalid --grep expressions are rejected",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--grep", "(", data.Labels().Get("a"))
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
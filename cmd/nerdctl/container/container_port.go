@@ -26,7 +26,9 @@ import (
 
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
 	"github.com/containerd/nerdctl/v2/pkg/portutil"
@@ -42,9 +44,84 @@ func PortCommand() *cobra.Command {
 		SilenceUsage:      true,
 		SilenceErrors:     true,
 	}
+	cmd.AddCommand(
+		portAddCommand(),
+		portRemoveCommand(),
+	)
 	return cmd
 }
 
+func portAddCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:               "add [flags] CONTAINER [[HOST_IP:]HOST_PORT:]CONTAINER_PORT[/PROTO]...",
+		Args:              cobra.MinimumNArgs(2),
+		Short:             "Publish ports of a container, which may be running",
+		RunE:              portAddAction,
+		ValidArgsFunction: portAddRemoveShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	return cmd
+}
+
+func portAddAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), globalOptions.Namespace, globalOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return container.PortAdd(ctx, client, types.ContainerPortAddOptions{
+		Stdout:    cmd.OutOrStdout(),
+		GOptions:  globalOptions,
+		Container: args[0],
+		Ports:     args[1:],
+	})
+}
+
+func portRemoveCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:               "rm [flags] CONTAINER [[HOST_IP:]HOST_PORT:]CONTAINER_PORT[/PROTO]...",
+		Aliases:           []string{"remove"},
+		Args:              cobra.MinimumNArgs(2),
+		Short:             "Unpublish ports of a container, which may be running",
+		Long:              "Unpublish ports of a container, which may be running.\nThe host IP and the host port can be omitted to unpublish all the mappings of the container port.",
+		RunE:              portRemoveAction,
+		ValidArgsFunction: portAddRemoveShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	return cmd
+}
+
+func portRemoveAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), globalOptions.Namespace, globalOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return container.PortRemove(ctx, client, types.ContainerPortRemoveOptions{
+		Stdout:    cmd.OutOrStdout(),
+		GOptions:  globalOptions,
+		Container: args[0],
+		Ports:     args[1:],
+	})
+}
+
+func portAddRemoveShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completion.ContainerNames(cmd, nil)
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
 func portAction(cmd *cobra.Command, args []string) error {
 	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
 	if err != nil {
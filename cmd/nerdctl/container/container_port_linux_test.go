@@ -0,0 +1,133 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nettestutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/portlock"
+)
+
+func TestPortAddRemove(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// `port add` and `port rm` are not supported by Docker
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		port, err := portlock.Acquire(0)
+		if err != nil {
+			helpers.T().Log(fmt.Sprintf("Failed to acquire port: %v", err))
+			helpers.T().FailNow()
+		}
+		data.Labels().Set("hostPort", strconv.Itoa(port))
+		data.Labels().Set("containerName", data.Identifier())
+		helpers.Ensure("run", "-d", "--name", data.Identifier(), testutil.NginxAlpineImage)
+		nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+		port, _ := strconv.Atoi(data.Labels().Get("hostPort"))
+		_ = portlock.Release(port)
+	}
+
+	expectReachable := func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			Output: func(stdout string, t tig.T) {
+				resp, err := nettestutil.HTTPGet("http://127.0.0.1:"+data.Labels().Get("hostPort"), 30, false)
+				assert.NilError(t, err)
+				defer resp.Body.Close()
+				body, err := io.ReadAll(resp.Body)
+				assert.NilError(t, err)
+				expect.Contains(testutil.NginxAlpineIndexHTMLSnippet)(string(body), t)
+			},
+		}
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "add publishes the port of the running container",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("port", "add", data.Labels().Get("containerName"), data.Labels().Get("hostPort")+":80")
+			},
+			Expected: expectReachable,
+		},
+		{
+			Description: "port lists the added port",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("port", data.Labels().Get("containerName"), "80/tcp")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Contains(":" + data.Labels().Get("hostPort")),
+				}
+			},
+		},
+		{
+			Description: "add rejects a host port already published",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("port", "add", data.Labels().Get("containerName"), data.Labels().Get("hostPort")+":81")
+			},
+			Expected: test.Expects(1, nil, nil),
+		},
+		{
+			Description: "the port is kept across restarts",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				helpers.Ensure("restart", data.Labels().Get("containerName"))
+				return helpers.Command("port", data.Labels().Get("containerName"), "80/tcp")
+			},
+			Expected: expectReachable,
+		},
+		{
+			Description: "rm unpublishes the port",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				helpers.Ensure("port", "rm", data.Labels().Get("containerName"), "80/tcp")
+				return helpers.Command("port", data.Labels().Get("containerName"))
+			},
+			Expected: test.Expects(0, nil, expect.Equals("")),
+		},
+		{
+			Description: "rm fails for an unpublished port",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("port", "rm", data.Labels().Get("containerName"), "80/tcp")
+			},
+			Expected: test.Expects(1, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -0,0 +1,85 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+)
+
+func RestoreCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "restore [OPTIONS] ARCHIVE",
+		Args:  cobra.ExactArgs(1),
+		Short: "Recreate and restore a container from a checkpoint archive",
+		Long: `Recreate and restore a container from a checkpoint archive written by "nerdctl checkpoint create --export",
+on this host or another one. The container keeps its ID, and its image is pulled when missing.`,
+		RunE:          restoreAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().String("name", "", "Name of the restored container (default: the name of the checkpointed container)")
+	cmd.Flags().BoolP("quiet", "q", false, "Suppress the pull output")
+	return cmd
+}
+
+func restoreOptions(cmd *cobra.Command) (types.ContainerRestoreOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.ContainerRestoreOptions{}, err
+	}
+	name, err := cmd.Flags().GetString("name")
+	if err != nil {
+		return types.ContainerRestoreOptions{}, err
+	}
+	quiet, err := cmd.Flags().GetBool("quiet")
+	if err != nil {
+		return types.ContainerRestoreOptions{}, err
+	}
+	return types.ContainerRestoreOptions{
+		Stdout:   cmd.OutOrStdout(),
+		GOptions: globalOptions,
+		Name:     name,
+		ImagePullOpt: types.ImagePullOptions{
+			GOptions: globalOptions,
+			Stdout:   cmd.OutOrStdout(),
+			Stderr:   cmd.ErrOrStderr(),
+			Quiet:    quiet,
+		},
+	}, nil
+}
+
+func restoreAction(cmd *cobra.Command, args []string) error {
+	options, err := restoreOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	options.NerdctlCmd, options.NerdctlArgs = helpers.GlobalFlags(cmd)
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return container.Restore(ctx, client, args[0], options)
+}
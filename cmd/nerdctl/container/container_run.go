@@ -44,6 +44,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/logging"
 	"github.com/containerd/nerdctl/v2/pkg/netutil"
+	"github.com/containerd/nerdctl/v2/pkg/sdnotify"
 	"github.com/containerd/nerdctl/v2/pkg/signalutil"
 	"github.com/containerd/nerdctl/v2/pkg/taskutil"
 )
@@ -137,6 +138,10 @@ func setCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolP("publish-all", "P", false, "Publish all exposed ports to random ports")
 	cmd.Flags().String("ip", "", "IPv4 address to assign to the container")
 	cmd.Flags().String("ip6", "", "IPv6 address to assign to the container")
+	cmd.Flags().StringSlice("network-alias", nil, "Add network-scoped alias for the container")
+	// --network-scoped-alias is used internally by `nerdctl compose`, which has aliases that only apply to one of the networks
+	cmd.Flags().StringArray("network-scoped-alias", nil, "Add an alias for the container on a single network (NETWORK=ALIAS)")
+	cmd.Flags().MarkHidden("network-scoped-alias")
 	cmd.Flags().StringP("hostname", "h", "", "Container host name")
 	cmd.Flags().String("domainname", "", "Container domain name")
 	cmd.Flags().String("mac-address", "", "MAC address to assign to the container")
@@ -167,6 +172,13 @@ func setCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().Int("oom-score-adj", 0, "Tune container’s OOM preferences (-1000 to 1000, rootless: 100 to 1000)")
 	cmd.Flags().String("pid", "", "PID namespace to use")
 	cmd.Flags().String("uts", "", "UTS namespace to use")
+	cmd.Flags().String("pod", "", "Join the pod, sharing the network, IPC and UTS namespaces of its infra container")
+	// --pod-infra is used internally by `nerdctl pod create`
+	cmd.Flags().Bool("pod-infra", false, "Create the container as the infra container of the pod specified with --pod")
+	cmd.Flags().MarkHidden("pod-infra")
+	cmd.RegisterFlagCompletionFunc("pod", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completion.PodNames(cmd)
+	})
 	cmd.RegisterFlagCompletionFunc("pid", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"host"}, cobra.ShellCompDirectiveNoFileComp
 	})
@@ -256,6 +268,11 @@ func setCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().Duration("health-timeout", 0, "Maximum time to allow one check to run; 0 uses the image value or 30s when unset there too")
 	cmd.Flags().Int("health-retries", 0, "Consecutive failures needed to report unhealthy; 0 uses the image value or 3 when unset there too")
 	cmd.Flags().Duration("health-start-period", 0, "Start period for the container to initialize before starting health-retries countdown")
+	cmd.Flags().Duration("health-start-interval", 0, "Time between running the check during the start period; 0 uses the image value or the interval when unset there too")
+	cmd.Flags().String("health-on-failure", healthcheck.OnFailureNone, `Action to take once the container turns unhealthy ("none"|"kill"|"restart"|"stop")`)
+	cmd.RegisterFlagCompletionFunc("health-on-failure", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{healthcheck.OnFailureNone, healthcheck.OnFailureKill, healthcheck.OnFailureRestart, healthcheck.OnFailureStop}, cobra.ShellCompDirectiveNoFileComp
+	})
 	cmd.Flags().Bool("no-healthcheck", false, "Disable any container-specified HEALTHCHECK")
 
 	// #region env flags
@@ -298,6 +315,10 @@ func setCreateFlags(cmd *cobra.Command) {
 	// shared memory flags
 	cmd.Flags().String("shm-size", "", "Size of /dev/shm")
 	cmd.Flags().String("pidfile", "", "file path to write the task's pid")
+	cmd.Flags().String("sdnotify", sdnotify.Conmon, `Mode used to notify systemd that the container is ready when it is run by a systemd service ("conmon"|"container"|"healthy")`)
+	cmd.RegisterFlagCompletionFunc("sdnotify", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{sdnotify.Conmon, sdnotify.Container, sdnotify.Healthy}, cobra.ShellCompDirectiveNoFileComp
+	})
 
 	// #region verify flags
 	cmd.Flags().String("verify", "none", "Verify the image (none|cosign|notation)")
@@ -443,8 +464,21 @@ func runAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	dataStore, err := clientutil.DataStore(createOpt.GOptions.DataRoot, createOpt.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	if err := containerutil.MountVolumes(lab, c.ID(), dataStore); err != nil {
+		return err
+	}
 	logURI := lab[labels.LogURI]
 	detachC := make(chan struct{})
+	// The container may notify systemd as soon as its task starts
+	notifier := &sdnotify.Notifier{}
+	if !createOpt.Detach {
+		notifier = sdnotify.Prepare(ctx, c, lab)
+	}
+	defer notifier.Stop()
 	task, err := taskutil.NewTask(ctx, client, c, taskutil.TaskOptions{
 		AttachStreamOpt: createOpt.Attach,
 		IsInteractive:   createOpt.Interactive,
@@ -458,6 +492,9 @@ func runAction(cmd *cobra.Command, args []string) error {
 		CheckpointDir:   "",
 	})
 	if err != nil {
+		if unmountErr := containerutil.UnmountVolumes(lab, c.ID(), dataStore); unmountErr != nil {
+			log.L.WithError(unmountErr).Warnf("failed to unmount the volumes of container %s", c.ID())
+		}
 		return err
 	}
 
@@ -469,6 +506,7 @@ func runAction(cmd *cobra.Command, args []string) error {
 	if err := task.Start(ctx); err != nil {
 		return err
 	}
+	containerutil.RecordEvent(ctx, c, dataStore, "start", nil)
 
 	// Set status label running should call after task is started.
 	_, restartPolicyExist := lab[restart.PolicyLabel]
@@ -494,6 +532,7 @@ func runAction(cmd *cobra.Command, args []string) error {
 		fmt.Fprintln(createOpt.Stdout, id)
 		return nil
 	}
+	notifier.Start(ctx)
 	if createOpt.TTY {
 		if err := consoleutil.HandleConsoleResize(ctx, task, con); err != nil {
 			log.L.WithError(err).Error("console resize")
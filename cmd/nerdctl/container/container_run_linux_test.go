@@ -650,6 +650,52 @@ func TestRunWithFluentdLogDriverWithLogOpt(t *testing.T) {
 	testCase.Run(t)
 }
 
+func TestLogsWithFluentdLogDriverCache(t *testing.T) {
+	testCase := nerdtest.Setup()
+	testCase.NoParallel = true
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		tempDirectory := data.Temp().Dir("fluentd")
+		err := os.Chmod(tempDirectory, 0o777)
+		assert.NilError(helpers.T(), err)
+		data.Labels().Set("cached", data.Identifier("cached"))
+		data.Labels().Set("uncached", data.Identifier("uncached"))
+		helpers.Ensure("run", "-d", "--name", data.Identifier("fluentd"), "-p", "24226:24224", "-v", fmt.Sprintf("%s:/fluentd/log", tempDirectory), testutil.FluentdImage)
+		time.Sleep(3 * time.Second)
+	}
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier("cached"))
+		helpers.Anyhow("rm", "-f", data.Identifier("uncached"))
+		helpers.Anyhow("rm", "-f", data.Identifier("fluentd"))
+	}
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "logs are read from the local cache",
+			NoParallel:  true,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "--log-driver", "fluentd", "--log-opt", "fluentd-address=127.0.0.1:24226",
+					"--name", data.Labels().Get("cached"), testutil.CommonImage, "sh", "-c", "echo dual-logging")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", data.Labels().Get("cached"))
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Contains("dual-logging")),
+		},
+		{
+			Description: "logs cannot be read when the cache is disabled",
+			NoParallel:  true,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "--log-driver", "fluentd", "--log-opt", "fluentd-address=127.0.0.1:24226", "--log-opt", "cache-disabled=true",
+					"--name", data.Labels().Get("uncached"), testutil.CommonImage, "sh", "-c", "echo dual-logging")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", data.Labels().Get("uncached"))
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+	}
+	testCase.Run(t)
+}
+
 func TestRunWithOOMScoreAdj(t *testing.T) {
 	score := "-42"
 	testCase := nerdtest.Setup()
@@ -20,6 +20,8 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -89,6 +91,36 @@ func loadNetworkFlags(cmd *cobra.Command, globalOpts types.GlobalCommandOptions)
 	}
 	netOpts.IP6Address = ip6Address
 
+	// --network-alias=<alias>
+	networkAliases, err := cmd.Flags().GetStringSlice("network-alias")
+	if err != nil {
+		return netOpts, err
+	}
+	networkAliases = strutil.DedupeStrSlice(networkAliases)
+	// --network-scoped-alias=<network>=<alias>
+	scopedAliases, err := cmd.Flags().GetStringArray("network-scoped-alias")
+	if err != nil {
+		return netOpts, err
+	}
+	if len(networkAliases) > 0 || len(scopedAliases) > 0 {
+		netOpts.NetworkAliases = make(map[string][]string, len(netOpts.NetworkSlice))
+	}
+	if len(networkAliases) > 0 {
+		for _, netstr := range netOpts.NetworkSlice {
+			netOpts.NetworkAliases[netstr] = networkAliases
+		}
+	}
+	for _, s := range scopedAliases {
+		netstr, alias, ok := strings.Cut(s, "=")
+		if !ok || alias == "" {
+			return netOpts, fmt.Errorf("invalid network-scoped alias %q, expected NETWORK=ALIAS", s)
+		}
+		if !slices.Contains(netOpts.NetworkSlice, netstr) {
+			return netOpts, fmt.Errorf("network-scoped alias %q refers to network %q, which the container is not connected to", alias, netstr)
+		}
+		netOpts.NetworkAliases[netstr] = strutil.DedupeStrSlice(append(slices.Clone(netOpts.NetworkAliases[netstr]), alias))
+	}
+
 	// -h/--hostname=<container hostname>
 	hostName, err := cmd.Flags().GetString("hostname")
 	if err != nil {
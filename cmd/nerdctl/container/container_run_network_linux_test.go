@@ -17,6 +17,7 @@
 package container
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -1410,3 +1411,82 @@ func TestRunExposePublishAll(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+func TestRunNetworkAlias(t *testing.T) {
+	nerdtest.Setup()
+	testCase := &test.Case{
+		Require: require.Not(require.Windows),
+		Setup: func(data test.Data, helpers test.Helpers) {
+			helpers.Ensure("network", "create", data.Identifier())
+			helpers.Ensure("run", "-d", "--name", data.Identifier("server"), "--network", data.Identifier(),
+				"--network-alias", "db", "--network-alias", "database", testutil.CommonImage, "sleep", "inf")
+			nerdtest.EnsureContainerStarted(helpers, data.Identifier("server"))
+		},
+		Cleanup: func(data test.Data, helpers test.Helpers) {
+			helpers.Anyhow("rm", "-f", data.Identifier("server"))
+			helpers.Anyhow("network", "rm", data.Identifier())
+		},
+		SubTests: []*test.Case{
+			{
+				Description: "aliases are resolvable from the network",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("run", "--rm", "--network", data.Identifier(), testutil.CommonImage, "cat", "/etc/hosts")
+				},
+				Expected: test.Expects(0, nil, expect.Contains(" db database")),
+			},
+			{
+				Description: "aliases are shown in inspect",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("inspect", "--format", "{{json .NetworkSettings.Networks}}", data.Identifier("server"))
+				},
+				Expected: test.Expects(0, nil, expect.Contains(`"Aliases":["db","database"]`)),
+			},
+		},
+	}
+	testCase.Run(t)
+}
+
+func TestRunNetworkScopedAlias(t *testing.T) {
+	nerdtest.Setup()
+	testCase := &test.Case{
+		Require: require.Not(require.Windows),
+		Setup: func(data test.Data, helpers test.Helpers) {
+			helpers.Ensure("network", "create", data.Identifier("front"))
+			helpers.Ensure("network", "create", data.Identifier("back"))
+			helpers.Ensure("run", "-d", "--name", data.Identifier("server"),
+				"--network", data.Identifier("front"), "--network", data.Identifier("back"),
+				"--network-scoped-alias", data.Identifier("front")+"=web", testutil.CommonImage, "sleep", "inf")
+			nerdtest.EnsureContainerStarted(helpers, data.Identifier("server"))
+		},
+		Cleanup: func(data test.Data, helpers test.Helpers) {
+			helpers.Anyhow("rm", "-f", data.Identifier("server"))
+			helpers.Anyhow("network", "rm", data.Identifier("front"))
+			helpers.Anyhow("network", "rm", data.Identifier("back"))
+		},
+		SubTests: []*test.Case{
+			{
+				Description: "alias is resolvable from its network",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("run", "--rm", "--network", data.Identifier("front"), testutil.CommonImage, "cat", "/etc/hosts")
+				},
+				Expected: test.Expects(0, nil, expect.Contains(" web")),
+			},
+			{
+				Description: "alias is not resolvable from the other network",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("run", "--rm", "--network", data.Identifier("back"), testutil.CommonImage, "cat", "/etc/hosts")
+				},
+				Expected: test.Expects(0, nil, expect.DoesNotContain(" web")),
+			},
+			{
+				Description: "alias of an unknown network is rejected",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("run", "--rm", "--network", data.Identifier("back"),
+						"--network-scoped-alias", data.Identifier("front")+"=web", testutil.CommonImage, "true")
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("which the container is not connected to")}, nil),
+			},
+		},
+	}
+	testCase.Run(t)
+}
@@ -46,6 +46,7 @@ func StartCommand() *cobra.Command {
 	cmd.Flags().BoolP("interactive", "i", false, "Attach container's STDIN")
 	cmd.Flags().String("checkpoint", "", "checkpoint name")
 	cmd.Flags().String("checkpoint-dir", "", "checkpoint directory")
+	cmd.Flags().String("pidfile", "", "file path to write the pid of the started container")
 	return cmd
 }
 
@@ -74,6 +75,10 @@ func startOptions(cmd *cobra.Command) (types.ContainerStartOptions, error) {
 	if err != nil {
 		return types.ContainerStartOptions{}, err
 	}
+	pidFile, err := cmd.Flags().GetString("pidfile")
+	if err != nil {
+		return types.ContainerStartOptions{}, err
+	}
 	return types.ContainerStartOptions{
 		Stdout:        cmd.OutOrStdout(),
 		GOptions:      globalOptions,
@@ -82,6 +87,7 @@ func startOptions(cmd *cobra.Command) (types.ContainerStartOptions, error) {
 		Interactive:   interactive,
 		Checkpoint:    checkpoint,
 		CheckpointDir: checkpointDir,
+		PidFile:       pidFile,
 	}, nil
 }
 
@@ -131,3 +131,44 @@ func TestStartWithCheckpoint(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+func TestStartPidFile(t *testing.T) {
+	testCase := nerdtest.Setup()
+	// `start --pidfile` is not supported by Docker
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("create", "--name", data.Identifier(), testutil.CommonImage, "sleep", nerdtest.Infinity)
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "the pid of the started container is written",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("start", "--pidfile", data.Temp().Path("container.pid"), data.Identifier())
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(_ string, t tig.T) {
+						pid := helpers.Capture("inspect", "--format", "{{.State.Pid}}", data.Identifier())
+						assert.Equal(t, data.Temp().Load("container.pid"), strings.TrimSpace(pid))
+					},
+				}
+			},
+		},
+		{
+			Description: "--pidfile cannot be used with --attach",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("start", "-a", "--pidfile", data.Temp().Path("container.pid"), data.Identifier())
+			},
+			Expected: test.Expects(1, []error{errors.New("--pidfile cannot be used with --attach")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -105,6 +105,23 @@ func TestStats(t *testing.T) {
 			},
 			Expected: test.Expects(0, nil, expect.Contains("1GiB")),
 		},
+		{
+			Description: "openmetrics",
+			Require:     require.Not(nerdtest.Docker),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("stats", "--format", "openmetrics", data.Identifier("memlimited"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.All(
+						expect.Contains("# TYPE nerdctl_container_cpu_usage_percent gauge"),
+						expect.Contains(`name="`+data.Identifier("memlimited")+`"`),
+						expect.Contains("nerdctl_container_memory_limit_bytes{"),
+						expect.Contains("# EOF"),
+					),
+				}
+			},
+		},
 	}
 
 	testCase.Run(t)
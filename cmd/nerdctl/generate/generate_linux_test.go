@@ -0,0 +1,150 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package generate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestGenerateSystemd(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// `generate systemd` is not supported by Docker
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("create", "--name", data.Identifier(), testutil.CommonImage, "sleep", nerdtest.Infinity)
+		data.Labels().Set("container", data.Identifier())
+		helpers.Ensure("create", "--name", data.Identifier("pidfile"), "--pidfile", data.Temp().Path("container.pid"),
+			testutil.CommonImage, "sleep", nerdtest.Infinity)
+		data.Labels().Set("pidfile", data.Identifier("pidfile"))
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+		helpers.Anyhow("rm", "-f", data.Identifier("pidfile"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "the unit starts the container attached",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("generate", "systemd", "--time", "5", data.Labels().Get("container"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				name := data.Labels().Get("container")
+				return &test.Expected{
+					Output: expect.All(
+						expect.Contains(
+							"# container-"+name+".service",
+							"Type=notify",
+							"start -a "+name,
+							"stop -t 5 "+name,
+							"KillMode=mixed",
+						),
+						expect.DoesNotContain("PIDFile="),
+					),
+				}
+			},
+		},
+		{
+			Description: "the unit of a container with a pid file starts it detached",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("generate", "systemd", data.Labels().Get("pidfile"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				pidFile := data.Temp().Path("container.pid")
+				return &test.Expected{
+					Output: expect.All(
+						expect.Contains(
+							"Type=forking",
+							"start --pidfile="+pidFile+" "+data.Labels().Get("pidfile"),
+							"PIDFile="+pidFile,
+						),
+						expect.DoesNotContain("Type=notify"),
+					),
+				}
+			},
+		},
+		{
+			Description: "invalid restart policy",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("generate", "systemd", "--restart-policy", "sometimes", data.Labels().Get("container"))
+			},
+			Expected: test.Expects(1, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunSdNotify(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "--sdnotify=container forwards the notification socket",
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--name", data.Identifier(), "--sdnotify=container", testutil.CommonImage, "printenv", "NOTIFY_SOCKET")
+			},
+			Expected: test.Expects(0, nil, expect.Equals("/run/notify/notify.sock\n")),
+		},
+		{
+			Description: "--sdnotify=healthy requires a health check",
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--name", data.Identifier(), "--sdnotify=healthy", testutil.CommonImage, "true")
+			},
+			Expected: test.Expects(1, nil, nil),
+		},
+		{
+			Description: "--sdnotify=healthy requires the health check timers",
+			// the health check timers are not created in rootless mode
+			Require: nerdtest.Rootless,
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("create", "--name", data.Identifier(), "--sdnotify=healthy",
+					"--health-cmd", "true", testutil.CommonImage, "sleep", nerdtest.Infinity)
+			},
+			Expected: test.Expects(1, []error{errors.New("requires the systemd health check timers")}, nil),
+		},
+		{
+			Description: "invalid --sdnotify mode",
+			Command:     test.Command("run", "--rm", "--sdnotify=ignore", testutil.CommonImage, "true"),
+			Expected:    test.Expects(1, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -0,0 +1,102 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package generate
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/generate"
+)
+
+func systemdCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "systemd [flags] [CONTAINER...]",
+		Short: "Generate systemd units for containers",
+		Long: `Generate systemd units that run containers with "nerdctl start -a" and stop them with "nerdctl stop".
+The units of the containers of a pod depend on the unit of its infra container,
+and the units of the containers of a compose project (--compose-project) follow the "depends_on" of the services.`,
+		RunE:              systemdAction,
+		ValidArgsFunction: systemdShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	cmd.Flags().String("compose-project", "", "Generate the units of the containers of a compose project")
+	cmd.Flags().String("restart-policy", "on-failure", `Restart policy of the units ("no"|"on-success"|"on-failure"|"on-abnormal"|"on-watchdog"|"on-abort"|"always")`)
+	cmd.RegisterFlagCompletionFunc("restart-policy", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"no", "on-success", "on-failure", "on-abnormal", "on-watchdog", "on-abort", "always"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().UintP("time", "t", 10, "Seconds to wait for the containers to stop before killing them")
+	cmd.Flags().Bool("files", false, "Write the units to files in the current directory, and print their paths")
+	return cmd
+}
+
+func systemdOptions(cmd *cobra.Command) (types.GenerateSystemdOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.GenerateSystemdOptions{}, err
+	}
+	composeProject, err := cmd.Flags().GetString("compose-project")
+	if err != nil {
+		return types.GenerateSystemdOptions{}, err
+	}
+	restartPolicy, err := cmd.Flags().GetString("restart-policy")
+	if err != nil {
+		return types.GenerateSystemdOptions{}, err
+	}
+	stopTimeout, err := cmd.Flags().GetUint("time")
+	if err != nil {
+		return types.GenerateSystemdOptions{}, err
+	}
+	files, err := cmd.Flags().GetBool("files")
+	if err != nil {
+		return types.GenerateSystemdOptions{}, err
+	}
+	nerdctlCmd, nerdctlArgs := helpers.GlobalFlags(cmd)
+	return types.GenerateSystemdOptions{
+		Stdout:         cmd.OutOrStdout(),
+		GOptions:       globalOptions,
+		ComposeProject: composeProject,
+		RestartPolicy:  restartPolicy,
+		StopTimeout:    stopTimeout,
+		Files:          files,
+		NerdctlCmd:     nerdctlCmd,
+		NerdctlArgs:    nerdctlArgs,
+	}, nil
+}
+
+func systemdAction(cmd *cobra.Command, args []string) error {
+	options, err := systemdOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return generate.Systemd(ctx, client, args, options)
+}
+
+func systemdShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completion.ContainerNames(cmd, nil)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil/load"
 )
 
@@ -37,7 +38,7 @@ func LoadCommand() *cobra.Command {
 		SilenceErrors: true,
 	}
 
-	cmd.Flags().StringP("input", "i", "", "Read from tar archive file, instead of STDIN")
+	cmd.Flags().StringP("input", "i", "", "Read from tar archive file or OCI image layout directory, instead of STDIN")
 	cmd.Flags().BoolP("quiet", "q", false, "Suppress the load output")
 
 	// #region platform flags
@@ -94,6 +95,9 @@ func loadAction(cmd *cobra.Command, _ []string) error {
 	}
 	defer cancel()
 
-	_, err = load.FromArchive(ctx, client, options)
+	loaded, err := load.FromArchive(ctx, client, options)
+	for _, img := range loaded {
+		image.RecordEvent(ctx, options.GOptions, img.Name, "load", nil)
+	}
 	return err
 }
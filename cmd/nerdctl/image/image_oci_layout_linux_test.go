@@ -0,0 +1,95 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestOCILayout(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// OCI image layout directories are not supported by docker save/load
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("pull", "--quiet", testutil.CommonImage)
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "save --format oci-dir writes a layout",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("save", "--format", "oci-dir", "-o", filepath.Join(data.Temp().Path(), "layout"), testutil.CommonImage)
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, t tig.T) {
+						for _, f := range []string{"oci-layout", "index.json", "blobs/sha256"} {
+							_, err := os.Stat(filepath.Join(data.Temp().Path(), "layout", f))
+							assert.NilError(t, err)
+						}
+					},
+				}
+			},
+		},
+		{
+			Description: "load --input reads a layout",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("save", "--format", "oci-dir", "-o", filepath.Join(data.Temp().Path(), "layout"), testutil.CommonImage)
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("load", "--input", filepath.Join(data.Temp().Path(), "layout"))
+			},
+			Expected: test.Expects(0, nil, expect.Contains("Loaded image:")),
+		},
+		{
+			Description: "push to a layout, then run from it",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("push", testutil.CommonImage, "oci-layout://"+filepath.Join(data.Temp().Path(), "layout")+":v1")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "oci-layout://"+filepath.Join(data.Temp().Path(), "layout")+":v1", "echo", "foo")
+			},
+			Expected: test.Expects(0, nil, expect.Equals("foo\n")),
+		},
+		{
+			Description: "pull an unknown tag",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("push", testutil.CommonImage, "oci-layout://"+filepath.Join(data.Temp().Path(), "layout")+":v1")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("pull", "oci-layout://"+filepath.Join(data.Temp().Path(), "layout")+":v2")
+			},
+			Expected: test.Expects(1, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -17,6 +17,8 @@
 package image
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
@@ -24,6 +26,8 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/converter"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
 )
 
 const (
@@ -32,9 +36,10 @@ const (
 
 func PushCommand() *cobra.Command {
 	var cmd = &cobra.Command{
-		Use:               "push [flags] NAME[:TAG]",
+		Use:               "push [flags] NAME[:TAG] [oci-layout://PATH[:TAG]]",
 		Short:             "Push an image or a repository to a registry. Optionally specify \"ipfs://\" or \"ipns://\" scheme to push image to IPFS.",
-		Args:              helpers.IsExactArgs(1),
+		Long:              "Push an image or a repository to a registry.\nOptionally specify \"ipfs://\" or \"ipns://\" scheme to push image to IPFS, or an \"oci-layout://PATH[:TAG]\" destination to write it to an OCI image layout directory.",
+		Args:              cobra.RangeArgs(1, 2),
 		RunE:              pushAction,
 		ValidArgsFunction: pushShellComplete,
 		SilenceUsage:      true,
@@ -47,7 +52,13 @@ func PushCommand() *cobra.Command {
 	cmd.Flags().Bool("all-platforms", false, "Push content for all platforms")
 	// #endregion
 
-	cmd.Flags().Bool("estargz", false, "Convert the image into eStargz")
+	cmd.Flags().Bool("estargz", false, "Convert the image into eStargz (same as --compression=estargz)")
+	cmd.Flags().String("compression", "", "Recompress the layers before pushing (gzip|zstd|zstd:chunked|estargz)")
+	cmd.RegisterFlagCompletionFunc("compression", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return converter.Compressions(), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().Int("compression-level", -1, "Compression level for --compression (default depends on the compression)")
+	cmd.Flags().Bool("force-compression", false, "Recompress the layers even when they already use the --compression format")
 	cmd.Flags().Bool("ipfs-ensure-image", true, "Ensure the entire contents of the image is locally available before push")
 	cmd.Flags().String("ipfs-address", "", "multiaddr of IPFS API (default uses $IPFS_PATH env variable if defined or local directory ~/.ipfs)")
 
@@ -89,6 +100,18 @@ func pushOptions(cmd *cobra.Command) (types.ImagePushOptions, error) {
 	if err != nil {
 		return types.ImagePushOptions{}, err
 	}
+	compression, err := cmd.Flags().GetString("compression")
+	if err != nil {
+		return types.ImagePushOptions{}, err
+	}
+	compressionLevel, err := cmd.Flags().GetInt("compression-level")
+	if err != nil {
+		return types.ImagePushOptions{}, err
+	}
+	forceCompression, err := cmd.Flags().GetBool("force-compression")
+	if err != nil {
+		return types.ImagePushOptions{}, err
+	}
 	ipfsEnsureImage, err := cmd.Flags().GetBool("ipfs-ensure-image")
 	if err != nil {
 		return types.ImagePushOptions{}, err
@@ -120,6 +143,9 @@ func pushOptions(cmd *cobra.Command) (types.ImagePushOptions, error) {
 		Platforms:                      platform,
 		AllPlatforms:                   allPlatforms,
 		Estargz:                        estargz,
+		Compression:                    compression,
+		CompressionLevel:               compressionLevel,
+		ForceCompression:               forceCompression,
 		IpfsEnsureImage:                ipfsEnsureImage,
 		IpfsAddress:                    ipfsAddress,
 		Quiet:                          quiet,
@@ -134,6 +160,12 @@ func pushAction(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	rawRef := args[0]
+	if len(args) == 2 {
+		if !referenceutil.IsOCILayout(args[1]) {
+			return fmt.Errorf("the destination must be an %sPATH[:TAG] reference, got %q", referenceutil.OCILayoutPrefix, args[1])
+		}
+		options.OCILayout = args[1]
+	}
 
 	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
 	if err != nil {
@@ -44,7 +44,11 @@ func SaveCommand() *cobra.Command {
 		SilenceUsage:      true,
 		SilenceErrors:     true,
 	}
-	cmd.Flags().StringP("output", "o", "", "Write to a file, instead of STDOUT")
+	cmd.Flags().StringP("output", "o", "", "Write to a file (or a directory for --format=oci-dir), instead of STDOUT")
+	cmd.Flags().String("format", image.SaveFormatTar, "Output format (tar|oci-dir)")
+	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{image.SaveFormatTar, image.SaveFormatOCIDir}, cobra.ShellCompDirectiveNoFileComp
+	})
 	cmd.Flags().BoolP("quiet", "q", false, "Suppress the progress output")
 
 	// #region platform flags
@@ -75,12 +79,17 @@ func saveOptions(cmd *cobra.Command) (types.ImageSaveOptions, error) {
 	if err != nil {
 		return types.ImageSaveOptions{}, err
 	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return types.ImageSaveOptions{}, err
+	}
 
 	return types.ImageSaveOptions{
 		GOptions:     globalOptions,
 		AllPlatforms: allPlatforms,
 		Platform:     platform,
 		Quiet:        quiet,
+		Format:       format,
 	}, err
 }
 
@@ -94,6 +103,9 @@ func saveAction(cmd *cobra.Command, args []string) error {
 	outputPath, err := cmd.Flags().GetString("output")
 	if err != nil {
 		return err
+	} else if options.Format == image.SaveFormatOCIDir {
+		options.OutputDir = outputPath
+		output = nil
 	} else if outputPath != "" {
 		f, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY, 0644)
 		if err != nil {
@@ -119,7 +131,7 @@ func saveAction(cmd *cobra.Command, args []string) error {
 	}
 	defer cancel()
 
-	if err = image.Save(ctx, client, args, options); err != nil && outputPath != "" {
+	if err = image.Save(ctx, client, args, options); err != nil && outputPath != "" && options.OutputDir == "" {
 		os.Remove(outputPath)
 	}
 	return err
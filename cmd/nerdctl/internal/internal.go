@@ -31,6 +31,8 @@ func Command() *cobra.Command {
 
 	cmd.AddCommand(
 		newInternalOCIHookCommandCommand(),
+		newInternalDNSServerCommand(),
+		newInternalSeccompTrackerCommand(),
 	)
 
 	return cmd
@@ -0,0 +1,120 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/dnsutil"
+	"github.com/containerd/nerdctl/v2/pkg/dnsutil/embeddeddns"
+	"github.com/containerd/nerdctl/v2/pkg/resolvconf"
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+)
+
+// dnsServerIdleTimeout is the time after which the embedded DNS server exits when its network has no container.
+const dnsServerIdleTimeout = time.Minute
+
+func newInternalDNSServerCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:           "dns-server",
+		Short:         "Embedded DNS server of a bridge network",
+		Args:          cobra.NoArgs,
+		RunE:          internalDNSServerAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().String("address", "", "Gateway address of the network to listen on")
+	return cmd
+}
+
+func internalDNSServerAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	addressStr, err := cmd.Flags().GetString("address")
+	if err != nil {
+		return err
+	}
+	address := net.ParseIP(addressStr)
+	if address == nil {
+		return fmt.Errorf("invalid address %q", addressStr)
+	}
+	dataStore, err := clientutil.DataStore(globalOptions.DataRoot, globalOptions.Address)
+	if err != nil {
+		return err
+	}
+
+	upstreams := globalOptions.DNS
+	if len(upstreams) == 0 {
+		if upstreams, err = hostNameservers(); err != nil {
+			return err
+		}
+	}
+	srv := &embeddeddns.Server{
+		DataStore:   dataStore,
+		Address:     address,
+		IdleTimeout: dnsServerIdleTimeout,
+	}
+	for _, upstream := range upstreams {
+		srv.Upstreams = append(srv.Upstreams, net.JoinHostPort(upstream, "53"))
+	}
+
+	ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	defer embeddeddns.RemovePIDFile(dataStore, address)
+	return srv.Serve(ctx)
+}
+
+// hostNameservers returns the nameservers of the host, as the server runs on the host.
+// Unlike for containers, the localhost nameservers (e.g., systemd-resolved) are reachable,
+// except in rootless mode, where the server runs in the network namespace of RootlessKit.
+func hostNameservers() ([]string, error) {
+	var nameservers []string
+	if rootlessutil.IsRootlessChild() {
+		slirp4Dns, err := dnsutil.GetSlirp4netnsDNS()
+		if err != nil {
+			return nil, err
+		}
+		nameservers = append(nameservers, slirp4Dns...)
+	}
+	conf, err := resolvconf.Get()
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		conf = &resolvconf.File{}
+	}
+	for _, ns := range resolvconf.GetNameservers(conf.Content, resolvconf.IP) {
+		if ip := net.ParseIP(ns); ip != nil && ip.IsLoopback() && rootlessutil.IsRootlessChild() {
+			continue
+		}
+		nameservers = append(nameservers, ns)
+	}
+	return nameservers, nil
+}
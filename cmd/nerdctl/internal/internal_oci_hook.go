@@ -19,6 +19,7 @@ package internal
 import (
 	"errors"
 	"os"
+	"slices"
 
 	"github.com/spf13/cobra"
 
@@ -57,10 +58,20 @@ func internalOCIHookAction(cmd *cobra.Command, args []string) error {
 	cniPath := globalOptions.CNIPath
 	cniNetconfpath := globalOptions.CNINetConfPath
 	bridgeIP := globalOptions.BridgeIP
+	args0, args := helpers.GlobalFlags(cmd)
+	nerdctlCmd := append([]string{args0}, args...)
+	var dnsServerCmd []string
+	if globalOptions.EmbeddedDNS {
+		dnsServerCmd = append(slices.Clone(nerdctlCmd), "internal", "dns-server")
+	}
+	seccompTrackerCmd := append(slices.Clone(nerdctlCmd), "internal", "seccomp-tracker")
 	return ocihook.Run(os.Stdin, os.Stderr, event,
+		globalOptions.Address,
 		dataStore,
 		cniPath,
 		cniNetconfpath,
 		bridgeIP,
+		dnsServerCmd,
+		seccompTrackerCmd,
 	)
 }
@@ -0,0 +1,70 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package internal
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/seccomputil"
+)
+
+func newInternalSeccompTrackerCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:           "seccomp-tracker",
+		Short:         "Track the processes of a container recording its seccomp profile",
+		Args:          cobra.NoArgs,
+		RunE:          internalSeccompTrackerAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().Int("pid", 0, "PID of the init process of the container")
+	cmd.Flags().String("output", "", "File to write the PIDs of the processes of the container to")
+	return cmd
+}
+
+func internalSeccompTrackerAction(cmd *cobra.Command, args []string) error {
+	pid, err := cmd.Flags().GetInt("pid")
+	if err != nil {
+		return err
+	}
+	if pid <= 0 {
+		return fmt.Errorf("invalid pid %d", pid)
+	}
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		return fmt.Errorf("output must be set")
+	}
+	f, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	return seccomputil.TrackProcesses(ctx, pid, f, func() {
+		fmt.Fprintln(cmd.OutOrStdout(), "ready")
+	})
+}
@@ -0,0 +1,173 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package kube
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestKubePlay(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// `kube play` is not supported by Docker
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		manifest := fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  greeting: hello
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: %s
+spec:
+  containers:
+  - name: app
+    image: %s
+    command: ["sleep", "%s"]
+    env:
+    - name: GREETING
+      valueFrom:
+        configMapKeyRef:
+          name: config
+          key: greeting
+    volumeMounts:
+    - name: config
+      mountPath: /etc/app
+    - name: data
+      mountPath: /data
+  volumes:
+  - name: config
+    configMap:
+      name: config
+  - name: data
+    emptyDir: {}
+`, data.Identifier(), testutil.CommonImage, nerdtest.Infinity)
+		data.Temp().Save(manifest, "pod.yaml")
+		helpers.Ensure("kube", "play", "--infra-image", testutil.NginxAlpineImage, data.Temp().Path("pod.yaml"))
+		data.Labels().Set("pod", data.Identifier())
+		data.Labels().Set("manifest", data.Temp().Path("pod.yaml"))
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("kube", "down", data.Temp().Path("pod.yaml"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "environment variables are resolved from config maps",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("exec", data.Labels().Get("pod")+"-app", "printenv", "GREETING")
+			},
+			Expected: test.Expects(0, nil, expect.Equals("hello\n")),
+		},
+		{
+			Description: "config map volumes are mounted",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("exec", data.Labels().Get("pod")+"-app", "cat", "/etc/app/greeting")
+			},
+			Expected: test.Expects(0, nil, expect.Equals("hello")),
+		},
+		{
+			Description: "kube generate emits the pod",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("kube", "generate", data.Labels().Get("pod")+"-app")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Contains(
+						"kind: Pod",
+						"name: "+data.Labels().Get("pod"),
+						"- name: app",
+						"name: GREETING",
+						"mountPath: /etc/app",
+					),
+				}
+			},
+		},
+		{
+			Description: "kube down removes the pod",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				helpers.Ensure("kube", "down", data.Labels().Get("manifest"))
+				return helpers.Command("pod", "inspect", data.Labels().Get("pod"))
+			},
+			Expected: test.Expects(1, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestKubePlayRollback(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// `kube play` is not supported by Docker
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		manifest := fmt.Sprintf(`apiVersion: v1
+kind: Pod
+metadata:
+  name: %s
+spec:
+  containers:
+  - name: app
+    image: %s
+    command: ["sleep", "%s"]
+  - name: broken
+    image: 127.0.0.1:1/nonexistent:latest
+`, data.Identifier(), testutil.CommonImage, nerdtest.Infinity)
+		data.Temp().Save(manifest, "pod.yaml")
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("kube", "down", data.Temp().Path("pod.yaml"))
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("kube", "play", "--infra-image", testutil.NginxAlpineImage, data.Temp().Path("pod.yaml"))
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			ExitCode: expect.ExitCodeGenericFail,
+			Errors:   []error{errors.New(`failed to create container "` + data.Identifier() + `-broken"`)},
+			Output: func(_ string, t tig.T) {
+				// the pod and the container created before the failure are removed
+				helpers.Fail("pod", "inspect", data.Identifier())
+				helpers.Fail("container", "inspect", data.Identifier()+"-app")
+			},
+		}
+	}
+
+	testCase.Run(t)
+}
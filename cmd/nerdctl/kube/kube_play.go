@@ -0,0 +1,80 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package kube
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/kube"
+	"github.com/containerd/nerdctl/v2/pkg/podutil"
+)
+
+func playCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "play [flags] FILE",
+		Args:          helpers.IsExactArgs(1),
+		Short:         "Create and start the pods of a Kubernetes manifest",
+		Long:          "Create and start the pods of a Kubernetes manifest.\nPod, Deployment and ConfigMap resources are supported. Each pod is created as a nerdctl pod.",
+		RunE:          playAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().String("infra-image", podutil.DefaultInfraImage, "Image of the infra containers of the pods")
+	cmd.RegisterFlagCompletionFunc("infra-image", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completion.ImageNames(cmd)
+	})
+	return cmd
+}
+
+func playOptions(cmd *cobra.Command) (types.KubePlayOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.KubePlayOptions{}, err
+	}
+	infraImage, err := cmd.Flags().GetString("infra-image")
+	if err != nil {
+		return types.KubePlayOptions{}, err
+	}
+	nerdctlCmd, nerdctlArgs := helpers.GlobalFlags(cmd)
+	return types.KubePlayOptions{
+		Stdout:      cmd.OutOrStdout(),
+		Stderr:      cmd.ErrOrStderr(),
+		GOptions:    globalOptions,
+		InfraImage:  infraImage,
+		NerdctlCmd:  nerdctlCmd,
+		NerdctlArgs: nerdctlArgs,
+	}, nil
+}
+
+func playAction(cmd *cobra.Command, args []string) error {
+	options, err := playOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return kube.Play(ctx, client, args[0], options)
+}
@@ -45,7 +45,8 @@ func appNeedsRootlessParentMain(cmd *cobra.Command, args []string) bool {
 	// completion, login, logout, version: false, because it shouldn't require the daemon to be running
 	// apparmor: false, because it requires the initial mount namespace to access /sys/kernel/security
 	// cp, compose cp: false, because it requires the initial mount namespace to inspect file owners
-	case "", "completion", "login", "logout", "apparmor", "cp", "version":
+	// seccomp: false, because it only reads profiles
+	case "", "completion", "login", "logout", "apparmor", "cp", "seccomp", "version":
 		return false
 	case "container":
 		if len(commands) < 3 {
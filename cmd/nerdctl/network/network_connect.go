@@ -0,0 +1,86 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package network
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/network"
+)
+
+func connectCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "connect [flags] NETWORK CONTAINER",
+		Short:             "Connect a container to a network",
+		Args:              helpers.IsExactArgs(2),
+		RunE:              connectAction,
+		ValidArgsFunction: networkConnectShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	cmd.Flags().String("ip", "", "IPv4 address (e.g., 172.30.100.104)")
+	cmd.Flags().String("ip6", "", "IPv6 address (e.g., 2001:db8::33)")
+	cmd.Flags().StringSlice("alias", nil, "Add network-scoped alias for the container")
+	return cmd
+}
+
+func connectAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	ip, err := cmd.Flags().GetString("ip")
+	if err != nil {
+		return err
+	}
+	ip6, err := cmd.Flags().GetString("ip6")
+	if err != nil {
+		return err
+	}
+	aliases, err := cmd.Flags().GetStringSlice("alias")
+	if err != nil {
+		return err
+	}
+
+	options := types.NetworkConnectOptions{
+		GOptions:   globalOptions,
+		Network:    args[0],
+		Container:  args[1],
+		IPAddress:  ip,
+		IP6Address: ip6,
+		Aliases:    aliases,
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return network.Connect(ctx, client, options)
+}
+
+func networkConnectShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completion.NetworkNames(cmd, []string{"host", "none"})
+	}
+	return completion.ContainerNames(cmd, nil)
+}
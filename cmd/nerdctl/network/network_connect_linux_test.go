@@ -0,0 +1,111 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package network
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestNetworkConnect(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = nerdtest.Rootful
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("network", "create", data.Identifier("net"), "--subnet", "10.5.101.0/24")
+		data.Labels().Set("net", data.Identifier("net"))
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("network", "rm", data.Labels().Get("net"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "Connect a running container",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "-d", "--name", data.Identifier(), testutil.CommonImage, "sleep", nerdtest.Infinity)
+				helpers.Ensure("run", "-d", "--name", data.Identifier("peer"), "--net", data.Labels().Get("net"), testutil.CommonImage, "sleep", nerdtest.Infinity)
+				helpers.Ensure("network", "connect", "--ip", "10.5.101.42", "--alias", "connected-alias", data.Labels().Get("net"), data.Identifier())
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier(), data.Identifier("peer"))
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("exec", data.Identifier("peer"), "cat", "/etc/hosts")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Contains("10.5.101.42", "connected-alias", data.Identifier()),
+				}
+			},
+		},
+		{
+			Description: "Disconnect a running container",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "-d", "--name", data.Identifier(), testutil.CommonImage, "sleep", nerdtest.Infinity)
+				helpers.Ensure("network", "connect", data.Labels().Get("net"), data.Identifier())
+				helpers.Ensure("exec", data.Identifier(), "ip", "link", "show", "eth1")
+				helpers.Ensure("network", "disconnect", data.Labels().Get("net"), data.Identifier())
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("exec", data.Identifier(), "ip", "link", "show", "eth1")
+			},
+			Expected: test.Expects(1, nil, nil),
+		},
+		{
+			Description: "Connected network is set up on restart",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "-d", "--name", data.Identifier(), testutil.CommonImage, "sleep", nerdtest.Infinity)
+				helpers.Ensure("network", "connect", data.Labels().Get("net"), data.Identifier())
+				helpers.Ensure("restart", data.Identifier())
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("exec", data.Identifier(), "ip", "addr", "show", "eth1")
+			},
+			Expected: test.Expects(0, nil, expect.Contains("10.5.101.")),
+		},
+		{
+			Description: "Cannot disconnect the last network",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "-d", "--name", data.Identifier(), testutil.CommonImage, "sleep", nerdtest.Infinity)
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("network", "disconnect", "bridge", data.Identifier())
+			},
+			Expected: test.Expects(1, []error{errors.New("last network")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
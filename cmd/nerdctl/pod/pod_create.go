@@ -0,0 +1,119 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package pod
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/pod"
+	"github.com/containerd/nerdctl/v2/pkg/podutil"
+)
+
+func createCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "create [flags] POD",
+		Args:          helpers.IsExactArgs(1),
+		Short:         "Create a pod",
+		Long:          "Create a pod by running its infra container. Containers join the pod with `nerdctl run --pod POD`.",
+		RunE:          createAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().String("infra-image", podutil.DefaultInfraImage, "Image of the infra container")
+	cmd.RegisterFlagCompletionFunc("infra-image", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completion.ImageNames(cmd)
+	})
+	cmd.Flags().String("hostname", "", "Host name of the pod (default: the pod name)")
+	cmd.Flags().StringSliceP("publish", "p", nil, "Publish a port of the pod to the host")
+	cmd.Flags().StringArrayP("label", "l", nil, "Set metadata on the pod")
+	cmd.Flags().StringSlice("network", nil, "Connect the pod to a network")
+	cmd.RegisterFlagCompletionFunc("network", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completion.NetworkNames(cmd, []string{})
+	})
+	cmd.Flags().StringSlice("add-host", nil, "Add a custom host-to-IP mapping (host:ip)")
+	cmd.Flags().StringSlice("dns", nil, "Set custom DNS servers")
+	return cmd
+}
+
+func createOptions(cmd *cobra.Command) (types.PodCreateOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.PodCreateOptions{}, err
+	}
+	infraImage, err := cmd.Flags().GetString("infra-image")
+	if err != nil {
+		return types.PodCreateOptions{}, err
+	}
+	hostname, err := cmd.Flags().GetString("hostname")
+	if err != nil {
+		return types.PodCreateOptions{}, err
+	}
+	publish, err := cmd.Flags().GetStringSlice("publish")
+	if err != nil {
+		return types.PodCreateOptions{}, err
+	}
+	labels, err := cmd.Flags().GetStringArray("label")
+	if err != nil {
+		return types.PodCreateOptions{}, err
+	}
+	networks, err := cmd.Flags().GetStringSlice("network")
+	if err != nil {
+		return types.PodCreateOptions{}, err
+	}
+	addHost, err := cmd.Flags().GetStringSlice("add-host")
+	if err != nil {
+		return types.PodCreateOptions{}, err
+	}
+	dns, err := cmd.Flags().GetStringSlice("dns")
+	if err != nil {
+		return types.PodCreateOptions{}, err
+	}
+	nerdctlCmd, nerdctlArgs := helpers.GlobalFlags(cmd)
+	return types.PodCreateOptions{
+		Stdout:      cmd.OutOrStdout(),
+		Stderr:      cmd.ErrOrStderr(),
+		GOptions:    globalOptions,
+		InfraImage:  infraImage,
+		Hostname:    hostname,
+		Publish:     publish,
+		Labels:      labels,
+		Networks:    networks,
+		AddHost:     addHost,
+		DNSServers:  dns,
+		NerdctlCmd:  nerdctlCmd,
+		NerdctlArgs: nerdctlArgs,
+	}, nil
+}
+
+func createAction(cmd *cobra.Command, args []string) error {
+	options, err := createOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return pod.Create(ctx, client, args[0], options)
+}
@@ -0,0 +1,173 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package pod
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestPod(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// Pods are not supported by Docker
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		// nginx keeps running, so it can be used as the infra container and be reached by the other containers
+		helpers.Ensure("pod", "create", "--infra-image", testutil.NginxAlpineImage, data.Identifier())
+		helpers.Ensure("run", "-d", "--pod", data.Identifier(), "--name", data.Identifier("member"),
+			testutil.CommonImage, "sleep", nerdtest.Infinity)
+		data.Labels().Set("pod", data.Identifier())
+		data.Labels().Set("member", data.Identifier("member"))
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("pod", "rm", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "containers share the hostname of the pod",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("exec", data.Labels().Get("member"), "hostname")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Equals(data.Labels().Get("pod") + "\n"),
+				}
+			},
+		},
+		{
+			Description: "containers share the network namespace of the pod",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("exec", data.Labels().Get("member"), "wget", "-qO-", "http://127.0.0.1:80")
+			},
+			Expected: test.Expects(0, nil, expect.Contains(testutil.NginxAlpineIndexHTMLSnippet)),
+		},
+		{
+			Description: "--pod cannot be used with --network",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--pod", data.Labels().Get("pod"), "--network", "host", testutil.CommonImage)
+			},
+			Expected: test.Expects(1, []error{errors.New("cannot be used with --pod")}, nil),
+		},
+		{
+			Description: "pod ps lists the pod",
+			Command:     test.Command("pod", "ps", "--format", "json"),
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, t tig.T) {
+						found := false
+						for _, line := range splitLines(stdout) {
+							var p struct {
+								Name       string
+								Status     string
+								Containers int
+							}
+							assert.NilError(t, json.Unmarshal([]byte(line), &p))
+							if p.Name == data.Labels().Get("pod") {
+								found = true
+								assert.Equal(t, p.Status, "Running")
+								assert.Equal(t, p.Containers, 2)
+							}
+						}
+						assert.Assert(t, found, "pod %s not found in %s", data.Labels().Get("pod"), stdout)
+					},
+				}
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestPodLifecycle(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("pod", "create", "--infra-image", testutil.NginxAlpineImage, data.Identifier())
+		helpers.Ensure("run", "-d", "--pod", data.Identifier(), "--name", data.Identifier("member"),
+			testutil.CommonImage, "sleep", nerdtest.Infinity)
+		data.Labels().Set("pod", data.Identifier())
+		data.Labels().Set("member", data.Identifier("member"))
+		helpers.Ensure("pod", "stop", data.Identifier())
+		helpers.Ensure("pod", "start", data.Identifier())
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("pod", "rm", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "containers are restarted in the namespaces of the pod",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("exec", data.Labels().Get("member"), "wget", "-qO-", "http://127.0.0.1:80")
+			},
+			Expected: test.Expects(0, nil, expect.Contains(testutil.NginxAlpineIndexHTMLSnippet)),
+		},
+		{
+			Description: "running pods are not removed without --force",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("pod", "rm", data.Labels().Get("pod"))
+			},
+			Expected: test.Expects(1, []error{errors.New("is running")}, nil),
+		},
+		{
+			Description: "pod rm removes the containers of the pod",
+			NoParallel:  true,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("pod", "rm", "-f", data.Labels().Get("pod"))
+			},
+			Command: test.Command("ps", "-a", "--format", "{{.Names}}"),
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.DoesNotContain(data.Labels().Get("pod")),
+				}
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func splitLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,97 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/registry"
+)
+
+func serveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve [flags]",
+		Short: "Serve the images of the namespace over the OCI Distribution API",
+		Long: `Serve the images and the content of the namespace (--namespace) over the OCI Distribution API,
+so that other hosts can pull them, e.g., "nerdctl pull HOST:5000/library/alpine:latest" for "docker.io/library/alpine:latest".
+The registry is read-only unless --allow-push is specified.`,
+		Args:          cobra.NoArgs,
+		RunE:          serveAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().String("listen", "localhost:5000", "Address to listen on")
+	cmd.Flags().Bool("allow-push", false, "Accept pushes of images")
+	cmd.Flags().String("htpasswd", "", "Require basic auth with the users of an htpasswd file (bcrypt hashes only)")
+	cmd.Flags().String("tls-cert", "", "Path of the TLS certificate")
+	cmd.Flags().String("tls-key", "", "Path of the TLS private key")
+	return cmd
+}
+
+func serveOptions(cmd *cobra.Command) (types.RegistryServeOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.RegistryServeOptions{}, err
+	}
+	listen, err := cmd.Flags().GetString("listen")
+	if err != nil {
+		return types.RegistryServeOptions{}, err
+	}
+	allowPush, err := cmd.Flags().GetBool("allow-push")
+	if err != nil {
+		return types.RegistryServeOptions{}, err
+	}
+	htpasswd, err := cmd.Flags().GetString("htpasswd")
+	if err != nil {
+		return types.RegistryServeOptions{}, err
+	}
+	tlsCert, err := cmd.Flags().GetString("tls-cert")
+	if err != nil {
+		return types.RegistryServeOptions{}, err
+	}
+	tlsKey, err := cmd.Flags().GetString("tls-key")
+	if err != nil {
+		return types.RegistryServeOptions{}, err
+	}
+	return types.RegistryServeOptions{
+		Stdout:    cmd.OutOrStdout(),
+		GOptions:  globalOptions,
+		Listen:    listen,
+		AllowPush: allowPush,
+		Htpasswd:  htpasswd,
+		TLSCert:   tlsCert,
+		TLSKey:    tlsKey,
+	}, nil
+}
+
+func serveAction(cmd *cobra.Command, args []string) error {
+	options, err := serveOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return registry.Serve(ctx, client, options)
+}
@@ -0,0 +1,101 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestRegistryServe(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// FIXME: this is bad and likely to collide with other tests
+	const listenAddr = "localhost:5556"
+
+	var server test.TestableCommand
+
+	// `registry serve` is not supported by Docker
+	testCase.Require = require.Not(nerdtest.Docker)
+	testCase.NoParallel = true
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("pull", "--quiet", testutil.CommonImage)
+
+		server = helpers.Command("registry", "serve", "--listen", listenAddr, "--allow-push")
+		server.WithTimeout(60 * time.Second)
+		server.Background()
+		// Let it start
+		time.Sleep(time.Second)
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		if server != nil {
+			server.Signal(os.Kill)
+		}
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "pull a local image",
+			NoParallel:  true,
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rmi", "-f", listenAddr+"/"+testutil.CommonImage)
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				helpers.Ensure("pull", "--quiet", "--insecure-registry", listenAddr+"/"+testutil.CommonImage)
+				return helpers.Command("run", "--rm", listenAddr+"/"+testutil.CommonImage, "echo", "hello")
+			},
+			Expected: test.Expects(0, nil, expect.Equals("hello\n")),
+		},
+		{
+			Description: "unknown manifest",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("pull", "--quiet", "--insecure-registry", listenAddr+"/nerdctl/does-not-exist:latest")
+			},
+			Expected: test.Expects(1, nil, nil),
+		},
+		{
+			Description: "push an image",
+			NoParallel:  true,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("tag", testutil.CommonImage, listenAddr+"/nerdctl/"+data.Identifier()+":pushed")
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rmi", "-f", listenAddr+"/nerdctl/"+data.Identifier()+":pushed")
+				helpers.Anyhow("rmi", "-f", "nerdctl/"+data.Identifier()+":pushed")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				helpers.Ensure("push", "--insecure-registry", listenAddr+"/nerdctl/"+data.Identifier()+":pushed")
+				// the image is stored under the repository and the tag of the request
+				return helpers.Command("image", "inspect", "nerdctl/"+data.Identifier()+":pushed")
+			},
+			Expected: test.Expects(0, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
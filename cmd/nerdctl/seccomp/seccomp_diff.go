@@ -0,0 +1,44 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package seccomp
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/seccomp"
+)
+
+func diffCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff PROFILE1 PROFILE2",
+		Short: "Compare the syscalls allowed by two seccomp profiles",
+		Long: `Compare the syscalls allowed by two seccomp profiles, e.g., recorded with "--security-opt seccomp=record:PATH".
+The syscalls only allowed by PROFILE1 are prefixed with "-", and the ones only allowed by PROFILE2 with "+".`,
+		Args:          cobra.ExactArgs(2),
+		RunE:          diffAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	return cmd
+}
+
+func diffAction(cmd *cobra.Command, args []string) error {
+	return seccomp.Diff(args[0], args[1], types.SeccompDiffOptions{
+		Stdout: cmd.OutOrStdout(),
+	})
+}
@@ -0,0 +1,95 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package seccomp
+
+import (
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestSeccompDiff(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// `seccomp diff` is not supported by Docker
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		data.Temp().Save(`{"defaultAction":"SCMP_ACT_ERRNO","syscalls":[{"names":["read","write"],"action":"SCMP_ACT_ALLOW"}]}`, "old.json")
+		data.Temp().Save(`{"defaultAction":"SCMP_ACT_ERRNO","syscalls":[{"names":["read","openat"],"action":"SCMP_ACT_ALLOW"}]}`, "new.json")
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("seccomp", "diff", data.Temp().Path("old.json"), data.Temp().Path("new.json"))
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			Output: expect.Equals("--- " + data.Temp().Path("old.json") + "\n+++ " + data.Temp().Path("new.json") + "\n-write\n+openat\n"),
+		}
+	}
+
+	testCase.Run(t)
+}
+
+func TestSeccompRecord(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// recording is not supported by Docker, and requires the audit records of the host
+	testCase.Require = require.All(
+		require.Not(nerdtest.Docker),
+		require.Not(nerdtest.Rootless),
+	)
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		helpers.Ensure("run", "--rm", "--security-opt", "seccomp=record:"+data.Temp().Path("recorded.json"),
+			testutil.CommonImage, "true")
+		return helpers.Command("seccomp", "diff", data.Temp().Path("recorded.json"), data.Temp().Path("recorded.json"))
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		// the profile is written on the removal of the container
+		return &test.Expected{
+			Output: expect.Equals("--- " + data.Temp().Path("recorded.json") + "\n+++ " + data.Temp().Path("recorded.json") + "\n"),
+		}
+	}
+
+	testCase.Run(t)
+}
+
+func TestSeccompRecordRootless(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.All(
+		require.Not(nerdtest.Docker),
+		nerdtest.Rootless,
+	)
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("run", "--rm", "--security-opt", "seccomp=record:"+data.Temp().Path("recorded.json"),
+			testutil.CommonImage, "true")
+	}
+
+	testCase.Expected = test.Expects(1, nil, nil)
+
+	testCase.Run(t)
+}
@@ -0,0 +1,77 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestSystemDf(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("volume", "create", data.Identifier())
+		helpers.Ensure("run", "-d", "--name", data.Identifier(), "-v", data.Identifier()+":/data",
+			testutil.CommonImage, "sleep", nerdtest.Infinity)
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+		helpers.Anyhow("volume", "rm", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "summary",
+			Command:     test.Command("system", "df"),
+			Expected: test.Expects(0, nil, expect.Contains(
+				"TYPE", "RECLAIMABLE", "Images", "Containers", "Local Volumes", "Build Cache",
+			)),
+		},
+		{
+			Description: "json",
+			Command:     test.Command("system", "df", "--format", "json"),
+			Expected: test.Expects(0, nil, expect.Contains(
+				`"Type":"Images"`, `"Type":"Containers"`, `"Type":"Local Volumes"`, `"Type":"Build Cache"`,
+			)),
+		},
+		{
+			Description: "verbose",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("system", "df", "-v")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Contains(
+						"Images space usage:", "SHARED SIZE", "UNIQUE SIZE",
+						"Containers space usage:", data.Identifier(),
+						"Local Volumes space usage:",
+						"Build cache usage:",
+					),
+				}
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
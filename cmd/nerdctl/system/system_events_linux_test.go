@@ -178,3 +178,69 @@ func TestEventFilters(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+func TestEventsSinceUntil(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("create", "--name", data.Identifier(), testutil.CommonImage)
+		helpers.Ensure("rm", data.Identifier())
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		// --until in the past only replays the past events, and returns
+		return helpers.Command("events", "--since", "5m", "--until", "0s",
+			"--filter", "container="+data.Identifier(), "--format", "json")
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			Output: expect.All(
+				expect.Contains("\"Action\":\"create\""),
+				expect.Contains("\"Action\":\"destroy\""),
+				expect.DoesNotContain("\"Action\":\"start\""),
+			),
+		}
+	}
+
+	testCase.Run(t)
+}
+
+func TestEventsLiveJournal(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// The volume and network events are only recorded in the event journal of nerdctl
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier())
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		cmd := helpers.Command("events", "--filter", "type=volume", "--format", "json")
+		cmd.WithTimeout(10 * time.Second)
+		cmd.Background()
+		// Leave time to the command to subscribe
+		time.Sleep(2 * time.Second)
+		helpers.Ensure("volume", "create", data.Identifier())
+		helpers.Ensure("volume", "rm", data.Identifier())
+		return cmd
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			ExitCode: expect.ExitCodeTimeout,
+			Output: expect.All(
+				expect.Contains("\"Action\":\"create\""),
+				expect.Contains("\"Action\":\"destroy\""),
+				expect.Contains(data.Identifier()),
+			),
+		}
+	}
+
+	testCase.Run(t)
+}
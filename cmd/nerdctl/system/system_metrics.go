@@ -0,0 +1,80 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/system"
+)
+
+func metricsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "metrics",
+		Short:         "Expose the container metrics",
+		RunE:          helpers.UnknownSubcommandAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.AddCommand(metricsServeCommand())
+	return cmd
+}
+
+func metricsServeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "serve",
+		Short:         "Serve the statistics of the running containers of all the namespaces in the OpenMetrics format",
+		Args:          cobra.NoArgs,
+		RunE:          metricsServeAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().String("listen", "127.0.0.1:9323", "Address to serve the metrics on")
+	return cmd
+}
+
+func metricsServeOptions(cmd *cobra.Command) (types.SystemMetricsServeOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.SystemMetricsServeOptions{}, err
+	}
+	listen, err := cmd.Flags().GetString("listen")
+	if err != nil {
+		return types.SystemMetricsServeOptions{}, err
+	}
+	return types.SystemMetricsServeOptions{
+		Stdout:   cmd.OutOrStdout(),
+		GOptions: globalOptions,
+		Listen:   listen,
+	}, nil
+}
+
+func metricsServeAction(cmd *cobra.Command, _ []string) error {
+	options, err := metricsServeOptions(cmd)
+	if err != nil {
+		return err
+	}
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return system.MetricsServe(ctx, client, options)
+}
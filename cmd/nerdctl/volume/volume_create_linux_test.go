@@ -0,0 +1,134 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volume
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestVolumeCreateTmpfs(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = nerdtest.Rootful
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("volume", "create", "--opt", "type=tmpfs", "--opt", "device=tmpfs", "--opt", "o=size=1m", data.Identifier())
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier())
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("run", "--name", data.Identifier(), "-v", data.Identifier()+":/mnt", testutil.CommonImage, "grep", " /mnt ", "/proc/mounts")
+	}
+
+	testCase.Expected = test.Expects(expect.ExitCodeSuccess, nil, expect.Contains("tmpfs"))
+
+	testCase.Run(t)
+}
+
+func TestVolumeCreateTmpfsAutomaticRestart(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = nerdtest.Rootful
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("volume", "create", "--opt", "type=tmpfs", "--opt", "device=tmpfs", "--opt", "o=size=1m", data.Identifier())
+		// The task is restarted by the containerd restart monitor, without going through `nerdctl start`,
+		// so the volume must stay mounted across restarts.
+		helpers.Ensure("run", "-d", "--restart=always", "--name", data.Identifier(), "-v", data.Identifier()+":/mnt", testutil.CommonImage,
+			"sh", "-c", "grep ' /mnt ' /proc/mounts || echo NOT-MOUNTED; sleep 1")
+		for range 60 {
+			if strings.Count(helpers.Capture("logs", data.Identifier()), "\n") >= 2 {
+				break
+			}
+			time.Sleep(time.Second)
+		}
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier())
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("logs", data.Identifier())
+	}
+
+	testCase.Expected = test.Expects(expect.ExitCodeSuccess, nil, expect.All(
+		expect.Contains("tmpfs"),
+		expect.DoesNotContain("NOT-MOUNTED"),
+	))
+
+	testCase.Run(t)
+}
+
+func TestVolumeCreateTmpfsOnFailureExitSuccess(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = nerdtest.Rootful
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("volume", "create", "--opt", "type=tmpfs", "--opt", "device=tmpfs", "--opt", "o=size=1m", data.Identifier())
+		data.Labels().Set("mountpoint", strings.TrimSpace(helpers.Capture("volume", "inspect", "--format", "{{.Mountpoint}}", data.Identifier())))
+		// The container is not restarted, as it does not fail
+		helpers.Ensure("run", "-d", "--restart=on-failure", "--name", data.Identifier(), "-v", data.Identifier()+":/mnt", testutil.CommonImage, "true")
+		for range 60 {
+			if strings.TrimSpace(helpers.Capture("inspect", "--format", "{{.State.Status}}", data.Identifier())) == "exited" {
+				break
+			}
+			time.Sleep(time.Second)
+		}
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier())
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("rm", data.Identifier())
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			Output: func(stdout string, t tig.T) {
+				mountinfo, err := os.ReadFile("/proc/self/mountinfo")
+				if err != nil {
+					t.Log(err.Error())
+					t.FailNow()
+				}
+				// The volume is unmounted once its only container is removed
+				expect.DoesNotContain(" "+data.Labels().Get("mountpoint")+" ")(string(mountinfo), t)
+			},
+		}
+	}
+
+	testCase.Run(t)
+}
@@ -70,6 +70,7 @@ require (
 	golang.org/x/sys v0.47.0 //gomodjail:unconfined
 	golang.org/x/term v0.45.0 //gomodjail:unconfined
 	golang.org/x/text v0.41.0
+	google.golang.org/protobuf v1.36.12-0.20260120151049-f2248ac996af //gomodjail:unconfined
 	gotest.tools/v3 v3.5.2
 	tags.cncf.io/container-device-interface v1.1.1-0.20260720132747-49ac08dcf160 //gomodjail:unconfined
 )
@@ -135,8 +136,6 @@ require (
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20260630182238-925bb5da69e7 // indirect
 	//gomodjail:unconfined
 	google.golang.org/grpc v1.82.1 // indirect
-	//gomodjail:unconfined
-	google.golang.org/protobuf v1.36.12-0.20260120151049-f2248ac996af // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 	lukechampine.com/blake3 v1.3.0 // indirect
 	sigs.k8s.io/yaml v1.6.0 // indirect
@@ -31,3 +31,14 @@ type ApparmorListOptions struct {
 type ApparmorInspectOptions struct {
 	Stdout io.Writer
 }
+
+// ApparmorGenerateOptions specifies options for `nerdctl apparmor generate`
+type ApparmorGenerateOptions struct {
+	Stdout io.Writer
+	// Name of the profile, overriding the name in the rules
+	Name string
+	// Template is the path of the profile template. Defaults to the template of the default profile.
+	Template string
+	// DryRun prints the generated profile instead of loading it
+	DryRun bool
+}
@@ -26,6 +26,8 @@ type CheckpointCreateOptions struct {
 	LeaveRunning bool
 	// Checkpoint directory
 	CheckpointDir string
+	// Export is the path of a portable checkpoint archive to write, instead of the checkpoint directory
+	Export string
 }
 
 type CheckpointListOptions struct {
@@ -30,6 +30,8 @@ type NetworkOptions struct {
 	IPAddress string
 	// IP6Address set specific static IP6 address(es) to use
 	IP6Address string
+	// NetworkAliases are the network-scoped aliases of the container, keyed by the networks of NetworkSlice
+	NetworkAliases map[string][]string
 	// Hostname set container host name
 	Hostname string
 	// Domainname specifies the container's domain name
@@ -36,6 +36,23 @@ type ContainerStartOptions struct {
 	Checkpoint string
 	// CheckpointDir is the directory to store checkpoints
 	CheckpointDir string
+	// PidFile is the file to write the pid of the started container to
+	PidFile string
+	// NerdctlCmd is the command name of nerdctl
+	NerdctlCmd string
+	// NerdctlArgs is the arguments of nerdctl
+	NerdctlArgs []string
+}
+
+// ContainerRestoreOptions specifies options for `nerdctl container restore`.
+type ContainerRestoreOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Name is the name of the restored container, the name of the checkpointed container by default
+	Name string
+	// ImagePullOpt specifies the options to pull the image of the container when it is missing
+	ImagePullOpt ImagePullOptions
 	// NerdctlCmd is the command name of nerdctl
 	NerdctlCmd string
 	// NerdctlArgs is the arguments of nerdctl
@@ -95,6 +112,10 @@ type ContainerCreateOptions struct {
 	Pull string
 	// Pid namespace to use
 	Pid string
+	// Pod to join. The container shares the network, IPC and UTS namespaces of the pod infra container.
+	Pod string
+	// PodInfra marks the container as the infra container of Pod (set by `nerdctl pod create`)
+	PodInfra bool
 	// StopSignal signal to stop a container, default is SIGTERM
 	StopSignal string
 	// StopTimeout specifies the timeout (in seconds) to stop a container
@@ -265,6 +286,8 @@ type ContainerCreateOptions struct {
 	CidFile string
 	// PidFile specifies the file path to write the task's pid. The CLI syntax conforms to Podman convention.
 	PidFile string
+	// SdNotify is the mode used to notify systemd that the container is ready ("conmon"|"container"|"healthy")
+	SdNotify string
 	// #endregion
 
 	// #region for logging flags
@@ -300,12 +323,14 @@ type ContainerCreateOptions struct {
 	ImagePullOpt ImagePullOptions
 
 	// Healthcheck related fields
-	HealthCmd         string
-	HealthInterval    time.Duration
-	HealthTimeout     time.Duration
-	HealthRetries     int
-	HealthStartPeriod time.Duration
-	NoHealthcheck     bool
+	HealthCmd           string
+	HealthInterval      time.Duration
+	HealthTimeout       time.Duration
+	HealthRetries       int
+	HealthStartPeriod   time.Duration
+	HealthStartInterval time.Duration
+	HealthOnFailure     string
+	NoHealthcheck       bool
 
 	// UserNS name for user namespace mapping of container
 	UserNS string
@@ -325,6 +350,18 @@ type ContainerStopOptions struct {
 	Signal string
 }
 
+// ContainerHealthCheckOptions specifies options for `nerdctl container healthcheck`.
+type ContainerHealthCheckOptions struct {
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Timer indicates that the health check is run by the health check timer, which skips the checks that are not due
+	Timer bool
+	// NerdctlCmd is the command name of nerdctl
+	NerdctlCmd string
+	// NerdctlArgs is the arguments of nerdctl
+	NerdctlArgs []string
+}
+
 // ContainerRestartOptions specifies options for `nerdctl (container) restart`.
 type ContainerRestartOptions struct {
 	Stdout  io.Writer
@@ -469,6 +506,16 @@ type ContainerLogsOptions struct {
 	Until string
 	// Details specifies whether to show extra details provided to logs
 	Details bool
+	// Filters selects the containers to show the logs of, in addition to the ones given as arguments
+	Filters []string
+	// Format specifies the output format of the logs. Either empty (text) or "json".
+	Format string
+	// Grep only shows the lines of the logs matching the regular expression
+	Grep string
+	// NoColor disables the colors of the container name prefixes
+	NoColor bool
+	// NoLogPrefix disables the container name prefixes when showing the logs of multiple containers
+	NoLogPrefix bool
 }
 
 // ContainerWaitOptions specifies options for `nerdctl (container) wait`.
@@ -547,6 +594,29 @@ type ContainerCpOptions struct {
 	ToStdout bool
 }
 
+// ContainerPortAddOptions specifies options for `nerdctl port add`.
+type ContainerPortAddOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Container is the container to publish the ports of
+	Container string
+	// Ports are the port mappings to publish, in the format of `--publish`
+	Ports []string
+}
+
+// ContainerPortRemoveOptions specifies options for `nerdctl port rm`.
+type ContainerPortRemoveOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Container is the container to unpublish the ports of
+	Container string
+	// Ports are the port mappings to unpublish, in the format of `--publish`.
+	// The host IP and the host port can be omitted to match any.
+	Ports []string
+}
+
 // ContainerStatsOptions specifies options for `nerdctl stats`.
 type ContainerStatsOptions struct {
 	Stdout io.Writer
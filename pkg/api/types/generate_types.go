@@ -0,0 +1,37 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import "io"
+
+// GenerateSystemdOptions specifies options for `nerdctl generate systemd`.
+type GenerateSystemdOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// ComposeProject generates the units of the containers of the compose project
+	ComposeProject string
+	// RestartPolicy is the systemd restart policy of the units
+	RestartPolicy string
+	// StopTimeout is the timeout (in seconds) to stop the containers
+	StopTimeout uint
+	// Files writes the units to files in the current directory, instead of printing them
+	Files bool
+	// NerdctlCmd is the command name of nerdctl
+	NerdctlCmd string
+	// NerdctlArgs is the arguments of nerdctl
+	NerdctlArgs []string
+}
@@ -208,6 +208,14 @@ type ImagePushOptions struct {
 
 	// Estargz convert image to sStargz
 	Estargz bool
+	// Compression recompresses the layers with gzip, zstd, zstd:chunked or estargz before pushing
+	Compression string
+	// CompressionLevel is the level used for Compression (negative for the default)
+	CompressionLevel int
+	// ForceCompression recompresses layers even when they already use Compression
+	ForceCompression bool
+	// OCILayout is an "oci-layout://PATH[:TAG]" reference to write the image to, instead of pushing it to a registry
+	OCILayout string
 	// IpfsEnsureImage ensure image is pushed to IPFS
 	IpfsEnsureImage bool
 	// IpfsAddress multiaddr of IPFS API (default uses $IPFS_PATH env variable if defined or local directory ~/.ipfs)
@@ -292,6 +300,10 @@ type ImageSaveOptions struct {
 	Platform []string
 	// Quiet suppresses the progress output.
 	Quiet bool
+	// Format is either "tar" (default) or "oci-dir"
+	Format string
+	// OutputDir is the OCI image layout directory written for the "oci-dir" format
+	OutputDir string
 }
 
 // ImageSignOptions contains options for signing an image. It contains options from
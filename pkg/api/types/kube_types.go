@@ -0,0 +1,46 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import "io"
+
+// KubePlayOptions specifies options for `nerdctl kube play`.
+type KubePlayOptions struct {
+	Stdout   io.Writer
+	Stderr   io.Writer
+	GOptions GlobalCommandOptions
+	// InfraImage is the image of the infra containers of the pods
+	InfraImage string
+	// NerdctlCmd is the command name of nerdctl
+	NerdctlCmd string
+	// NerdctlArgs is the arguments of nerdctl
+	NerdctlArgs []string
+}
+
+// KubeDownOptions specifies options for `nerdctl kube down`.
+type KubeDownOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+}
+
+// KubeGenerateOptions specifies options for `nerdctl kube generate`.
+type KubeGenerateOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Name is the name of the generated pod
+	Name string
+}
@@ -80,6 +80,32 @@ type NetworkPruneOptions struct {
 	NetworkDriversToKeep []string
 }
 
+// NetworkConnectOptions specifies options for `nerdctl network connect`.
+type NetworkConnectOptions struct {
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Network is the network to connect the container to
+	Network string
+	// Container is the container to connect
+	Container string
+	// IPAddress is the IPv4 address of the container on the network
+	IPAddress string
+	// IP6Address is the IPv6 address of the container on the network
+	IP6Address string
+	// Aliases are the network-scoped aliases of the container
+	Aliases []string
+}
+
+// NetworkDisconnectOptions specifies options for `nerdctl network disconnect`.
+type NetworkDisconnectOptions struct {
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Network is the network to disconnect the container from
+	Network string
+	// Container is the container to disconnect
+	Container string
+}
+
 // NetworkRemoveOptions specifies options for `nerdctl network rm`.
 type NetworkRemoveOptions struct {
 	Stdout io.Writer
@@ -0,0 +1,93 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"io"
+	"time"
+)
+
+// PodCreateOptions specifies options for `nerdctl pod create`.
+type PodCreateOptions struct {
+	Stdout   io.Writer
+	Stderr   io.Writer
+	GOptions GlobalCommandOptions
+	// InfraImage is the image of the infra container
+	InfraImage string
+	// Hostname is the host name of the pod, defaults to the pod name
+	Hostname string
+	// Publish publishes the ports of the pod
+	Publish []string
+	// Labels are the labels of the infra container
+	Labels []string
+	// Networks are the networks the pod is connected to
+	Networks []string
+	// AddHost adds custom host-to-IP mappings to the pod
+	AddHost []string
+	// DNSServers are the custom DNS servers of the pod
+	DNSServers []string
+	// NerdctlCmd is the command name of nerdctl
+	NerdctlCmd string
+	// NerdctlArgs is the arguments of nerdctl
+	NerdctlArgs []string
+}
+
+// PodStartOptions specifies options for `nerdctl pod start`.
+type PodStartOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// NerdctlCmd is the command name of nerdctl
+	NerdctlCmd string
+	// NerdctlArgs is the arguments of nerdctl
+	NerdctlArgs []string
+}
+
+// PodStopOptions specifies options for `nerdctl pod stop`.
+type PodStopOptions struct {
+	Stdout   io.Writer
+	Stderr   io.Writer
+	GOptions GlobalCommandOptions
+	// Timeout specifies how long to wait after sending a SIGTERM and before sending a SIGKILL.
+	// If it's nil, the default is 10 seconds.
+	Timeout *time.Duration
+}
+
+// PodRemoveOptions specifies options for `nerdctl pod rm`.
+type PodRemoveOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Force removes running pods
+	Force bool
+}
+
+// PodListOptions specifies options for `nerdctl pod ps`.
+type PodListOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Quiet only displays pod IDs
+	Quiet bool
+	// Format the output using the given go template
+	Format string
+}
+
+// PodInspectOptions specifies options for `nerdctl pod inspect`.
+type PodInspectOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Format the output using the given go template
+	Format string
+}
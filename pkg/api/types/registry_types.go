@@ -0,0 +1,36 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import "io"
+
+// RegistryServeOptions specifies options for `nerdctl registry serve`.
+type RegistryServeOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Listen is the address to serve the registry on, e.g., "localhost:5000"
+	Listen string
+	// AllowPush accepts pushes of blobs and manifests
+	AllowPush bool
+	// Htpasswd is the path of an htpasswd file (bcrypt) enabling basic auth
+	Htpasswd string
+	// TLSCert is the path of the TLS certificate
+	TLSCert string
+	// TLSKey is the path of the TLS private key
+	TLSKey string
+}
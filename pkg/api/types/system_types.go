@@ -39,6 +39,32 @@ type SystemEventsOptions struct {
 	Format string
 	// Filter events based on given conditions
 	Filters []string
+	// Since replays the events of the event journal created since the given timestamp
+	Since string
+	// Until streams the events until the given timestamp
+	Until string
+}
+
+// SystemDfOptions specifies options for `nerdctl system df`.
+type SystemDfOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Verbose shows the disk usage of each object
+	Verbose bool
+	// Format the output using the given Go template, e.g, '{{json .}}'
+	Format string
+	// BuildKitHost the address of BuildKit host
+	BuildKitHost string
+}
+
+// SystemMetricsServeOptions specifies options for `nerdctl system metrics serve`.
+type SystemMetricsServeOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Listen is the address to serve the metrics on, e.g., "127.0.0.1:9323"
+	Listen string
 }
 
 // SystemPruneOptions specifies options for `nerdctl system prune`.
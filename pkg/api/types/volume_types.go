@@ -24,6 +24,10 @@ type VolumeCreateOptions struct {
 	GOptions GlobalCommandOptions
 	// Labels are the volume labels
 	Labels []string
+	// Driver is the volume driver name
+	Driver string
+	// DriverOpts are the driver specific options
+	DriverOpts map[string]string
 }
 
 // VolumeInspectOptions specifies options for `nerdctl volume inspect`.
@@ -17,6 +17,7 @@
 package apparmorutil
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -154,6 +155,27 @@ func Profiles() ([]Profile, error) {
 	return res, nil
 }
 
+// Load loads (or replaces) a profile. Needs root.
+func Load(profile string) error {
+	f, err := os.CreateTemp(os.Getenv("XDG_RUNTIME_DIR"), "nerdctl-apparmor-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(profile); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	out, err := exec.Command("apparmor_parser", "-Kr", f.Name()).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to load apparmor profile (parser error %q): %w", strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
 // Unload unloads a profile. Needs access to /sys/kernel/security/apparmor/.remove .
 func Unload(target string) error {
 	remover, err := os.OpenFile("/sys/kernel/security/apparmor/.remove", os.O_RDWR|os.O_TRUNC, 0644)
@@ -0,0 +1,203 @@
+//go:build linux
+
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apparmorutil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/containerd/containerd/v2/contrib/apparmor"
+	"github.com/containerd/containerd/v2/pkg/cap"
+)
+
+// GeneratePrefix is the prefix of `--security-opt apparmor=generate:<RULES>`.
+const GeneratePrefix = "generate:"
+
+// GeneratedProfilePrefix is the prefix of the names of the generated profiles.
+// The profiles named by the rules must have this prefix too, so that they cannot
+// replace the default profile nor the profiles of the host.
+const GeneratedProfilePrefix = "nerdctl-generated-"
+
+// Rules are the per-container rules added to a profile template.
+//
+// e.g.,
+//
+//	name: nerdctl-generated-web
+//	denyWrite:
+//	  - /etc/**
+//	denyNetwork:
+//	  - raw
+//	  - inet6
+//	denyCapabilities:
+//	  - NET_RAW
+type Rules struct {
+	// Name is the name of the profile, which must start with GeneratedProfilePrefix.
+	// Defaults to GeneratedProfilePrefix + the digest of the profile.
+	Name string `yaml:"name,omitempty"`
+	// DenyWrite is the list of the paths (AppArmor globs) that cannot be written.
+	DenyWrite []string `yaml:"denyWrite,omitempty"`
+	// DenyNetwork is the list of the network rules (e.g., "inet6", "raw", "inet stream") to deny.
+	DenyNetwork []string `yaml:"denyNetwork,omitempty"`
+	// DenyCapabilities is the list of the capabilities (e.g., "NET_RAW", "CAP_SYS_ADMIN") to deny.
+	DenyCapabilities []string `yaml:"denyCapabilities,omitempty"`
+}
+
+// TemplateData is the data passed to the profile template.
+type TemplateData struct {
+	// Name is the name of the profile.
+	Name string
+	// Rules are the AppArmor rules generated from Rules, without the trailing commas.
+	Rules []string
+}
+
+// LoadRules loads Rules from a YAML file.
+func LoadRules(path string) (*Rules, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules Rules
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("failed to parse AppArmor rules %q: %w", path, err)
+	}
+	return &rules, nil
+}
+
+// DefaultTemplate returns the template of the default profile, with the rules
+// appended to the end of the profile.
+func DefaultTemplate() (string, error) {
+	const namePlaceholder = "__NERDCTL_PROFILE_NAME__"
+	profile, err := apparmor.DumpDefaultProfile(namePlaceholder)
+	if err != nil {
+		return "", err
+	}
+	end := strings.LastIndex(profile, "}")
+	if end < 0 {
+		return "", errors.New("unexpected default AppArmor profile: missing the closing brace")
+	}
+	profile = profile[:end] + "{{range .Rules}}  {{.}},\n{{end}}" + profile[end:]
+	return strings.ReplaceAll(profile, namePlaceholder, "{{.Name}}"), nil
+}
+
+// Generate generates a profile from a template (see TemplateData) and rules.
+// The name of the profile is returned along with the profile.
+func Generate(tmpl string, rules *Rules) (name, profile string, err error) {
+	t, err := template.New("apparmor_profile").Parse(tmpl)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse the AppArmor profile template: %w", err)
+	}
+	lines, err := rules.lines()
+	if err != nil {
+		return "", "", err
+	}
+	name = rules.Name
+	if name == "" {
+		// Name the profile after its content, so that the same rules reuse the same profile
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, TemplateData{Rules: lines}); err != nil {
+			return "", "", err
+		}
+		digest := sha256.Sum256(buf.Bytes())
+		name = GeneratedProfilePrefix + hex.EncodeToString(digest[:])[:12]
+	} else if err := validateProfileName(name); err != nil {
+		return "", "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, TemplateData{Name: name, Rules: lines}); err != nil {
+		return "", "", err
+	}
+	return name, buf.String(), nil
+}
+
+var profileNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+func validateProfileName(name string) error {
+	if !profileNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid AppArmor profile name %q: must match %s", name, profileNameRegexp)
+	}
+	if !strings.HasPrefix(name, GeneratedProfilePrefix) || name == GeneratedProfilePrefix {
+		return fmt.Errorf("invalid AppArmor profile name %q: must start with %q", name, GeneratedProfilePrefix)
+	}
+	return nil
+}
+
+var (
+	// networkDomains and networkTypes are the keywords of the AppArmor network rules.
+	// See apparmor.d(5).
+	networkDomains = []string{
+		"unix", "inet", "ax25", "ipx", "appletalk", "netrom", "bridge", "atmpvc", "x25", "inet6",
+		"rose", "netbeui", "security", "key", "netlink", "packet", "ash", "econet", "atmsvc", "rds",
+		"sna", "irda", "pppox", "wanpipe", "llc", "ib", "mpls", "can", "tipc", "bluetooth", "iucv",
+		"rxrpc", "isdn", "phonet", "ieee802154", "caif", "alg", "nfc", "vsock", "kcm", "qipcrtr",
+		"smc", "xdp", "mctp",
+	}
+	networkTypes = []string{"stream", "dgram", "seqpacket", "rdm", "raw", "packet"}
+)
+
+func (r *Rules) lines() ([]string, error) {
+	var lines []string
+	for _, p := range r.DenyWrite {
+		if !strings.HasPrefix(p, "/") && !strings.HasPrefix(p, "@{") {
+			return nil, fmt.Errorf("invalid denyWrite path %q: must be absolute", p)
+		}
+		if strings.ContainsAny(p, " \t\r\n,\"#") {
+			return nil, fmt.Errorf("invalid denyWrite path %q: must not contain spaces, commas, quotes, nor '#'", p)
+		}
+		// "w" implies "a" (append)
+		lines = append(lines, "deny "+p+" w")
+	}
+	for _, n := range r.DenyNetwork {
+		fields := strings.Fields(strings.ToLower(n))
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, fmt.Errorf("invalid denyNetwork rule %q: must be \"DOMAIN\", \"TYPE\", or \"DOMAIN TYPE\"", n)
+		}
+		for i, f := range fields {
+			// "packet" is both a domain and a type
+			isDomain := i == 0 && slices.Contains(networkDomains, f)
+			isType := slices.Contains(networkTypes, f) && (i == 1 || len(fields) == 1)
+			if !isDomain && !isType {
+				return nil, fmt.Errorf("invalid denyNetwork rule %q: unknown network domain or type %q", n, f)
+			}
+		}
+		lines = append(lines, "deny network "+strings.Join(fields, " "))
+	}
+	for _, c := range r.DenyCapabilities {
+		name := strings.ToUpper(c)
+		if !strings.HasPrefix(name, "CAP_") {
+			name = "CAP_" + name
+		}
+		if !slices.Contains(cap.Known(), name) {
+			return nil, fmt.Errorf("invalid denyCapabilities entry %q: unknown capability", c)
+		}
+		lines = append(lines, "deny capability "+strings.ToLower(strings.TrimPrefix(name, "CAP_")))
+	}
+	return lines, nil
+}
@@ -0,0 +1,83 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apparmorutil
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+const testTemplate = `profile {{.Name}} {
+  file,
+{{range .Rules}}  {{.}},
+{{end}}}
+`
+
+func TestGenerate(t *testing.T) {
+	rules := &Rules{
+		Name:             "nerdctl-generated-test",
+		DenyWrite:        []string{"/etc/**"},
+		DenyNetwork:      []string{"raw", "inet6", "Inet Stream", "packet"},
+		DenyCapabilities: []string{"NET_RAW", "cap_sys_admin"},
+	}
+	name, profile, err := Generate(testTemplate, rules)
+	assert.NilError(t, err)
+	assert.Equal(t, name, "nerdctl-generated-test")
+	assert.Equal(t, profile, `profile nerdctl-generated-test {
+  file,
+  deny /etc/** w,
+  deny network raw,
+  deny network inet6,
+  deny network inet stream,
+  deny network packet,
+  deny capability net_raw,
+  deny capability sys_admin,
+}
+`)
+}
+
+func TestGenerateName(t *testing.T) {
+	name1, _, err := Generate(testTemplate, &Rules{DenyWrite: []string{"/etc/**"}})
+	assert.NilError(t, err)
+	assert.Assert(t, strings.HasPrefix(name1, GeneratedProfilePrefix))
+	name2, _, err := Generate(testTemplate, &Rules{DenyWrite: []string{"/etc/**"}})
+	assert.NilError(t, err)
+	assert.Equal(t, name1, name2)
+	name3, _, err := Generate(testTemplate, &Rules{DenyWrite: []string{"/usr/**"}})
+	assert.NilError(t, err)
+	assert.Assert(t, name1 != name3)
+}
+
+func TestGenerateInvalid(t *testing.T) {
+	for _, rules := range []*Rules{
+		{Name: "nerdctl-generated-foo bar"},
+		{Name: "nerdctl-default"},
+		{Name: "docker-default"},
+		{Name: GeneratedProfilePrefix},
+		{DenyWrite: []string{"etc/**"}},
+		{DenyWrite: []string{"/etc/** rw, /"}},
+		{DenyNetwork: []string{"inet tcp"}},
+		{DenyNetwork: []string{"stream inet"}},
+		{DenyNetwork: []string{"inet stream raw"}},
+		{DenyCapabilities: []string{"CAP_FOO"}},
+	} {
+		_, _, err := Generate(testTemplate, rules)
+		assert.Assert(t, err != nil, "rules %+v", rules)
+	}
+}
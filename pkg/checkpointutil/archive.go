@@ -0,0 +1,206 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package checkpointutil
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/containerd/containerd/v2/pkg/archive/compression"
+)
+
+// ArchiveVersion is the version of the format of the checkpoint archives.
+const ArchiveVersion = 1
+
+// Entries of a checkpoint archive.
+const (
+	// ArchiveMetadata is the Metadata of the container, in JSON
+	ArchiveMetadata = "checkpoint.json"
+	// ArchiveSpec is the OCI runtime spec of the container, in JSON
+	ArchiveSpec = "spec.json"
+	// ArchiveCheckpoint is the CRIU image of the container, as a tar
+	ArchiveCheckpoint = "checkpoint.tar"
+	// ArchiveRootfs is the diff of the rootfs of the container from its image, as an uncompressed layer
+	ArchiveRootfs = "rootfs.tar"
+	// ArchiveState is the directory of the files of the state directory of the container (resolv.conf, hosts, ...)
+	ArchiveState = "state"
+)
+
+// Metadata describes the container of a checkpoint archive.
+type Metadata struct {
+	Version int `json:"version"`
+	// ID is the ID of the container, which is kept on restore
+	ID string `json:"id"`
+	// Name is the name of the container
+	Name string `json:"name"`
+	// Checkpoint is the name of the checkpoint, if any
+	Checkpoint string `json:"checkpoint,omitempty"`
+	// Image is the name of the image of the container
+	Image string `json:"image"`
+	// Runtime is the runtime of the container
+	Runtime Runtime `json:"runtime"`
+	// Labels are the labels of the container, including the nerdctl labels (networks, ports, mounts, ...)
+	Labels map[string]string `json:"labels,omitempty"`
+	// DataStore is the data store of nerdctl on the host of the container, to relocate the paths of the spec
+	DataStore string `json:"dataStore"`
+	// Created is the time of the checkpoint
+	Created time.Time `json:"created"`
+}
+
+// Runtime is the runtime of a container, with its options marshaled as a typeurl.Any.
+type Runtime struct {
+	Name           string `json:"name"`
+	OptionsTypeURL string `json:"optionsTypeURL,omitempty"`
+	Options        []byte `json:"options,omitempty"`
+}
+
+// ArchiveCompression returns the compression of a checkpoint archive from the extension of its path:
+// zstd for ".zst" and ".zstd", gzip for ".gz" and ".tgz", none otherwise.
+func ArchiveCompression(p string) compression.Compression {
+	switch strings.ToLower(filepath.Ext(p)) {
+	case ".zst", ".zstd":
+		return compression.Zstd
+	case ".gz", ".tgz":
+		return compression.Gzip
+	default:
+		return compression.Uncompressed
+	}
+}
+
+// ArchiveWriter writes the entries of a checkpoint archive.
+type ArchiveWriter struct {
+	cw io.WriteCloser
+	tw *tar.Writer
+}
+
+// NewArchiveWriter returns a writer of a checkpoint archive to w.
+func NewArchiveWriter(w io.Writer, c compression.Compression) (*ArchiveWriter, error) {
+	cw, err := compression.CompressStream(w, c)
+	if err != nil {
+		return nil, err
+	}
+	return &ArchiveWriter{cw: cw, tw: tar.NewWriter(cw)}, nil
+}
+
+// WriteFile writes an entry of size bytes read from r.
+func (aw *ArchiveWriter) WriteFile(name string, size int64, r io.Reader) error {
+	if err := aw.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0o600,
+		ModTime:  time.Now(),
+	}); err != nil {
+		return err
+	}
+	if _, err := io.CopyN(aw.tw, r, size); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// WriteJSON writes an entry with v marshaled in JSON.
+func (aw *ArchiveWriter) WriteJSON(name string, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return aw.WriteFile(name, int64(len(b)), strings.NewReader(string(b)))
+}
+
+// Close flushes the archive. It does not close the underlying writer.
+func (aw *ArchiveWriter) Close() error {
+	if err := aw.tw.Close(); err != nil {
+		return err
+	}
+	return aw.cw.Close()
+}
+
+// ExtractArchive extracts a checkpoint archive, compressed or not, into dir and returns its metadata.
+func ExtractArchive(r io.Reader, dir string) (Metadata, error) {
+	dr, err := compression.DecompressStream(r)
+	if err != nil {
+		return Metadata{}, err
+	}
+	defer dr.Close()
+	if err := os.MkdirAll(filepath.Join(dir, ArchiveState), 0o700); err != nil {
+		return Metadata{}, err
+	}
+	tr := tar.NewReader(dr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Metadata{}, fmt.Errorf("failed to read the checkpoint archive: %w", err)
+		}
+		if !validArchiveEntry(hdr) {
+			return Metadata{}, fmt.Errorf("unexpected entry %q in the checkpoint archive", hdr.Name)
+		}
+		f, err := os.OpenFile(filepath.Join(dir, filepath.FromSlash(hdr.Name)), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err != nil {
+			return Metadata{}, err
+		}
+		_, err = io.Copy(f, tr)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return Metadata{}, err
+		}
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, ArchiveMetadata))
+	if err != nil {
+		return Metadata{}, fmt.Errorf("not a checkpoint archive: %w", err)
+	}
+	var meta Metadata
+	if err := json.Unmarshal(b, &meta); err != nil {
+		return Metadata{}, fmt.Errorf("failed to parse %s: %w", ArchiveMetadata, err)
+	}
+	if meta.Version != ArchiveVersion {
+		return Metadata{}, fmt.Errorf("unsupported checkpoint archive version %d", meta.Version)
+	}
+	for _, name := range []string{ArchiveSpec, ArchiveCheckpoint, ArchiveRootfs} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return Metadata{}, fmt.Errorf("the checkpoint archive has no %s", name)
+		}
+	}
+	return meta, nil
+}
+
+// validArchiveEntry returns whether hdr is a regular file among the entries of a checkpoint archive.
+func validArchiveEntry(hdr *tar.Header) bool {
+	if hdr.Typeflag != tar.TypeReg {
+		return false
+	}
+	switch hdr.Name {
+	case ArchiveMetadata, ArchiveSpec, ArchiveCheckpoint, ArchiveRootfs:
+		return true
+	}
+	dir, base := path.Split(hdr.Name)
+	return dir == ArchiveState+"/" && base != "" && base != "." && base != ".."
+}
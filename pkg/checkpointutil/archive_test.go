@@ -0,0 +1,102 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package checkpointutil
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/pkg/archive/compression"
+)
+
+func writeTestArchive(t *testing.T, c compression.Compression, meta Metadata, extra map[string]string) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	aw, err := NewArchiveWriter(&buf, c)
+	assert.NilError(t, err)
+	assert.NilError(t, aw.WriteJSON(ArchiveMetadata, meta))
+	for name, data := range map[string]string{
+		ArchiveSpec:       `{"ociVersion":"1.0.2"}`,
+		ArchiveCheckpoint: "criu",
+		ArchiveRootfs:     "rootfs",
+	} {
+		assert.NilError(t, aw.WriteFile(name, int64(len(data)), strings.NewReader(data)))
+	}
+	for name, data := range extra {
+		assert.NilError(t, aw.WriteFile(name, int64(len(data)), strings.NewReader(data)))
+	}
+	assert.NilError(t, aw.Close())
+	return &buf
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	for _, c := range []compression.Compression{compression.Uncompressed, compression.Gzip, compression.Zstd} {
+		meta := Metadata{
+			Version: ArchiveVersion,
+			ID:      "0123456789abcdef",
+			Name:    "web",
+			Image:   "docker.io/library/nginx:alpine",
+			Runtime: Runtime{Name: "io.containerd.runc.v2"},
+			Labels:  map[string]string{"nerdctl/name": "web"},
+		}
+		buf := writeTestArchive(t, c, meta, map[string]string{ArchiveState + "/resolv.conf": "nameserver 10.4.0.1\n"})
+
+		dir := t.TempDir()
+		extracted, err := ExtractArchive(buf, dir)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, extracted, meta)
+
+		b, err := os.ReadFile(filepath.Join(dir, ArchiveState, "resolv.conf"))
+		assert.NilError(t, err)
+		assert.Equal(t, string(b), "nameserver 10.4.0.1\n")
+		b, err = os.ReadFile(filepath.Join(dir, ArchiveCheckpoint))
+		assert.NilError(t, err)
+		assert.Equal(t, string(b), "criu")
+	}
+}
+
+func TestExtractArchiveRejects(t *testing.T) {
+	meta := Metadata{Version: ArchiveVersion, ID: "0123456789abcdef"}
+	for _, name := range []string{"../escape", ArchiveState + "/../../escape", ArchiveState + "/sub/file", "unknown.json"} {
+		buf := writeTestArchive(t, compression.Uncompressed, meta, map[string]string{name: "x"})
+		_, err := ExtractArchive(buf, t.TempDir())
+		assert.ErrorContains(t, err, "unexpected entry", name)
+	}
+
+	buf := writeTestArchive(t, compression.Uncompressed, Metadata{Version: ArchiveVersion + 1}, nil)
+	_, err := ExtractArchive(buf, t.TempDir())
+	assert.ErrorContains(t, err, "unsupported checkpoint archive version")
+
+	var noMeta bytes.Buffer
+	tw := tar.NewWriter(&noMeta)
+	assert.NilError(t, tw.Close())
+	_, err = ExtractArchive(&noMeta, t.TempDir())
+	assert.ErrorContains(t, err, "not a checkpoint archive")
+}
+
+func TestArchiveCompression(t *testing.T) {
+	assert.Equal(t, ArchiveCompression("web.tar.zst"), compression.Zstd)
+	assert.Equal(t, ArchiveCompression("web.tar.ZSTD"), compression.Zstd)
+	assert.Equal(t, ArchiveCompression("web.tgz"), compression.Gzip)
+	assert.Equal(t, ArchiveCompression("web.tar"), compression.Uncompressed)
+}
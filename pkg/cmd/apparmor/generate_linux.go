@@ -0,0 +1,69 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apparmor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/apparmorutil"
+)
+
+// Generate generates a profile from the rules file and loads it.
+func Generate(rulesPath string, options types.ApparmorGenerateOptions) error {
+	rules, err := apparmorutil.LoadRules(rulesPath)
+	if err != nil {
+		return err
+	}
+	if options.Name != "" {
+		rules.Name = options.Name
+	}
+	var tmpl string
+	if options.Template != "" {
+		b, err := os.ReadFile(options.Template)
+		if err != nil {
+			return err
+		}
+		tmpl = string(b)
+	} else {
+		tmpl, err = apparmorutil.DefaultTemplate()
+		if err != nil {
+			return err
+		}
+	}
+	name, profile, err := apparmorutil.Generate(tmpl, rules)
+	if err != nil {
+		return err
+	}
+	if options.DryRun {
+		_, err = fmt.Fprint(options.Stdout, profile)
+		return err
+	}
+	if !apparmorutil.CanLoadNewProfile() {
+		return errors.New("loading an AppArmor profile requires root and AppArmor to be enabled on the host (hint: use --dry-run to only print the profile)")
+	}
+	log.L.Infof("Loading profile %q", name)
+	if err := apparmorutil.Load(profile); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(options.Stdout, name)
+	return err
+}
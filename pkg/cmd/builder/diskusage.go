@@ -0,0 +1,83 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/buildkitutil"
+)
+
+// DiskUsage returns the build cache records of the BuildKit host.
+func DiskUsage(ctx context.Context, buildkitHost string) ([]buildkitutil.UsageInfo, error) {
+	buildctlBinary, err := buildkitutil.BuildctlBinary()
+	if err != nil {
+		return nil, err
+	}
+	buildctlArgs := buildkitutil.BuildctlBaseArgs(buildkitHost)
+	buildctlArgs = append(buildctlArgs, "du", "--format={{json .}}")
+	buildctlCmd := exec.Command(buildctlBinary, buildctlArgs...)
+	log.G(ctx).Debugf("running %v", buildctlCmd.Args)
+	var stderr bytes.Buffer
+	buildctlCmd.Stderr = &stderr
+	out, err := buildctlCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run %v: %w (stderr: %q)", buildctlCmd.Args, err, stderr.String())
+	}
+	return decodeUsageInfo(bytes.NewReader(out))
+}
+
+// decodeUsageInfo decodes the output of `buildctl du --format={{json .}}`,
+// which is either an array of records, or a stream of records.
+func decodeUsageInfo(r io.Reader) ([]buildkitutil.UsageInfo, error) {
+	dec := json.NewDecoder(r)
+	result := make([]buildkitutil.UsageInfo, 0)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to decode the build cache records: %w", err)
+		}
+		trimmed := bytes.TrimSpace(raw)
+		if bytes.Equal(trimmed, []byte("null")) {
+			// no record
+			continue
+		}
+		if trimmed[0] == '[' {
+			var records []buildkitutil.UsageInfo
+			if err := json.Unmarshal(raw, &records); err != nil {
+				return nil, fmt.Errorf("failed to decode the build cache records: %w", err)
+			}
+			result = append(result, records...)
+			continue
+		}
+		var record buildkitutil.UsageInfo
+		if err := json.Unmarshal(raw, &record); err != nil {
+			return nil, fmt.Errorf("failed to decode the build cache record: %w", err)
+		}
+		result = append(result, record)
+	}
+	return result, nil
+}
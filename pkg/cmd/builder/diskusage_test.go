@@ -0,0 +1,48 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builder
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDecodeUsageInfo(t *testing.T) {
+	array := `[{"id":"a","size":10,"inUse":true},{"id":"b","size":20,"shared":true}]`
+	records, err := decodeUsageInfo(strings.NewReader(array))
+	assert.NilError(t, err)
+	assert.Equal(t, len(records), 2)
+	assert.Equal(t, records[0].ID, "a")
+	assert.Equal(t, records[0].InUse, true)
+	assert.Equal(t, records[1].Size, int64(20))
+	assert.Equal(t, records[1].Shared, true)
+
+	stream := "{\"id\":\"a\",\"size\":10}\n{\"id\":\"b\",\"size\":20}\n"
+	records, err = decodeUsageInfo(strings.NewReader(stream))
+	assert.NilError(t, err)
+	assert.Equal(t, len(records), 2)
+	assert.Equal(t, records[1].ID, "b")
+
+	records, err = decodeUsageInfo(strings.NewReader("null\n"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(records), 0)
+
+	_, err = decodeUsageInfo(strings.NewReader("{"))
+	assert.ErrorContains(t, err, "failed to decode")
+}
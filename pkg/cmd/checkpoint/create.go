@@ -21,20 +21,29 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"path"
 	"path/filepath"
+	"strings"
+	"time"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 
 	"github.com/containerd/containerd/api/types/runc/options"
 	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/containers"
 	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/diff"
 	"github.com/containerd/containerd/v2/core/images"
 	"github.com/containerd/containerd/v2/pkg/archive"
+	"github.com/containerd/containerd/v2/pkg/rootfs"
 	"github.com/containerd/containerd/v2/plugins"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/checkpointutil"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
 )
 
 func Create(ctx context.Context, client *containerd.Client, containerID string, checkpointName string, options types.CheckpointCreateOptions) error {
@@ -68,6 +77,16 @@ func Create(ctx context.Context, client *containerd.Client, containerID string,
 		return fmt.Errorf("failed to get task for container %q: %w", containerID, err)
 	}
 
+	if options.Export != "" {
+		// keep the checkpoint and the rootfs diff until they are written to the archive
+		var done func(context.Context) error
+		ctx, done, err = client.WithLease(ctx)
+		if err != nil {
+			return err
+		}
+		defer done(ctx)
+	}
+
 	img, err := task.Checkpoint(ctx, withCheckpointOpts(info.Runtime.Name, !options.LeaveRunning))
 	if err != nil {
 		return err
@@ -98,6 +117,14 @@ func Create(ctx context.Context, client *containerd.Client, containerID string,
 		return errors.New("invalid checkpoint")
 	}
 
+	if options.Export != "" {
+		if err := exportArchive(ctx, client, container, info, *cpDesc, checkpointName, options); err != nil {
+			return err
+		}
+		fmt.Fprintf(options.Stdout, "%s\n", options.Export)
+		return nil
+	}
+
 	if options.CheckpointDir == "" {
 		options.CheckpointDir = filepath.Join(options.GOptions.DataRoot, "checkpoints")
 	}
@@ -137,3 +164,110 @@ func withCheckpointOpts(rt string, exit bool) containerd.CheckpointTaskOpts {
 		return nil
 	}
 }
+
+// exportArchive writes a portable checkpoint archive of the container, with the CRIU image of cpDesc,
+// the diff of its rootfs, its spec, its labels and the files of its state directory.
+func exportArchive(ctx context.Context, client *containerd.Client, container containerd.Container, info containers.Container, cpDesc ocispec.Descriptor, checkpointName string, options types.CheckpointCreateOptions) (retErr error) {
+	cs := client.ContentStore()
+	rootfsDesc, err := rootfs.CreateDiff(ctx, info.SnapshotKey, client.SnapshotService(info.Snapshotter), client.DiffService(),
+		diff.WithMediaType(ocispec.MediaTypeImageLayer),
+		diff.WithReference("nerdctl-checkpoint-rootfs-"+container.ID()))
+	if err != nil {
+		return fmt.Errorf("failed to create the diff of the rootfs: %w", err)
+	}
+	spec, err := container.Spec(ctx)
+	if err != nil {
+		return err
+	}
+	dataStore, err := clientutil.DataStore(options.GOptions.DataRoot, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+
+	meta := checkpointutil.Metadata{
+		Version:    checkpointutil.ArchiveVersion,
+		ID:         container.ID(),
+		Name:       info.Labels[labels.Name],
+		Checkpoint: checkpointName,
+		Image:      info.Image,
+		Runtime:    checkpointutil.Runtime{Name: info.Runtime.Name},
+		Labels:     info.Labels,
+		DataStore:  dataStore,
+		Created:    time.Now().UTC(),
+	}
+	if info.Runtime.Options != nil {
+		meta.Runtime.OptionsTypeURL = info.Runtime.Options.GetTypeUrl()
+		meta.Runtime.Options = info.Runtime.Options.GetValue()
+	}
+
+	f, err := os.Create(options.Export)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); retErr == nil {
+			retErr = closeErr
+		}
+		if retErr != nil {
+			os.Remove(options.Export)
+		}
+	}()
+	aw, err := checkpointutil.NewArchiveWriter(f, checkpointutil.ArchiveCompression(options.Export))
+	if err != nil {
+		return err
+	}
+	if err := aw.WriteJSON(checkpointutil.ArchiveMetadata, meta); err != nil {
+		return err
+	}
+	if err := aw.WriteJSON(checkpointutil.ArchiveSpec, spec); err != nil {
+		return err
+	}
+	for name, desc := range map[string]ocispec.Descriptor{
+		checkpointutil.ArchiveCheckpoint: cpDesc,
+		checkpointutil.ArchiveRootfs:     rootfsDesc,
+	} {
+		ra, err := cs.ReaderAt(ctx, desc)
+		if err != nil {
+			return err
+		}
+		err = aw.WriteFile(name, desc.Size, content.NewReader(ra))
+		ra.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if err := exportStateDir(aw, info.Labels[labels.StateDir]); err != nil {
+		return err
+	}
+	return aw.Close()
+}
+
+// exportStateDir writes the files of the state directory of a container (resolv.conf, hosts, network-config.json, ...),
+// except its logs.
+func exportStateDir(aw *checkpointutil.ArchiveWriter, stateDir string) error {
+	if stateDir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(stateDir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if !e.Type().IsRegular() || strings.HasSuffix(e.Name(), ".log") {
+			continue
+		}
+		f, err := os.Open(filepath.Join(stateDir, e.Name()))
+		if err != nil {
+			return err
+		}
+		st, err := f.Stat()
+		if err == nil {
+			err = aw.WriteFile(path.Join(checkpointutil.ArchiveState, e.Name()), st.Size(), f)
+		}
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -46,6 +46,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
+	"github.com/containerd/nerdctl/v2/pkg/config"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/dnsutil/hostsstore"
 	"github.com/containerd/nerdctl/v2/pkg/flagutil"
@@ -66,6 +67,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/portutil"
 	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+	"github.com/containerd/nerdctl/v2/pkg/sdnotify"
 	"github.com/containerd/nerdctl/v2/pkg/store"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
@@ -141,11 +143,23 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 		oci.WithDefaultSpec(),
 	)
 
+	var podOpts []oci.SpecOpts
+	if options.PodInfra {
+		internalLabels.pod = options.Pod
+		internalLabels.podInfra = true
+	} else if options.Pod != "" {
+		netManager, podOpts, err = joinPod(ctx, client, netManager, &internalLabels, &options)
+		if err != nil {
+			return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), err
+		}
+	}
+
 	platformOpts, err := setPlatformOptions(ctx, client, id, netManager.NetworkOptions().UTSNamespace, &internalLabels, options)
 	if err != nil {
 		return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), err
 	}
 	opts = append(opts, platformOpts...)
+	opts = append(opts, podOpts...)
 
 	if len(options.CDIDevices) > 0 || len(options.GPUs) > 0 {
 		opts = append(opts, withStaticCDIRegistry(options.GOptions.CDISpecDirs))
@@ -271,6 +285,16 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 		return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), err
 	}
 
+	if options.SdNotify == sdnotify.Container {
+		sdNotifyOpts, err := withSdNotifySocket(internalLabels.stateDir)
+		if err != nil {
+			return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), err
+		}
+		opts = append(opts, sdNotifyOpts...)
+		envs = append(envs, "NOTIFY_SOCKET="+sdnotify.ContainerSocket)
+	}
+	internalLabels.sdNotify = options.SdNotify
+
 	// -i with -d requires -t. Without a pty, nerdctl (being daemonless) has no
 	// process to keep the container's stdin open after it detaches, so the
 	// process would read EOF immediately; with -t the shim holds the pty open.
@@ -405,6 +429,18 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 	}
 	if healthcheckConfig != "" {
 		internalLabels.healthcheck = healthcheckConfig
+		internalLabels.healthOnFailure = options.HealthOnFailure
+	}
+	if options.SdNotify == sdnotify.Healthy {
+		if healthcheckConfig == "" {
+			return nil, generateRemoveOrphanedDirsFunc(ctx, id, dataStore, internalLabels), errors.New("--sdnotify=healthy requires a health check")
+		}
+		// Only the health check timers update the health state that `nerdctl start -a` waits for
+		cfg := config.Config(options.GOptions)
+		if !healthcheck.TimersEnabled(&cfg) {
+			return nil, generateRemoveOrphanedDirsFunc(ctx, id, dataStore, internalLabels),
+				errors.New("--sdnotify=healthy requires the systemd health check timers, which are not available in rootless mode, without systemd, or with disable_hc_systemd")
+		}
 	}
 
 	lCOpts, err := withContainerLabels(options.Label, options.LabelFile)
@@ -487,6 +523,7 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 		return nil, generateGcFunc(ctx, c, options.GOptions.Namespace, id, options.Name, dataStore, containerErr, containerNameStore, netManager, internalLabels), returnedError
 	}
 
+	containerutil.RecordEvent(ctx, c, dataStore, "create", nil)
 	return c, nil, nil
 }
 
@@ -815,6 +852,7 @@ type internalLabels struct {
 	networks             []string
 	ipAddress            string
 	ip6Address           string
+	networkAliases       map[string][]string
 	macAddress           string
 	dnsServers           []string
 	dnsSearchDomains     []string
@@ -826,6 +864,13 @@ type internalLabels struct {
 	pidContainer string
 	// ipc namespace & dev/shm
 	ipc string
+	// uts namespace
+	utsContainer string
+	// pod
+	pod      string
+	podInfra bool
+	// systemd notification mode
+	sdNotify string
 	// log
 	logURI string
 	// a label to check whether the --rm option is specified.
@@ -843,7 +888,8 @@ type internalLabels struct {
 
 	user string
 
-	healthcheck string
+	healthcheck     string
+	healthOnFailure string
 
 	privileged bool
 
@@ -914,6 +960,14 @@ func withInternalLabels(internalLabels internalLabels) (containerd.NewContainerO
 		m[labels.IP6Address] = internalLabels.ip6Address
 	}
 
+	if len(internalLabels.networkAliases) > 0 {
+		networkAliasesJSON, err := json.Marshal(internalLabels.networkAliases)
+		if err != nil {
+			return nil, err
+		}
+		m[labels.NetworkAliases] = string(networkAliasesJSON)
+	}
+
 	m[labels.Platform], err = platformutil.NormalizeString(internalLabels.platform)
 	if err != nil {
 		return nil, err
@@ -950,6 +1004,22 @@ func withInternalLabels(internalLabels internalLabels) (containerd.NewContainerO
 		m[labels.IPC] = internalLabels.ipc
 	}
 
+	if internalLabels.utsContainer != "" {
+		m[labels.UTSContainer] = internalLabels.utsContainer
+	}
+
+	if internalLabels.pod != "" {
+		m[labels.Pod] = internalLabels.pod
+	}
+
+	if internalLabels.podInfra {
+		m[labels.PodInfra] = "true"
+	}
+
+	if internalLabels.sdNotify != "" {
+		m[labels.SdNotify] = internalLabels.sdNotify
+	}
+
 	if internalLabels.rm != "" {
 		m[labels.ContainerAutoRemove] = internalLabels.rm
 	}
@@ -998,9 +1068,29 @@ func withInternalLabels(internalLabels internalLabels) (containerd.NewContainerO
 		m[labels.HealthCheck] = internalLabels.healthcheck
 	}
 
+	if internalLabels.healthOnFailure != "" && internalLabels.healthOnFailure != healthcheck.OnFailureNone {
+		m[labels.HealthOnFailure] = internalLabels.healthOnFailure
+	}
+
 	return containerd.WithAdditionalContainerLabels(m), nil
 }
 
+// withSdNotifySocket mounts the directory of the notification socket, which is forwarded to systemd by `nerdctl start -a`.
+func withSdNotifySocket(stateDir string) ([]oci.SpecOpts, error) {
+	if runtime.GOOS != "linux" {
+		return nil, errors.New("--sdnotify=container is only supported on linux")
+	}
+	dir := sdnotify.SocketDir(stateDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return []oci.SpecOpts{
+		oci.WithMounts([]specs.Mount{
+			{Type: "bind", Source: dir, Destination: sdnotify.ContainerSocketDir, Options: []string{"bind", "rw"}},
+		}),
+	}, nil
+}
+
 func withHealthcheck(options types.ContainerCreateOptions, ensuredImage *imgutil.EnsuredImage) (string, error) {
 	// If explicitly disabled
 	if options.NoHealthcheck {
@@ -1042,6 +1132,9 @@ func withHealthcheck(options types.ContainerCreateOptions, ensuredImage *imgutil
 	if options.HealthStartPeriod != 0 {
 		hc.StartPeriod = options.HealthStartPeriod
 	}
+	if options.HealthStartInterval != 0 {
+		hc.StartInterval = options.HealthStartInterval
+	}
 
 	// Apply defaults for any unset values, but only if we have a healthcheck configured
 	if len(hc.Test) > 0 && hc.Test[0] != "NONE" {
@@ -1066,6 +1159,7 @@ func (il *internalLabels) loadNetOpts(opts types.NetworkOptions) {
 	il.ipAddress = opts.IPAddress
 	il.ip6Address = opts.IP6Address
 	il.networks = opts.NetworkSlice
+	il.networkAliases = opts.NetworkAliases
 	il.macAddress = opts.MACAddress
 	il.dnsServers = opts.DNSServers
 	il.dnsSearchDomains = opts.DNSSearchDomains
@@ -19,16 +19,22 @@ package container
 import (
 	"context"
 	"fmt"
+	"syscall"
 	"time"
 
 	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
 
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/config"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 )
 
 // HealthCheck executes the health check command for a container
-func HealthCheck(ctx context.Context, client *containerd.Client, container containerd.Container) error {
+func HealthCheck(ctx context.Context, client *containerd.Client, container containerd.Container, options types.ContainerHealthCheckOptions) error {
 	task, err := container.Task(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to get container task: %w", err)
@@ -74,8 +80,79 @@ func HealthCheck(ctx context.Context, client *containerd.Client, container conta
 		hcConfig.Retries = healthcheck.DefaultProbeRetries
 	}
 
+	// The timer runs at the start interval when one is set, skip the checks that are not due after the start period
+	if options.Timer {
+		var state *healthcheck.HealthState
+		if stateJSON := info.Labels[labels.HealthState]; stateJSON != "" {
+			if state, err = healthcheck.HealthStateFromJSON(stateJSON); err != nil {
+				return fmt.Errorf("invalid health state: %w", err)
+			}
+		}
+		if !healthcheck.IsProbeDue(hcConfig, state, info.CreatedAt, time.Now()) {
+			log.G(ctx).Debugf("health check of container %s is not due, skipping", container.ID())
+			return nil
+		}
+	}
+
 	// Execute the health check
-	return healthcheck.ExecuteHealthCheck(ctx, task, container, hcConfig)
+	return healthcheck.ExecuteHealthCheck(ctx, task, container, hcConfig, healthCheckOnFailure(client, options))
+}
+
+// healthCheckOnFailure returns the function applying the failure action to a container that became unhealthy,
+// and recording it as a container event.
+func healthCheckOnFailure(client *containerd.Client, options types.ContainerHealthCheckOptions) healthcheck.OnFailureFunc {
+	return func(ctx context.Context, container containerd.Container, action healthcheck.OnFailureAction) error {
+		switch action {
+		case healthcheck.OnFailureKill:
+			// Unlike `nerdctl kill`, the container is not marked as explicitly stopped, so that its restart policy applies
+			task, err := container.Task(ctx, nil)
+			if err != nil {
+				return err
+			}
+			if err := task.Kill(ctx, syscall.SIGKILL); err != nil {
+				return err
+			}
+		case healthcheck.OnFailureStop:
+			if err := containerutil.Stop(ctx, container, nil, ""); err != nil {
+				return err
+			}
+			// Like `nerdctl stop`, except that the service of the timer may be running this health check
+			removeTimer := healthcheck.RemoveTransientHealthCheckFiles
+			if options.Timer {
+				removeTimer = healthcheck.RemoveTimer
+			}
+			if err := removeTimer(ctx, container); err != nil {
+				log.G(ctx).WithError(err).Warnf("failed to remove the health check timer of container %s", container.ID())
+			}
+		case healthcheck.OnFailureRestart:
+			if err := containerutil.Stop(ctx, container, nil, ""); err != nil {
+				return err
+			}
+			// The health check timer is still running (it is running this health check)
+			cfg := config.Config(options.GOptions)
+			cfg.DisableHCSystemd = true
+			if err := containerutil.Start(ctx, container, false, false, client, "", "", &cfg, options.NerdctlCmd, options.NerdctlArgs); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported health check failure action %q", action)
+		}
+		event := &healthcheck.OnFailureEvent{
+			ContainerID: container.ID(),
+			Status:      healthcheck.Unhealthy,
+			Action:      action,
+		}
+		if err := client.EventService().Publish(ctx, healthcheck.OnFailureEventTopic, event); err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to publish the health check failure event of container %s", container.ID())
+		}
+		if dataStore, err := clientutil.DataStore(options.GOptions.DataRoot, options.GOptions.Address); err == nil {
+			containerutil.RecordEvent(ctx, container, dataStore, "health_status", map[string]string{
+				"healthStatus": string(healthcheck.Unhealthy),
+				"onFailure":    string(action),
+			})
+		}
+		return nil
+	}
 }
 
 // If configuredValue is zero, use defaultValue instead.
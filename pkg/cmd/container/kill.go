@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"syscall"
 
@@ -54,6 +55,10 @@ func Kill(ctx context.Context, client *containerd.Client, reqs []string, options
 	if err != nil {
 		return err
 	}
+	dataStore, err := clientutil.DataStore(options.GOptions.DataRoot, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
 
 	walker := &containerwalker.ContainerWalker{
 		Client: client,
@@ -71,6 +76,7 @@ func Kill(ctx context.Context, client *containerd.Client, reqs []string, options
 				}
 				return err
 			}
+			containerutil.RecordEvent(ctx, found.Container, dataStore, "kill", map[string]string{"signal": strconv.Itoa(int(parsedSignal))})
 			_, err := fmt.Fprintln(options.Stdout, found.Container.ID())
 			return err
 		},
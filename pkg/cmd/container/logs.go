@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"regexp"
 	"sort"
 	"strings"
 	"syscall"
@@ -39,7 +40,10 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/logging"
 )
 
-func Logs(ctx context.Context, client *containerd.Client, container string, options types.ContainerLogsOptions) error {
+// Logs prints the logs of the given containers. The logs of a single container
+// are printed as is; the logs of multiple containers (or of the containers
+// matching options.Filters) are merged in timestamp order.
+func Logs(ctx context.Context, client *containerd.Client, containers []string, options types.ContainerLogsOptions) error {
 	dataStore, err := clientutil.DataStore(options.GOptions.DataRoot, options.GOptions.Address)
 	if err != nil {
 		return err
@@ -50,6 +54,22 @@ func Logs(ctx context.Context, client *containerd.Client, container string, opti
 		log.G(ctx).Warn("Currently, `nerdctl logs` only supports containers created with `nerdctl run -d` or CRI")
 	}
 
+	switch options.Format {
+	case "", "json":
+	default:
+		return fmt.Errorf("unsupported format %q, only \"json\" is supported", options.Format)
+	}
+	var grep *regexp.Regexp
+	if options.Grep != "" {
+		grep, err = regexp.Compile(options.Grep)
+		if err != nil {
+			return fmt.Errorf("invalid grep expression %q: %w", options.Grep, err)
+		}
+	}
+	if len(containers) != 1 || len(options.Filters) > 0 || options.Format != "" || grep != nil {
+		return mergedLogs(ctx, client, containers, dataStore, grep, options)
+	}
+
 	stopChannel := make(chan os.Signal, 1)
 	// catch OS signals:
 	signal.Notify(stopChannel, syscall.SIGTERM, syscall.SIGINT)
@@ -60,127 +80,134 @@ func Logs(ctx context.Context, client *containerd.Client, container string, opti
 			if found.MatchCount > 1 {
 				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
 			}
-			l, err := found.Container.Labels(ctx)
+			logViewer, err := newContainerLogViewer(ctx, found.Container, dataStore, stopChannel, options)
 			if err != nil {
 				return err
 			}
 
-			logPath, err := getLogPath(ctx, found.Container)
+			return logViewer.PrintLogsTo(options.Stdout, options.Stderr)
+		},
+	}
+	n, err := walker.Walk(ctx, containers[0])
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("no such container %s", containers[0])
+	}
+	return nil
+}
+
+// newContainerLogViewer returns the log viewer of the container for the given options.
+// When following the logs of a running container, os.Interrupt is sent to
+// stopChannel once the container has exited.
+func newContainerLogViewer(ctx context.Context, container containerd.Container, dataStore string, stopChannel chan os.Signal, options types.ContainerLogsOptions) (*logging.ContainerLogViewer, error) {
+	l, err := container.Labels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	logPath, err := getLogPath(ctx, container)
+	if err != nil {
+		return nil, err
+	}
+
+	follow := options.Follow
+	if follow {
+		task, err := container.Task(ctx, nil)
+		if err != nil {
+			if !errdefs.IsNotFound(err) {
+				return nil, err
+			}
+			follow = false
+		} else {
+			status, err := task.Status(ctx)
 			if err != nil {
-				return err
+				return nil, err
 			}
-
-			follow := options.Follow
-			if follow {
-				task, err := found.Container.Task(ctx, nil)
+			if status.Status != containerd.Running {
+				follow = false
+			} else {
+				waitCh, err := task.Wait(ctx)
 				if err != nil {
-					if !errdefs.IsNotFound(err) {
-						return err
-					}
-					follow = false
-				} else {
-					status, err := task.Status(ctx)
-					if err != nil {
-						return err
-					}
-					if status.Status != containerd.Running {
-						follow = false
-					} else {
-						waitCh, err := task.Wait(ctx)
-						if err != nil {
-							return fmt.Errorf("failed to get wait channel for task %#v: %w", task, err)
-						}
-
-						// Setup goroutine to send stop event if container task finishes:
-						go func() {
-							<-waitCh
-							// Wait for logger to process remaining logs after container exit
-							if err = logging.WaitForLogger(dataStore, l[labels.Namespace], found.Container.ID()); err != nil {
-								log.G(ctx).WithError(err).Error("failed to wait for logger shutdown")
-							}
-							log.G(ctx).Debugf("container task has finished, sending kill signal to log viewer")
-							stopChannel <- os.Interrupt
-						}()
-					}
+					return nil, fmt.Errorf("failed to get wait channel for task %#v: %w", task, err)
 				}
+
+				// Setup goroutine to send stop event if container task finishes:
+				go func() {
+					<-waitCh
+					// Wait for logger to process remaining logs after container exit
+					if err = logging.WaitForLogger(dataStore, l[labels.Namespace], container.ID()); err != nil {
+						log.G(ctx).WithError(err).Error("failed to wait for logger shutdown")
+					}
+					log.G(ctx).Debugf("container task has finished, sending kill signal to log viewer")
+					stopChannel <- os.Interrupt
+				}()
 			}
+		}
+	}
 
-			// When follow was requested but the task has already stopped,
-			// wait for the logger to finish writing before reading the log file.
-			if !follow && options.Follow {
-				if err := logging.WaitForLogger(dataStore, l[labels.Namespace], found.Container.ID()); err != nil {
-					log.G(ctx).WithError(err).Warn("failed to wait for logger shutdown")
-				}
+	// When follow was requested but the task has already stopped,
+	// wait for the logger to finish writing before reading the log file.
+	if !follow && options.Follow {
+		if err := logging.WaitForLogger(dataStore, l[labels.Namespace], container.ID()); err != nil {
+			log.G(ctx).WithError(err).Warn("failed to wait for logger shutdown")
+		}
+	}
+
+	var detailPrefix string
+	if options.Details {
+		if logConfigJSON, ok := l["nerdctl/log-config"]; ok {
+			type logConfig struct {
+				Opts map[string]string `json:"opts"`
 			}
 
-			var detailPrefix string
-			if options.Details {
-				if logConfigJSON, ok := l["nerdctl/log-config"]; ok {
-					type logConfig struct {
-						Opts map[string]string `json:"opts"`
-					}
+			e, err := getContainerEnvs(ctx, container)
+			if err != nil {
+				return nil, err
+			}
 
-					e, err := getContainerEnvs(ctx, found.Container)
-					if err != nil {
-						return err
+			var logCfg logConfig
+			var optPairs []string
+
+			if err := json.Unmarshal([]byte(logConfigJSON), &logCfg); err == nil {
+				envOpts, labelOpts := getLogOpts(logCfg.Opts)
+
+				for _, v := range envOpts {
+					if env, ok := e[v]; ok {
+						optPairs = append(optPairs, fmt.Sprintf("%s=%s", v, env))
 					}
+				}
 
-					var logCfg logConfig
-					var optPairs []string
-
-					if err := json.Unmarshal([]byte(logConfigJSON), &logCfg); err == nil {
-						envOpts, labelOpts := getLogOpts(logCfg.Opts)
-
-						for _, v := range envOpts {
-							if env, ok := e[v]; ok {
-								optPairs = append(optPairs, fmt.Sprintf("%s=%s", v, env))
-							}
-						}
-
-						for _, v := range labelOpts {
-							if label, ok := l[v]; ok {
-								optPairs = append(optPairs, fmt.Sprintf("%s=%s", v, label))
-							}
-						}
-
-						if len(optPairs) > 0 {
-							sort.Strings(optPairs)
-							detailPrefix = strings.Join(optPairs, ",")
-						}
-					} else {
-						log.L.Warn("failed to parse `--details` option, detailed information might not be displayed")
+				for _, v := range labelOpts {
+					if label, ok := l[v]; ok {
+						optPairs = append(optPairs, fmt.Sprintf("%s=%s", v, label))
 					}
 				}
-			}
 
-			logViewOpts := logging.LogViewOptions{
-				ContainerID:       found.Container.ID(),
-				Namespace:         l[labels.Namespace],
-				DatastoreRootPath: dataStore,
-				LogPath:           logPath,
-				Follow:            follow,
-				Timestamps:        options.Timestamps,
-				Tail:              options.Tail,
-				Since:             options.Since,
-				Until:             options.Until,
-				Details:           options.Details,
-				DetailPrefix:      &detailPrefix,
-			}
-			logViewer, err := logging.InitContainerLogViewer(l, logViewOpts, stopChannel, options.GOptions.Experimental)
-			if err != nil {
-				return err
+				if len(optPairs) > 0 {
+					sort.Strings(optPairs)
+					detailPrefix = strings.Join(optPairs, ",")
+				}
+			} else {
+				log.L.Warn("failed to parse `--details` option, detailed information might not be displayed")
 			}
-
-			return logViewer.PrintLogsTo(options.Stdout, options.Stderr)
-		},
+		}
 	}
-	n, err := walker.Walk(ctx, container)
-	if err != nil {
-		return err
-	} else if n == 0 {
-		return fmt.Errorf("no such container %s", container)
+
+	logViewOpts := logging.LogViewOptions{
+		ContainerID:       container.ID(),
+		Namespace:         l[labels.Namespace],
+		DatastoreRootPath: dataStore,
+		LogPath:           logPath,
+		Follow:            follow,
+		Timestamps:        options.Timestamps,
+		Tail:              options.Tail,
+		Since:             options.Since,
+		Until:             options.Until,
+		Details:           options.Details,
+		DetailPrefix:      &detailPrefix,
 	}
-	return nil
+	return logging.InitContainerLogViewer(l, logViewOpts, stopChannel, options.GOptions.Experimental)
 }
 
 func getLogPath(ctx context.Context, container containerd.Container) (string, error) {
@@ -0,0 +1,327 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+
+	containerd "github.com/containerd/containerd/v2/client"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/composer/pipetagger"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/idgen"
+	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+	"github.com/containerd/nerdctl/v2/pkg/logging"
+)
+
+// logMergeWindow is how long the lines of followed logs are held back, so that
+// they can be sorted with the lines the other containers logged at the same time.
+const logMergeWindow = 200 * time.Millisecond
+
+// logRecord is a line of the merged logs. It is also the `--format json` output.
+type logRecord struct {
+	Container string `json:"container"`
+	ID        string `json:"id"`
+	logging.LogEntry
+
+	received time.Time
+}
+
+// mergedLogs prints the logs of the given containers and of the containers
+// matching options.Filters, interleaved in timestamp order.
+func mergedLogs(ctx context.Context, client *containerd.Client, reqs []string, dataStore string, grep *regexp.Regexp, options types.ContainerLogsOptions) error {
+	containers, err := logsContainers(ctx, client, reqs, options.Filters)
+	if err != nil {
+		return err
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	// catch OS signals:
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(sigCh)
+
+	type logSource struct {
+		name        string
+		id          string
+		viewer      *logging.ContainerLogViewer
+		stopChannel chan os.Signal
+	}
+	sources := make([]logSource, 0, len(containers))
+	printer := &logPrinter{
+		stdout:     options.Stdout,
+		stderr:     options.Stderr,
+		json:       options.Format == "json",
+		timestamps: options.Timestamps,
+		width:      -1,
+		colors:     make(map[string]*color.Color),
+	}
+	for _, c := range containers {
+		l, err := c.Labels(ctx)
+		if err != nil {
+			return err
+		}
+		name := containerutil.GetContainerName(l)
+		if name == "" {
+			name = idgen.TruncateID(c.ID())
+		}
+		stopChannel := make(chan os.Signal, 1)
+		viewer, err := newContainerLogViewer(ctx, c, dataStore, stopChannel, options)
+		if err != nil {
+			return fmt.Errorf("failed to get the logs of container %s: %w", name, err)
+		}
+		sources = append(sources, logSource{name: name, id: c.ID(), viewer: viewer, stopChannel: stopChannel})
+
+		printer.width = max(printer.width, len(name)+1)
+		if !options.NoColor {
+			printer.colors[name] = color.New(pipetagger.ChooseColorAttrs(name)...)
+		}
+	}
+	// The logs of a single container are printed without prefixes, as in the default output.
+	if options.NoLogPrefix || (len(reqs) == 1 && len(options.Filters) == 0) {
+		printer.width = -1
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case sig := <-sigCh:
+			for _, s := range sources {
+				select {
+				case s.stopChannel <- sig:
+				default:
+				}
+			}
+		case <-done:
+		}
+	}()
+
+	records := make([]<-chan logRecord, len(sources))
+	errs := make([]error, len(sources))
+	var wg sync.WaitGroup
+	for i, s := range sources {
+		ch := make(chan logRecord)
+		records[i] = ch
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(ch)
+			err := s.viewer.PrintLogEntriesTo(func(e logging.LogEntry) {
+				if grep != nil && !grep.MatchString(e.Log) {
+					return
+				}
+				ch <- logRecord{Container: s.name, ID: s.id, LogEntry: e, received: time.Now()}
+			})
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to get the logs of container %s: %w", s.name, err)
+			}
+		}()
+	}
+
+	mergeLogRecords(records, options.Follow, logMergeWindow, printer.print)
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// logsContainers returns the containers matching the requests and the filters, without duplicates.
+func logsContainers(ctx context.Context, client *containerd.Client, reqs []string, filters []string) ([]containerd.Container, error) {
+	var containers []containerd.Container
+	seen := make(map[string]struct{})
+	add := func(c containerd.Container) {
+		if _, ok := seen[c.ID()]; !ok {
+			seen[c.ID()] = struct{}{}
+			containers = append(containers, c)
+		}
+	}
+
+	walker := &containerwalker.ContainerWalker{
+		Client: client,
+		OnFound: func(ctx context.Context, found containerwalker.Found) error {
+			if found.MatchCount > 1 {
+				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
+			}
+			add(found.Container)
+			return nil
+		},
+	}
+	for _, req := range reqs {
+		n, err := walker.Walk(ctx, req)
+		if err != nil {
+			return nil, err
+		} else if n == 0 {
+			return nil, fmt.Errorf("no such container %s", req)
+		}
+	}
+
+	if len(filters) > 0 {
+		filtered, _, err := filterContainers(ctx, client, filters, 0, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range filtered {
+			add(c)
+		}
+	}
+	return containers, nil
+}
+
+// mergeLogRecords prints the records received from the sources in timestamp
+// order, until all the sources are closed. The records of each source are
+// expected to be in timestamp order.
+//
+// Without follow, only the next record of each source is held, and the oldest
+// one is printed. With follow, a source may not have a next record yet, so
+// each record is held back for the given window instead, so that it can be
+// sorted with the records of the other sources received in the meantime.
+func mergeLogRecords(sources []<-chan logRecord, follow bool, window time.Duration, printFn func(logRecord)) {
+	if follow {
+		mergeFollowedLogRecords(fanInLogRecords(sources), window, printFn)
+		return
+	}
+	heads := make([]*logRecord, len(sources))
+	next := func(i int) {
+		heads[i] = nil
+		if r, ok := <-sources[i]; ok {
+			heads[i] = &r
+		}
+	}
+	for i := range sources {
+		next(i)
+	}
+	for {
+		oldest := -1
+		for i, h := range heads {
+			if h != nil && (oldest < 0 || h.Time.Before(heads[oldest].Time)) {
+				oldest = i
+			}
+		}
+		if oldest < 0 {
+			return
+		}
+		printFn(*heads[oldest])
+		next(oldest)
+	}
+}
+
+// fanInLogRecords returns a channel receiving the records of all the sources,
+// closed once all the sources are closed.
+func fanInLogRecords(sources []<-chan logRecord) <-chan logRecord {
+	records := make(chan logRecord)
+	var wg sync.WaitGroup
+	for _, src := range sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range src {
+				records <- r
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(records)
+	}()
+	return records
+}
+
+// mergeFollowedLogRecords prints the records received from the channel in
+// timestamp order, each record being held back for the given window.
+func mergeFollowedLogRecords(records <-chan logRecord, window time.Duration, printFn func(logRecord)) {
+	var pending []logRecord
+	// flush prints the pending records in timestamp order, stopping at the
+	// first one received after cutoff. A zero cutoff prints all of them.
+	flush := func(cutoff time.Time) {
+		sort.SliceStable(pending, func(i, j int) bool {
+			return pending[i].Time.Before(pending[j].Time)
+		})
+		n := 0
+		for ; n < len(pending); n++ {
+			if !cutoff.IsZero() && pending[n].received.After(cutoff) {
+				break
+			}
+			printFn(pending[n])
+		}
+		pending = pending[n:]
+	}
+
+	ticker := time.NewTicker(window / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case r, ok := <-records:
+			if !ok {
+				flush(time.Time{})
+				return
+			}
+			pending = append(pending, r)
+		case now := <-ticker.C:
+			flush(now.Add(-window))
+		}
+	}
+}
+
+// logPrinter prints log records as text, prefixed with the colored container
+// names like `nerdctl compose logs`, or as JSON lines.
+type logPrinter struct {
+	stdout     io.Writer
+	stderr     io.Writer
+	json       bool
+	timestamps bool
+	// width is the width of the container name prefix. -1 disables the prefix.
+	width  int
+	colors map[string]*color.Color
+}
+
+func (p *logPrinter) print(r logRecord) {
+	if p.json {
+		_ = json.NewEncoder(p.stdout).Encode(r)
+		return
+	}
+
+	w := p.stdout
+	if r.Stream == "stderr" {
+		w = p.stderr
+	}
+	line := r.Log
+	if p.timestamps {
+		line = r.Time.Format(time.RFC3339Nano) + " " + line
+	}
+	if p.width < 0 {
+		fmt.Fprintln(w, line)
+		return
+	}
+	name := r.Container
+	if c, ok := p.colors[name]; ok {
+		name = c.Sprint(name)
+	}
+	fmt.Fprintf(w, "%s%s|%s\n", name, strings.Repeat(" ", p.width-len(r.Container)), line)
+}
@@ -0,0 +1,50 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/logging"
+)
+
+func TestMergeLogRecords(t *testing.T) {
+	base := time.Now()
+	source := func(name string, offsets ...int) <-chan logRecord {
+		ch := make(chan logRecord)
+		go func() {
+			defer close(ch)
+			for _, o := range offsets {
+				ch <- logRecord{Container: name, LogEntry: logging.LogEntry{Log: name, Time: base.Add(time.Duration(o) * time.Second)}}
+			}
+		}()
+		return ch
+	}
+	sources := []<-chan logRecord{
+		source("a", 1, 4, 5),
+		source("b", 2, 3, 6),
+		source("c"),
+	}
+	var got []string
+	mergeLogRecords(sources, false, 0, func(r logRecord) {
+		got = append(got, r.Container)
+	})
+	assert.DeepEqual(t, got, []string{"a", "b", "b", "a", "a", "b"})
+}
@@ -0,0 +1,173 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/runtime/restart"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/typeurl/v2"
+
+	"github.com/containerd/nerdctl/v2/pkg/containerinspector"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/statsutil"
+)
+
+// metricsSampleInterval is the interval between the two readings needed to compute
+// the CPU usage of a container that has not been sampled yet.
+const metricsSampleInterval = 500 * time.Millisecond
+
+// MetricsCollector collects the statistics of containers for the OpenMetrics exposition.
+// It keeps the previous readings of the containers, so that successive collections
+// (e.g., the scrapes of `nerdctl system metrics serve`) do not need to wait for a second reading.
+type MetricsCollector struct {
+	mu sync.Mutex
+	// previous is keyed by namespace, then by container ID
+	previous map[string]map[string]*statsutil.ContainerStats
+}
+
+// NewMetricsCollector creates a MetricsCollector.
+func NewMetricsCollector() *MetricsCollector {
+	return &MetricsCollector{
+		previous: make(map[string]map[string]*statsutil.ContainerStats),
+	}
+}
+
+// Collect returns the samples of the containers, which must belong to the namespace of ctx.
+// The containers without a task are skipped, and the previous readings of the containers
+// of the namespace that are not passed anymore are forgotten.
+func (m *MetricsCollector) Collect(ctx context.Context, containers []containerd.Container) ([]statsutil.MetricsSample, error) {
+	ns, err := namespaces.NamespaceRequired(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	previous := make(map[string]*statsutil.ContainerStats, len(containers))
+	var primed []containerd.Container
+	for _, c := range containers {
+		if p, ok := m.previous[ns][c.ID()]; ok {
+			previous[c.ID()] = p
+			continue
+		}
+		p := new(statsutil.ContainerStats)
+		if _, err := sampleContainer(ctx, c, p, true); err != nil {
+			logSampleError(ctx, c, err)
+			continue
+		}
+		previous[c.ID()] = p
+		primed = append(primed, c)
+	}
+	m.previous[ns] = previous
+	if len(primed) > 0 {
+		// sleep to create distant CPU readings
+		time.Sleep(metricsSampleInterval)
+	}
+
+	var samples []statsutil.MetricsSample
+	for _, c := range containers {
+		p, ok := previous[c.ID()]
+		if !ok {
+			continue
+		}
+		entry, err := sampleContainer(ctx, c, p, false)
+		if err != nil {
+			logSampleError(ctx, c, err)
+			delete(previous, c.ID())
+			continue
+		}
+		sample, err := metricsSample(ctx, c, entry)
+		if err != nil {
+			logSampleError(ctx, c, err)
+			continue
+		}
+		sample.Namespace = ns
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
+
+func logSampleError(ctx context.Context, c containerd.Container, err error) {
+	if errdefs.IsNotFound(err) {
+		// the container has no task, or was removed in the meantime
+		return
+	}
+	log.G(ctx).WithError(err).Warnf("failed to collect the statistics of container %s", c.ID())
+}
+
+// sampleContainer takes a reading of the statistics of the container.
+// When firstSet is true, the reading is only stored into previousStats.
+func sampleContainer(ctx context.Context, c containerd.Container, previousStats *statsutil.ContainerStats, firstSet bool) (statsutil.StatsEntry, error) {
+	task, err := c.Task(ctx, nil)
+	if err != nil {
+		return statsutil.StatsEntry{}, err
+	}
+	// Sample system CPU usage close to container usage to avoid
+	// noise in metric calculations.
+	systemUsage, onlineCPUs, err := getSystemCPUUsage()
+	if err != nil {
+		return statsutil.StatsEntry{}, err
+	}
+	systemInfo := statsutil.SystemInfo{
+		OnlineCPUs:  onlineCPUs,
+		SystemUsage: systemUsage,
+	}
+	metric, err := task.Metrics(ctx)
+	if err != nil {
+		return statsutil.StatsEntry{}, err
+	}
+	anydata, err := typeurl.UnmarshalAny(metric.Data)
+	if err != nil {
+		return statsutil.StatsEntry{}, err
+	}
+	netNS, err := containerinspector.InspectNetNS(ctx, int(task.Pid()))
+	if err != nil {
+		return statsutil.StatsEntry{}, err
+	}
+	return setContainerStatsAndRenderStatsEntry(previousStats, firstSet, anydata, int(task.Pid()), netNS.Interfaces, systemInfo)
+}
+
+func metricsSample(ctx context.Context, c containerd.Container, entry statsutil.StatsEntry) (statsutil.MetricsSample, error) {
+	info, err := c.Info(ctx, containerd.WithoutRefreshedMetadata)
+	if err != nil {
+		return statsutil.MetricsSample{}, err
+	}
+	entry.ID = c.ID()
+	entry.Name = containerutil.GetContainerName(info.Labels)
+	sample := statsutil.MetricsSample{
+		StatsEntry: entry,
+		Image:      info.Image,
+	}
+	sample.RestartCount, _ = strconv.Atoi(info.Labels[restart.CountLabel])
+	if s := info.Labels[labels.HealthState]; s != "" {
+		if state, err := healthcheck.HealthStateFromJSON(s); err == nil {
+			sample.Health = string(state.Status)
+		}
+	}
+	return sample, nil
+}
@@ -23,12 +23,17 @@ import (
 	containerd "github.com/containerd/containerd/v2/client"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
 )
 
 // Pause pauses all containers specified by `reqs`.
 func Pause(ctx context.Context, client *containerd.Client, reqs []string, options types.ContainerPauseOptions) error {
+	dataStore, err := clientutil.DataStore(options.GOptions.DataRoot, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
 	walker := &containerwalker.ContainerWalker{
 		Client: client,
 		OnFound: func(ctx context.Context, found containerwalker.Found) error {
@@ -38,6 +43,7 @@ func Pause(ctx context.Context, client *containerd.Client, reqs []string, option
 			if err := containerutil.Pause(ctx, client, found.Container.ID()); err != nil {
 				return err
 			}
+			containerutil.RecordEvent(ctx, found.Container, dataStore, "pause", nil)
 
 			_, err := fmt.Fprintln(options.Stdout, found.Req)
 			return err
@@ -0,0 +1,75 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/pkg/oci"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/podutil"
+)
+
+// joinPod reconfigures the options so that the container shares the network, IPC and UTS namespaces
+// of the infra container of options.Pod. It returns the networking manager to use and the UTS spec options.
+func joinPod(ctx context.Context, client *containerd.Client, netManager containerutil.NetworkOptionsManager, internalLabels *internalLabels, options *types.ContainerCreateOptions) (containerutil.NetworkOptionsManager, []oci.SpecOpts, error) {
+	if runtime.GOOS != "linux" {
+		return nil, nil, errors.New("--pod is only supported on linux")
+	}
+	netOpts := netManager.NetworkOptions()
+	if options.IPC != "" {
+		return nil, nil, errors.New("conflicting options: --ipc cannot be used with --pod")
+	}
+	if netOpts.UTSNamespace != "" {
+		return nil, nil, errors.New("conflicting options: --uts cannot be used with --pod")
+	}
+
+	infra, err := podutil.Infra(ctx, client, options.Pod)
+	if err != nil {
+		return nil, nil, err
+	}
+	infraLabels, err := infra.Labels(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	pod := infraLabels[labels.Pod]
+	status, err := containerutil.ContainerStatus(ctx, infra)
+	if err != nil || status.Status != containerd.Running {
+		return nil, nil, fmt.Errorf("pod %s is not running, start it with `nerdctl pod start %s`", pod, pod)
+	}
+
+	netOpts.NetworkSlice = []string{"container:" + infra.ID()}
+	netManager, err = containerutil.NewNetworkingOptionsManager(options.GOptions, netOpts, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	options.IPC = "container:" + infra.ID()
+	utsOpts, err := containerutil.GenerateSharingUTSOpts(ctx, infra)
+	if err != nil {
+		return nil, nil, err
+	}
+	internalLabels.pod = pod
+	internalLabels.utsContainer = infra.ID()
+	return netManager, utsOpts, nil
+}
@@ -0,0 +1,104 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/go-cni"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+	"github.com/containerd/nerdctl/v2/pkg/portutil"
+)
+
+// PortAdd publishes ports of a container, which may be running.
+func PortAdd(ctx context.Context, client *containerd.Client, options types.ContainerPortAddOptions) error {
+	var ports []cni.PortMapping
+	for _, p := range options.Ports {
+		pm, err := portutil.ParseFlagP(p)
+		if err != nil {
+			return err
+		}
+		ports = append(ports, pm...)
+	}
+	return updatePorts(ctx, client, options.Container, options.GOptions, func(current []cni.PortMapping) ([]cni.PortMapping, error) {
+		for _, p := range ports {
+			for _, c := range current {
+				if c.HostPort == p.HostPort && c.Protocol == p.Protocol && hostIPsOverlap(c.HostIP, p.HostIP) {
+					return nil, fmt.Errorf("port %s/%s is already published to container port %d",
+						net.JoinHostPort(c.HostIP, fmt.Sprint(c.HostPort)), c.Protocol, c.ContainerPort)
+				}
+			}
+			current = append(current, p)
+		}
+		return current, nil
+	})
+}
+
+// PortRemove unpublishes ports of a container, which may be running.
+func PortRemove(ctx context.Context, client *containerd.Client, options types.ContainerPortRemoveOptions) error {
+	return updatePorts(ctx, client, options.Container, options.GOptions, func(current []cni.PortMapping) ([]cni.PortMapping, error) {
+		for _, p := range options.Ports {
+			matched, err := portutil.MatchFlagP(current, p)
+			if err != nil {
+				return nil, err
+			}
+			if len(matched) == 0 {
+				return nil, fmt.Errorf("no port mapping matches %q", p)
+			}
+			current = slices.DeleteFunc(current, func(c cni.PortMapping) bool {
+				return slices.Contains(matched, c)
+			})
+		}
+		return current, nil
+	})
+}
+
+func updatePorts(ctx context.Context, client *containerd.Client, req string, globalOptions types.GlobalCommandOptions,
+	update func(current []cni.PortMapping) ([]cni.PortMapping, error)) error {
+	walker := &containerwalker.ContainerWalker{
+		Client: client,
+		OnFound: func(ctx context.Context, found containerwalker.Found) error {
+			if found.MatchCount > 1 {
+				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
+			}
+			return containerutil.UpdatePortMappings(ctx, found.Container, globalOptions, update)
+		},
+	}
+	n, err := walker.Walk(ctx, req)
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("no such container %s", req)
+	}
+	return nil
+}
+
+// hostIPsOverlap returns whether two host IPs of port mappings can conflict.
+func hostIPsOverlap(a, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil || ipA.IsUnspecified() || ipB.IsUnspecified() {
+		return true
+	}
+	return ipA.Equal(ipB)
+}
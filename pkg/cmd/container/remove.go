@@ -34,6 +34,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/dnsutil/hostsstore"
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
 	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
 	"github.com/containerd/nerdctl/v2/pkg/ipcutil"
@@ -112,8 +113,12 @@ func RemoveContainer(ctx context.Context, c containerd.Container, globalOptions
 	// Capture the container's snapshotter before deletion: image-mount views were
 	// created against it, which may differ from the current --snapshotter flag.
 	imageMountSnapshotter := globalOptions.Snapshotter
-	if info, err := c.Info(ctx); err == nil && info.Snapshotter != "" {
-		imageMountSnapshotter = info.Snapshotter
+	var imageName string
+	if info, err := c.Info(ctx); err == nil {
+		if info.Snapshotter != "" {
+			imageMountSnapshotter = info.Snapshotter
+		}
+		imageName = info.Image
 	}
 
 	// Get datastore
@@ -241,6 +246,17 @@ func RemoveContainer(ctx context.Context, c containerd.Container, globalOptions
 		}
 
 		// Container has been removed successfully. Now we just finish the cleanup on our side.
+		eventutil.Record(ctx, dataStore, eventutil.JournalEvent{
+			Type:       eventutil.TypeContainer,
+			Action:     "destroy",
+			ID:         id,
+			Attributes: map[string]string{"name": name, "image": imageName},
+		})
+
+		// Release the volumes kept mounted for a restart that did not happen - soft failure
+		if err := containerutil.UnmountVolumes(containerLabels, id, dataStore); err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to unmount the volumes of container %q", id)
+		}
 
 		// Cleanup IPC - soft failure
 		if err = ipcutil.CleanUp(ipc); err != nil {
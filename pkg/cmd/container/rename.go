@@ -26,6 +26,7 @@ import (
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/dnsutil/hostsstore"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
@@ -54,8 +55,16 @@ func Rename(ctx context.Context, client *containerd.Client, containerID, newCont
 			if found.MatchCount > 1 {
 				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
 			}
-			return renameContainer(ctx, found.Container, newContainerName,
-				options.GOptions.Namespace, namest, hostst)
+			containerLabels, err := found.Container.Labels(ctx)
+			if err != nil {
+				return err
+			}
+			if err := renameContainer(ctx, found.Container, newContainerName,
+				options.GOptions.Namespace, namest, hostst); err != nil {
+				return err
+			}
+			containerutil.RecordEvent(ctx, found.Container, dataStore, "rename", map[string]string{"oldName": containerLabels[labels.Name]})
+			return nil
 		},
 	}
 
@@ -24,6 +24,7 @@ import (
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/config"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
@@ -32,6 +33,10 @@ import (
 
 // Restart will restart one or more containers.
 func Restart(ctx context.Context, client *containerd.Client, containers []string, options types.ContainerRestartOptions) error {
+	dataStore, err := clientutil.DataStore(options.GOption.DataRoot, options.GOption.Address)
+	if err != nil {
+		return err
+	}
 	walker := &containerwalker.ContainerWalker{
 		Client: client,
 		OnFound: func(ctx context.Context, found containerwalker.Found) error {
@@ -48,10 +53,12 @@ func Restart(ctx context.Context, client *containerd.Client, containers []string
 			if err := containerutil.Stop(ctx, found.Container, options.Timeout, options.Signal); err != nil {
 				return err
 			}
+			containerutil.RecordEvent(ctx, found.Container, dataStore, "stop", nil)
 
 			if err := containerutil.Start(ctx, found.Container, false, false, client, "", "", (*config.Config)(&options.GOption), options.NerdctlCmd, options.NerdctlArgs); err != nil {
 				return err
 			}
+			containerutil.RecordEvent(ctx, found.Container, dataStore, "restart", nil)
 			_, err = fmt.Fprintln(options.Stdout, found.Req)
 			return err
 		},
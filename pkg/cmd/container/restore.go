@@ -0,0 +1,346 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/pkg/archive"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/typeurl/v2"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/checkpointutil"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
+	"github.com/containerd/nerdctl/v2/pkg/config"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/logging"
+	"github.com/containerd/nerdctl/v2/pkg/namestore"
+	"github.com/containerd/nerdctl/v2/pkg/platformutil"
+)
+
+// Restore recreates the container of a checkpoint archive written by `nerdctl checkpoint create --export`,
+// on this host or another one, and restores its processes from the checkpoint.
+// The container keeps its ID; the image is pulled when missing.
+func Restore(ctx context.Context, client *containerd.Client, archivePath string, options types.ContainerRestoreOptions) (retErr error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	dir, err := os.MkdirTemp("", "nerdctl-restore-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	meta, err := checkpointutil.ExtractArchive(f, dir)
+	if err != nil {
+		return err
+	}
+	specJSON, err := os.ReadFile(filepath.Join(dir, checkpointutil.ArchiveSpec))
+	if err != nil {
+		return err
+	}
+	var spec specs.Spec
+	if err := json.Unmarshal(specJSON, &spec); err != nil {
+		return fmt.Errorf("failed to parse the spec of the checkpoint: %w", err)
+	}
+
+	id := meta.ID
+	if _, err := client.LoadContainer(ctx, id); err == nil {
+		return fmt.Errorf("container %s already exists", id)
+	} else if !errdefs.IsNotFound(err) {
+		return err
+	}
+	name := options.Name
+	if name == "" {
+		name = meta.Name
+	}
+
+	var platformSS []string // len: 0 or 1
+	if platform := meta.Labels[labels.Platform]; platform != "" {
+		platformSS = append(platformSS, platform)
+	}
+	ocispecPlatforms, err := platformutil.NewOCISpecPlatformSlice(false, platformSS)
+	if err != nil {
+		return err
+	}
+	options.ImagePullOpt.Mode = "missing"
+	options.ImagePullOpt.OCISpecPlatform = ocispecPlatforms
+	ensured, err := image.EnsureImage(ctx, client, meta.Image, options.ImagePullOpt)
+	if err != nil {
+		return err
+	}
+
+	// keep the rootfs diff until it is applied
+	ctx, done, err := client.WithLease(ctx)
+	if err != nil {
+		return err
+	}
+	defer done(ctx)
+
+	dataStore, err := clientutil.DataStore(options.GOptions.DataRoot, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	stateDir, err := containerutil.ContainerStateDirPath(options.GOptions.Namespace, dataStore, id)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(stateDir, 0o700); err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			os.RemoveAll(stateDir)
+		}
+	}()
+	if err := restoreStateDir(filepath.Join(dir, checkpointutil.ArchiveState), stateDir); err != nil {
+		return err
+	}
+
+	// relocate the paths of the source host, e.g., when the data roots differ
+	relocations := [][2]string{
+		{meta.Labels[labels.StateDir], stateDir},
+		{meta.DataStore, dataStore},
+	}
+	relocate := func(p string) string {
+		for _, r := range relocations {
+			if r[0] != "" && (p == r[0] || strings.HasPrefix(p, r[0]+"/")) {
+				return r[1] + strings.TrimPrefix(p, r[0])
+			}
+		}
+		return p
+	}
+	for i := range spec.Mounts {
+		spec.Mounts[i].Source = relocate(spec.Mounts[i].Source)
+	}
+	if spec.Hooks != nil {
+		spec.Hooks.CreateRuntime = slices.DeleteFunc(spec.Hooks.CreateRuntime, isNerdctlOCIHook)
+		spec.Hooks.Poststop = slices.DeleteFunc(spec.Hooks.Poststop, isNerdctlOCIHook)
+	}
+	hookOpt, err := withNerdctlOCIHook(options.NerdctlCmd, options.NerdctlArgs)
+	if err != nil {
+		return err
+	}
+
+	containerLabels := make(map[string]string, len(meta.Labels))
+	for k, v := range meta.Labels {
+		containerLabels[k] = v
+	}
+	containerLabels[labels.Namespace] = options.GOptions.Namespace
+	containerLabels[labels.Name] = name
+	containerLabels[labels.StateDir] = stateDir
+	if err := restoreLogConfig(containerLabels, dataStore, options.GOptions.Namespace, id); err != nil {
+		return err
+	}
+	if err := ensureVolumes(containerLabels, options); err != nil {
+		return err
+	}
+
+	containerNameStore, err := namestore.New(dataStore, options.GOptions.Namespace)
+	if err != nil {
+		return err
+	}
+	if err := containerNameStore.Acquire(name, id); err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			containerNameStore.Release(name, id)
+		}
+	}()
+
+	var runtimeOptions any
+	if meta.Runtime.OptionsTypeURL != "" {
+		runtimeOptions, err = typeurl.UnmarshalByTypeURL(meta.Runtime.OptionsTypeURL, meta.Runtime.Options)
+		if err != nil {
+			return fmt.Errorf("failed to decode the runtime options: %w", err)
+		}
+	}
+
+	c, err := client.NewContainer(ctx, id,
+		containerd.WithImage(ensured.Image),
+		containerd.WithSnapshotter(ensured.Snapshotter),
+		containerd.WithNewSnapshot(id, ensured.Image),
+		containerd.WithRuntime(meta.Runtime.Name, runtimeOptions),
+		containerd.WithContainerLabels(containerLabels),
+		containerd.WithSpec(&spec, hookOpt, propagateInternalContainerdLabelsToOCIAnnotations()),
+	)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			if err := c.Delete(context.WithoutCancel(ctx), containerd.WithSnapshotCleanup); err != nil {
+				log.G(ctx).WithError(err).Warnf("failed to remove container %s", id)
+			}
+		}
+	}()
+
+	if err := applyRootfsDiff(ctx, client, filepath.Join(dir, checkpointutil.ArchiveRootfs), ensured.Snapshotter, id); err != nil {
+		return err
+	}
+
+	checkpointDir := filepath.Join(dir, "criu")
+	if err := extractTar(ctx, filepath.Join(dir, checkpointutil.ArchiveCheckpoint), checkpointDir); err != nil {
+		return fmt.Errorf("failed to extract the checkpoint: %w", err)
+	}
+
+	containerutil.RecordEvent(ctx, c, dataStore, "create", nil)
+	if err := containerutil.Start(ctx, c, false, false, client, "", checkpointDir, (*config.Config)(&options.GOptions), options.NerdctlCmd, options.NerdctlArgs); err != nil {
+		return err
+	}
+	fmt.Fprintln(options.Stdout, name)
+	return nil
+}
+
+// isNerdctlOCIHook returns whether h is a hook added by withNerdctlOCIHook.
+func isNerdctlOCIHook(h specs.Hook) bool {
+	i := slices.Index(h.Args, "internal")
+	return i >= 0 && i+1 < len(h.Args) && h.Args[i+1] == "oci-hook"
+}
+
+// restoreStateDir copies the files of the state directory of the checkpoint archive.
+func restoreStateDir(src, stateDir string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		b, err := os.ReadFile(filepath.Join(src, e.Name()))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(stateDir, e.Name()), b, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreLogConfig initializes the log driver of the container and updates its log URI for this host.
+func restoreLogConfig(containerLabels map[string]string, dataStore, ns, id string) error {
+	logConfigJSON, ok := containerLabels[labels.LogConfig]
+	if !ok {
+		return nil
+	}
+	var logConfig logging.LogConfig
+	if err := json.Unmarshal([]byte(logConfigJSON), &logConfig); err != nil {
+		return err
+	}
+	if logConfig.Driver == "" {
+		return nil
+	}
+	logDriver, err := logging.GetDriver(logConfig.Driver, logConfig.Opts, logConfig.Address)
+	if err != nil {
+		return err
+	}
+	if err := logDriver.Init(dataStore, ns, id); err != nil {
+		return err
+	}
+	logURI, err := GenerateLogURI(dataStore)
+	if err != nil {
+		return err
+	}
+	containerLabels[labels.LogURI] = logURI.String()
+	return nil
+}
+
+// ensureVolumes creates the named volumes of the container that do not exist on this host. Their content is not restored.
+func ensureVolumes(containerLabels map[string]string, options types.ContainerRestoreOptions) error {
+	volStore, err := volume.Store(options.GOptions.Namespace, options.GOptions.DataRoot, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	if err := volStore.Lock(); err != nil {
+		return err
+	}
+	defer volStore.Release()
+	for _, vol := range containerutil.GetContainerVolumes(containerLabels) {
+		if vol.Type != "volume" || vol.Name == "" {
+			continue
+		}
+		if _, err := volStore.CreateWithoutLock(vol.Name, nil); err != nil {
+			return fmt.Errorf("failed to create volume %q: %w", vol.Name, err)
+		}
+	}
+	return nil
+}
+
+// applyRootfsDiff applies the uncompressed layer at layerPath to the snapshot of the container.
+func applyRootfsDiff(ctx context.Context, client *containerd.Client, layerPath, snapshotter, key string) error {
+	f, err := os.Open(layerPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	digester := digest.Canonical.Digester()
+	size, err := io.Copy(digester.Hash(), f)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	desc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageLayer,
+		Digest:    digester.Digest(),
+		Size:      size,
+	}
+	if err := content.WriteBlob(ctx, client.ContentStore(), "nerdctl-restore-rootfs-"+key, f, desc); err != nil {
+		return err
+	}
+	mounts, err := client.SnapshotService(snapshotter).Mounts(ctx, key)
+	if err != nil {
+		return err
+	}
+	if _, err := client.DiffService().Apply(ctx, desc, mounts); err != nil {
+		return fmt.Errorf("failed to apply the diff of the rootfs: %w", err)
+	}
+	return nil
+}
+
+func extractTar(ctx context.Context, tarPath, dir string) error {
+	f, err := os.Open(tarPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	_, err = archive.Apply(ctx, dir, f)
+	return err
+}
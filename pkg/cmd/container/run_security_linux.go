@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -36,7 +37,10 @@ import (
 
 	"github.com/containerd/nerdctl/v2/pkg/apparmorutil"
 	"github.com/containerd/nerdctl/v2/pkg/defaults"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/maputil"
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+	"github.com/containerd/nerdctl/v2/pkg/seccomputil"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
@@ -56,6 +60,30 @@ const (
 	systemPathsUnconfined = "unconfined"
 )
 
+// withSeccompRecording runs the container with a profile logging its syscalls, so that the postStop hook
+// writes a profile allowing them to path.
+func withSeccompRecording(path string) (oci.SpecOpts, error) {
+	if path == "" {
+		return nil, errors.New(`invalid security-opt "seccomp=record:PATH": the path is empty`)
+	}
+	if rootlessutil.IsRootless() {
+		// the audit records cannot be read in rootless mode
+		return nil, errors.New("seccomp profiles cannot be recorded in rootless mode")
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *specs.Spec) error {
+		s.Linux.Seccomp = seccomputil.RecordingProfile(seccomp.DefaultProfile(s))
+		if s.Annotations == nil {
+			s.Annotations = map[string]string{}
+		}
+		s.Annotations[labels.SeccompRecord] = path
+		return nil
+	}, nil
+}
+
 func generateSecurityOpts(privileged bool, selinuxEnabled bool, securityOptsMap map[string]string) ([]oci.SpecOpts, error) {
 	for k := range securityOptsMap {
 		switch k {
@@ -70,7 +98,13 @@ func generateSecurityOpts(privileged bool, selinuxEnabled bool, securityOptsMap
 			return nil, errors.New("invalid security-opt \"seccomp\"")
 		}
 
-		if seccompProfile != "unconfined" {
+		if recordPath, ok := strings.CutPrefix(seccompProfile, seccomputil.RecordPrefix); ok {
+			recordOpt, err := withSeccompRecording(recordPath)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, recordOpt)
+		} else if seccompProfile != "unconfined" {
 			opts = append(opts, seccomp.WithProfile(seccompProfile))
 		}
 	} else {
@@ -83,7 +117,13 @@ func generateSecurityOpts(privileged bool, selinuxEnabled bool, securityOptsMap
 		if aaProfile == "" {
 			return nil, errors.New("invalid security-opt \"apparmor\"")
 		}
-		if aaProfile != "unconfined" {
+		if rulesPath, ok := strings.CutPrefix(aaProfile, apparmorutil.GeneratePrefix); ok {
+			aaOpt, err := withGeneratedAppArmorProfile(rulesPath)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, aaOpt)
+		} else if aaProfile != "unconfined" {
 			if !canApplyExistingProfile {
 				log.L.Warnf("the host does not support AppArmor. Ignoring profile %q", aaProfile)
 			} else {
@@ -173,6 +213,34 @@ func WithSelinuxLabel(process, mount string) oci.SpecOpts {
 	}
 }
 
+// withGeneratedAppArmorProfile generates a profile from the rules file
+// with the template of the default profile, and loads it.
+func withGeneratedAppArmorProfile(rulesPath string) (oci.SpecOpts, error) {
+	if rulesPath == "" {
+		return nil, errors.New("invalid security-opt \"apparmor=generate:\": the path of the rules must be specified")
+	}
+	if !apparmorutil.CanLoadNewProfile() {
+		return nil, errors.New("security-opt \"apparmor=generate:\" requires root and AppArmor to be enabled on the host")
+	}
+	rules, err := apparmorutil.LoadRules(rulesPath)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := apparmorutil.DefaultTemplate()
+	if err != nil {
+		return nil, err
+	}
+	name, profile, err := apparmorutil.Generate(tmpl, rules)
+	if err != nil {
+		return nil, err
+	}
+	if err := apparmorutil.Load(profile); err != nil {
+		return nil, err
+	}
+	log.L.Debugf("loaded the generated AppArmor profile %q", name)
+	return apparmor.WithProfile(name), nil
+}
+
 func canonicalizeCapName(s string) string {
 	if s == "" {
 		return ""
@@ -19,7 +19,9 @@ package container
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strconv"
 
 	containerd "github.com/containerd/containerd/v2/client"
 
@@ -28,6 +30,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/config"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
 )
 
 // Start starts a list of `containers`. If attach is true, it only starts a single container.
@@ -38,6 +41,15 @@ func Start(ctx context.Context, client *containerd.Client, reqs []string, option
 	if options.Checkpoint != "" && len(reqs) > 1 {
 		return fmt.Errorf("you cannot start multiple containers with checkpoint at once")
 	}
+	if options.PidFile != "" {
+		if len(reqs) > 1 {
+			return fmt.Errorf("you cannot start multiple containers with a pid file at once")
+		}
+		// `start -a` only returns once the container exits
+		if options.Attach {
+			return fmt.Errorf("--pidfile cannot be used with --attach")
+		}
+	}
 
 	walker := &containerwalker.ContainerWalker{
 		Client: client,
@@ -59,6 +71,11 @@ func Start(ctx context.Context, client *containerd.Client, reqs []string, option
 			if err := containerutil.Start(ctx, found.Container, options.Attach, options.Interactive, client, options.DetachKeys, checkpointDir, (*config.Config)(&options.GOptions), options.NerdctlCmd, options.NerdctlArgs); err != nil {
 				return err
 			}
+			if options.PidFile != "" {
+				if err := writePidFile(ctx, found.Container, options.PidFile); err != nil {
+					return err
+				}
+			}
 			if !options.Attach {
 				_, err := fmt.Fprintln(options.Stdout, found.Req)
 				if err != nil {
@@ -71,3 +88,15 @@ func Start(ctx context.Context, client *containerd.Client, reqs []string, option
 
 	return walker.WalkAll(ctx, reqs, true)
 }
+
+// writePidFile writes the pid of the task of the container to path.
+func writePidFile(ctx context.Context, container containerd.Container, path string) error {
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return filesystem.WriteFileWithRename(path, []byte(strconv.Itoa(int(task.Pid()))), 0o644)
+}
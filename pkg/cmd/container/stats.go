@@ -89,6 +89,10 @@ func Stats(ctx context.Context, client *containerd.Client, containerIDs []string
 		return errors.New("stats requires cgroup v2 for rootless containers, see https://rootlesscontaine.rs/getting-started/common/cgroup2/")
 	}
 
+	if options.Format == "openmetrics" {
+		return statsOpenMetrics(ctx, client, containerIDs, options)
+	}
+
 	showAll := len(containerIDs) == 0
 	closeChan := make(chan error)
 
@@ -340,6 +344,38 @@ func Stats(ctx context.Context, client *containerd.Client, containerIDs []string
 	return err
 }
 
+// statsOpenMetrics writes a single reading of the statistics of the containers in the OpenMetrics text format.
+func statsOpenMetrics(ctx context.Context, client *containerd.Client, containerIDs []string, options types.ContainerStatsOptions) error {
+	var containers []containerd.Container
+	if len(containerIDs) == 0 {
+		all, err := client.Containers(ctx)
+		if err != nil {
+			return err
+		}
+		for _, c := range all {
+			if options.All || strings.HasPrefix(formatter.ContainerStatus(ctx, c), "Up") {
+				containers = append(containers, c)
+			}
+		}
+	} else {
+		walker := &containerwalker.ContainerWalker{
+			Client: client,
+			OnFound: func(ctx context.Context, found containerwalker.Found) error {
+				containers = append(containers, found.Container)
+				return nil
+			},
+		}
+		if err := walker.WalkAll(ctx, containerIDs, false); err != nil {
+			return err
+		}
+	}
+	samples, err := NewMetricsCollector().Collect(ctx, containers)
+	if err != nil {
+		return err
+	}
+	return statsutil.WriteOpenMetrics(options.Stdout, samples)
+}
+
 func collect(ctx context.Context, globalOptions types.GlobalCommandOptions, s *statsutil.Stats, waitFirst *sync.WaitGroup, id string, noStream bool) {
 	log.G(ctx).Debugf("collecting stats for %s", s.ID)
 	var (
@@ -24,6 +24,7 @@ import (
 	"github.com/containerd/errdefs"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
@@ -32,6 +33,10 @@ import (
 
 // Stop stops a list of containers specified by `reqs`.
 func Stop(ctx context.Context, client *containerd.Client, reqs []string, opt types.ContainerStopOptions) error {
+	dataStore, err := clientutil.DataStore(opt.GOptions.DataRoot, opt.GOptions.Address)
+	if err != nil {
+		return err
+	}
 	walker := &containerwalker.ContainerWalker{
 		Client: client,
 		OnFound: func(ctx context.Context, found containerwalker.Found) error {
@@ -51,6 +56,7 @@ func Stop(ctx context.Context, client *containerd.Client, reqs []string, opt typ
 				}
 				return err
 			}
+			containerutil.RecordEvent(ctx, found.Container, dataStore, "stop", nil)
 			if err := ocihook.CleanupPortReserverProcess(opt.GOptions.Namespace, found.Container.ID()); err != nil {
 				return fmt.Errorf("unable to cleanup port reserver process for container: %s: %w", found.Req, err)
 			}
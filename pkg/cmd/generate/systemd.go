@@ -0,0 +1,225 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package generate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	containerd "github.com/containerd/containerd/v2/client"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/podutil"
+)
+
+// restartPolicies are the values of the Restart setting of systemd services.
+var restartPolicies = []string{"no", "on-success", "on-failure", "on-abnormal", "on-watchdog", "on-abort", "always"}
+
+// unit describes the systemd service of a container.
+type unit struct {
+	// container is the name of the container
+	container string
+	// pidFile is the `--pidfile` of the container
+	pidFile string
+	// requires are the units of the containers the container depends on
+	requires []string
+}
+
+// unitName returns the name of the systemd service of the container.
+func unitName(container string) string {
+	return "container-" + container + ".service"
+}
+
+// Systemd prints the systemd services that run the containers with `nerdctl start -a`, or writes them to files.
+func Systemd(ctx context.Context, client *containerd.Client, reqs []string, options types.GenerateSystemdOptions) error {
+	if !slices.Contains(restartPolicies, options.RestartPolicy) {
+		return fmt.Errorf("invalid restart policy %q, must be one of %s", options.RestartPolicy, strings.Join(restartPolicies, ", "))
+	}
+	var containers []containerd.Container
+	if options.ComposeProject != "" {
+		if len(reqs) > 0 {
+			return errors.New("containers cannot be specified with --compose-project")
+		}
+		var err error
+		containers, err = client.Containers(ctx, fmt.Sprintf("labels.%q==%s", labels.ComposeProject, options.ComposeProject))
+		if err != nil {
+			return err
+		}
+		if len(containers) == 0 {
+			return fmt.Errorf("no containers found for compose project %s", options.ComposeProject)
+		}
+	} else {
+		if len(reqs) == 0 {
+			return errors.New("requires at least 1 container, or --compose-project")
+		}
+		walker := &containerwalker.ContainerWalker{
+			Client: client,
+			OnFound: func(ctx context.Context, found containerwalker.Found) error {
+				if found.MatchCount > 1 {
+					return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
+				}
+				containers = append(containers, found.Container)
+				return nil
+			},
+		}
+		if err := walker.WalkAll(ctx, reqs, true); err != nil {
+			return err
+		}
+	}
+
+	var units []unit
+	for _, c := range containers {
+		u, err := containerUnit(ctx, client, c)
+		if err != nil {
+			return err
+		}
+		units = append(units, u)
+	}
+	slices.SortFunc(units, func(a, b unit) int { return strings.Compare(a.container, b.container) })
+
+	execPrefix := execCommand(options)
+	for i, u := range units {
+		content := unitFile(u, execPrefix, options)
+		if options.Files {
+			path, err := filepath.Abs(unitName(u.container))
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				return err
+			}
+			fmt.Fprintln(options.Stdout, path)
+			continue
+		}
+		if i > 0 {
+			fmt.Fprintln(options.Stdout)
+		}
+		fmt.Fprint(options.Stdout, content)
+	}
+	return nil
+}
+
+// containerUnit returns the unit of the container. The dependencies are resolved from the pod and the compose labels.
+func containerUnit(ctx context.Context, client *containerd.Client, c containerd.Container) (unit, error) {
+	l, err := c.Labels(ctx)
+	if err != nil {
+		return unit{}, err
+	}
+	u := unit{container: l[labels.Name]}
+	if u.container == "" {
+		u.container = c.ID()
+	}
+	// A relative pid file is resolved by the OCI hook, from a directory that is not known here
+	if pidFile := l[labels.PIDFile]; filepath.IsAbs(pidFile) {
+		u.pidFile = pidFile
+	}
+	if pod := l[labels.Pod]; pod != "" && l[labels.PodInfra] != "true" {
+		u.requires = append(u.requires, unitName(podutil.InfraContainerName(pod)))
+	}
+	if project, dependsOn := l[labels.ComposeProject], l[labels.ComposeDependsOn]; project != "" && dependsOn != "" {
+		for _, dep := range strings.Split(dependsOn, ",") {
+			service, _, _ := strings.Cut(dep, ":")
+			deps, err := client.Containers(ctx, fmt.Sprintf("labels.%q==%s,labels.%q==%s", labels.ComposeProject, project, labels.ComposeService, service))
+			if err != nil {
+				return unit{}, err
+			}
+			for _, d := range deps {
+				dl, err := d.Labels(ctx)
+				if err != nil {
+					return unit{}, err
+				}
+				if name := dl[labels.Name]; name != "" {
+					u.requires = append(u.requires, unitName(name))
+				}
+			}
+		}
+	}
+	slices.Sort(u.requires)
+	return u, nil
+}
+
+// execCommand returns the nerdctl command and the global flags used in ExecStart and ExecStop.
+// The namespace is always specified, as the units must not depend on the environment of systemd.
+func execCommand(options types.GenerateSystemdOptions) string {
+	args := []string{options.NerdctlCmd}
+	for _, a := range options.NerdctlArgs {
+		if strings.HasPrefix(a, "--namespace=") || strings.HasPrefix(a, "-n=") {
+			continue
+		}
+		args = append(args, a)
+	}
+	args = append(args, "--namespace="+options.GOptions.Namespace)
+	return strings.Join(args, " ")
+}
+
+// unitFile returns the content of the unit.
+//
+// The container is run by `nerdctl start -a`, which is the main process of the service (Type=notify):
+// it notifies systemd when the container is ready (see `nerdctl run --sdnotify`), forwards the signals
+// to the container, and exits with the exit code of the container.
+// When the container has a pid file, the container is started detached instead (Type=forking),
+// and its process, whose pid is written by `nerdctl start --pidfile`, is the main process of the service.
+func unitFile(u unit, execPrefix string, options types.GenerateSystemdOptions) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", unitName(u.container))
+	fmt.Fprintf(&b, "# autogenerated by nerdctl\n\n")
+
+	fmt.Fprintf(&b, "[Unit]\n")
+	fmt.Fprintf(&b, "Description=nerdctl container %s\n", u.container)
+	fmt.Fprintf(&b, "Documentation=https://github.com/containerd/nerdctl/blob/main/docs/command-reference.md\n")
+	fmt.Fprintf(&b, "Wants=network-online.target\n")
+	fmt.Fprintf(&b, "After=network-online.target containerd.service\n")
+	if len(u.requires) > 0 {
+		deps := strings.Join(u.requires, " ")
+		fmt.Fprintf(&b, "Requires=%s\n", deps)
+		fmt.Fprintf(&b, "After=%s\n", deps)
+	}
+
+	fmt.Fprintf(&b, "\n[Service]\n")
+	if u.pidFile != "" {
+		fmt.Fprintf(&b, "Type=forking\n")
+	} else {
+		fmt.Fprintf(&b, "Type=notify\n")
+		fmt.Fprintf(&b, "NotifyAccess=all\n")
+	}
+	fmt.Fprintf(&b, "Restart=%s\n", options.RestartPolicy)
+	// Leave time to `nerdctl stop` to kill the container after the timeout
+	fmt.Fprintf(&b, "TimeoutStopSec=%d\n", options.StopTimeout+60)
+	if u.pidFile != "" {
+		fmt.Fprintf(&b, "ExecStart=%s start --pidfile=%s %s\n", execPrefix, u.pidFile, u.container)
+	} else {
+		fmt.Fprintf(&b, "ExecStart=%s start -a %s\n", execPrefix, u.container)
+	}
+	fmt.Fprintf(&b, "ExecStop=%s stop -t %d %s\n", execPrefix, options.StopTimeout, u.container)
+	// The container runs in the cgroup of containerd-shim, not in the cgroup of the service.
+	// ExecStop stops the container, then only the attached `nerdctl start -a` process, if any, remains to be killed.
+	fmt.Fprintf(&b, "KillMode=mixed\n")
+	if u.pidFile != "" {
+		fmt.Fprintf(&b, "PIDFile=%s\n", u.pidFile)
+	}
+
+	fmt.Fprintf(&b, "\n[Install]\n")
+	fmt.Fprintf(&b, "WantedBy=default.target\n")
+	return b.String()
+}
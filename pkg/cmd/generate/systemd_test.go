@@ -0,0 +1,85 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package generate
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+)
+
+func TestUnitFile(t *testing.T) {
+	options := types.GenerateSystemdOptions{
+		RestartPolicy: "on-failure",
+		StopTimeout:   10,
+	}
+	content := unitFile(unit{
+		container: "web",
+		requires:  []string{"container-db.service", "container-mypod-infra.service"},
+	}, "/usr/local/bin/nerdctl --namespace=default", options)
+
+	assert.Equal(t, content, `# container-web.service
+# autogenerated by nerdctl
+
+[Unit]
+Description=nerdctl container web
+Documentation=https://github.com/containerd/nerdctl/blob/main/docs/command-reference.md
+Wants=network-online.target
+After=network-online.target containerd.service
+Requires=container-db.service container-mypod-infra.service
+After=container-db.service container-mypod-infra.service
+
+[Service]
+Type=notify
+NotifyAccess=all
+Restart=on-failure
+TimeoutStopSec=70
+ExecStart=/usr/local/bin/nerdctl --namespace=default start -a web
+ExecStop=/usr/local/bin/nerdctl --namespace=default stop -t 10 web
+KillMode=mixed
+
+[Install]
+WantedBy=default.target
+`)
+
+	content = unitFile(unit{container: "db"}, "nerdctl --namespace=default", options)
+	assert.Assert(t, !strings.Contains(content, "Requires="))
+	assert.Assert(t, !strings.Contains(content, "PIDFile="))
+
+	// the process of the container is the main process of the service
+	content = unitFile(unit{container: "db", pidFile: "/run/db.pid"}, "nerdctl --namespace=default", options)
+	assert.Assert(t, strings.Contains(content, `Type=forking
+Restart=on-failure
+TimeoutStopSec=70
+ExecStart=nerdctl --namespace=default start --pidfile=/run/db.pid db
+ExecStop=nerdctl --namespace=default stop -t 10 db
+KillMode=mixed
+PIDFile=/run/db.pid
+`))
+	assert.Assert(t, !strings.Contains(content, "NotifyAccess="))
+}
+
+func TestExecCommand(t *testing.T) {
+	assert.Equal(t, execCommand(types.GenerateSystemdOptions{
+		GOptions:    types.GlobalCommandOptions{Namespace: "k8s.io"},
+		NerdctlCmd:  "/usr/local/bin/nerdctl",
+		NerdctlArgs: []string{"--namespace=k8s.io", "--address=/run/containerd/containerd.sock"},
+	}), "/usr/local/bin/nerdctl --address=/run/containerd/containerd.sock --namespace=k8s.io")
+}
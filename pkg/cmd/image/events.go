@@ -0,0 +1,44 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
+)
+
+// RecordEvent records the event of the image in the event journal, with the reference of the image as ID.
+func RecordEvent(ctx context.Context, globalOptions types.GlobalCommandOptions, ref, action string, attributes map[string]string) {
+	dataStore, err := clientutil.DataStore(globalOptions.DataRoot, globalOptions.Address)
+	if err != nil {
+		return
+	}
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	attributes["name"] = ref
+	eventutil.Record(ctx, dataStore, eventutil.JournalEvent{
+		Namespace:  globalOptions.Namespace,
+		Type:       eventutil.TypeImage,
+		Action:     action,
+		ID:         ref,
+		Attributes: attributes,
+	})
+}
@@ -26,6 +26,7 @@ import (
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/ocilayout"
 	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
 	"github.com/containerd/nerdctl/v2/pkg/ipfs"
 	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
@@ -34,10 +35,11 @@ import (
 
 // Pull pulls an image specified by `rawRef`.
 func Pull(ctx context.Context, client *containerd.Client, rawRef string, options types.ImagePullOptions) error {
-	_, err := EnsureImage(ctx, client, rawRef, options)
+	ensured, err := EnsureImage(ctx, client, rawRef, options)
 	if err != nil {
 		return err
 	}
+	RecordEvent(ctx, options.GOptions, ensured.Ref, "pull", nil)
 
 	return nil
 }
@@ -46,6 +48,10 @@ func Pull(ctx context.Context, client *containerd.Client, rawRef string, options
 func EnsureImage(ctx context.Context, client *containerd.Client, rawRef string, options types.ImagePullOptions) (*imgutil.EnsuredImage, error) {
 	var ensured *imgutil.EnsuredImage
 
+	if referenceutil.IsOCILayout(rawRef) {
+		return ensureImageFromOCILayout(ctx, client, rawRef, options)
+	}
+
 	parsedReference, err := referenceutil.Parse(rawRef)
 	if err != nil {
 		return nil, err
@@ -87,3 +93,28 @@ func EnsureImage(ctx context.Context, client *containerd.Client, rawRef string,
 	}
 	return ensured, err
 }
+
+// ensureImageFromOCILayout imports an image from an "oci-layout://" reference.
+// The layout is local, so the image is always imported regardless of the pull mode.
+func ensureImageFromOCILayout(ctx context.Context, client *containerd.Client, rawRef string, options types.ImagePullOptions) (*imgutil.EnsuredImage, error) {
+	if options.VerifyOptions.Provider != "" && options.VerifyOptions.Provider != "none" {
+		return nil, errors.New("--verify flag is not supported on OCI image layouts")
+	}
+	ref, err := referenceutil.ParseOCILayout(rawRef)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := ocilayout.ReadIndex(ref.Path)
+	if err != nil {
+		return nil, err
+	}
+	desc, err := ocilayout.Select(idx, ref)
+	if err != nil {
+		return nil, err
+	}
+	name, err := ocilayout.ImageName(ref.Path, desc)
+	if err != nil {
+		return nil, err
+	}
+	return imgutil.PullImage(ctx, client, ocilayout.NewResolver(ref.Path, desc), name, options)
+}
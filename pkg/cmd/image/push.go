@@ -20,16 +20,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"path/filepath"
-
-	"github.com/opencontainers/go-digest"
-	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"strings"
 
 	containerd "github.com/containerd/containerd/v2/client"
-	"github.com/containerd/containerd/v2/core/content"
 	"github.com/containerd/containerd/v2/core/images"
 	"github.com/containerd/containerd/v2/core/images/converter"
 	"github.com/containerd/containerd/v2/core/remotes"
@@ -38,9 +34,6 @@ import (
 	"github.com/containerd/containerd/v2/pkg/reference"
 	"github.com/containerd/log"
 	"github.com/containerd/platforms"
-	"github.com/containerd/stargz-snapshotter/estargz"
-	"github.com/containerd/stargz-snapshotter/estargz/zstdchunked"
-	estargzconvert "github.com/containerd/stargz-snapshotter/nativeconverter/estargz"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/containerdutil"
@@ -48,6 +41,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/imgutil"
 	nerdconverter "github.com/containerd/nerdctl/v2/pkg/imgutil/converter"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil/dockerconfigresolver"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/ocilayout"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil/push"
 	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
 	"github.com/containerd/nerdctl/v2/pkg/ipfs"
@@ -64,7 +58,15 @@ func Push(ctx context.Context, client *containerd.Client, rawRef string, options
 		return err
 	}
 
+	compression, err := pushCompression(options)
+	if err != nil {
+		return err
+	}
+
 	if parsedReference.Protocol != "" {
+		if options.OCILayout != "" {
+			return errors.New("IPFS images cannot be pushed to an OCI image layout")
+		}
 		if parsedReference.Protocol != referenceutil.IPFSProtocol {
 			return fmt.Errorf("ipfs scheme is only supported but got %q", parsedReference.Protocol)
 		}
@@ -96,8 +98,11 @@ func Push(ctx context.Context, client *containerd.Client, rawRef string, options
 		}
 
 		var layerConvert converter.ConvertFunc
-		if options.Estargz {
-			layerConvert = eStargzConvertFunc()
+		if compression != "" {
+			layerConvert, err = nerdconverter.CompressionLayerConvertFunc(compression, options.CompressionLevel, options.ForceCompression)
+			if err != nil {
+				return err
+			}
 		}
 		c, err := ipfs.Push(ctx, client, parsedReference.String(), layerConvert, options.AllPlatforms, options.Platforms, options.IpfsEnsureImage, ipfsPath)
 		if err != nil {
@@ -139,14 +144,17 @@ func Push(ctx context.Context, client *containerd.Client, rawRef string, options
 		log.G(ctx).Infof("pushing as a reduced-platform image (%s, %s)", platImg.Target.MediaType, platImg.Target.Digest)
 	}
 
-	if options.Estargz {
-		pushRef = ref + "-tmp-esgz"
-		esgzImg, err := nerdconverter.Convert(ctx, client, pushRef, ref, converter.WithPlatform(platMC), converter.WithLayerConvertFunc(eStargzConvertFunc()))
+	if compression != "" {
+		pushRef = ref + "-tmp-" + strings.ReplaceAll(compression, ":", "-")
+		convertedImg, err := push.Recompress(ctx, client, pushRef, ref, platMC, compression, options.CompressionLevel, options.ForceCompression)
 		if err != nil {
-			return fmt.Errorf("failed to convert to eStargz: %v", err)
+			return fmt.Errorf("failed to convert to %s: %w", compression, err)
 		}
-		defer client.ImageService().Delete(ctx, esgzImg.Name, images.SynchronousDelete())
-		log.G(ctx).Infof("pushing as an eStargz image (%s, %s)", esgzImg.Target.MediaType, esgzImg.Target.Digest)
+		defer client.ImageService().Delete(ctx, convertedImg.Name, images.SynchronousDelete())
+		log.G(ctx).Infof("pushing as a %s image (%s, %s)", compression, convertedImg.Target.MediaType, convertedImg.Target.Digest)
+	}
+	if options.OCILayout != "" {
+		return pushOCILayout(ctx, client, parsedReference, pushRef, platMC, options)
 	}
 	if !options.AllowNondistributableArtifacts {
 		if err := pushImageWithLocal(ctx, client, parsedReference, pushRef, ref, options, platMC); err != nil {
@@ -193,51 +201,62 @@ func Push(ctx context.Context, client *containerd.Client, rawRef string, options
 			return err
 		}
 	}
+	RecordEvent(ctx, options.GOptions, ref, "push", nil)
 	if options.Quiet {
 		fmt.Fprintln(options.Stdout, ref)
 	}
 	return nil
 }
 
-func eStargzConvertFunc() converter.ConvertFunc {
-	convertToESGZ := estargzconvert.LayerConvertFunc()
-	return func(ctx context.Context, cs content.Store, desc ocispec.Descriptor) (*ocispec.Descriptor, error) {
-		if isReusableESGZ(ctx, cs, desc) {
-			log.L.Infof("reusing estargz %s without conversion", desc.Digest)
-			return nil, nil
-		}
-		newDesc, err := convertToESGZ(ctx, cs, desc)
-		if err != nil {
-			return nil, err
-		}
-		log.L.Infof("converted %q to %s", desc.MediaType, newDesc.Digest)
-		return newDesc, err
+// pushOCILayout writes the image pushRef into the OCI image layout referenced by options.OCILayout,
+// under the name of parsedReference.
+func pushOCILayout(ctx context.Context, client *containerd.Client, parsedReference *referenceutil.ImageReference, pushRef string, platMC platforms.MatchComparer, options types.ImagePushOptions) error {
+	layoutRef, err := referenceutil.ParseOCILayout(options.OCILayout)
+	if err != nil {
+		return err
 	}
-
-}
-
-func isReusableESGZ(ctx context.Context, cs content.Store, desc ocispec.Descriptor) bool {
-	dgstStr, ok := desc.Annotations[estargz.TOCJSONDigestAnnotation]
-	if !ok {
-		return false
+	if layoutRef.Digest != "" {
+		return fmt.Errorf("cannot push to the digest reference %q, specify a tag instead", options.OCILayout)
 	}
-	tocdgst, err := digest.Parse(dgstStr)
-	if err != nil {
-		return false
+	tag := layoutRef.Tag
+	if tag == "" {
+		tag = parsedReference.Tag
 	}
-	ra, err := cs.ReaderAt(ctx, desc)
+	img, err := client.ImageService().Get(ctx, pushRef)
 	if err != nil {
-		return false
+		return err
 	}
-	defer ra.Close()
-	r, err := estargz.Open(io.NewSectionReader(ra, 0, desc.Size), estargz.WithDecompressors(new(zstdchunked.Decompressor)))
-	if err != nil {
-		return false
+	img.Name = parsedReference.String()
+	if err := ocilayout.Save(ctx, client.ContentStore(), layoutRef.Path, img, tag, platMC); err != nil {
+		return err
+	}
+	log.G(ctx).Infof("pushed %s to %s%s:%s", img.Name, referenceutil.OCILayoutPrefix, layoutRef.Path, tag)
+	RecordEvent(ctx, options.GOptions, img.Name, "push", nil)
+	if options.Quiet {
+		fmt.Fprintln(options.Stdout, img.Name)
+	}
+	return nil
+}
+
+// pushCompression returns the compression requested by --compression, or by the legacy --estargz flag.
+func pushCompression(options types.ImagePushOptions) (string, error) {
+	compression := options.Compression
+	if options.Estargz {
+		if compression != "" && compression != nerdconverter.CompressionEstargz {
+			return "", fmt.Errorf("--estargz conflicts with --compression=%s", compression)
+		}
+		compression = nerdconverter.CompressionEstargz
+	}
+	if compression == "" {
+		if options.ForceCompression {
+			return "", errors.New("--force-compression requires --compression")
+		}
+		return "", nil
 	}
-	if _, err := r.VerifyTOC(tocdgst); err != nil {
-		return false
+	if err := nerdconverter.ValidateCompression(compression); err != nil {
+		return "", err
 	}
-	return true
+	return compression, nil
 }
 
 func pushImageWithLocal(ctx context.Context, client *containerd.Client, parsedReference *referenceutil.ImageReference, pushRef, rawRef string, options types.ImagePushOptions, platMC platforms.MatchComparer) error {
@@ -96,6 +96,7 @@ func Remove(ctx context.Context, client *containerd.Client, args []string, optio
 						return err
 					}
 
+					RecordEvent(ctx, options.GOptions, originalName, "untag", nil)
 					fmt.Fprintf(options.Stdout, "Untagged: %s\n", originalName)
 					fmt.Fprintf(options.Stdout, "Untagged: %s@%s\n", originalName, found.Image.Target.Digest.String())
 
@@ -115,6 +116,7 @@ func Remove(ctx context.Context, client *containerd.Client, args []string, optio
 			if err := is.Delete(ctx, found.Image.Name, delOpts...); err != nil {
 				return err
 			}
+			RecordEvent(ctx, options.GOptions, found.Image.Name, "delete", nil)
 			fmt.Fprintf(options.Stdout, "Untagged: %s@%s\n", found.Image.Name, found.Image.Target.Digest)
 			for _, digest := range digests {
 				fmt.Fprintf(options.Stdout, "Deleted: %s\n", digest)
@@ -149,6 +151,7 @@ func Remove(ctx context.Context, client *containerd.Client, args []string, optio
 						return false, err
 					}
 
+					RecordEvent(ctx, options.GOptions, originalName, "untag", nil)
 					fmt.Fprintf(options.Stdout, "Untagged: %s\n", originalName)
 					fmt.Fprintf(options.Stdout, "Untagged: %s@%s\n", originalName, found.Image.Target.Digest.String())
 
@@ -168,6 +171,7 @@ func Remove(ctx context.Context, client *containerd.Client, args []string, optio
 			if err := is.Delete(ctx, found.Image.Name, delOpts...); err != nil {
 				return false, err
 			}
+			RecordEvent(ctx, options.GOptions, found.Image.Name, "delete", nil)
 			fmt.Fprintf(options.Stdout, "Untagged: %s@%s\n", found.Image.Name, found.Image.Target.Digest)
 			for _, digest := range digests {
 				fmt.Fprintf(options.Stdout, "Deleted: %s\n", digest)
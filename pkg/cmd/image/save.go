@@ -18,6 +18,7 @@ package image
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -29,18 +30,38 @@ import (
 	"github.com/containerd/containerd/v2/core/transfer"
 	tarchive "github.com/containerd/containerd/v2/core/transfer/archive"
 	transferimage "github.com/containerd/containerd/v2/core/transfer/image"
+	"github.com/containerd/log"
 	"github.com/containerd/platforms"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/ocilayout"
 	"github.com/containerd/nerdctl/v2/pkg/platformutil"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 	"github.com/containerd/nerdctl/v2/pkg/transferutil"
 )
 
+// Formats of `nerdctl save`.
+const (
+	SaveFormatTar    = "tar"
+	SaveFormatOCIDir = "oci-dir"
+)
+
 // Save exports `images` to a `io.Writer` (e.g., a file writer, or os.Stdout) specified by `options.Stdout`.
 func Save(ctx context.Context, client *containerd.Client, images []string, options types.ImageSaveOptions) error {
 	images = strutil.DedupeStrSlice(images)
 
+	switch options.Format {
+	case "", SaveFormatTar:
+		// NOP
+	case SaveFormatOCIDir:
+		if options.OutputDir == "" {
+			return errors.New("--format=oci-dir requires an output directory (-o)")
+		}
+	default:
+		return fmt.Errorf("unsupported format %q, must be %q or %q", options.Format, SaveFormatTar, SaveFormatOCIDir)
+	}
+
 	var exportOpts []tarchive.ExportOpt
 
 	if len(options.Platform) > 0 {
@@ -61,8 +82,40 @@ func Save(ctx context.Context, client *containerd.Client, images []string, optio
 		return err
 	}
 
-	imageService := client.ImageService()
+	imageRefs, err := resolveSaveRefs(ctx, client, images, platMC, options)
+	if err != nil {
+		return err
+	}
+
+	if options.Format == SaveFormatOCIDir {
+		return saveOCILayout(ctx, client, imageRefs, platMC, options)
+	}
+
 	var storeOpts []transferimage.StoreOpt
+	for _, imageRef := range imageRefs {
+		storeOpts = append(storeOpts, transferimage.WithExtraReference(imageRef))
+	}
+
+	w := nopWriteCloser{options.Stdout}
+
+	progressOutput := io.Writer(os.Stderr)
+	if options.Quiet {
+		progressOutput = io.Discard
+	}
+	pf, done := transferutil.ProgressHandler(ctx, progressOutput)
+	defer done()
+
+	return client.Transfer(ctx,
+		transferimage.NewStore("", storeOpts...),
+		tarchive.NewImageExportStream(w, "", exportOpts...),
+		transfer.WithProgress(pf),
+	)
+}
+
+// resolveSaveRefs returns the names of the images to save, ensuring their content is available locally.
+func resolveSaveRefs(ctx context.Context, client *containerd.Client, images []string, platMC platforms.MatchComparer, options types.ImageSaveOptions) ([]string, error) {
+	imageService := client.ImageService()
+	var imageRefs []string
 	for _, img := range images {
 		var imageRef string
 
@@ -72,14 +125,14 @@ func Save(ctx context.Context, client *containerd.Client, images []string, optio
 			if dgst, err = digest.Parse("sha256:" + img); err != nil {
 				named, err := reference.ParseNormalizedNamed(img)
 				if err != nil {
-					return fmt.Errorf("invalid image name %q: %w", img, err)
+					return nil, fmt.Errorf("invalid image name %q: %w", img, err)
 				}
 				imageRef = reference.TagNameOnly(named).String()
 				err = EnsureAllContent(ctx, client, imageRef, platMC, options.GOptions)
 				if err != nil {
-					return err
+					return nil, err
 				}
-				storeOpts = append(storeOpts, transferimage.WithExtraReference(imageRef))
+				imageRefs = append(imageRefs, imageRef)
 				continue
 			}
 		}
@@ -87,34 +140,46 @@ func Save(ctx context.Context, client *containerd.Client, images []string, optio
 		filters := []string{fmt.Sprintf("target.digest~=^%s$", dgst.String())}
 		imageList, err := imageService.List(ctx, filters...)
 		if err != nil {
-			return fmt.Errorf("failed to list images: %w", err)
+			return nil, fmt.Errorf("failed to list images: %w", err)
 		}
 		if len(imageList) == 0 {
-			return fmt.Errorf("image %q: not found", img)
+			return nil, fmt.Errorf("image %q: not found", img)
 		}
 
 		imageRef = imageList[0].Name
 		err = EnsureAllContent(ctx, client, imageRef, platMC, options.GOptions)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		storeOpts = append(storeOpts, transferimage.WithExtraReference(imageRef))
+		imageRefs = append(imageRefs, imageRef)
 	}
+	return imageRefs, nil
+}
 
-	w := nopWriteCloser{options.Stdout}
-
-	progressOutput := io.Writer(os.Stderr)
-	if options.Quiet {
-		progressOutput = io.Discard
+// saveOCILayout writes the images into the OCI image layout directory options.OutputDir, tagged with their tags.
+func saveOCILayout(ctx context.Context, client *containerd.Client, imageRefs []string, platMC platforms.MatchComparer, options types.ImageSaveOptions) error {
+	tagged := map[string]string{}
+	for _, imageRef := range imageRefs {
+		img, err := client.ImageService().Get(ctx, imageRef)
+		if err != nil {
+			return err
+		}
+		tag := "latest"
+		if parsed, err := referenceutil.Parse(imageRef); err == nil && parsed.Tag != "" {
+			tag = parsed.Tag
+		}
+		if other, ok := tagged[tag]; ok {
+			return fmt.Errorf("images %q and %q are both tagged %q, save them to different layouts", other, imageRef, tag)
+		}
+		tagged[tag] = imageRef
+		if err := ocilayout.Save(ctx, client.ContentStore(), options.OutputDir, img, tag, platMC); err != nil {
+			return fmt.Errorf("failed to save %q: %w", imageRef, err)
+		}
+		if !options.Quiet {
+			log.G(ctx).Infof("saved %s as %s%s:%s", imageRef, referenceutil.OCILayoutPrefix, options.OutputDir, tag)
+		}
 	}
-	pf, done := transferutil.ProgressHandler(ctx, progressOutput)
-	defer done()
-
-	return client.Transfer(ctx,
-		transferimage.NewStore("", storeOpts...),
-		tarchive.NewImageExportStream(w, "", exportOpts...),
-		transfer.WithProgress(pf),
-	)
+	return nil
 }
 
 type nopWriteCloser struct {
@@ -50,5 +50,9 @@ func Tag(ctx context.Context, client *containerd.Client, options types.ImageTagO
 	sourceStore := transferimage.NewStore(parsedSource.String())
 	targetStore := transferimage.NewStore(parsedTarget.String())
 
-	return client.Transfer(ctx, sourceStore, targetStore)
+	if err := client.Transfer(ctx, sourceStore, targetStore); err != nil {
+		return err
+	}
+	RecordEvent(ctx, options.GOptions, parsedTarget.String(), "tag", map[string]string{"source": parsedSource.String()})
+	return nil
 }
@@ -0,0 +1,99 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	containerd "github.com/containerd/containerd/v2/client"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/pod"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+// Down removes the pods, the containers and the volumes created by `nerdctl kube play` for the manifest.
+// Persistent volume claims are kept.
+func Down(ctx context.Context, client *containerd.Client, file string, options types.KubeDownOptions) error {
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+	containers, err := client.Containers(ctx, fmt.Sprintf("labels.%q==%q", labels.KubeFile, absFile))
+	if err != nil {
+		return err
+	}
+	pods := make(map[string]struct{})
+	var others []string
+	for _, c := range containers {
+		l, err := c.Labels(ctx)
+		if err != nil {
+			return err
+		}
+		if p := l[labels.Pod]; p != "" {
+			pods[p] = struct{}{}
+		} else {
+			others = append(others, c.ID())
+		}
+	}
+	podNames := make([]string, 0, len(pods))
+	for p := range pods {
+		podNames = append(podNames, p)
+	}
+	sort.Strings(podNames)
+	for _, p := range podNames {
+		if err := pod.Remove(ctx, client, []string{p}, types.PodRemoveOptions{
+			Stdout:   options.Stdout,
+			GOptions: options.GOptions,
+			Force:    true,
+		}); err != nil {
+			return err
+		}
+	}
+	if len(others) > 0 {
+		if err := container.Remove(ctx, client, others, types.ContainerRemoveOptions{
+			Stdout:   io.Discard,
+			GOptions: options.GOptions,
+			Force:    true,
+		}); err != nil {
+			return err
+		}
+	}
+
+	vols, err := volume.Volumes(options.GOptions.Namespace, options.GOptions.DataRoot, options.GOptions.Address, false, []string{"label=" + labels.KubeFile + "=" + absFile})
+	if err != nil {
+		return err
+	}
+	if len(vols) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(vols))
+	for name := range vols {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return volume.Remove(ctx, client, names, types.VolumeRemoveOptions{
+		Stdout:   io.Discard,
+		GOptions: options.GOptions,
+	})
+}
@@ -0,0 +1,309 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-connections/nat"
+
+	containerd "github.com/containerd/containerd/v2/client"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
+	"github.com/containerd/nerdctl/v2/pkg/kubeutil"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/podutil"
+)
+
+// Generate prints the YAML of a Kubernetes Pod made of the containers.
+func Generate(ctx context.Context, client *containerd.Client, reqs []string, options types.KubeGenerateOptions) error {
+	inspected, err := container.Inspect(ctx, client, reqs, types.ContainerInspectOptions{
+		GOptions: options.GOptions,
+		Mode:     "dockercompat",
+	})
+	if err != nil {
+		return err
+	}
+	var containers []*dockercompat.Container
+	for _, x := range inspected {
+		c, ok := x.(*dockercompat.Container)
+		if !ok {
+			return fmt.Errorf("unexpected inspect result %T", x)
+		}
+		if c.Config != nil && c.Config.Labels[labels.PodInfra] == "true" {
+			continue
+		}
+		containers = append(containers, c)
+	}
+	if len(containers) == 0 {
+		return fmt.Errorf("no containers to generate a pod from: %v", reqs)
+	}
+
+	memberOf := containerLabel(containers[0], labels.Pod)
+	for _, c := range containers[1:] {
+		if containerLabel(c, labels.Pod) != memberOf {
+			memberOf = ""
+			break
+		}
+	}
+	podName := options.Name
+	if podName == "" {
+		podName = memberOf
+	}
+	if podName == "" {
+		podName = strings.TrimPrefix(containers[0].Name, "/") + "-pod"
+	}
+
+	p := &kubeutil.Pod{
+		APIVersion: kubeutil.APIVersion,
+		Kind:       "Pod",
+		Metadata:   kubeutil.ObjectMeta{Name: podName},
+	}
+	var infraPorts nat.PortMap
+	if memberOf != "" {
+		if infraPorts, err = podPortBindings(ctx, client, memberOf, options.GOptions); err != nil {
+			return err
+		}
+	}
+	volumes := make(map[string]kubeutil.Volume)
+	for i, c := range containers {
+		kc, err := generateContainer(ctx, client, c, memberOf, volumes)
+		if err != nil {
+			return err
+		}
+		if memberOf != "" {
+			// The ports of a pod are published by its infra container.
+			if i == 0 {
+				kc.Ports = containerPorts(infraPorts)
+			}
+		} else if c.HostConfig != nil {
+			kc.Ports = containerPorts(c.HostConfig.PortBindings)
+		}
+		if i == 0 && c.HostConfig != nil {
+			p.Spec.RestartPolicy = restartPolicy(c.HostConfig.RestartPolicy.Name)
+		}
+		p.Spec.Containers = append(p.Spec.Containers, kc)
+	}
+	volNames := make([]string, 0, len(volumes))
+	for name := range volumes {
+		volNames = append(volNames, name)
+	}
+	sort.Strings(volNames)
+	for _, name := range volNames {
+		p.Spec.Volumes = append(p.Spec.Volumes, volumes[name])
+	}
+
+	b, err := kubeutil.Marshal(p)
+	if err != nil {
+		return err
+	}
+	_, err = options.Stdout.Write(b)
+	return err
+}
+
+func containerLabel(c *dockercompat.Container, key string) string {
+	if c.Config == nil {
+		return ""
+	}
+	return c.Config.Labels[key]
+}
+
+// podPortBindings returns the port bindings of the infra container of the pod.
+func podPortBindings(ctx context.Context, client *containerd.Client, pod string, globalOptions types.GlobalCommandOptions) (nat.PortMap, error) {
+	infra, err := podutil.Infra(ctx, client, pod)
+	if err != nil {
+		return nil, err
+	}
+	inspected, err := container.Inspect(ctx, client, []string{infra.ID()}, types.ContainerInspectOptions{
+		GOptions: globalOptions,
+		Mode:     "dockercompat",
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, x := range inspected {
+		if c, ok := x.(*dockercompat.Container); ok && c.HostConfig != nil {
+			return c.HostConfig.PortBindings, nil
+		}
+	}
+	return nil, nil
+}
+
+// generateContainer translates the inspected container into a Kubernetes container.
+// The volumes used by the container are added to volumes.
+func generateContainer(ctx context.Context, client *containerd.Client, c *dockercompat.Container, pod string, volumes map[string]kubeutil.Volume) (kubeutil.Container, error) {
+	name := strings.TrimPrefix(c.Name, "/")
+	if pod != "" {
+		name = strings.TrimPrefix(name, pod+"-")
+	}
+	kc := kubeutil.Container{Name: name, Image: c.Image}
+	if c.Config != nil && c.Config.Image != "" {
+		kc.Image = c.Config.Image
+	}
+
+	// Omit the command and the environment inherited from the image
+	var imgEntrypoint, imgCmd, imgEnv []string
+	if img, err := client.GetImage(ctx, kc.Image); err == nil {
+		if cfg, _, err := imgutil.ReadImageConfig(ctx, img); err == nil {
+			imgEntrypoint, imgCmd, imgEnv = cfg.Config.Entrypoint, cfg.Config.Cmd, cfg.Config.Env
+		}
+	}
+	processArgs := append([]string{c.Path}, c.Args...)
+	switch {
+	case c.Path == "" || slices.Equal(processArgs, append(slices.Clone(imgEntrypoint), imgCmd...)):
+	case len(imgEntrypoint) > 0 && len(processArgs) >= len(imgEntrypoint) && slices.Equal(processArgs[:len(imgEntrypoint)], imgEntrypoint):
+		kc.Args = processArgs[len(imgEntrypoint):]
+	default:
+		kc.Command = []string{c.Path}
+		kc.Args = c.Args
+	}
+
+	if c.Config != nil {
+		kc.WorkingDir = c.Config.WorkingDir
+		for _, e := range c.Config.Env {
+			if slices.Contains(imgEnv, e) {
+				continue
+			}
+			k, v, _ := strings.Cut(e, "=")
+			kc.Env = append(kc.Env, kubeutil.EnvVar{Name: k, Value: v})
+		}
+		if u := c.Config.User; u != "" {
+			uid, gid, hasGID := strings.Cut(u, ":")
+			if n, err := strconv.ParseInt(uid, 10, 64); err == nil {
+				sc := ensureSecurityContext(&kc)
+				sc.RunAsUser = &n
+				if g, err := strconv.ParseInt(gid, 10, 64); hasGID && err == nil {
+					sc.RunAsGroup = &g
+				}
+			}
+		}
+	}
+
+	for _, m := range c.Mounts {
+		var vol kubeutil.Volume
+		switch m.Type {
+		case "bind":
+			vol = kubeutil.Volume{
+				Name:     fmt.Sprintf("%s-volume-%d", name, len(volumes)),
+				HostPath: &kubeutil.HostPathVolumeSource{Path: m.Source},
+			}
+			for _, existing := range volumes {
+				if existing.HostPath != nil && existing.HostPath.Path == m.Source {
+					vol = existing
+				}
+			}
+		case "volume":
+			vol = kubeutil.Volume{
+				Name:                  m.Name,
+				PersistentVolumeClaim: &kubeutil.PersistentVolumeClaimVolumeSource{ClaimName: m.Name},
+			}
+		default:
+			continue
+		}
+		volumes[vol.Name] = vol
+		kc.VolumeMounts = append(kc.VolumeMounts, kubeutil.VolumeMount{
+			Name:      vol.Name,
+			MountPath: m.Destination,
+			ReadOnly:  !m.RW,
+		})
+	}
+
+	if hc := c.HostConfig; hc != nil {
+		limits := make(map[string]string)
+		if hc.Memory > 0 {
+			limits["memory"] = kubeutil.FormatMemory(hc.Memory)
+		}
+		if hc.CPUQuota > 0 && hc.CPUPeriod > 0 {
+			limits["cpu"] = kubeutil.FormatCPU(float64(hc.CPUQuota) / float64(hc.CPUPeriod))
+		}
+		if len(limits) > 0 {
+			kc.Resources.Limits = limits
+		}
+		if hc.MemoryReservation > 0 {
+			kc.Resources.Requests = map[string]string{"memory": kubeutil.FormatMemory(hc.MemoryReservation)}
+		}
+		if hc.Privileged {
+			t := true
+			ensureSecurityContext(&kc).Privileged = &t
+		}
+		if hc.ReadonlyRootfs {
+			t := true
+			ensureSecurityContext(&kc).ReadOnlyRootFilesystem = &t
+		}
+		if len(hc.CapAdd) > 0 || len(hc.CapDrop) > 0 {
+			ensureSecurityContext(&kc).Capabilities = &kubeutil.Capabilities{Add: hc.CapAdd, Drop: hc.CapDrop}
+		}
+	}
+	return kc, nil
+}
+
+func ensureSecurityContext(c *kubeutil.Container) *kubeutil.SecurityContext {
+	if c.SecurityContext == nil {
+		c.SecurityContext = &kubeutil.SecurityContext{}
+	}
+	return c.SecurityContext
+}
+
+// containerPorts translates the port bindings into container ports, sorted by container port.
+func containerPorts(bindings nat.PortMap) []kubeutil.ContainerPort {
+	var ports []kubeutil.ContainerPort
+	for port, hostBindings := range bindings {
+		containerPort := int32(port.Int())
+		for _, b := range hostBindings {
+			hostPort, err := strconv.ParseInt(b.HostPort, 10, 32)
+			if err != nil {
+				continue
+			}
+			kp := kubeutil.ContainerPort{
+				ContainerPort: containerPort,
+				HostPort:      int32(hostPort),
+				Protocol:      strings.ToUpper(port.Proto()),
+			}
+			if b.HostIP != "" && b.HostIP != "0.0.0.0" {
+				kp.HostIP = b.HostIP
+			}
+			ports = append(ports, kp)
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].ContainerPort != ports[j].ContainerPort {
+			return ports[i].ContainerPort < ports[j].ContainerPort
+		}
+		return ports[i].HostPort < ports[j].HostPort
+	})
+	return ports
+}
+
+// restartPolicy translates a nerdctl restart policy into a Kubernetes one.
+func restartPolicy(name string) string {
+	switch name {
+	case "always", "unless-stopped":
+		return "Always"
+	case "on-failure":
+		return "OnFailure"
+	default:
+		return "Never"
+	}
+}
@@ -0,0 +1,182 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package kube
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
+	"github.com/containerd/nerdctl/v2/pkg/kubeutil"
+)
+
+func TestContainerCreateOptions(t *testing.T) {
+	uid, gid := int64(1000), int64(2000)
+	f := false
+	p := &kubeutil.Pod{
+		Metadata: kubeutil.ObjectMeta{Name: "web"},
+		Spec: kubeutil.PodSpec{
+			RestartPolicy:   "OnFailure",
+			SecurityContext: &kubeutil.PodSecurityContext{RunAsUser: &uid, RunAsGroup: &gid},
+		},
+	}
+	c := &kubeutil.Container{
+		Name:    "app",
+		Image:   "alpine",
+		Command: []string{"sh", "-c"},
+		Args:    []string{"echo $GREETING"},
+		Ports:   []kubeutil.ContainerPort{{Name: "http", ContainerPort: 8080}},
+		Env: []kubeutil.EnvVar{
+			{Name: "FOO", Value: "bar"},
+			{Name: "GREETING", ValueFrom: &kubeutil.EnvVarSource{ConfigMapKeyRef: &kubeutil.ConfigMapKeySelector{Name: "config", Key: "greeting"}}},
+		},
+		Resources: kubeutil.ResourceRequirements{
+			Limits:   map[string]string{"cpu": "500m", "memory": "128Mi"},
+			Requests: map[string]string{"cpu": "250m", "memory": "64Mi"},
+		},
+		VolumeMounts: []kubeutil.VolumeMount{
+			{Name: "data", MountPath: "/data"},
+			{Name: "config", MountPath: "/etc/app"},
+		},
+		LivenessProbe: &kubeutil.Probe{
+			HTTPGet:             &kubeutil.HTTPGetAction{Path: "/healthz", Port: "http"},
+			InitialDelaySeconds: 5,
+		},
+		ImagePullPolicy: "Always",
+		SecurityContext: &kubeutil.SecurityContext{
+			AllowPrivilegeEscalation: &f,
+			Capabilities:             &kubeutil.Capabilities{Drop: []string{"ALL"}},
+		},
+	}
+	configMaps := map[string]map[string]string{"config": {"greeting": "hello"}}
+	volumes := map[string]volumeSource{
+		"data":   {source: "web-data"},
+		"config": {source: "web-config", readOnly: true},
+	}
+
+	opt, args, err := containerCreateOptions(types.ContainerCreateOptions{Label: []string{"foo=bar"}}, p, c, configMaps, volumes)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, args, []string{"alpine", "echo $GREETING"})
+	assert.Equal(t, opt.Name, "web-app")
+	assert.Equal(t, opt.Pod, "web")
+	assert.Assert(t, opt.EntrypointChanged)
+	assert.DeepEqual(t, opt.Entrypoint, []string{"sh", "-c"})
+	assert.DeepEqual(t, opt.Env, []string{"FOO=bar", "GREETING=hello"})
+	assert.Equal(t, opt.CPUs, 0.5)
+	assert.Equal(t, opt.Memory, "134217728")
+	assert.Equal(t, opt.CPUShares, uint64(256))
+	assert.Assert(t, opt.MemoryReservationChanged)
+	assert.Equal(t, opt.MemoryReservation, "67108864")
+	assert.Equal(t, opt.User, "1000:2000")
+	assert.DeepEqual(t, opt.SecurityOpt, []string{"no-new-privileges"})
+	assert.DeepEqual(t, opt.CapDrop, []string{"ALL"})
+	assert.Equal(t, opt.Pull, "always")
+	assert.Equal(t, opt.Restart, "on-failure")
+	assert.DeepEqual(t, opt.Volume, []string{"web-data:/data", "web-config:/etc/app:ro"})
+	assert.Equal(t, opt.HealthCmd, "curl -fsS -o /dev/null http://127.0.0.1:8080/healthz || wget -q -O /dev/null http://127.0.0.1:8080/healthz")
+	assert.Equal(t, opt.HealthInterval, 10*time.Second)
+	assert.Equal(t, opt.HealthTimeout, time.Second)
+	assert.Equal(t, opt.HealthStartPeriod, 5*time.Second)
+	assert.Equal(t, opt.HealthRetries, 3)
+	assert.Equal(t, opt.HealthOnFailure, healthcheck.OnFailureRestart)
+}
+
+func TestContainerCreateOptionsErrors(t *testing.T) {
+	p := &kubeutil.Pod{Metadata: kubeutil.ObjectMeta{Name: "web"}}
+
+	_, _, err := containerCreateOptions(types.ContainerCreateOptions{}, p, &kubeutil.Container{
+		Name:         "app",
+		Image:        "alpine",
+		VolumeMounts: []kubeutil.VolumeMount{{Name: "missing", MountPath: "/data"}},
+	}, nil, nil)
+	assert.ErrorContains(t, err, `volume "missing" not found`)
+
+	_, _, err = containerCreateOptions(types.ContainerCreateOptions{}, p, &kubeutil.Container{
+		Name:  "app",
+		Image: "alpine",
+		Env: []kubeutil.EnvVar{
+			{Name: "GREETING", ValueFrom: &kubeutil.EnvVarSource{ConfigMapKeyRef: &kubeutil.ConfigMapKeySelector{Name: "config", Key: "greeting"}}},
+		},
+	}, nil, nil)
+	assert.ErrorContains(t, err, `key "greeting" not found in config map "config"`)
+
+	_, _, err = containerCreateOptions(types.ContainerCreateOptions{}, p, &kubeutil.Container{
+		Name:      "app",
+		Image:     "alpine",
+		Resources: kubeutil.ResourceRequirements{Limits: map[string]string{"memory": "lots"}},
+	}, nil, nil)
+	assert.ErrorContains(t, err, "lots")
+}
+
+func TestProbeCommand(t *testing.T) {
+	ports := []kubeutil.ContainerPort{{Name: "db", ContainerPort: 5432}}
+
+	cmd, err := probeCommand(&kubeutil.Probe{Exec: &kubeutil.ExecAction{Command: []string{"cat", "/tmp/it's healthy"}}}, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, cmd, `cat '/tmp/it'\''s healthy'`)
+
+	cmd, err = probeCommand(&kubeutil.Probe{TCPSocket: &kubeutil.TCPSocketAction{Port: "db"}}, ports)
+	assert.NilError(t, err)
+	assert.Equal(t, cmd, "nc -z 127.0.0.1 5432")
+
+	_, err = probeCommand(&kubeutil.Probe{TCPSocket: &kubeutil.TCPSocketAction{Port: "http"}}, ports)
+	assert.ErrorContains(t, err, `probe port "http" not found`)
+
+	_, err = probeCommand(&kubeutil.Probe{}, nil)
+	assert.ErrorContains(t, err, "exec, httpGet or tcpSocket")
+}
+
+func TestPublishSpec(t *testing.T) {
+	assert.Equal(t, publishSpec(kubeutil.ContainerPort{HostPort: 8080, ContainerPort: 80}), "8080:80")
+	assert.Equal(t, publishSpec(kubeutil.ContainerPort{HostPort: 53, ContainerPort: 53, Protocol: "UDP", HostIP: "127.0.0.1"}), "127.0.0.1:53:53/udp")
+}
+
+func TestConfigMapFiles(t *testing.T) {
+	data := map[string]string{"a": "1", "b": "2"}
+
+	files, err := configMapFiles(data, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, files, data)
+
+	files, err = configMapFiles(data, []kubeutil.KeyToPath{{Key: "a", Path: "dir/a.conf"}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, files, map[string]string{"dir/a.conf": "1"})
+
+	_, err = configMapFiles(data, []kubeutil.KeyToPath{{Key: "a", Path: "../a.conf"}})
+	assert.ErrorContains(t, err, "invalid path")
+
+	_, err = configMapFiles(data, []kubeutil.KeyToPath{{Key: "c", Path: "c"}})
+	assert.ErrorContains(t, err, `key "c" not found`)
+}
+
+func TestContainerPorts(t *testing.T) {
+	ports := containerPorts(nat.PortMap{
+		"80/tcp": {{HostIP: "0.0.0.0", HostPort: "8080"}},
+		"53/udp": {{HostIP: "127.0.0.1", HostPort: "5353"}},
+	})
+	assert.DeepEqual(t, ports, []kubeutil.ContainerPort{
+		{ContainerPort: 53, HostPort: 5353, Protocol: "UDP", HostIP: "127.0.0.1"},
+		{ContainerPort: 80, HostPort: 8080, Protocol: "TCP"},
+	})
+	assert.Equal(t, restartPolicy("unless-stopped"), "Always")
+	assert.Equal(t, restartPolicy("on-failure"), "OnFailure")
+	assert.Equal(t, restartPolicy(""), "Never")
+}
@@ -0,0 +1,509 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/pod"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
+	"github.com/containerd/nerdctl/v2/pkg/consoleutil"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/defaults"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
+	"github.com/containerd/nerdctl/v2/pkg/kubeutil"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+// volumeSource is a pod volume resolved to a bind source or a named volume.
+type volumeSource struct {
+	source   string
+	readOnly bool
+}
+
+// Play creates and starts the pods of the manifest.
+// Each pod is run as a nerdctl pod, and each of its containers is created with `--pod`.
+func Play(ctx context.Context, client *containerd.Client, file string, options types.KubePlayOptions) error {
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(absFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	manifest, err := kubeutil.Parse(f)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	for _, p := range manifest.Pods {
+		if err := playPod(ctx, client, absFile, &p, manifest.ConfigMaps, options); err != nil {
+			return fmt.Errorf("failed to play pod %q: %w", p.Metadata.Name, err)
+		}
+		fmt.Fprintln(options.Stdout, p.Metadata.Name)
+	}
+	return nil
+}
+
+// playPod creates and starts the pod. The pod and its containers are removed when a container fails to be created or started.
+func playPod(ctx context.Context, client *containerd.Client, file string, p *kubeutil.Pod, configMaps map[string]map[string]string, options types.KubePlayOptions) (err error) {
+	podName := p.Metadata.Name
+	var publish []string
+	for _, c := range p.Spec.Containers {
+		for _, port := range c.Ports {
+			if port.HostPort == 0 {
+				continue
+			}
+			publish = append(publish, publishSpec(port))
+		}
+	}
+	podLabels := []string{labels.KubeFile + "=" + file}
+	for k, v := range p.Metadata.Labels {
+		podLabels = append(podLabels, k+"="+v)
+	}
+	if err := pod.Create(ctx, client, podName, types.PodCreateOptions{
+		Stdout:      io.Discard,
+		Stderr:      options.Stderr,
+		GOptions:    options.GOptions,
+		InfraImage:  options.InfraImage,
+		Hostname:    p.Spec.Hostname,
+		Publish:     publish,
+		Labels:      podLabels,
+		NerdctlCmd:  options.NerdctlCmd,
+		NerdctlArgs: options.NerdctlArgs,
+	}); err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		if rmErr := pod.Remove(ctx, client, []string{podName}, types.PodRemoveOptions{
+			Stdout:   io.Discard,
+			GOptions: options.GOptions,
+			Force:    true,
+		}); rmErr != nil {
+			log.G(ctx).WithError(rmErr).Warnf("failed to remove pod %q", podName)
+		}
+	}()
+
+	volumes, err := prepareVolumes(ctx, file, p, configMaps, options.GOptions)
+	if err != nil {
+		return err
+	}
+
+	base := types.ContainerCreateOptions{
+		Stdout:             io.Discard,
+		Stderr:             options.Stderr,
+		GOptions:           options.GOptions,
+		NerdctlCmd:         options.NerdctlCmd,
+		NerdctlArgs:        options.NerdctlArgs,
+		Detach:             true,
+		DetachKeys:         consoleutil.DefaultDetachKeys,
+		StopSignal:         "SIGTERM",
+		LogDriver:          "json-file",
+		Isolation:          "default",
+		Cgroupns:           defaults.CgroupnsMode(),
+		Runtime:            defaults.Runtime,
+		Systemd:            "false",
+		MemorySwappiness64: -1,
+		PidsLimit:          -1,
+		CPUQuota:           -1,
+		Label:              []string{labels.KubeFile + "=" + file},
+		ImagePullOpt: types.ImagePullOptions{
+			GOptions: options.GOptions,
+			Stdout:   options.Stderr,
+			Stderr:   options.Stderr,
+		},
+	}
+	for _, c := range p.Spec.Containers {
+		createOpt, args, err := containerCreateOptions(base, p, &c, configMaps, volumes)
+		if err != nil {
+			return fmt.Errorf("container %q: %w", c.Name, err)
+		}
+		netManager, err := containerutil.NewNetworkingOptionsManager(options.GOptions, types.NetworkOptions{NetworkSlice: []string{"none"}}, client)
+		if err != nil {
+			return err
+		}
+		ctr, gc, err := container.Create(ctx, client, args, netManager, createOpt)
+		if err != nil {
+			if gc != nil {
+				gc()
+			}
+			return fmt.Errorf("failed to create container %q: %w", createOpt.Name, err)
+		}
+		if err := container.Start(ctx, client, []string{ctr.ID()}, types.ContainerStartOptions{
+			Stdout:      io.Discard,
+			GOptions:    options.GOptions,
+			NerdctlCmd:  options.NerdctlCmd,
+			NerdctlArgs: options.NerdctlArgs,
+		}); err != nil {
+			return fmt.Errorf("failed to start container %q: %w", createOpt.Name, err)
+		}
+	}
+	return nil
+}
+
+// publishSpec returns the `--publish` value of the port, in the form of "[hostIP:]hostPort:containerPort[/protocol]".
+func publishSpec(port kubeutil.ContainerPort) string {
+	s := fmt.Sprintf("%d:%d", port.HostPort, port.ContainerPort)
+	if port.HostIP != "" {
+		s = port.HostIP + ":" + s
+	}
+	if port.Protocol != "" {
+		s += "/" + strings.ToLower(port.Protocol)
+	}
+	return s
+}
+
+// podVolumeName returns the name of the nerdctl volume backing the pod volume.
+func podVolumeName(podName, volName string) string {
+	return podName + "-" + volName
+}
+
+// prepareVolumes creates the volumes of the pod, and maps the pod volume names to their sources.
+func prepareVolumes(ctx context.Context, file string, p *kubeutil.Pod, configMaps map[string]map[string]string, globalOptions types.GlobalCommandOptions) (map[string]volumeSource, error) {
+	res := make(map[string]volumeSource)
+	kubeLabel := labels.KubeFile + "=" + file
+	for _, v := range p.Spec.Volumes {
+		switch {
+		case v.HostPath != nil:
+			if v.HostPath.Type == "DirectoryOrCreate" {
+				if err := os.MkdirAll(v.HostPath.Path, 0o755); err != nil {
+					return nil, err
+				}
+			}
+			res[v.Name] = volumeSource{source: v.HostPath.Path}
+		case v.EmptyDir != nil:
+			if v.EmptyDir.Medium != "" {
+				log.G(ctx).Warnf("volume %q: emptyDir medium %q is ignored", v.Name, v.EmptyDir.Medium)
+			}
+			name := podVolumeName(p.Metadata.Name, v.Name)
+			if _, err := volume.Create(name, types.VolumeCreateOptions{
+				Stdout:   io.Discard,
+				GOptions: globalOptions,
+				Labels:   []string{kubeLabel},
+			}); err != nil {
+				return nil, err
+			}
+			res[v.Name] = volumeSource{source: name}
+		case v.ConfigMap != nil:
+			data, ok := configMaps[v.ConfigMap.Name]
+			if !ok && (v.ConfigMap.Optional == nil || !*v.ConfigMap.Optional) {
+				return nil, fmt.Errorf("volume %q: config map %q not found", v.Name, v.ConfigMap.Name)
+			}
+			files, err := configMapFiles(data, v.ConfigMap.Items)
+			if err != nil {
+				return nil, fmt.Errorf("volume %q: %w", v.Name, err)
+			}
+			name := podVolumeName(p.Metadata.Name, v.Name)
+			vol, err := volume.Create(name, types.VolumeCreateOptions{
+				Stdout:   io.Discard,
+				GOptions: globalOptions,
+				Labels:   []string{kubeLabel},
+			})
+			if err != nil {
+				return nil, err
+			}
+			for path, content := range files {
+				dst := filepath.Join(vol.Mountpoint, path)
+				if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+					return nil, err
+				}
+				if err := os.WriteFile(dst, []byte(content), 0o644); err != nil {
+					return nil, err
+				}
+			}
+			res[v.Name] = volumeSource{source: name, readOnly: true}
+		case v.PersistentVolumeClaim != nil:
+			// Claims are not labeled, so that their data survives `nerdctl kube down`.
+			if _, err := volume.Create(v.PersistentVolumeClaim.ClaimName, types.VolumeCreateOptions{
+				Stdout:   io.Discard,
+				GOptions: globalOptions,
+			}); err != nil {
+				return nil, err
+			}
+			res[v.Name] = volumeSource{source: v.PersistentVolumeClaim.ClaimName, readOnly: v.PersistentVolumeClaim.ReadOnly}
+		default:
+			return nil, fmt.Errorf("volume %q: unsupported volume type (supported types: emptyDir, hostPath, configMap, persistentVolumeClaim)", v.Name)
+		}
+	}
+	return res, nil
+}
+
+// configMapFiles maps the relative paths of the files of a config map volume to their content.
+func configMapFiles(data map[string]string, items []kubeutil.KeyToPath) (map[string]string, error) {
+	files := make(map[string]string)
+	if len(items) == 0 {
+		for k, v := range data {
+			files[k] = v
+		}
+		return files, nil
+	}
+	for _, item := range items {
+		v, ok := data[item.Key]
+		if !ok {
+			return nil, fmt.Errorf("key %q not found in config map", item.Key)
+		}
+		path := filepath.Clean(item.Path)
+		if filepath.IsAbs(path) || path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("invalid path %q", item.Path)
+		}
+		files[path] = v
+	}
+	return files, nil
+}
+
+// containerCreateOptions translates the container of the pod into the options and the arguments of `nerdctl create`.
+func containerCreateOptions(base types.ContainerCreateOptions, p *kubeutil.Pod, c *kubeutil.Container, configMaps map[string]map[string]string, volumes map[string]volumeSource) (types.ContainerCreateOptions, []string, error) {
+	opt := base
+	opt.Name = p.Metadata.Name + "-" + c.Name
+	opt.Pod = p.Metadata.Name
+	opt.Label = append([]string(nil), base.Label...)
+	args := append([]string{c.Image}, c.Args...)
+	if len(c.Command) > 0 {
+		opt.EntrypointChanged = true
+		opt.Entrypoint = c.Command
+	}
+	opt.Workdir = c.WorkingDir
+	opt.TTY = c.TTY
+	opt.Interactive = c.Stdin && c.TTY
+
+	for _, e := range c.Env {
+		if e.ValueFrom == nil || e.ValueFrom.ConfigMapKeyRef == nil {
+			opt.Env = append(opt.Env, e.Name+"="+e.Value)
+			continue
+		}
+		ref := e.ValueFrom.ConfigMapKeyRef
+		optional := ref.Optional != nil && *ref.Optional
+		v, ok := configMaps[ref.Name][ref.Key]
+		if !ok {
+			if optional {
+				continue
+			}
+			return opt, nil, fmt.Errorf("env %q: key %q not found in config map %q", e.Name, ref.Key, ref.Name)
+		}
+		opt.Env = append(opt.Env, e.Name+"="+v)
+	}
+
+	if q, ok := c.Resources.Limits["cpu"]; ok {
+		cpus, err := kubeutil.ParseCPU(q)
+		if err != nil {
+			return opt, nil, err
+		}
+		opt.CPUs = cpus
+	}
+	if q, ok := c.Resources.Limits["memory"]; ok {
+		mem, err := kubeutil.ParseMemory(q)
+		if err != nil {
+			return opt, nil, err
+		}
+		opt.Memory = strconv.FormatInt(mem, 10)
+	}
+	if q, ok := c.Resources.Requests["cpu"]; ok {
+		cpus, err := kubeutil.ParseCPU(q)
+		if err != nil {
+			return opt, nil, err
+		}
+		opt.CPUShares = uint64(cpus * 1024)
+	}
+	if q, ok := c.Resources.Requests["memory"]; ok {
+		mem, err := kubeutil.ParseMemory(q)
+		if err != nil {
+			return opt, nil, err
+		}
+		opt.MemoryReservationChanged = true
+		opt.MemoryReservation = strconv.FormatInt(mem, 10)
+	}
+
+	var runAsUser, runAsGroup *int64
+	if p.Spec.SecurityContext != nil {
+		runAsUser, runAsGroup = p.Spec.SecurityContext.RunAsUser, p.Spec.SecurityContext.RunAsGroup
+	}
+	if sc := c.SecurityContext; sc != nil {
+		if sc.RunAsUser != nil {
+			runAsUser = sc.RunAsUser
+		}
+		if sc.RunAsGroup != nil {
+			runAsGroup = sc.RunAsGroup
+		}
+		opt.Privileged = sc.Privileged != nil && *sc.Privileged
+		opt.ReadOnly = sc.ReadOnlyRootFilesystem != nil && *sc.ReadOnlyRootFilesystem
+		if sc.AllowPrivilegeEscalation != nil && !*sc.AllowPrivilegeEscalation {
+			opt.SecurityOpt = append(opt.SecurityOpt, "no-new-privileges")
+		}
+		if sc.Capabilities != nil {
+			opt.CapAdd = sc.Capabilities.Add
+			opt.CapDrop = sc.Capabilities.Drop
+		}
+	}
+	if runAsUser != nil {
+		opt.User = strconv.FormatInt(*runAsUser, 10)
+		if runAsGroup != nil {
+			opt.User += ":" + strconv.FormatInt(*runAsGroup, 10)
+		}
+	} else if runAsGroup != nil {
+		return opt, nil, errors.New("runAsGroup requires runAsUser")
+	}
+
+	switch c.ImagePullPolicy {
+	case "Always":
+		opt.Pull = "always"
+	case "Never":
+		opt.Pull = "never"
+	default:
+		opt.Pull = "missing"
+	}
+	switch p.Spec.RestartPolicy {
+	case "", "Always":
+		opt.Restart = "always"
+	case "OnFailure":
+		opt.Restart = "on-failure"
+	case "Never":
+		opt.Restart = "no"
+	default:
+		return opt, nil, fmt.Errorf("unsupported restart policy %q", p.Spec.RestartPolicy)
+	}
+
+	for _, m := range c.VolumeMounts {
+		src, ok := volumes[m.Name]
+		if !ok {
+			return opt, nil, fmt.Errorf("volume %q not found", m.Name)
+		}
+		v := src.source + ":" + m.MountPath
+		if m.ReadOnly || src.readOnly {
+			v += ":ro"
+		}
+		opt.Volume = append(opt.Volume, v)
+	}
+
+	probe, onFailure := c.LivenessProbe, healthcheck.OnFailureRestart
+	if opt.Restart == "no" {
+		onFailure = healthcheck.OnFailureKill
+	}
+	if probe == nil {
+		probe, onFailure = c.ReadinessProbe, healthcheck.OnFailureNone
+	}
+	opt.HealthOnFailure = healthcheck.OnFailureNone
+	if probe != nil {
+		cmd, err := probeCommand(probe, c.Ports)
+		if err != nil {
+			return opt, nil, err
+		}
+		opt.HealthCmd = cmd
+		opt.HealthInterval = probeDuration(probe.PeriodSeconds, 10)
+		opt.HealthTimeout = probeDuration(probe.TimeoutSeconds, 1)
+		opt.HealthStartPeriod = probeDuration(probe.InitialDelaySeconds, 0)
+		opt.HealthRetries = 3
+		if probe.FailureThreshold > 0 {
+			opt.HealthRetries = int(probe.FailureThreshold)
+		}
+		opt.HealthOnFailure = onFailure
+	}
+	return opt, args, nil
+}
+
+// probeDuration returns the duration of a probe field, with the Kubernetes default when it is unset.
+func probeDuration(seconds, defaultSeconds int32) time.Duration {
+	if seconds <= 0 {
+		seconds = defaultSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// probeCommand returns the health check shell command equivalent to the probe.
+func probeCommand(probe *kubeutil.Probe, ports []kubeutil.ContainerPort) (string, error) {
+	switch {
+	case probe.Exec != nil:
+		if len(probe.Exec.Command) == 0 {
+			return "", errors.New("exec probe has no command")
+		}
+		quoted := make([]string, len(probe.Exec.Command))
+		for i, arg := range probe.Exec.Command {
+			quoted[i] = shellQuote(arg)
+		}
+		return strings.Join(quoted, " "), nil
+	case probe.HTTPGet != nil:
+		port, err := probePort(probe.HTTPGet.Port, ports)
+		if err != nil {
+			return "", err
+		}
+		host := probe.HTTPGet.Host
+		if host == "" {
+			host = "127.0.0.1"
+		}
+		scheme := strings.ToLower(probe.HTTPGet.Scheme)
+		if scheme == "" {
+			scheme = "http"
+		}
+		path := probe.HTTPGet.Path
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		url := shellQuote(fmt.Sprintf("%s://%s:%d%s", scheme, host, port, path))
+		return fmt.Sprintf("curl -fsS -o /dev/null %s || wget -q -O /dev/null %s", url, url), nil
+	case probe.TCPSocket != nil:
+		port, err := probePort(probe.TCPSocket.Port, ports)
+		if err != nil {
+			return "", err
+		}
+		host := probe.TCPSocket.Host
+		if host == "" {
+			host = "127.0.0.1"
+		}
+		return fmt.Sprintf("nc -z %s %d", shellQuote(host), port), nil
+	default:
+		return "", errors.New("probe must have one of exec, httpGet or tcpSocket")
+	}
+}
+
+// probePort resolves the port of a probe, which is either a number or the name of a container port.
+func probePort(port string, ports []kubeutil.ContainerPort) (int32, error) {
+	if n, err := strconv.ParseInt(port, 10, 32); err == nil {
+		return int32(n), nil
+	}
+	for _, p := range ports {
+		if p.Name != "" && p.Name == port {
+			return p.ContainerPort, nil
+		}
+	}
+	return 0, fmt.Errorf("probe port %q not found", port)
+}
+
+// shellQuote quotes s for POSIX shells.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=@%+,", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
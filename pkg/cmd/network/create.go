@@ -17,6 +17,7 @@
 package network
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"sort"
@@ -75,6 +76,7 @@ func Create(options types.NetworkCreateOptions, stdout io.Writer) error {
 		}
 		return err
 	}
+	recordEvent(context.TODO(), options.GOptions, net, "create", nil)
 	_, err = fmt.Fprintln(stdout, *net.NerdctlID)
 	return err
 }
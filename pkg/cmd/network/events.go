@@ -0,0 +1,62 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
+	"github.com/containerd/nerdctl/v2/pkg/netutil"
+)
+
+// recordEvent records the event of the network in the event journal, with the name of the network as attribute.
+func recordEvent(ctx context.Context, globalOptions types.GlobalCommandOptions, network *netutil.NetworkConfig, action string, attributes map[string]string) {
+	dataStore, err := clientutil.DataStore(globalOptions.DataRoot, globalOptions.Address)
+	if err != nil {
+		return
+	}
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	attributes["name"] = network.Name
+	var id string
+	if network.NerdctlID != nil {
+		id = *network.NerdctlID
+	}
+	eventutil.Record(ctx, dataStore, eventutil.JournalEvent{
+		Namespace:  globalOptions.Namespace,
+		Type:       eventutil.TypeNetwork,
+		Action:     action,
+		ID:         id,
+		Attributes: attributes,
+	})
+}
+
+// recordContainerEvent records the event of the network of the container, e.g. "connect".
+func recordContainerEvent(ctx context.Context, globalOptions types.GlobalCommandOptions, networkName, containerID, action string) {
+	e, err := netutil.NewCNIEnv(globalOptions.CNIPath, globalOptions.CNINetConfPath, netutil.WithNamespace(globalOptions.Namespace))
+	if err != nil {
+		return
+	}
+	network, err := e.NetworkByNameOrID(networkName)
+	if err != nil {
+		return
+	}
+	recordEvent(ctx, globalOptions, network, action, map[string]string{"container": containerID})
+}
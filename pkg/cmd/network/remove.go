@@ -71,6 +71,7 @@ func Remove(ctx context.Context, client *containerd.Client, options types.Networ
 		if err := cniEnv.RemoveNetwork(network); err != nil {
 			errs = append(errs, err)
 		} else {
+			recordEvent(ctx, options.GOptions, network, "destroy", nil)
 			result = append(result, req)
 		}
 	}
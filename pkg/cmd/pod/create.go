@@ -0,0 +1,97 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/identifiers"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/podutil"
+)
+
+// Create creates a pod by running its infra container.
+// The infra container holds the network, IPC and UTS namespaces joined by the containers created with `--pod`.
+func Create(ctx context.Context, client *containerd.Client, name string, options types.PodCreateOptions) error {
+	if err := identifiers.ValidateDockerCompat(name); err != nil {
+		return fmt.Errorf("invalid pod name: %w", err)
+	}
+	infras, err := podutil.Infras(ctx, client)
+	if err != nil {
+		return err
+	}
+	for _, infra := range infras {
+		l, err := infra.Labels(ctx)
+		if err != nil {
+			return err
+		}
+		if l[labels.Pod] == name {
+			return fmt.Errorf("pod %s already exists", name)
+		}
+	}
+	if options.NerdctlCmd == "" {
+		return errors.New("cannot create the infra container: the nerdctl command is unknown")
+	}
+
+	hostname := options.Hostname
+	if hostname == "" {
+		hostname = name
+	}
+	image := options.InfraImage
+	if image == "" {
+		image = podutil.DefaultInfraImage
+	}
+	args := []string{
+		"run", "-d",
+		"--name", podutil.InfraContainerName(name),
+		"--pod", name, "--pod-infra",
+		"--ipc", "shareable",
+		"--hostname", hostname,
+	}
+	for _, p := range options.Publish {
+		args = append(args, "--publish", p)
+	}
+	for _, l := range options.Labels {
+		args = append(args, "--label", l)
+	}
+	for _, n := range options.Networks {
+		args = append(args, "--network", n)
+	}
+	for _, h := range options.AddHost {
+		args = append(args, "--add-host", h)
+	}
+	for _, d := range options.DNSServers {
+		args = append(args, "--dns", d)
+	}
+	args = append(args, image)
+
+	cmd := exec.CommandContext(ctx, options.NerdctlCmd, append(options.NerdctlArgs, args...)...)
+	cmd.Stdout = options.Stdout
+	cmd.Stderr = options.Stderr
+	log.G(ctx).Debugf("Running %v", cmd.Args)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run the infra container of pod %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/errdefs"
+
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/podutil"
+)
+
+// Pod is the inspection result of a pod, as printed by `nerdctl pod inspect`.
+type Pod struct {
+	// ID is the ID of the infra container
+	ID       string
+	Name     string
+	Created  time.Time
+	State    string
+	Hostname string
+	Labels   map[string]string
+	// Containers contains the infra container first, then the other containers of the pod
+	Containers []PodContainer
+}
+
+// PodContainer is a container of a pod.
+type PodContainer struct {
+	ID    string
+	Name  string
+	State string
+}
+
+// inspect returns the details of the pod of the infra container.
+func inspect(ctx context.Context, client *containerd.Client, infra containerd.Container) (*Pod, error) {
+	info, err := infra.Info(ctx, containerd.WithoutRefreshedMetadata)
+	if err != nil {
+		return nil, err
+	}
+	pod := &Pod{
+		ID:       infra.ID(),
+		Name:     info.Labels[labels.Pod],
+		Created:  info.CreatedAt,
+		Hostname: info.Labels[labels.Hostname],
+		Labels:   make(map[string]string),
+	}
+	for k, v := range info.Labels {
+		if !strings.HasPrefix(k, labels.Prefix) {
+			pod.Labels[k] = v
+		}
+	}
+	infraState := containerutil.ContainerState(ctx, infra)
+	pod.Containers = append(pod.Containers, PodContainer{
+		ID:    infra.ID(),
+		Name:  info.Labels[labels.Name],
+		State: infraState,
+	})
+
+	members, err := podutil.Members(ctx, client, pod.Name)
+	if err != nil {
+		return nil, err
+	}
+	var memberStates []string
+	for _, c := range members {
+		l, err := c.Labels(ctx)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		state := containerutil.ContainerState(ctx, c)
+		memberStates = append(memberStates, state)
+		pod.Containers = append(pod.Containers, PodContainer{
+			ID:    c.ID(),
+			Name:  l[labels.Name],
+			State: state,
+		})
+	}
+	pod.State = podState(infraState, memberStates)
+	return pod, nil
+}
+
+// podState returns the state of the pod from the states of its containers.
+// A pod whose infra container is running is "Degraded" unless all of its containers are running.
+func podState(infraState string, memberStates []string) string {
+	switch infraState {
+	case string(containerd.Running):
+		for _, state := range memberStates {
+			if state != string(containerd.Running) {
+				return "Degraded"
+			}
+		}
+		return "Running"
+	case string(containerd.Created):
+		return "Created"
+	case "exited":
+		return "Exited"
+	case string(containerd.Paused):
+		return "Paused"
+	default:
+		return "Unknown"
+	}
+}
+
+// memberIDs returns the IDs of the containers of the pod, excluding its infra container.
+func memberIDs(ctx context.Context, client *containerd.Client, pod string) ([]string, error) {
+	members, err := podutil.Members(ctx, client, pod)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(members))
+	for _, c := range members {
+		ids = append(ids, c.ID())
+	}
+	return ids, nil
+}
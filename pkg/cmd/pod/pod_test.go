@@ -0,0 +1,33 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package pod
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestPodState(t *testing.T) {
+	assert.Equal(t, podState("running", nil), "Running")
+	assert.Equal(t, podState("running", []string{"running", "running"}), "Running")
+	assert.Equal(t, podState("running", []string{"running", "exited"}), "Degraded")
+	assert.Equal(t, podState("created", nil), "Created")
+	assert.Equal(t, podState("exited", []string{"exited"}), "Exited")
+	assert.Equal(t, podState("paused", []string{"running"}), "Paused")
+	assert.Equal(t, podState("unknown", nil), "Unknown")
+}
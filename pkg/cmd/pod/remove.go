@@ -0,0 +1,70 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	containerd "github.com/containerd/containerd/v2/client"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/podutil"
+)
+
+// Remove removes the containers of each pod, then its infra container.
+// Running pods are only removed with options.Force.
+func Remove(ctx context.Context, client *containerd.Client, reqs []string, options types.PodRemoveOptions) error {
+	for _, req := range reqs {
+		infra, err := podutil.Infra(ctx, client, req)
+		if err != nil {
+			return err
+		}
+		l, err := infra.Labels(ctx)
+		if err != nil {
+			return err
+		}
+		if !options.Force && containerutil.ContainerState(ctx, infra) == string(containerd.Running) {
+			return fmt.Errorf("pod %s is running. stop the pod first or force removal", req)
+		}
+		rmOpts := types.ContainerRemoveOptions{
+			Stdout:   io.Discard,
+			GOptions: options.GOptions,
+			Force:    options.Force,
+		}
+		ids, err := memberIDs(ctx, client, l[labels.Pod])
+		if err != nil {
+			return err
+		}
+		if len(ids) > 0 {
+			if err := container.Remove(ctx, client, ids, rmOpts); err != nil {
+				return err
+			}
+		}
+		if err := container.Remove(ctx, client, []string{infra.ID()}, rmOpts); err != nil {
+			return fmt.Errorf("failed to remove the infra container of pod %s: %w", req, err)
+		}
+		if _, err := fmt.Fprintln(options.Stdout, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	containerd "github.com/containerd/containerd/v2/client"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/podutil"
+)
+
+// Start starts the infra container of each pod, then the other containers of the pod.
+func Start(ctx context.Context, client *containerd.Client, reqs []string, options types.PodStartOptions) error {
+	startOpts := types.ContainerStartOptions{
+		Stdout:      io.Discard,
+		GOptions:    options.GOptions,
+		NerdctlCmd:  options.NerdctlCmd,
+		NerdctlArgs: options.NerdctlArgs,
+	}
+	for _, req := range reqs {
+		infra, err := podutil.Infra(ctx, client, req)
+		if err != nil {
+			return err
+		}
+		l, err := infra.Labels(ctx)
+		if err != nil {
+			return err
+		}
+		if err := container.Start(ctx, client, []string{infra.ID()}, startOpts); err != nil {
+			return fmt.Errorf("failed to start the infra container of pod %s: %w", req, err)
+		}
+		ids, err := memberIDs(ctx, client, l[labels.Pod])
+		if err != nil {
+			return err
+		}
+		if len(ids) > 0 {
+			if err := container.Start(ctx, client, ids, startOpts); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(options.Stdout, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
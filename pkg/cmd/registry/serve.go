@@ -0,0 +1,88 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/registry"
+)
+
+// Serve serves the images of the namespace as an OCI Distribution registry, until ctx is done.
+func Serve(ctx context.Context, client *containerd.Client, options types.RegistryServeOptions) error {
+	if (options.TLSCert == "") != (options.TLSKey == "") {
+		return errors.New("--tls-cert and --tls-key must be specified together")
+	}
+	registryOptions := registry.Options{
+		AllowPush: options.AllowPush,
+	}
+	if options.Htpasswd != "" {
+		credentials, err := registry.LoadHtpasswd(options.Htpasswd)
+		if err != nil {
+			return err
+		}
+		registryOptions.Credentials = credentials
+		if options.TLSCert == "" {
+			log.G(ctx).Warn("basic auth without TLS sends the passwords in clear text")
+		}
+	}
+	h, err := registry.NewHandler(ctx, client, registryOptions)
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", options.Listen)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	scheme, mode := "http", "read-only"
+	if options.TLSCert != "" {
+		scheme = "https"
+	}
+	if options.AllowPush {
+		mode = "read-write"
+	}
+	fmt.Fprintf(options.Stdout, "Serving namespace %q (%s) on %s://%s/v2/\n", options.GOptions.Namespace, mode, scheme, ln.Addr())
+	if options.TLSCert != "" {
+		err = srv.ServeTLS(ln, options.TLSCert, options.TLSKey)
+	} else {
+		err = srv.Serve(ln)
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
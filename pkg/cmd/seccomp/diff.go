@@ -0,0 +1,56 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package seccomp
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/seccomputil"
+)
+
+// Diff prints the differences between the syscalls allowed by two seccomp profiles, in the style of a unified diff:
+// the syscalls only allowed by the first profile are prefixed with "-", the ones only allowed by the second with "+".
+func Diff(oldPath, newPath string, options types.SeccompDiffOptions) error {
+	oldProfile, err := seccomputil.LoadProfile(oldPath)
+	if err != nil {
+		return err
+	}
+	newProfile, err := seccomputil.LoadProfile(newPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(options.Stdout, "--- %s\n+++ %s\n", oldPath, newPath)
+	if oldProfile.DefaultAction != newProfile.DefaultAction {
+		fmt.Fprintf(options.Stdout, "-defaultAction: %s\n+defaultAction: %s\n", oldProfile.DefaultAction, newProfile.DefaultAction)
+	}
+	oldSyscalls := seccomputil.AllowedSyscalls(oldProfile)
+	newSyscalls := seccomputil.AllowedSyscalls(newProfile)
+	for _, name := range oldSyscalls {
+		if !slices.Contains(newSyscalls, name) {
+			fmt.Fprintf(options.Stdout, "-%s\n", name)
+		}
+	}
+	for _, name := range newSyscalls {
+		if !slices.Contains(oldSyscalls, name) {
+			fmt.Fprintf(options.Stdout, "+%s\n", name)
+		}
+	}
+	return nil
+}
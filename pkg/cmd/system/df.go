@@ -0,0 +1,477 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/docker/go-units"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/snapshots"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/buildkitutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/builder"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
+	"github.com/containerd/nerdctl/v2/pkg/containerdutil"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+)
+
+// DiskUsageSummary is a row of `nerdctl system df`.
+type DiskUsageSummary struct {
+	Type        string
+	TotalCount  string
+	Active      string
+	Size        string
+	Reclaimable string
+}
+
+// ImageDiskUsage is a row of the images of `nerdctl system df -v`.
+type ImageDiskUsage struct {
+	Repository   string
+	Tag          string
+	ID           string
+	CreatedSince string
+	Size         string
+	SharedSize   string
+	UniqueSize   string
+	Containers   string
+}
+
+// ContainerDiskUsage is a row of the containers of `nerdctl system df -v`.
+type ContainerDiskUsage struct {
+	ID           string
+	Image        string
+	Size         string
+	CreatedSince string
+	Status       string
+	Names        string
+}
+
+// VolumeDiskUsage is a row of the volumes of `nerdctl system df -v`.
+type VolumeDiskUsage struct {
+	Name  string
+	Links string
+	Size  string
+}
+
+// BuildCacheDiskUsage is a row of the build cache of `nerdctl system df -v`.
+type BuildCacheDiskUsage struct {
+	ID            string
+	CacheType     string
+	Size          string
+	CreatedSince  string
+	LastUsedSince string
+	UsageCount    string
+	Shared        string
+}
+
+// DiskUsageVerbose is the output of `nerdctl system df -v`.
+type DiskUsageVerbose struct {
+	Images     []ImageDiskUsage
+	Containers []ContainerDiskUsage
+	Volumes    []VolumeDiskUsage
+	BuildCache []BuildCacheDiskUsage
+}
+
+// usageTotals is the accounting of a type of objects.
+type usageTotals struct {
+	total       int
+	active      int
+	size        int64
+	reclaimable int64
+}
+
+func (t usageTotals) summary(typ string) DiskUsageSummary {
+	reclaimable := humanSize(t.reclaimable)
+	if t.size > 0 {
+		reclaimable = fmt.Sprintf("%s (%d%%)", reclaimable, t.reclaimable*100/t.size)
+	}
+	return DiskUsageSummary{
+		Type:        typ,
+		TotalCount:  strconv.Itoa(t.total),
+		Active:      strconv.Itoa(t.active),
+		Size:        humanSize(t.size),
+		Reclaimable: reclaimable,
+	}
+}
+
+func humanSize(size int64) string {
+	return units.HumanSizeWithPrecision(float64(size), 3)
+}
+
+// imageRecord is the disk usage of an image, with the number of containers using it.
+type imageRecord struct {
+	name       string
+	id         string
+	createdAt  time.Time
+	usage      *imgutil.DiskUsage
+	containers int
+}
+
+// imageRecordSize is the size of an image, and the part of it shared with other images.
+type imageRecordSize struct {
+	size   int64
+	shared int64
+}
+
+// accountImages returns the size of each image, and the totals of the images.
+// The blobs and the layers shared by several images are accounted once,
+// and only the part of the images that is not used by the active images is reclaimable.
+func accountImages(records []imageRecord) ([]imageRecordSize, usageTotals) {
+	refs := map[string]int{}
+	for _, r := range records {
+		for k := range r.usage.Blobs {
+			refs["blob:"+k]++
+		}
+		for k := range r.usage.Layers {
+			refs["layer:"+k]++
+		}
+	}
+
+	var totals usageTotals
+	sizes := make([]imageRecordSize, len(records))
+	all := map[string]int64{}
+	active := map[string]int64{}
+	for i, r := range records {
+		totals.total++
+		if r.containers > 0 {
+			totals.active++
+		}
+		account := func(key string, size int64) {
+			sizes[i].size += size
+			if refs[key] > 1 {
+				sizes[i].shared += size
+			}
+			all[key] = size
+			if r.containers > 0 {
+				active[key] = size
+			}
+		}
+		for k, size := range r.usage.Blobs {
+			account("blob:"+k, size)
+		}
+		for k, size := range r.usage.Layers {
+			account("layer:"+k, size)
+		}
+	}
+	var activeSize int64
+	for _, size := range all {
+		totals.size += size
+	}
+	for _, size := range active {
+		activeSize += size
+	}
+	totals.reclaimable = totals.size - activeSize
+	return sizes, totals
+}
+
+// accountBuildCache returns the totals of the build cache records.
+// Similar to Docker, the records shared with the images are not accounted.
+func accountBuildCache(records []buildkitutil.UsageInfo) usageTotals {
+	var totals usageTotals
+	for _, r := range records {
+		totals.total++
+		if r.InUse {
+			totals.active++
+		}
+		if r.Shared {
+			continue
+		}
+		totals.size += r.Size
+		if !r.InUse {
+			totals.reclaimable += r.Size
+		}
+	}
+	return totals
+}
+
+// DiskUsage shows the disk usage of the images, the containers, the volumes and the build cache.
+func DiskUsage(ctx context.Context, client *containerd.Client, options types.SystemDfOptions) error {
+	var tmpl *template.Template
+	switch options.Format {
+	case "", "table":
+	case "raw", "wide":
+		return errors.New("unsupported format: \"raw\" and \"wide\"")
+	default:
+		var err error
+		tmpl, err = formatter.ParseTemplate(options.Format)
+		if err != nil {
+			return err
+		}
+	}
+
+	containers, err := client.Containers(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Containers
+	var (
+		containerTotals usageTotals
+		containerRows   []ContainerDiskUsage
+		imageUsers      = map[string]int{}
+		snapshotters    = map[string]snapshots.Snapshotter{}
+	)
+	for _, c := range containers {
+		info, err := c.Info(ctx, containerd.WithoutRefreshedMetadata)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		imageUsers[info.Image]++
+		var size int64
+		if info.SnapshotKey != "" {
+			snapshotter, ok := snapshotters[info.Snapshotter]
+			if !ok {
+				snapshotter = containerdutil.SnapshotService(client, info.Snapshotter)
+				snapshotters[info.Snapshotter] = snapshotter
+			}
+			rw, _, err := imgutil.ResourceUsage(ctx, snapshotter, info.SnapshotKey)
+			if err != nil {
+				log.G(ctx).WithError(err).Debugf("failed to get the size of container %s", c.ID())
+			}
+			size = rw.Size
+		}
+		status := formatter.ContainerStatus(ctx, c)
+		containerTotals.total++
+		containerTotals.size += size
+		if strings.HasPrefix(status, "Up") {
+			containerTotals.active++
+		} else {
+			containerTotals.reclaimable += size
+		}
+		containerRows = append(containerRows, ContainerDiskUsage{
+			ID:           c.ID()[:min(12, len(c.ID()))],
+			Image:        info.Image,
+			Size:         humanSize(size),
+			CreatedSince: formatter.TimeSinceInHuman(info.CreatedAt),
+			Status:       status,
+			Names:        containerutil.GetContainerName(info.Labels),
+		})
+	}
+
+	// Images
+	imgs, err := client.ListImages(ctx)
+	if err != nil {
+		return err
+	}
+	snapshotter := containerdutil.SnapshotService(client, options.GOptions.Snapshotter)
+	var records []imageRecord
+	for _, img := range imgs {
+		usage, err := imgutil.ImageDiskUsage(ctx, client.ContentStore(), snapshotter, img)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		records = append(records, imageRecord{
+			name:       img.Name(),
+			id:         img.Target().Digest.String(),
+			createdAt:  img.Metadata().CreatedAt,
+			usage:      usage,
+			containers: imageUsers[img.Name()],
+		})
+	}
+	imageSizes, imageTotals := accountImages(records)
+	imageRows := make([]ImageDiskUsage, len(records))
+	for i, r := range records {
+		repository, tag := imgutil.ParseRepoTag(r.name)
+		if repository == "" {
+			repository = "<none>"
+		}
+		if tag == "" {
+			tag = "<none>"
+		}
+		id := r.id
+		if _, encoded, ok := strings.Cut(id, ":"); ok {
+			id = encoded[:min(12, len(encoded))]
+		}
+		imageRows[i] = ImageDiskUsage{
+			Repository:   repository,
+			Tag:          tag,
+			ID:           id,
+			CreatedSince: formatter.TimeSinceInHuman(r.createdAt),
+			Size:         humanSize(imageSizes[i].size),
+			SharedSize:   humanSize(imageSizes[i].shared),
+			UniqueSize:   humanSize(imageSizes[i].size - imageSizes[i].shared),
+			Containers:   strconv.Itoa(r.containers),
+		}
+	}
+
+	// Volumes
+	volStore, err := volume.Store(options.GOptions.Namespace, options.GOptions.DataRoot, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	vols, err := volStore.List(true)
+	if err != nil {
+		return err
+	}
+	links, err := volume.UsedVolumes(ctx, containers)
+	if err != nil {
+		return err
+	}
+	var (
+		volumeTotals usageTotals
+		volumeRows   []VolumeDiskUsage
+	)
+	for _, v := range vols {
+		volumeTotals.total++
+		volumeTotals.size += v.Size
+		if links[v.Name] > 0 {
+			volumeTotals.active++
+		} else {
+			volumeTotals.reclaimable += v.Size
+		}
+		volumeRows = append(volumeRows, VolumeDiskUsage{
+			Name:  v.Name,
+			Links: strconv.Itoa(links[v.Name]),
+			Size:  humanSize(v.Size),
+		})
+	}
+	sort.Slice(volumeRows, func(i, j int) bool {
+		return volumeRows[i].Name < volumeRows[j].Name
+	})
+
+	// Build cache
+	var buildCache []buildkitutil.UsageInfo
+	if options.BuildKitHost != "" {
+		buildCache, err = builder.DiskUsage(ctx, options.BuildKitHost)
+		if err != nil {
+			log.G(ctx).WithError(err).Warn("failed to get the build cache usage")
+		}
+	}
+	buildCacheTotals := accountBuildCache(buildCache)
+	var buildCacheRows []BuildCacheDiskUsage
+	for _, r := range buildCache {
+		lastUsed := ""
+		if r.LastUsedAt != nil {
+			lastUsed = formatter.TimeSinceInHuman(*r.LastUsedAt)
+		}
+		buildCacheRows = append(buildCacheRows, BuildCacheDiskUsage{
+			ID:            r.ID[:min(12, len(r.ID))],
+			CacheType:     string(r.RecordType),
+			Size:          humanSize(r.Size),
+			CreatedSince:  formatter.TimeSinceInHuman(r.CreatedAt),
+			LastUsedSince: lastUsed,
+			UsageCount:    strconv.Itoa(r.UsageCount),
+			Shared:        strconv.FormatBool(r.Shared),
+		})
+	}
+
+	if options.Verbose {
+		verbose := DiskUsageVerbose{
+			Images:     imageRows,
+			Containers: containerRows,
+			Volumes:    volumeRows,
+			BuildCache: buildCacheRows,
+		}
+		if tmpl != nil {
+			return printTemplate(options.Stdout, tmpl, verbose)
+		}
+		return printDiskUsageVerbose(options.Stdout, verbose, buildCacheTotals.size)
+	}
+
+	summaries := []DiskUsageSummary{
+		imageTotals.summary("Images"),
+		containerTotals.summary("Containers"),
+		volumeTotals.summary("Local Volumes"),
+		buildCacheTotals.summary("Build Cache"),
+	}
+	if tmpl != nil {
+		for _, s := range summaries {
+			if err := printTemplate(options.Stdout, tmpl, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	w := tabwriter.NewWriter(options.Stdout, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tTOTAL\tACTIVE\tSIZE\tRECLAIMABLE")
+	for _, s := range summaries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Type, s.TotalCount, s.Active, s.Size, s.Reclaimable)
+	}
+	return w.Flush()
+}
+
+func printTemplate(w io.Writer, tmpl *template.Template, v any) error {
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, v); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w, b.String())
+	return err
+}
+
+func printDiskUsageVerbose(stdout io.Writer, verbose DiskUsageVerbose, buildCacheSize int64) error {
+	fmt.Fprintf(stdout, "Images space usage:\n\n")
+	w := tabwriter.NewWriter(stdout, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(w, "REPOSITORY\tTAG\tIMAGE ID\tCREATED\tSIZE\tSHARED SIZE\tUNIQUE SIZE\tCONTAINERS")
+	for _, r := range verbose.Images {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Repository, r.Tag, r.ID, r.CreatedSince, r.Size, r.SharedSize, r.UniqueSize, r.Containers)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "\nContainers space usage:\n\n")
+	w = tabwriter.NewWriter(stdout, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(w, "CONTAINER ID\tIMAGE\tSIZE\tCREATED\tSTATUS\tNAMES")
+	for _, r := range verbose.Containers {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", r.ID, r.Image, r.Size, r.CreatedSince, r.Status, r.Names)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "\nLocal Volumes space usage:\n\n")
+	w = tabwriter.NewWriter(stdout, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(w, "VOLUME NAME\tLINKS\tSIZE")
+	for _, r := range verbose.Volumes {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", r.Name, r.Links, r.Size)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "\nBuild cache usage: %s\n\n", humanSize(buildCacheSize))
+	w = tabwriter.NewWriter(stdout, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(w, "CACHE ID\tCACHE TYPE\tSIZE\tCREATED\tLAST USED\tUSAGE\tSHARED")
+	for _, r := range verbose.BuildCache {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.ID, r.CacheType, r.Size, r.CreatedSince, r.LastUsedSince, r.UsageCount, r.Shared)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,84 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/buildkitutil"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+)
+
+func TestAccountImages(t *testing.T) {
+	records := []imageRecord{
+		{
+			name: "base",
+			usage: &imgutil.DiskUsage{
+				Blobs:  map[string]int64{"sha256:index": 1, "sha256:layer1": 10},
+				Layers: map[string]int64{"chain1": 100},
+			},
+			containers: 1,
+		},
+		{
+			name: "app",
+			usage: &imgutil.DiskUsage{
+				Blobs:  map[string]int64{"sha256:app": 2, "sha256:layer1": 10, "sha256:layer2": 20},
+				Layers: map[string]int64{"chain1": 100, "chain2": 200},
+			},
+		},
+	}
+	sizes, totals := accountImages(records)
+
+	assert.Equal(t, sizes[0].size, int64(111))
+	assert.Equal(t, sizes[0].shared, int64(110))
+	assert.Equal(t, sizes[1].size, int64(332))
+	assert.Equal(t, sizes[1].shared, int64(110))
+
+	assert.Equal(t, totals.total, 2)
+	assert.Equal(t, totals.active, 1)
+	// the shared blob and layer are accounted once
+	assert.Equal(t, totals.size, int64(333))
+	// only the part of "app" that is not used by "base" is reclaimable
+	assert.Equal(t, totals.reclaimable, int64(222))
+}
+
+func TestAccountBuildCache(t *testing.T) {
+	totals := accountBuildCache([]buildkitutil.UsageInfo{
+		{ID: "a", Size: 10, InUse: true},
+		{ID: "b", Size: 20},
+		{ID: "c", Size: 40, Shared: true},
+	})
+	assert.Equal(t, totals.total, 3)
+	assert.Equal(t, totals.active, 1)
+	assert.Equal(t, totals.size, int64(30))
+	assert.Equal(t, totals.reclaimable, int64(20))
+}
+
+func TestUsageTotalsSummary(t *testing.T) {
+	s := usageTotals{total: 3, active: 1, size: 2000, reclaimable: 500}.summary("Images")
+	assert.Equal(t, s.Type, "Images")
+	assert.Equal(t, s.TotalCount, "3")
+	assert.Equal(t, s.Active, "1")
+	assert.Equal(t, s.Size, "2kB")
+	assert.Equal(t, s.Reclaimable, "500B (25%)")
+
+	s = usageTotals{}.summary("Build Cache")
+	assert.Equal(t, s.Size, "0B")
+	assert.Equal(t, s.Reclaimable, "0B")
+}
@@ -28,23 +28,37 @@ import (
 
 	_ "github.com/containerd/containerd/api/events" // Register grpc event types
 	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/containers"
 	"github.com/containerd/containerd/v2/core/events"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
 	"github.com/containerd/log"
 	"github.com/containerd/typeurl/v2"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
 	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+	"github.com/containerd/nerdctl/v2/pkg/timestamp"
 )
 
+// journalPollInterval is the interval between the reads of the event journal while streaming.
+const journalPollInterval = 500 * time.Millisecond
+
 // EventOut contains information about an event.
 type EventOut struct {
 	Timestamp time.Time
 	ID        string
 	Namespace string
 	Topic     string
-	Action    Action
-	Event     string
-	Labels    map[string]string
+	// Type is the type of the object, e.g. "container"
+	Type       string
+	Action     Action
+	Event      string
+	Labels     map[string]string
+	Attributes map[string]string `json:",omitempty"`
 }
 
 type Action string
@@ -52,9 +66,25 @@ type Action string
 const (
 	START   Action = "start"
 	UNKNOWN Action = "unknown"
+
+	CREATE  Action = "create"
+	DESTROY Action = "destroy"
+	DIE     Action = "die"
+	OOM     Action = "oom"
+	PAUSE   Action = "pause"
+	UNPAUSE Action = "unpause"
+	DELETE  Action = "delete"
 )
 
-var actions = [...]Action{START, UNKNOWN}
+// actions are the Docker-compatible actions that can be filtered with "event=".
+// The actions that are not mapped from the containerd topics are only recorded in the event journal,
+// which is polled every journalPollInterval while streaming.
+var actions = [...]Action{
+	START, UNKNOWN, CREATE, DESTROY, DIE, OOM, PAUSE, UNPAUSE, DELETE,
+	"stop", "restart", "kill", "rename", "health_status",
+	"connect", "disconnect",
+	"pull", "push", "tag", "untag", "load",
+}
 
 func isAction(action string) bool {
 	action = strings.ToLower(action)
@@ -68,7 +98,36 @@ func isAction(action string) bool {
 	return false
 }
 
+// topicActions maps the containerd topics to the Docker actions.
+var topicActions = map[string]Action{
+	"/containers/create": CREATE,
+	"/containers/delete": DESTROY,
+	"/tasks/exit":        DIE,
+	"/tasks/oom":         OOM,
+	"/tasks/paused":      PAUSE,
+	"/tasks/resumed":     UNPAUSE,
+	"/images/delete":     DELETE,
+
+	healthcheck.OnFailureEventTopic: "health_status",
+}
+
+// publishedByContainerd returns whether the journal event is also published by containerd, e.g. "start" or "die".
+func publishedByContainerd(e *eventutil.JournalEvent) bool {
+	if e.Type == eventutil.TypeContainer && e.Action == string(START) {
+		return true
+	}
+	for topic, action := range topicActions {
+		if TopicToType(topic) == e.Type && string(action) == e.Action {
+			return true
+		}
+	}
+	return false
+}
+
 func TopicToAction(topic string) Action {
+	if action, ok := topicActions[strings.ToLower(topic)]; ok {
+		return action
+	}
 	if strings.Contains(strings.ToLower(topic), string(START)) {
 		return START
 	}
@@ -76,9 +135,49 @@ func TopicToAction(topic string) Action {
 	return UNKNOWN
 }
 
+// TopicToType returns the type of the object of the containerd topic, e.g. "container" for "/tasks/start".
+func TopicToType(topic string) string {
+	kind, _, _ := strings.Cut(strings.TrimPrefix(topic, "/"), "/")
+	switch kind {
+	case "containers", "tasks":
+		return eventutil.TypeContainer
+	case "images":
+		return eventutil.TypeImage
+	case "networks":
+		return eventutil.TypeNetwork
+	case "volumes":
+		return eventutil.TypeVolume
+	}
+	return strings.TrimSuffix(kind, "s")
+}
+
 // EventFilter for filtering events
 type EventFilter func(*EventOut) bool
 
+// matchImage returns whether the image reference matches the filter value,
+// both being normalized, e.g. "alpine" matches "docker.io/library/alpine:latest".
+func matchImage(ref, filterValue string) bool {
+	if ref == "" {
+		return false
+	}
+	if ref == filterValue {
+		return true
+	}
+	parsedRef, err := referenceutil.Parse(ref)
+	if err != nil {
+		return false
+	}
+	parsedValue, err := referenceutil.Parse(filterValue)
+	if err != nil {
+		return false
+	}
+	if parsedRef.String() == parsedValue.String() {
+		return true
+	}
+	// "alpine" matches any tag of alpine
+	return parsedValue.ExplicitTag == "" && parsedValue.Digest == "" && parsedRef.Name() == parsedValue.Name()
+}
+
 // generateEventFilter is similar to Podman implementation:
 // https://github.com/containers/podman/blob/189d862d54b3824c74bf7474ddfed6de69ec5a09/libpod/events/filters.go#L11
 func generateEventFilter(filter, filterValue string) (func(e *EventOut) bool, error) {
@@ -116,6 +215,43 @@ func generateEventFilter(filter, filterValue string) (func(e *EventOut) bool, er
 			}
 			return got == value
 		}, nil
+	case "CONTAINER":
+		// Similar to Docker, the ID and the name can be abbreviated
+		return func(e *EventOut) bool {
+			if e.Type != eventutil.TypeContainer || e.ID == "" {
+				return false
+			}
+			return strings.HasPrefix(e.ID, filterValue) || (e.Attributes["name"] != "" && strings.HasPrefix(e.Attributes["name"], filterValue))
+		}, nil
+	case "IMAGE":
+		return func(e *EventOut) bool {
+			switch e.Type {
+			case eventutil.TypeContainer:
+				return matchImage(e.Attributes["image"], filterValue)
+			case eventutil.TypeImage:
+				return matchImage(e.ID, filterValue) || matchImage(e.Attributes["name"], filterValue)
+			}
+			return false
+		}, nil
+	case "TYPE":
+		return func(e *EventOut) bool {
+			return strings.EqualFold(e.Type, filterValue)
+		}, nil
+	case "NETWORK":
+		return func(e *EventOut) bool {
+			if e.Type != eventutil.TypeNetwork {
+				return false
+			}
+			return (e.ID != "" && strings.HasPrefix(e.ID, filterValue)) || e.Attributes["name"] == filterValue
+		}, nil
+	case "VOLUME":
+		return func(e *EventOut) bool {
+			return e.Type == eventutil.TypeVolume && e.ID == filterValue
+		}, nil
+	case "NAMESPACE":
+		return func(e *EventOut) bool {
+			return e.Namespace == filterValue
+		}, nil
 	}
 
 	return nil, fmt.Errorf("%s is an invalid or unsupported filter", filter)
@@ -170,10 +306,164 @@ func generateEventFilters(filters []string) (map[string][]EventFilter, error) {
 	return filterMap, nil
 }
 
+// parseEventTime parses the value of --since and --until, e.g. "10m" or "2006-01-02T15:04:05".
+func parseEventTime(value string, now time.Time) (time.Time, error) {
+	ts, err := timestamp.GetTimestamp(value, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	sec, nsec, err := timestamp.ParseTimestamps(ts, 0)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(sec, nsec), nil
+}
+
+// needsContainer returns whether the filters need the labels, the name or the image of the containers.
+func needsContainer(filters []string) bool {
+	for _, f := range filters {
+		key, _, _ := strings.Cut(f, "=")
+		switch strings.ToUpper(key) {
+		case "LABEL", "CONTAINER", "IMAGE":
+			return true
+		}
+	}
+	return false
+}
+
+// containerCache remembers the containers of the events, so that the events of the removed containers
+// (e.g., "destroy") still have the labels, the name and the image of the container.
+type containerCache map[string]containers.Container
+
+// newContainerCache returns a containerCache filled with the existing containers of the namespace of ctx.
+func newContainerCache(ctx context.Context, client *containerd.Client) containerCache {
+	cache := containerCache{}
+	namespace, err := namespaces.NamespaceRequired(ctx)
+	if err != nil {
+		return cache
+	}
+	list, err := client.ContainerService().List(ctx)
+	if err != nil {
+		log.G(ctx).WithError(err).Warn("failed to list the containers")
+		return cache
+	}
+	for _, c := range list {
+		cache[namespace+"/"+c.ID] = c
+	}
+	return cache
+}
+
+// fillContainerInfo sets the labels, and the name and image attributes of the container event.
+// When the container is already removed, the container remembered by the cache is used.
+func fillContainerInfo(ctx context.Context, client *containerd.Client, cache containerCache, eOut *EventOut) {
+	key := eOut.Namespace + "/" + eOut.ID
+	container, err := client.ContainerService().Get(namespaces.WithNamespace(ctx, eOut.Namespace), eOut.ID)
+	if err == nil {
+		cache[key] = container
+	} else if cached, ok := cache[key]; ok {
+		container = cached
+	} else {
+		log.G(ctx).WithError(err).WithField("containerID", eOut.ID).Debug("failed to retrieve container labels")
+		return
+	}
+	if eOut.Action == DESTROY {
+		delete(cache, key)
+	}
+	eOut.Labels = container.Labels
+	if eOut.Attributes == nil {
+		eOut.Attributes = map[string]string{}
+	}
+	if _, ok := eOut.Attributes["name"]; !ok && container.Labels[labels.Name] != "" {
+		eOut.Attributes["name"] = container.Labels[labels.Name]
+	}
+	if _, ok := eOut.Attributes["image"]; !ok {
+		eOut.Attributes["image"] = container.Image
+	}
+}
+
+// envelopeToEventOut converts the containerd event. It returns nil if the event cannot be decoded.
+// The container information is only set when cache is not nil.
+func envelopeToEventOut(ctx context.Context, client *containerd.Client, e *events.Envelope, cache containerCache) *EventOut {
+	var out []byte
+	var id string
+	attributes := map[string]string{}
+	if e.Event != nil {
+		v, err := typeurl.UnmarshalAny(e.Event)
+		if err != nil {
+			log.G(ctx).WithError(err).Warn("cannot unmarshal an event from Any")
+			return nil
+		}
+		out, err = json.Marshal(v)
+		if err != nil {
+			log.G(ctx).WithError(err).Warn("cannot marshal Any into JSON")
+			return nil
+		}
+	}
+	var data map[string]interface{}
+	err := json.Unmarshal(out, &data)
+	if err != nil {
+		log.G(ctx).WithError(err).Warn("cannot marshal Any into JSON")
+	} else {
+		if containerID, ok := data["container_id"].(string); ok {
+			id = containerID
+		} else if containerID, ok := data["id"].(string); ok && strings.HasPrefix(e.Topic, "/containers/") {
+			// The events of the containers themselves, e.g. "/containers/delete"
+			id = containerID
+		}
+		if name, ok := data["name"].(string); ok {
+			attributes["name"] = name
+		}
+	}
+	eOut := &EventOut{
+		Timestamp:  e.Timestamp,
+		ID:         id,
+		Namespace:  e.Namespace,
+		Topic:      e.Topic,
+		Type:       TopicToType(e.Topic),
+		Action:     TopicToAction(e.Topic),
+		Event:      string(out),
+		Labels:     map[string]string{},
+		Attributes: attributes,
+	}
+	if cache != nil && id != "" {
+		fillContainerInfo(ctx, client, cache, eOut)
+	}
+	return eOut
+}
+
+// journalEventToEventOut converts the event recorded in the event journal.
+// The container information is only set when cache is not nil.
+func journalEventToEventOut(ctx context.Context, client *containerd.Client, e *eventutil.JournalEvent, cache containerCache) *EventOut {
+	out, err := json.Marshal(e)
+	if err != nil {
+		log.G(ctx).WithError(err).Warn("cannot marshal the journal event into JSON")
+	}
+	attributes := map[string]string{}
+	for k, v := range e.Attributes {
+		attributes[k] = v
+	}
+	eOut := &EventOut{
+		Timestamp:  e.Timestamp,
+		ID:         e.ID,
+		Namespace:  e.Namespace,
+		Topic:      e.Topic(),
+		Type:       e.Type,
+		Action:     Action(e.Action),
+		Event:      string(out),
+		Labels:     map[string]string{},
+		Attributes: attributes,
+	}
+	if cache != nil && e.Type == eventutil.TypeContainer && e.ID != "" {
+		fillContainerInfo(ctx, client, cache, eOut)
+	}
+	return eOut
+}
+
 // Events is from https://github.com/containerd/containerd/blob/v1.4.3/cmd/ctr/commands/events/events.go
+//
+// With --since, the events recorded in the event journal are replayed before the live events are streamed.
+// With --until, the command returns once the time is reached.
 func Events(ctx context.Context, client *containerd.Client, options types.SystemEventsOptions) error {
-	eventsClient := client.EventService()
-	eventsCh, errCh := eventsClient.Subscribe(ctx)
 	var tmpl *template.Template
 	switch options.Format {
 	case "":
@@ -187,83 +477,142 @@ func Events(ctx context.Context, client *containerd.Client, options types.System
 			return err
 		}
 	}
-	labelFilterEnabled := false
-	for _, f := range options.Filters {
-		if strings.HasPrefix(strings.ToLower(f), "label=") {
-			labelFilterEnabled = true
-			break
+	return WatchEvents(ctx, client, options, func(eOut *EventOut) error {
+		if tmpl != nil {
+			var b bytes.Buffer
+			if err := tmpl.Execute(&b, eOut); err != nil {
+				return err
+			}
+			_, err := fmt.Fprintln(options.Stdout, b.String()+"\n")
+			return err
+		}
+		_, err := fmt.Fprintln(
+			options.Stdout,
+			eOut.Timestamp,
+			eOut.Namespace,
+			eOut.Topic,
+			eOut.Event,
+		)
+		return err
+	})
+}
+
+// WatchEvents calls handler for each event matching options.Filters, as `nerdctl events` prints them.
+// options.Stdout and options.Format are ignored.
+func WatchEvents(ctx context.Context, client *containerd.Client, options types.SystemEventsOptions, handler func(*EventOut) error) error {
+	now := time.Now()
+	var since, until time.Time
+	if options.Since != "" {
+		var err error
+		if since, err = parseEventTime(options.Since, now); err != nil {
+			return fmt.Errorf("invalid value for --since: %w", err)
+		}
+	}
+	if options.Until != "" {
+		var err error
+		if until, err = parseEventTime(options.Until, now); err != nil {
+			return fmt.Errorf("invalid value for --until: %w", err)
 		}
 	}
+	var cache containerCache
+	if needsContainer(options.Filters) {
+		cache = newContainerCache(ctx, client)
+	}
 	filterMap, err := generateEventFilters(options.Filters)
 	if err != nil {
 		return err
 	}
+	printEvent := func(eOut *EventOut) error {
+		if !applyFilters(eOut, filterMap) {
+			return nil
+		}
+		return handler(eOut)
+	}
+
+	dataStore, err := clientutil.DataStore(options.GOptions.DataRoot, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+
+	// Subscribe before replaying the journal, so that no event is missed in between
+	stream := until.IsZero() || until.After(now)
+	var (
+		eventsCh <-chan *events.Envelope
+		errCh    <-chan error
+		tail     *eventutil.JournalTail
+	)
+	if stream {
+		subCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		eventsCh, errCh = client.EventService().Subscribe(subCtx)
+		// The events that containerd does not publish are only recorded in the journal
+		if tail, err = eventutil.TailJournal(dataStore); err != nil {
+			return err
+		}
+	}
+
+	if !since.IsZero() {
+		replayUntil := now
+		if !until.IsZero() && until.Before(now) {
+			replayUntil = until
+		}
+		journal, err := eventutil.ReadJournal(dataStore, since, replayUntil)
+		if err != nil {
+			return err
+		}
+		for i := range journal {
+			if err := printEvent(journalEventToEventOut(ctx, client, &journal[i], cache)); err != nil {
+				return err
+			}
+		}
+	}
+	if !stream {
+		return nil
+	}
+
+	var untilCh <-chan time.Time
+	if !until.IsZero() {
+		timer := time.NewTimer(time.Until(until))
+		defer timer.Stop()
+		untilCh = timer.C
+	}
+	journalTicker := time.NewTicker(journalPollInterval)
+	defer journalTicker.Stop()
 	for {
 		var e *events.Envelope
 		select {
 		case e = <-eventsCh:
 		case err := <-errCh:
 			return err
-		}
-		if e != nil {
-			var out []byte
-			var id string
-			labels := map[string]string{}
-			if e.Event != nil {
-				v, err := typeurl.UnmarshalAny(e.Event)
-				if err != nil {
-					log.G(ctx).WithError(err).Warn("cannot unmarshal an event from Any")
-					continue
-				}
-				out, err = json.Marshal(v)
-				if err != nil {
-					log.G(ctx).WithError(err).Warn("cannot marshal Any into JSON")
-					continue
-				}
-			}
-			var data map[string]interface{}
-			err := json.Unmarshal(out, &data)
+		case <-untilCh:
+			return nil
+		case <-journalTicker.C:
+			journal, err := tail.Next()
 			if err != nil {
-				log.G(ctx).WithError(err).Warn("cannot marshal Any into JSON")
-			} else {
-				_, ok := data["container_id"]
-				if ok {
-					if containerID, ok := data["container_id"].(string); ok {
-						id = containerID
-					}
-				}
+				log.G(ctx).WithError(err).Warn("failed to read the event journal")
+				continue
 			}
-			if labelFilterEnabled && id != "" {
-				if container, err := client.ContainerService().Get(ctx, id); err != nil {
-					log.G(ctx).WithError(err).WithField("containerID", id).Debug("failed to retrieve container labels")
-				} else {
-					labels = container.Labels
+			for i := range journal {
+				je := &journal[i]
+				// Already replayed, or streamed from containerd
+				if (!since.IsZero() && !je.Timestamp.After(now)) || publishedByContainerd(je) {
+					continue
 				}
-			}
-			eOut := EventOut{e.Timestamp, id, e.Namespace, e.Topic, TopicToAction(e.Topic), string(out), labels}
-			match := applyFilters(&eOut, filterMap)
-			if match {
-				if tmpl != nil {
-					var b bytes.Buffer
-					if err := tmpl.Execute(&b, eOut); err != nil {
-						return err
-					}
-					if _, err := fmt.Fprintln(options.Stdout, b.String()+"\n"); err != nil {
-						return err
-					}
-				} else {
-					if _, err := fmt.Fprintln(
-						options.Stdout,
-						e.Timestamp,
-						e.Namespace,
-						e.Topic,
-						string(out),
-					); err != nil {
-						return err
-					}
+				if err := printEvent(journalEventToEventOut(ctx, client, je, cache)); err != nil {
+					return err
 				}
 			}
-
+			continue
+		}
+		if e == nil {
+			continue
+		}
+		eOut := envelopeToEventOut(ctx, client, e, cache)
+		if eOut == nil {
+			continue
+		}
+		if err := printEvent(eOut); err != nil {
+			return err
 		}
 	}
 }
@@ -0,0 +1,135 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	apievents "github.com/containerd/containerd/api/events"
+	"github.com/containerd/containerd/v2/core/events"
+	"github.com/containerd/typeurl/v2"
+
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
+)
+
+func TestTopicToAction(t *testing.T) {
+	assert.Equal(t, TopicToAction("/tasks/start"), START)
+	assert.Equal(t, TopicToAction("/tasks/exit"), DIE)
+	assert.Equal(t, TopicToAction("/containers/create"), CREATE)
+	assert.Equal(t, TopicToAction("/containers/delete"), DESTROY)
+	assert.Equal(t, TopicToAction("/snapshot/prepare"), UNKNOWN)
+}
+
+func TestTopicToType(t *testing.T) {
+	assert.Equal(t, TopicToType("/tasks/start"), eventutil.TypeContainer)
+	assert.Equal(t, TopicToType("/containers/create"), eventutil.TypeContainer)
+	assert.Equal(t, TopicToType("/images/create"), eventutil.TypeImage)
+	assert.Equal(t, TopicToType("/snapshot/prepare"), "snapshot")
+}
+
+func TestEnvelopeToEventOutID(t *testing.T) {
+	for topic, event := range map[string]any{
+		"/containers/delete": &apievents.ContainerDelete{ID: "foo"},
+		"/tasks/exit":        &apievents.TaskExit{ContainerID: "foo", ID: "exec"},
+	} {
+		a, err := typeurl.MarshalAny(event)
+		assert.NilError(t, err)
+		eOut := envelopeToEventOut(context.Background(), nil, &events.Envelope{Topic: topic, Event: a}, nil)
+		assert.Equal(t, eOut.ID, "foo", topic)
+	}
+}
+
+func TestPublishedByContainerd(t *testing.T) {
+	assert.Assert(t, publishedByContainerd(&eventutil.JournalEvent{Type: eventutil.TypeContainer, Action: "start"}))
+	assert.Assert(t, publishedByContainerd(&eventutil.JournalEvent{Type: eventutil.TypeContainer, Action: "destroy"}))
+	assert.Assert(t, publishedByContainerd(&eventutil.JournalEvent{Type: eventutil.TypeImage, Action: "delete"}))
+	assert.Assert(t, !publishedByContainerd(&eventutil.JournalEvent{Type: eventutil.TypeContainer, Action: "stop"}))
+	assert.Assert(t, !publishedByContainerd(&eventutil.JournalEvent{Type: eventutil.TypeImage, Action: "pull"}))
+	assert.Assert(t, !publishedByContainerd(&eventutil.JournalEvent{Type: eventutil.TypeNetwork, Action: "create"}))
+	assert.Assert(t, !publishedByContainerd(&eventutil.JournalEvent{Type: eventutil.TypeVolume, Action: "destroy"}))
+}
+
+func TestEventFilters(t *testing.T) {
+	container := &EventOut{
+		ID:        "4a5b6c7d8e9f",
+		Namespace: "default",
+		Type:      eventutil.TypeContainer,
+		Action:    DIE,
+		Attributes: map[string]string{
+			"name":  "web",
+			"image": "docker.io/library/nginx:alpine",
+		},
+	}
+	image := &EventOut{
+		ID:         "docker.io/library/alpine:latest",
+		Namespace:  "default",
+		Type:       eventutil.TypeImage,
+		Action:     "pull",
+		Attributes: map[string]string{"name": "docker.io/library/alpine:latest"},
+	}
+	network := &EventOut{
+		ID:         "0123456789ab",
+		Namespace:  "other",
+		Type:       eventutil.TypeNetwork,
+		Action:     "connect",
+		Attributes: map[string]string{"name": "frontend", "container": "4a5b6c7d8e9f"},
+	}
+	volume := &EventOut{
+		ID:        "data",
+		Namespace: "default",
+		Type:      eventutil.TypeVolume,
+		Action:    "destroy",
+	}
+
+	testCases := []struct {
+		filters []string
+		matches []*EventOut
+	}{
+		{[]string{"container=4a5b"}, []*EventOut{container}},
+		{[]string{"container=web"}, []*EventOut{container}},
+		{[]string{"container=db"}, nil},
+		{[]string{"image=nginx"}, []*EventOut{container}},
+		{[]string{"image=nginx:alpine"}, []*EventOut{container}},
+		{[]string{"image=nginx:latest"}, nil},
+		{[]string{"image=alpine"}, []*EventOut{image}},
+		{[]string{"type=network"}, []*EventOut{network}},
+		{[]string{"type=volume", "type=image"}, []*EventOut{image, volume}},
+		{[]string{"network=frontend"}, []*EventOut{network}},
+		{[]string{"network=0123"}, []*EventOut{network}},
+		{[]string{"volume=data"}, []*EventOut{volume}},
+		{[]string{"namespace=other"}, []*EventOut{network}},
+		{[]string{"namespace=default", "event=die"}, []*EventOut{container}},
+		{[]string{"event=destroy", "type=container"}, nil},
+	}
+	for _, tc := range testCases {
+		filterMap, err := generateEventFilters(tc.filters)
+		assert.NilError(t, err)
+		var matches []*EventOut
+		for _, e := range []*EventOut{container, image, network, volume} {
+			if applyFilters(e, filterMap) {
+				matches = append(matches, e)
+			}
+		}
+		assert.DeepEqual(t, matches, tc.matches)
+	}
+
+	_, err := generateEventFilters([]string{"foo=bar"})
+	assert.ErrorContains(t, err, "invalid or unsupported filter")
+}
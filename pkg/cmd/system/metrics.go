@@ -0,0 +1,116 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/infoutil"
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+	"github.com/containerd/nerdctl/v2/pkg/statsutil"
+)
+
+// MetricsServe serves the statistics of the running containers of all the namespaces
+// in the OpenMetrics text format on /metrics, until ctx is done.
+func MetricsServe(ctx context.Context, client *containerd.Client, options types.SystemMetricsServeOptions) error {
+	if rootlessutil.IsRootless() && infoutil.CgroupsVersion() == "1" {
+		return errors.New("metrics requires cgroup v2 for rootless containers, see https://rootlesscontaine.rs/getting-started/common/cgroup2/")
+	}
+
+	collector := container.NewMetricsCollector()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		samples, err := collectAllNamespaces(r.Context(), client, collector)
+		if err != nil {
+			log.G(ctx).WithError(err).Error("failed to collect the container metrics")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		var b bytes.Buffer
+		if err := statsutil.WriteOpenMetrics(&b, samples); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", statsutil.OpenMetricsContentType)
+		w.Write(b.Bytes())
+	})
+
+	ln, err := net.Listen("tcp", options.Listen)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		// the namespace of ctx is overridden for each namespace when scraping
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(options.Stdout, "Serving metrics on http://%s/metrics\n", ln.Addr())
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// collectAllNamespaces collects the samples of the running containers of all the namespaces.
+func collectAllNamespaces(ctx context.Context, client *containerd.Client, collector *container.MetricsCollector) ([]statsutil.MetricsSample, error) {
+	nsList, err := client.NamespaceService().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var samples []statsutil.MetricsSample
+	for _, ns := range nsList {
+		nsCtx := namespaces.WithNamespace(ctx, ns)
+		containers, err := client.Containers(nsCtx)
+		if err != nil {
+			return nil, err
+		}
+		var running []containerd.Container
+		for _, c := range containers {
+			if strings.HasPrefix(formatter.ContainerStatus(nsCtx, c), "Up") {
+				running = append(running, c)
+			}
+		}
+		nsSamples, err := collector.Collect(nsCtx, running)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, nsSamples...)
+	}
+	return samples, nil
+}
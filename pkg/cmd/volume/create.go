@@ -17,6 +17,7 @@
 package volume
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/moby/moby/client/pkg/stringid"
@@ -37,10 +38,11 @@ func Create(name string, options types.VolumeCreateOptions) (*native.Volume, err
 		return nil, err
 	}
 	labels := strutil.DedupeStrSlice(options.Labels)
-	vol, err := volStore.Create(name, labels)
+	vol, err := volStore.Create(name, labels, options.Driver, options.DriverOpts)
 	if err != nil {
 		return nil, err
 	}
+	recordEvent(context.TODO(), options.GOptions, name, "create")
 	fmt.Fprintln(options.Stdout, name)
 	return vol, nil
 }
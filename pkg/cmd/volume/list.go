@@ -119,7 +119,7 @@ func lsPrintOutput(vols map[string]native.Volume, options types.VolumeListOption
 
 	for _, v := range vols {
 		p := volumePrintable{
-			Driver:     "local",
+			Driver:     v.Driver,
 			Labels:     "",
 			Mountpoint: v.Mountpoint,
 			Name:       v.Name,
@@ -45,7 +45,7 @@ func Prune(ctx context.Context, client *containerd.Client, options types.VolumeP
 			return nil, err
 		}
 
-		usedVolumesList, err := usedVolumes(ctx, containers)
+		usedVolumesList, err := UsedVolumes(ctx, containers)
 		if err != nil {
 			return nil, err
 		}
@@ -45,7 +45,7 @@ func Remove(ctx context.Context, client *containerd.Client, volumes []string, op
 
 	// Note: to avoid racy behavior, this is called by volStore.Remove *inside a lock*
 	removableVolumes := func() (volumeNames []string, cannotRemove []error, err error) {
-		usedVolumesList, err := usedVolumes(ctx, containers)
+		usedVolumesList, err := UsedVolumes(ctx, containers)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -67,6 +67,7 @@ func Remove(ctx context.Context, client *containerd.Client, volumes []string, op
 	}
 	// Otherwise, output on stdout whatever was successful
 	for _, name := range removedNames {
+		recordEvent(ctx, options.GOptions, name, "destroy")
 		fmt.Fprintln(options.Stdout, name)
 	}
 	// Log the rest
@@ -79,8 +80,9 @@ func Remove(ctx context.Context, client *containerd.Client, volumes []string, op
 	return nil
 }
 
-func usedVolumes(ctx context.Context, containers []containerd.Container) (map[string]struct{}, error) {
-	usedVolumesList := make(map[string]struct{})
+// UsedVolumes returns the names of the volumes mounted by the containers, with the number of containers mounting them.
+func UsedVolumes(ctx context.Context, containers []containerd.Container) (map[string]int, error) {
+	usedVolumesList := make(map[string]int)
 	for _, c := range containers {
 		l, err := c.Labels(ctx)
 		if err != nil {
@@ -105,7 +107,7 @@ func usedVolumes(ctx context.Context, containers []containerd.Container) (map[st
 		}
 		for _, m := range mounts {
 			if m.Type == mountutil.Volume {
-				usedVolumesList[m.Name] = struct{}{}
+				usedVolumesList[m.Name]++
 			}
 		}
 	}
@@ -17,7 +17,11 @@
 package volume
 
 import (
+	"context"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
 	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
 )
 
@@ -30,3 +34,17 @@ func Store(ns string, dataRoot string, address string) (volumestore.VolumeStore,
 	}
 	return volumestore.New(dataStore, ns)
 }
+
+// recordEvent records the event of the volume in the event journal.
+func recordEvent(ctx context.Context, globalOptions types.GlobalCommandOptions, name, action string) {
+	dataStore, err := clientutil.DataStore(globalOptions.DataRoot, globalOptions.Address)
+	if err != nil {
+		return
+	}
+	eventutil.Record(ctx, dataStore, eventutil.JournalEvent{
+		Namespace: globalOptions.Namespace,
+		Type:      eventutil.TypeVolume,
+		Action:    action,
+		ID:        name,
+	})
+}
@@ -133,6 +133,11 @@ type Composer struct {
 	config  *config.Config
 }
 
+// ProjectName returns the name of the project.
+func (c *Composer) ProjectName() string {
+	return c.project.Name
+}
+
 func (c *Composer) createNerdctlCmd(ctx context.Context, args ...string) *exec.Cmd {
 	return exec.CommandContext(ctx, c.NerdctlCmd, append(c.NerdctlArgs, args...)...)
 }
@@ -197,6 +197,9 @@ func (c *Composer) createServiceContainer(ctx context.Context, service *servicep
 		fmt.Sprintf("-l=%s=%s", labels.ComposeProject, c.project.Name),
 		fmt.Sprintf("-l=%s=%s", labels.ComposeService, service.Unparsed.Name),
 		fmt.Sprintf("-l=%s=%s", labels.ComposeConfigHash, currentHash),
+		fmt.Sprintf("-l=%s=%s", labels.ComposeConfigFiles, strings.Join(c.project.ComposeFiles, ",")),
+		fmt.Sprintf("-l=%s=%s", labels.ComposeWorkingDir, c.project.WorkingDir),
+		fmt.Sprintf("-l=%s=%s", labels.ComposeDependsOn, dependsOnLabel(service.Unparsed)),
 	}, container.RunArgs...)
 
 	cmd := c.createNerdctlCmd(ctx, append([]string{"create"}, container.RunArgs...)...)
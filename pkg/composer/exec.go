@@ -54,19 +54,28 @@ func (c *Composer) Exec(ctx context.Context, eo ExecOptions) error {
 		return err
 	}
 
-	containers, err := c.Containers(ctx, eo.ServiceName)
+	container, err := c.serviceContainer(ctx, eo.ServiceName, eo.Index)
 	if err != nil {
-		return fmt.Errorf("fail to get containers for service %s: %w", eo.ServiceName, err)
+		return err
+	}
+	return c.exec(ctx, container, eo)
+}
+
+// serviceContainer returns the container of the service with the given index (starting from 1).
+func (c *Composer) serviceContainer(ctx context.Context, service string, index int) (containerd.Container, error) {
+	containers, err := c.Containers(ctx, service)
+	if err != nil {
+		return nil, fmt.Errorf("fail to get containers for service %s: %w", service, err)
 	}
 	if len(containers) == 0 {
-		return fmt.Errorf("no running containers from service %s", eo.ServiceName)
+		return nil, fmt.Errorf("no running containers from service %s", service)
 	}
-	if eo.Index > len(containers) {
-		return fmt.Errorf("index (%d) out of range: only %d running instances from service %s",
-			eo.Index, len(containers), eo.ServiceName)
+	if index > len(containers) {
+		return nil, fmt.Errorf("index (%d) out of range: only %d running instances from service %s",
+			index, len(containers), service)
 	}
 	if len(containers) == 1 {
-		return c.exec(ctx, containers[0], eo)
+		return containers[0], nil
 	}
 	// The order of the containers is not consistently ascending
 	// we need to re-sort them.
@@ -79,7 +88,7 @@ func (c *Composer) Exec(ctx context.Context, eo ExecOptions) error {
 		indexJ, _ := strconv.Atoi(segsJ[len(segsJ)-1])
 		return indexI < indexJ
 	})
-	return c.exec(ctx, containers[eo.Index-1], eo)
+	return containers[index-1], nil
 }
 
 // exec constructs/executes the `nerdctl exec` command to be executed on the given container.
@@ -0,0 +1,106 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/errdefs"
+
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+// ProjectSummary is the summary of a project, as printed by `nerdctl compose ls`.
+type ProjectSummary struct {
+	Name string
+	// Status is the number of containers in each state, e.g., "running(2), exited(1)"
+	Status      string
+	ConfigFiles string
+}
+
+// ListProjects lists the projects of the containers of the namespace of ctx.
+// Unless all is set, only the projects with a running container are listed.
+// It does not need the compose files of the projects.
+func ListProjects(ctx context.Context, client *containerd.Client, all bool) ([]ProjectSummary, error) {
+	containers, err := client.Containers(ctx, fmt.Sprintf("labels.%q", labels.ComposeProject))
+	if err != nil {
+		return nil, err
+	}
+
+	type project struct {
+		states      map[string]int
+		configFiles []string
+	}
+	projects := make(map[string]*project)
+	for _, container := range containers {
+		containerLabels, err := container.Labels(ctx)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		name := containerLabels[labels.ComposeProject]
+		p, ok := projects[name]
+		if !ok {
+			p = &project{states: make(map[string]int)}
+			projects[name] = p
+		}
+		p.states[containerutil.ContainerState(ctx, container)]++
+		for _, f := range strings.Split(containerLabels[labels.ComposeConfigFiles], ",") {
+			if f != "" && !strutil.InStringSlice(p.configFiles, f) {
+				p.configFiles = append(p.configFiles, f)
+			}
+		}
+	}
+
+	var summaries []ProjectSummary
+	for name, p := range projects {
+		if !all && p.states[string(containerd.Running)] == 0 {
+			continue
+		}
+		summaries = append(summaries, ProjectSummary{
+			Name:        name,
+			Status:      formatProjectStatus(p.states),
+			ConfigFiles: strings.Join(p.configFiles, ","),
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries, nil
+}
+
+// formatProjectStatus formats the number of containers in each state like Docker Compose does.
+func formatProjectStatus(states map[string]int) string {
+	keys := make([]string, 0, len(states))
+	for state := range states {
+		keys = append(keys, state)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, state := range keys {
+		parts = append(parts, fmt.Sprintf("%s(%d)", state, states[state]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -37,6 +37,14 @@ type RestartOptions struct {
 // Restart restarts running/stopped containers in `services`. It calls
 // `nerdctl restart CONTAINER_ID` to do the actual job.
 func (c *Composer) Restart(ctx context.Context, opt RestartOptions, services []string) error {
+	// services depending on `services` with `restart: true` are restarted as well
+	if len(services) > 0 {
+		dependents, err := c.dependentsToRestart(services)
+		if err != nil {
+			return err
+		}
+		services = append(services, dependents...)
+	}
 	// in dependency order
 	return c.project.ForEachService(services, func(name string, svc *types.ServiceConfig) error {
 		containers, err := c.Containers(ctx, svc.Name)
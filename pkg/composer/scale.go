@@ -0,0 +1,110 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/compose-spec/compose-go/v2/types"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+// ScaleOptions stores all option input from `nerdctl compose scale`
+type ScaleOptions struct {
+	NoDeps bool
+}
+
+// Scale sets the number of containers of the services, e.g., {"web": 3}.
+// The missing containers are created (or started if they exist), and the excess containers
+// (the ones with the highest replica numbers) are removed. The existing containers are not recreated.
+func (c *Composer) Scale(ctx context.Context, opt ScaleOptions, scale map[string]int) error {
+	services := make([]string, 0, len(scale))
+	for name, replicas := range scale {
+		if replicas < 0 {
+			return fmt.Errorf("invalid number of replicas for service %q: %d", name, replicas)
+		}
+		if _, err := c.project.GetService(name); err != nil {
+			return err
+		}
+		services = append(services, name)
+	}
+	slices.Sort(services)
+
+	for _, name := range services {
+		if err := c.removeExcessReplicas(ctx, name, scale[name]); err != nil {
+			return err
+		}
+	}
+
+	uo := UpOptions{
+		Detach:     true,
+		NoRecreate: true,
+		NoDeps:     opt.NoDeps,
+		Scale:      scale,
+	}
+	return c.Up(ctx, uo, services)
+}
+
+// removeExcessReplicas removes the containers of the service that are not among its first `replicas` ones.
+func (c *Composer) removeExcessReplicas(ctx context.Context, service string, replicas int) error {
+	svc, err := c.project.GetService(service)
+	if err != nil {
+		return err
+	}
+	// GetService returns a copy of the service, but not of its deploy config
+	deploy := types.DeployConfig{}
+	if svc.Deploy != nil {
+		deploy = *svc.Deploy
+	}
+	deploy.Replicas = &replicas
+	svc.Deploy = &deploy
+	ps, err := serviceparser.Parse(c.project, svc)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(ps.Containers))
+	for _, container := range ps.Containers {
+		names = append(names, container.Name)
+	}
+
+	containers, err := c.Containers(ctx, service)
+	if err != nil {
+		return err
+	}
+	var excess []containerd.Container
+	for _, container := range containers {
+		info, err := container.Info(ctx, containerd.WithoutRefreshedMetadata)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(names, info.Labels[labels.Name]) {
+			log.G(ctx).Infof("Removing container %s", info.Labels[labels.Name])
+			excess = append(excess, container)
+		}
+	}
+	if len(excess) == 0 {
+		return nil
+	}
+	return c.removeContainers(ctx, excess, RemoveOptions{Stop: true})
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/identifiers"
 	"github.com/containerd/nerdctl/v2/pkg/reflectutil"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
 // ComposeExtensionKey defines fields used to implement extension features.
@@ -69,6 +70,7 @@ func warnUnknownFields(svc types.ServiceConfig) {
 		"ContainerName",
 		"DependsOn",
 		"Deploy",
+		"Develop", // handled by composer.Watch
 		"Devices",
 		"Dockerfile", // handled by the loader (normalizer)
 		"DNS",
@@ -130,9 +132,9 @@ func warnUnknownFields(svc types.ServiceConfig) {
 			"Interval",
 			"Retries",
 			"StartPeriod",
+			"StartInterval",
 			"Disable",
 			"Extensions",
-			// TODO: add support 'StartInterval'
 		); len(unknown) > 0 {
 			log.L.Warnf("Ignoring: service %s: healthcheck: %+v", svc.Name, unknown)
 		}
@@ -141,12 +143,14 @@ func warnUnknownFields(svc types.ServiceConfig) {
 	for depName, dep := range svc.DependsOn {
 		if unknown := reflectutil.UnknownNonEmptyFields(&dep,
 			"Condition",
+			"Required",
+			"Restart",
 		); len(unknown) > 0 {
 			log.L.Warnf("Ignoring: service %s: depends_on: %s: %+v", svc.Name, depName, unknown)
 		}
 		switch dep.Condition {
-		case "", types.ServiceConditionStarted:
-			// NOP
+		case "", types.ServiceConditionStarted, types.ServiceConditionHealthy, types.ServiceConditionCompletedSuccessfully:
+			// handled by composer.Up
 		default:
 			log.L.Warnf("Ignoring: service %s: depends_on: %s: condition %s", svc.Name, depName, dep.Condition)
 		}
@@ -624,6 +628,11 @@ func newContainer(project *types.Project, parsed *Service, i int) (*Container, e
 			if value != nil && value.MacAddress != "" {
 				c.RunArgs = append(c.RunArgs, "--mac-address="+value.MacAddress)
 			}
+			if value != nil {
+				for _, alias := range strutil.DedupeStrSlice(value.Aliases) {
+					c.RunArgs = append(c.RunArgs, fmt.Sprintf("--network-scoped-alias=%s=%s", net.fullName, alias))
+				}
+			}
 		}
 	}
 
@@ -801,6 +810,9 @@ func newContainer(project *types.Project, parsed *Service, i int) (*Container, e
 			if hc.StartPeriod != nil {
 				c.RunArgs = append(c.RunArgs, fmt.Sprintf("--health-start-period=%s", time.Duration(*hc.StartPeriod).String()))
 			}
+			if hc.StartInterval != nil {
+				c.RunArgs = append(c.RunArgs, fmt.Sprintf("--health-start-interval=%s", time.Duration(*hc.StartInterval).String()))
+			}
 		}
 	}
 
@@ -530,6 +530,45 @@ services:
 
 }
 
+func TestParseNetworkAliases(t *testing.T) {
+	t.Parallel()
+	const dockerComposeYAML = `
+services:
+  foo:
+    image: nginx:alpine
+    networks:
+      front:
+        aliases:
+          - web
+          - www
+      back:
+        aliases:
+          - web
+networks:
+  front: {}
+  back: {}
+`
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	project, err := testutil.LoadProject(comp.YAMLFullPath(), comp.ProjectName(), nil)
+	assert.NilError(t, err)
+
+	fooSvc, err := project.GetService("foo")
+	assert.NilError(t, err)
+
+	foo, err := Parse(project, fooSvc)
+	assert.NilError(t, err)
+
+	t.Logf("foo: %+v", foo)
+	for _, c := range foo.Containers {
+		assert.Assert(t, in(c.RunArgs, "--network-scoped-alias="+project.Name+"_front=web"))
+		assert.Assert(t, in(c.RunArgs, "--network-scoped-alias="+project.Name+"_front=www"))
+		assert.Assert(t, in(c.RunArgs, "--network-scoped-alias="+project.Name+"_back=web"))
+		assert.Assert(t, !in(c.RunArgs, "--network-scoped-alias="+project.Name+"_back=www"))
+	}
+}
+
 func TestParseConfigs(t *testing.T) {
 	t.Parallel()
 	if runtime.GOOS == "windows" {
@@ -638,6 +677,7 @@ services:
       timeout: 10s
       retries: 3
       start_period: 5s
+      start_interval: 2s
   cmd_exec:
     image: alpine:3.14
     healthcheck:
@@ -667,6 +707,7 @@ services:
 	assert.Assert(t, in(c.RunArgs, "--health-timeout=10s"))
 	assert.Assert(t, in(c.RunArgs, "--health-retries=3"))
 	assert.Assert(t, in(c.RunArgs, "--health-start-period=5s"))
+	assert.Assert(t, in(c.RunArgs, "--health-start-interval=2s"))
 
 	c = getContainersFromService(t, project, "cmd_exec")[0]
 	assert.Assert(t, in(c.RunArgs, "--health-cmd=curl -f http://localhost"))
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/compose-spec/compose-go/v2/types"
 
@@ -43,6 +44,8 @@ type UpOptions struct {
 	NoRecreate           bool
 	Scale                map[string]int // map of service name to replicas
 	Pull                 string
+	Watch                bool // watch the `develop.watch` paths of the services after starting them
+	NoDeps               bool // do not start the services the given services depend on
 }
 
 func (opts UpOptions) recreateStrategy() string {
@@ -86,6 +89,9 @@ func (c *Composer) Up(ctx context.Context, uo UpOptions, services []string) erro
 	var parsedServices []*serviceparser.Service
 	// use WithServices to sort the services in dependency order
 	forEachFn := func(name string, svc *types.ServiceConfig) error {
+		if uo.NoDeps && len(services) > 0 && !slices.Contains(services, svc.Name) {
+			return nil
+		}
 		if replicas, ok := uo.Scale[svc.Name]; ok {
 			if svc.Deploy == nil {
 				svc.Deploy = &types.DeployConfig{}
@@ -0,0 +1,267 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+// dependencyPollInterval is the interval between checks of a dependency's state.
+// Same as docker compose.
+const dependencyPollInterval = 500 * time.Millisecond
+
+// dependsOnLabel returns the value of the com.docker.compose.depends_on label of the service.
+// Same format as docker compose: "<service>:<condition>:<restart>", separated by commas.
+func dependsOnLabel(svc *types.ServiceConfig) string {
+	deps := make([]string, 0, len(svc.DependsOn))
+	for depName, dep := range svc.DependsOn {
+		condition := dep.Condition
+		if condition == "" {
+			condition = types.ServiceConditionStarted
+		}
+		deps = append(deps, fmt.Sprintf("%s:%s:%t", depName, condition, dep.Restart))
+	}
+	sort.Strings(deps)
+	return strings.Join(deps, ",")
+}
+
+// waitDependencies blocks until all `depends_on` conditions of the service are met.
+// FYI: https://github.com/docker/compose/blob/v2.29.0/pkg/compose/convergence.go#L303-L380
+func (c *Composer) waitDependencies(ctx context.Context, ps *serviceparser.Service) error {
+	for depName, dep := range ps.Unparsed.DependsOn {
+		switch dep.Condition {
+		case "", types.ServiceConditionStarted:
+			// upServices starts services in dependency order, so nothing to wait for
+			continue
+		case types.ServiceConditionHealthy, types.ServiceConditionCompletedSuccessfully:
+		default:
+			log.G(ctx).Warnf("Ignoring: service %s: depends_on: %s: condition %s", ps.Unparsed.Name, depName, dep.Condition)
+			continue
+		}
+
+		containers, err := c.Containers(ctx, depName)
+		if err != nil {
+			return err
+		}
+		if len(containers) == 0 {
+			if !dep.Required {
+				log.G(ctx).Warnf("service %s: optional dependency %q is not running, skipping", ps.Unparsed.Name, depName)
+				continue
+			}
+			return fmt.Errorf("service %s: dependency %q has no container", ps.Unparsed.Name, depName)
+		}
+
+		log.G(ctx).Infof("Waiting for service %s to be %s", depName, conditionDescription(dep.Condition))
+		for _, container := range containers {
+			if err := c.waitDependencyContainer(ctx, container, dep.Condition); err != nil {
+				if !dep.Required {
+					log.G(ctx).Warnf("service %s: optional dependency %q failed: %v", ps.Unparsed.Name, depName, err)
+					break
+				}
+				return fmt.Errorf("dependency failed to start: service %s: %w", depName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// waitDependencyContainer polls the container until the condition is met, the condition
+// can no longer be met, or the context is cancelled.
+func (c *Composer) waitDependencyContainer(ctx context.Context, container containerd.Container, condition string) error {
+	if condition == types.ServiceConditionHealthy {
+		return c.waitHealthy(ctx, container)
+	}
+	ticker := time.NewTicker(dependencyPollInterval)
+	defer ticker.Stop()
+	for {
+		done, err := dependencyConditionMet(ctx, container, condition)
+		if err != nil || done {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitHealthy polls the container until it is healthy, for at most the time its health check
+// needs to report it unhealthy (see healthyTimeout).
+// The health checks are run by systemd timers when they are enabled, otherwise they are run from here,
+// as nothing else would update the health state of the container.
+func (c *Composer) waitHealthy(ctx context.Context, container containerd.Container) error {
+	containerLabels, err := container.Labels(ctx)
+	if err != nil {
+		return err
+	}
+	name := containerLabels[labels.Name]
+	hcJSON, ok := containerLabels[labels.HealthCheck]
+	if !ok {
+		return fmt.Errorf("container %s has no healthcheck configured", name)
+	}
+	hc, err := healthcheck.HealthCheckFromJSON(hcJSON)
+	if err != nil {
+		return fmt.Errorf("invalid healthcheck configuration of container %s: %w", name, err)
+	}
+	if len(hc.Test) == 0 || hc.Test[0] == "NONE" {
+		return fmt.Errorf("container %s has its healthcheck disabled", name)
+	}
+	hc.ApplyDefaults()
+	probeInline := !healthcheck.TimersEnabled(c.config)
+	timeout := healthyTimeout(hc)
+	deadline := time.Now().Add(timeout)
+
+	ticker := time.NewTicker(dependencyPollInterval)
+	defer ticker.Stop()
+	var lastProbe time.Time
+	for {
+		if probeInline && time.Since(lastProbe) >= hc.TimerInterval() {
+			lastProbe = time.Now()
+			// A failing probe is recorded in the health state, which is checked below
+			if err := c.runNerdctlCmd(ctx, "container", "healthcheck", container.ID()); err != nil {
+				log.G(ctx).WithError(err).Debugf("health check of container %s failed", name)
+			}
+		}
+		if containerLabels, err = container.Labels(ctx); err != nil {
+			return err
+		}
+		if done, err := healthConditionMet(name, containerLabels); err != nil || done {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("container %s is not healthy after %s (start period %s, %d retries of %s)",
+				name, timeout, hc.StartPeriod, hc.Retries, hc.Interval)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// healthyTimeout returns the time after which a container that is still not healthy would have
+// been reported unhealthy by its health check: the start period, then the retries, with an extra interval
+// for the first check after the start period.
+func healthyTimeout(hc *healthcheck.Healthcheck) time.Duration {
+	return hc.StartPeriod + time.Duration(hc.Retries+1)*(hc.Interval+hc.Timeout)
+}
+
+func healthConditionMet(name string, containerLabels map[string]string) (bool, error) {
+	stateJSON, ok := containerLabels[labels.HealthState]
+	if !ok {
+		// the first probe has not run yet
+		return false, nil
+	}
+	state, err := healthcheck.HealthStateFromJSON(stateJSON)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse health state of container %s: %w", name, err)
+	}
+	switch state.Status {
+	case healthcheck.Healthy:
+		return true, nil
+	case healthcheck.Unhealthy:
+		return false, fmt.Errorf("container %s is unhealthy", name)
+	}
+	return false, nil
+}
+
+func dependencyConditionMet(ctx context.Context, container containerd.Container, condition string) (bool, error) {
+	containerLabels, err := container.Labels(ctx)
+	if err != nil {
+		return false, err
+	}
+	name := containerLabels[labels.Name]
+
+	switch condition {
+	case types.ServiceConditionCompletedSuccessfully:
+		task, err := container.Task(ctx, nil)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				// not started yet
+				return false, nil
+			}
+			return false, err
+		}
+		status, err := task.Status(ctx)
+		if err != nil {
+			return false, err
+		}
+		if status.Status != containerd.Stopped {
+			return false, nil
+		}
+		if status.ExitStatus != 0 {
+			return false, fmt.Errorf("container %s exited (%d)", name, status.ExitStatus)
+		}
+		return true, nil
+	}
+	return true, nil
+}
+
+func conditionDescription(condition string) string {
+	switch condition {
+	case types.ServiceConditionHealthy:
+		return "healthy"
+	case types.ServiceConditionCompletedSuccessfully:
+		return "completed successfully"
+	}
+	return "started"
+}
+
+// dependentsToRestart returns the services that declare `restart: true` on any of `services`.
+// FYI: https://docs.docker.com/reference/compose-file/services/#depends_on
+func (c *Composer) dependentsToRestart(services []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(services))
+	for _, s := range services {
+		seen[s] = struct{}{}
+	}
+	var dependents []string
+	queue := append([]string{}, services...)
+	for len(queue) > 0 {
+		svc, err := c.project.GetService(queue[0])
+		queue = queue[1:]
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range c.project.GetDependentsForService(svc, func(dep types.ServiceDependency) bool {
+			return dep.Restart
+		}) {
+			if _, ok := seen[d]; ok {
+				continue
+			}
+			seen[d] = struct{}{}
+			dependents = append(dependents, d)
+			queue = append(queue, d)
+		}
+	}
+	return dependents, nil
+}
@@ -0,0 +1,69 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
+)
+
+func TestDependentsToRestart(t *testing.T) {
+	c := &Composer{project: &types.Project{
+		Services: types.Services{
+			"db": {Name: "db"},
+			"api": {Name: "api", DependsOn: types.DependsOnConfig{
+				"db": {Condition: types.ServiceConditionHealthy, Restart: true, Required: true},
+			}},
+			"web": {Name: "web", DependsOn: types.DependsOnConfig{
+				"api": {Condition: types.ServiceConditionStarted, Restart: true, Required: true},
+			}},
+			"worker": {Name: "worker", DependsOn: types.DependsOnConfig{
+				"db": {Condition: types.ServiceConditionStarted, Required: true},
+			}},
+		},
+	}}
+
+	// transitively, and not the services depending on db without restart: true
+	dependents, err := c.dependentsToRestart([]string{"db"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, dependents, []string{"api", "web"})
+
+	// services that are restarted anyway are not repeated
+	dependents, err = c.dependentsToRestart([]string{"db", "web"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, dependents, []string{"api"})
+
+	dependents, err = c.dependentsToRestart([]string{"worker"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(dependents), 0)
+}
+
+func TestHealthyTimeout(t *testing.T) {
+	hc := &healthcheck.Healthcheck{
+		Test:        []string{"CMD", "true"},
+		Interval:    time.Second,
+		Timeout:     2 * time.Second,
+		StartPeriod: 10 * time.Second,
+		Retries:     3,
+	}
+	assert.Equal(t, healthyTimeout(hc), 22*time.Second)
+}
@@ -56,6 +56,9 @@ func (c *Composer) upServices(ctx context.Context, parsedServices []*servicepars
 	)
 	for _, ps := range parsedServices {
 		ps := ps
+		if err := c.waitDependencies(ctx, ps); err != nil {
+			return err
+		}
 		var runEG errgroup.Group
 		services = append(services, ps.Unparsed.Name)
 		for _, container := range ps.Containers {
@@ -85,6 +88,15 @@ func (c *Composer) upServices(ctx context.Context, parsedServices []*servicepars
 	if uo.AbortOnContainerExit {
 		defer c.stopContainersFromParsedServices(ctx, containers)
 	}
+	if uo.Watch {
+		watchCtx, cancelWatch := context.WithCancel(ctx)
+		defer cancelWatch()
+		go func() {
+			if err := c.watch(watchCtx, WatchOptions{}, services); err != nil {
+				log.G(ctx).WithError(err).Error("failed to watch services")
+			}
+		}()
+	}
 	log.G(ctx).Info("Attaching to logs")
 	lo := LogsOptions{
 		AbortOnContainerExit: uo.AbortOnContainerExit,
@@ -215,6 +227,9 @@ func (c *Composer) upServiceContainer(ctx context.Context, service *serviceparse
 		fmt.Sprintf("-l=%s=%s", labels.ComposeProject, c.project.Name),
 		fmt.Sprintf("-l=%s=%s", labels.ComposeService, service.Unparsed.Name),
 		fmt.Sprintf("-l=%s=%s", labels.ComposeConfigHash, currentHash),
+		fmt.Sprintf("-l=%s=%s", labels.ComposeConfigFiles, strings.Join(c.project.ComposeFiles, ",")),
+		fmt.Sprintf("-l=%s=%s", labels.ComposeWorkingDir, c.project.WorkingDir),
+		fmt.Sprintf("-l=%s=%s", labels.ComposeDependsOn, dependsOnLabel(service.Unparsed)),
 	}, container.RunArgs...)
 
 	cmd := c.createNerdctlCmd(ctx, append([]string{"run"}, container.RunArgs...)...)
@@ -0,0 +1,114 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/errdefs"
+
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+// Wait blocks until all the containers of `services` have stopped, and prints their exit codes.
+// It returns the exit code of the container that stopped last.
+func (c *Composer) Wait(ctx context.Context, services []string, writer io.Writer) (int, error) {
+	serviceNames, err := c.ServiceNames(services...)
+	if err != nil {
+		return 0, err
+	}
+	containers, err := c.Containers(ctx, serviceNames...)
+	if err != nil {
+		return 0, err
+	}
+	if len(containers) == 0 {
+		return 0, fmt.Errorf("no containers for services %v", serviceNames)
+	}
+	// Wait does not need to lock, and would prevent the containers from being stopped with compose.
+	if err := Unlock(); err != nil {
+		return 0, err
+	}
+
+	dataStore, err := clientutil.DataStore(c.config.DataRoot, c.config.Address)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		mu       sync.Mutex
+		exitCode int
+	)
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, container := range containers {
+		container := container
+		eg.Go(func() error {
+			info, err := container.Info(ctx, containerd.WithoutRefreshedMetadata)
+			if err != nil {
+				return err
+			}
+			code, err := waitExitCode(ctx, container, dataStore)
+			if err != nil {
+				return err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			exitCode = int(code)
+			_, err = fmt.Fprintf(writer, "container %q exited with status code %d\n", info.Labels[labels.Name], code)
+			return err
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return 0, err
+	}
+	return exitCode, nil
+}
+
+// waitExitCode waits for the task of the container to exit, and returns its exit code.
+// Like Docker, the exit code of a container that has already exited and has no task is the recorded one.
+func waitExitCode(ctx context.Context, container containerd.Container, dataStore string) (uint32, error) {
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		if !errdefs.IsNotFound(err) {
+			return 0, err
+		}
+		code, ok, err := eventutil.LastExitCode(dataStore, container.ID(), time.Time{})
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return 0, fmt.Errorf("container %s is not running and has no recorded exit code", container.ID())
+		}
+		return code, nil
+	}
+	statusC, err := task.Wait(ctx)
+	if err != nil {
+		return 0, err
+	}
+	status := <-statusC
+	code, _, err := status.Result()
+	return code, err
+}
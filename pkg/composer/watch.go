@@ -0,0 +1,367 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
+)
+
+// watchQuietPeriod is the duration without file events after which the pending
+// changes are applied. Same as docker compose.
+const watchQuietPeriod = 500 * time.Millisecond
+
+// defaultWatchIgnore is always ignored, in addition to the `ignore` patterns of each trigger.
+var defaultWatchIgnore = []string{".git", "*~", "*.swp", "*.swx", ".#*"}
+
+// WatchOptions stores all option input from `nerdctl compose watch`
+type WatchOptions struct {
+	NoUp  bool // do not run `up` before watching
+	Quiet bool // hide the messages about the applied actions
+}
+
+type watchTrigger struct {
+	service string
+	types.Trigger
+}
+
+// Watch runs `up` for `services` (unless NoUp is set), and then watches the paths
+// declared in `develop.watch` of each service, applying the corresponding action
+// on every change until ctx is cancelled.
+func (c *Composer) Watch(ctx context.Context, wo WatchOptions, services []string) error {
+	if !wo.NoUp {
+		if err := c.Up(ctx, UpOptions{Detach: true}, services); err != nil {
+			return err
+		}
+	}
+	// Watching runs until interrupted, so release the lock like `compose logs` does.
+	if err := Unlock(); err != nil {
+		return err
+	}
+	return c.watch(ctx, wo, services)
+}
+
+func (c *Composer) watch(ctx context.Context, wo WatchOptions, services []string) error {
+	var triggers []watchTrigger
+	err := c.project.ForEachService(services, func(name string, svc *types.ServiceConfig) error {
+		if svc.Develop == nil {
+			return nil
+		}
+		for _, t := range svc.Develop.Watch {
+			switch t.Action {
+			case types.WatchActionSync, types.WatchActionSyncRestart, types.WatchActionSyncExec:
+				if t.Target == "" {
+					return fmt.Errorf("service %s: develop.watch: %s requires a target", svc.Name, t.Action)
+				}
+			case types.WatchActionRebuild:
+				if svc.Build == nil {
+					return fmt.Errorf("service %s: develop.watch: rebuild requires a build section", svc.Name)
+				}
+			case types.WatchActionRestart:
+			default:
+				return fmt.Errorf("service %s: develop.watch: unknown action %q", svc.Name, t.Action)
+			}
+			if !filepath.IsAbs(t.Path) {
+				t.Path = c.project.RelativePath(t.Path)
+			}
+			triggers = append(triggers, watchTrigger{service: svc.Name, Trigger: t})
+		}
+		return nil
+	}, types.IgnoreDependencies)
+	if err != nil {
+		return err
+	}
+	if len(triggers) == 0 {
+		return errors.New("none of the selected services is configured for watch, consider setting a 'develop' section")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create fsnotify watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	for _, t := range triggers {
+		if err := addWatchRecursive(watcher, t); err != nil {
+			return err
+		}
+		if t.InitialSync && t.Action != types.WatchActionRebuild && t.Action != types.WatchActionRestart {
+			if err := c.applyWatchTrigger(ctx, t, []string{t.Path}, wo.Quiet); err != nil {
+				log.G(ctx).WithError(err).Errorf("service %s: initial sync failed", t.service)
+			}
+		}
+	}
+	log.G(ctx).Info("Watch enabled")
+
+	pending := make(map[string]struct{})
+	timer := time.NewTimer(watchQuietPeriod)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.G(ctx).WithError(err).Warn("watch error")
+		case e, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if e.Has(fsnotify.Create) {
+				if st, err := os.Stat(e.Name); err == nil && st.IsDir() {
+					for _, t := range triggers {
+						if isUnder(t.Path, e.Name) && !watchIgnored(t.Trigger, e.Name) {
+							_ = addWatchRecursive(watcher, watchTrigger{service: t.service, Trigger: types.Trigger{Path: e.Name, Ignore: t.Ignore}})
+						}
+					}
+				}
+			}
+			if e.Has(fsnotify.Chmod) && !e.Has(fsnotify.Write) {
+				continue
+			}
+			pending[e.Name] = struct{}{}
+			timer.Reset(watchQuietPeriod)
+		case <-timer.C:
+			changed := make([]string, 0, len(pending))
+			for p := range pending {
+				changed = append(changed, p)
+			}
+			pending = make(map[string]struct{})
+			c.handleWatchChanges(ctx, triggers, changed, wo.Quiet)
+		}
+	}
+}
+
+// handleWatchChanges applies the triggers matching the changed paths.
+// Each service is rebuilt at most once per batch, and a rebuild supersedes the other actions.
+func (c *Composer) handleWatchChanges(ctx context.Context, triggers []watchTrigger, changed []string, quiet bool) {
+	rebuilt := make(map[string]bool)
+	for _, t := range triggers {
+		if t.Action != types.WatchActionRebuild || rebuilt[t.service] {
+			continue
+		}
+		if len(matchingPaths(t.Trigger, changed)) > 0 {
+			rebuilt[t.service] = true
+			if err := c.applyWatchTrigger(ctx, t, nil, quiet); err != nil {
+				log.G(ctx).WithError(err).Errorf("service %s: rebuild failed", t.service)
+			}
+		}
+	}
+	for _, t := range triggers {
+		if t.Action == types.WatchActionRebuild || rebuilt[t.service] {
+			continue
+		}
+		paths := matchingPaths(t.Trigger, changed)
+		if len(paths) == 0 {
+			continue
+		}
+		if err := c.applyWatchTrigger(ctx, t, paths, quiet); err != nil {
+			log.G(ctx).WithError(err).Errorf("service %s: %s failed", t.service, t.Action)
+		}
+	}
+}
+
+func (c *Composer) applyWatchTrigger(ctx context.Context, t watchTrigger, paths []string, quiet bool) error {
+	if t.Action == types.WatchActionRebuild {
+		if !quiet {
+			log.G(ctx).Infof("Rebuilding service %s after changes were detected", t.service)
+		}
+		return c.rebuildService(ctx, t.service)
+	}
+
+	containers, err := c.Containers(ctx, t.service)
+	if err != nil {
+		return err
+	}
+	if len(containers) == 0 {
+		return fmt.Errorf("no container found for service %q", t.service)
+	}
+
+	switch t.Action {
+	case types.WatchActionSync, types.WatchActionSyncRestart, types.WatchActionSyncExec:
+		for _, p := range paths {
+			rel, err := filepath.Rel(t.Path, p)
+			if err != nil {
+				return err
+			}
+			dst := path.Join(t.Target, filepath.ToSlash(rel))
+			st, statErr := os.Stat(p)
+			src := p
+			if statErr == nil && st.IsDir() {
+				// `nerdctl cp` copies the content of a directory when the source ends with "/."
+				src = p + string(os.PathSeparator) + "."
+			}
+			for _, container := range containers {
+				args := []string{"cp", src, fmt.Sprintf("%s:%s", container.ID(), dst)}
+				if statErr != nil {
+					// the path was removed on the host
+					args = []string{"exec", container.ID(), "rm", "-rf", dst}
+				}
+				if err := c.runNerdctlCmd(ctx, args...); err != nil {
+					return err
+				}
+			}
+			if !quiet {
+				log.G(ctx).Infof("Syncing service %s: %s -> %s", t.service, p, dst)
+			}
+		}
+	}
+
+	switch t.Action {
+	case types.WatchActionRestart, types.WatchActionSyncRestart:
+		if !quiet {
+			log.G(ctx).Infof("Restarting service %s after changes were detected", t.service)
+		}
+		return c.restartContainers(ctx, containers, RestartOptions{})
+	case types.WatchActionSyncExec:
+		for _, container := range containers {
+			args := []string{"exec"}
+			if t.Exec.User != "" {
+				args = append(args, "--user="+t.Exec.User)
+			}
+			if t.Exec.WorkingDir != "" {
+				args = append(args, "--workdir="+t.Exec.WorkingDir)
+			}
+			if t.Exec.Privileged {
+				args = append(args, "--privileged")
+			}
+			for k, v := range t.Exec.Environment {
+				if v != nil {
+					args = append(args, fmt.Sprintf("--env=%s=%s", k, *v))
+				}
+			}
+			args = append(args, container.ID())
+			args = append(args, t.Exec.Command...)
+			if err := c.runNerdctlCmd(ctx, args...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rebuildService rebuilds the image of the service and recreates its containers.
+func (c *Composer) rebuildService(ctx context.Context, service string) error {
+	svc, err := c.project.GetService(service)
+	if err != nil {
+		return err
+	}
+	ps, err := serviceparser.Parse(c.project, svc)
+	if err != nil {
+		return err
+	}
+	if err := c.ensureServiceImage(ctx, ps, true, true, BuildOptions{}, false, ""); err != nil {
+		return err
+	}
+	for _, container := range ps.Containers {
+		if _, err := c.upServiceContainer(ctx, ps, container, RecreateForce); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addWatchRecursive adds the trigger path and all its non-ignored subdirectories to the watcher,
+// as fsnotify does not support recursive watches.
+func addWatchRecursive(watcher *fsnotify.Watcher, t watchTrigger) error {
+	st, err := os.Stat(t.Path)
+	if err != nil {
+		return fmt.Errorf("service %s: develop.watch: %w", t.service, err)
+	}
+	if !st.IsDir() {
+		return watcher.Add(t.Path)
+	}
+	return filepath.WalkDir(t.Path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if p != t.Path && watchIgnored(t.Trigger, p) {
+			return filepath.SkipDir
+		}
+		return watcher.Add(p)
+	})
+}
+
+// matchingPaths returns the changed paths that are under the trigger path and not ignored.
+func matchingPaths(t types.Trigger, changed []string) []string {
+	var res []string
+	for _, p := range changed {
+		if !isUnder(t.Path, p) || watchIgnored(t, p) {
+			continue
+		}
+		if len(t.Include) > 0 && !matchPatterns(t.Include, t.Path, p) {
+			continue
+		}
+		res = append(res, p)
+	}
+	return res
+}
+
+func watchIgnored(t types.Trigger, p string) bool {
+	return matchPatterns(defaultWatchIgnore, t.Path, p) || matchPatterns(t.Ignore, t.Path, p)
+}
+
+// matchPatterns reports whether the path relative to base, or any of its parent directories,
+// matches one of the patterns. A pattern without a slash also matches the base name of any
+// path element, like .dockerignore does for "**/pattern".
+func matchPatterns(patterns []string, base, p string) bool {
+	rel, err := filepath.Rel(base, p)
+	if err != nil || rel == "." {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	elems := strings.Split(rel, "/")
+	for _, pattern := range patterns {
+		pattern = strings.TrimPrefix(strings.TrimSuffix(filepath.ToSlash(pattern), "/"), "./")
+		pattern = strings.TrimPrefix(pattern, "**/")
+		for i := range elems {
+			if ok, _ := path.Match(pattern, strings.Join(elems[:i+1], "/")); ok {
+				return true
+			}
+			if !strings.Contains(pattern, "/") {
+				if ok, _ := path.Match(pattern, elems[i]); ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func isUnder(base, p string) bool {
+	rel, err := filepath.Rel(base, p)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator))
+}
@@ -0,0 +1,49 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestMatchingPaths(t *testing.T) {
+	base := filepath.FromSlash("/src")
+	p := func(s string) string { return filepath.Join(base, filepath.FromSlash(s)) }
+
+	trigger := types.Trigger{
+		Path:   base,
+		Ignore: []string{"node_modules/", "*.log", "build/tmp"},
+	}
+	changed := []string{
+		p("main.go"),
+		p("node_modules/foo/index.js"),
+		p("logs/app.log"),
+		p("build/tmp/x"),
+		p("build/out"),
+		p(".git/HEAD"),
+		p("pkg/.main.go.swp"),
+		filepath.FromSlash("/other/main.go"),
+	}
+	assert.DeepEqual(t, matchingPaths(trigger, changed), []string{p("main.go"), p("build/out")})
+
+	trigger.Include = []string{"*.go"}
+	assert.DeepEqual(t, matchingPaths(trigger, changed), []string{p("main.go")})
+}
@@ -48,6 +48,7 @@ type Config struct {
 	DNSSearch        []string `toml:"dns_search,omitempty"`
 	DisableHCSystemd bool     `toml:"disable_hc_systemd"`
 	SelinuxEnabled   bool     `toml:"selinux_enabled"`
+	EmbeddedDNS      bool     `toml:"embedded_dns"`
 }
 
 // New creates a default Config object statically,
@@ -75,5 +76,6 @@ func New() *Config {
 		DNSOpts:          []string{},
 		DNSSearch:        []string{},
 		DisableHCSystemd: false,
+		EmbeddedDNS:      false,
 	}
 }
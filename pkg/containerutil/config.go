@@ -108,6 +108,32 @@ func ReconfigPIDContainer(ctx context.Context, c containerd.Container, client *c
 	return nil
 }
 
+// ReconfigUTSContainer reconfigures the container's spec options for sharing UTS namespace.
+func ReconfigUTSContainer(ctx context.Context, c containerd.Container, client *containerd.Client, lab map[string]string) error {
+	targetContainerID, ok := lab[labels.UTSContainer]
+	if !ok {
+		return nil
+	}
+	if runtime.GOOS != "linux" {
+		return errors.New("sharing UTS namespace is only supported on linux")
+	}
+	targetCon, err := client.LoadContainer(ctx, targetContainerID)
+	if err != nil {
+		return err
+	}
+	opts, err := GenerateSharingUTSOpts(ctx, targetCon)
+	if err != nil {
+		return err
+	}
+	spec, err := c.Spec(ctx)
+	if err != nil {
+		return err
+	}
+	return c.Update(ctx, containerd.UpdateContainerOpts(
+		containerd.WithSpec(spec, oci.Compose(opts...)),
+	))
+}
+
 // ReconfigIPCContainer reconfigures the container's spec options for sharing IPC namespace and volumns.
 func ReconfigIPCContainer(ctx context.Context, c containerd.Container, client *containerd.Client, lab map[string]string) error {
 	ipc, err := ipcutil.DecodeIPCLabel(lab[labels.IPC])
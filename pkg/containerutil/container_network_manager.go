@@ -195,6 +195,10 @@ func (m *noneNetworkManager) VerifyNetworkOptions(_ context.Context) error {
 		return err
 	}
 
+	if len(m.netOpts.NetworkAliases) != 0 {
+		return errors.New("conflicting options: network-scoped aliases are only supported for CNI networks")
+	}
+
 	return nil
 }
 
@@ -372,9 +376,10 @@ func (m *containerNetworkManager) VerifyNetworkOptions(_ context.Context) error
 		"--hostname":   m.netOpts.Hostname,
 		"--domainname": m.netOpts.Domainname,
 		// NOTE: an empty slice still counts as a non-zero value so we check its length:
-		"-p/--publish": len(m.netOpts.PortMappings) != 0,
-		"--dns":        len(m.netOpts.DNSServers) != 0,
-		"--add-host":   len(m.netOpts.AddHost) != 0,
+		"-p/--publish":    len(m.netOpts.PortMappings) != 0,
+		"--dns":           len(m.netOpts.DNSServers) != 0,
+		"--add-host":      len(m.netOpts.AddHost) != 0,
+		"--network-alias": len(m.netOpts.NetworkAliases) != 0,
 	})
 
 	if len(nonZeroParams) != 0 {
@@ -539,6 +544,10 @@ func (m *hostNetworkManager) VerifyNetworkOptions(_ context.Context) error {
 		return errors.New("cannot use host networking on Windows")
 	}
 
+	if len(m.netOpts.NetworkAliases) != 0 {
+		return errors.New("conflicting options: network-scoped aliases are only supported for CNI networks")
+	}
+
 	return validateUtsSettings(m.netOpts)
 }
 
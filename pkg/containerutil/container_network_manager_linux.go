@@ -21,6 +21,7 @@ import (
 	"errors"
 	"io/fs"
 	"path/filepath"
+	"slices"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/pkg/oci"
@@ -33,6 +34,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/netutil"
 	"github.com/containerd/nerdctl/v2/pkg/resolvconf"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
 type cniNetworkManagerPlatform struct {
@@ -137,6 +139,26 @@ func (m *cniNetworkManager) ContainerNetworkingOpts(_ context.Context, container
 	return opts, cOpts, nil
 }
 
+// embeddedDNSServers returns the gateway addresses of the bridge networks of the container,
+// where the embedded DNS servers listen. See pkg/dnsutil/embeddeddns.
+func (m *cniNetworkManager) embeddedDNSServers() ([]string, error) {
+	e, err := netutil.NewCNIEnv(m.globalOptions.CNIPath, m.globalOptions.CNINetConfPath, netutil.WithNamespace(m.globalOptions.Namespace), netutil.WithDefaultNetwork(m.globalOptions.BridgeIP))
+	if err != nil {
+		return nil, err
+	}
+	var servers []string
+	for _, n := range m.netOpts.NetworkSlice {
+		netw, err := e.NetworkByNameOrID(n)
+		if err != nil {
+			return nil, err
+		}
+		for _, gw := range netw.BridgeGateways() {
+			servers = append(servers, gw.String())
+		}
+	}
+	return strutil.DedupeStrSlice(servers), nil
+}
+
 func (m *cniNetworkManager) buildResolvConf(resolvConfPath string) error {
 	var err error
 	slirp4Dns := []string{}
@@ -153,6 +175,19 @@ func (m *cniNetworkManager) buildResolvConf(resolvConfPath string) error {
 		dnsOptions    = m.netOpts.DNSResolvConfOptions
 	)
 
+	// Point at the embedded DNS servers unless --dns is specified.
+	// The global DNS servers are used as upstreams by the embedded DNS servers.
+	if m.globalOptions.EmbeddedDNS && (len(nameServers) == 0 || slices.Equal(nameServers, strutil.DedupeStrSlice(m.globalOptions.DNS))) {
+		gateways, err := m.embeddedDNSServers()
+		if err != nil {
+			return err
+		}
+		if len(gateways) > 0 {
+			nameServers = gateways
+			slirp4Dns = nil
+		}
+	}
+
 	// Use host defaults if any DNS settings are missing:
 	if len(nameServers) == 0 || len(searchDomains) == 0 || len(dnsOptions) == 0 {
 		conf, err := resolvconf.Get()
@@ -44,15 +44,19 @@ import (
 	"github.com/containerd/go-cni"
 	"github.com/containerd/log"
 
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/config"
 	"github.com/containerd/nerdctl/v2/pkg/consoleutil"
 	"github.com/containerd/nerdctl/v2/pkg/errutil"
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
 	"github.com/containerd/nerdctl/v2/pkg/formatter"
 	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/ipcutil"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/labels/k8slabels"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+	"github.com/containerd/nerdctl/v2/pkg/sdnotify"
 	"github.com/containerd/nerdctl/v2/pkg/signalutil"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 	"github.com/containerd/nerdctl/v2/pkg/taskutil"
@@ -87,6 +91,21 @@ func ContainerStatus(ctx context.Context, c containerd.Container) (containerd.St
 	return task.Status(ctx)
 }
 
+// ContainerState returns the Docker-compatible state of the container, e.g., "exited" for a stopped task.
+func ContainerState(ctx context.Context, c containerd.Container) string {
+	status, err := ContainerStatus(ctx, c)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return string(containerd.Created)
+		}
+		return string(containerd.Unknown)
+	}
+	if status.Status == containerd.Stopped {
+		return "exited"
+	}
+	return string(status.Status)
+}
+
 // ContainerNetNSPath returns the netns path of a container.
 func ContainerNetNSPath(ctx context.Context, c containerd.Container) (string, error) {
 	task, err := c.Task(ctx, nil)
@@ -103,6 +122,26 @@ func ContainerNetNSPath(ctx context.Context, c containerd.Container) (string, er
 	return fmt.Sprintf("/proc/%d/ns/net", task.Pid()), nil
 }
 
+// RecordEvent records the event of the container in the event journal of the data store,
+// with the name and the image of the container as attributes.
+func RecordEvent(ctx context.Context, container containerd.Container, dataStore, action string, attributes map[string]string) {
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	if info, err := container.Info(ctx, containerd.WithoutRefreshedMetadata); err == nil {
+		if _, ok := attributes["name"]; !ok && info.Labels[labels.Name] != "" {
+			attributes["name"] = info.Labels[labels.Name]
+		}
+		attributes["image"] = info.Image
+	}
+	eventutil.Record(ctx, dataStore, eventutil.JournalEvent{
+		Type:       eventutil.TypeContainer,
+		Action:     action,
+		ID:         container.ID(),
+		Attributes: attributes,
+	})
+}
+
 // UpdateStatusLabel updates the "containerd.io/restart.status"
 // label of the container according to the value of restart desired status.
 func UpdateStatusLabel(ctx context.Context, container containerd.Container, status containerd.ProcessStatus) error {
@@ -204,6 +243,27 @@ func GenerateSharingPIDOpts(ctx context.Context, targetCon containerd.Container)
 	return opts, nil
 }
 
+// GenerateSharingUTSOpts returns the oci.SpecOpts that shares the UTS namespace of `targetCon`.
+// `targetCon` must be running.
+func GenerateSharingUTSOpts(ctx context.Context, targetCon containerd.Container) ([]oci.SpecOpts, error) {
+	task, err := targetCon.Task(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	status, err := task.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if status.Status != containerd.Running {
+		return nil, fmt.Errorf("shared container is not running")
+	}
+	ns := specs.LinuxNamespace{
+		Type: specs.UTSNamespace,
+		Path: fmt.Sprintf("/proc/%d/ns/uts", task.Pid()),
+	}
+	return []oci.SpecOpts{oci.WithLinuxNamespace(ns)}, nil
+}
+
 // Start starts `container` with `attach` flag. If `attach` is true, it will attach to the container's stdio.
 func Start(ctx context.Context, container containerd.Container, isAttach bool, isInteractive bool, client *containerd.Client, detachKeys string, checkpointDir string, cfg *config.Config, nerdctlCmd string, nerdctlArgs []string) (err error) {
 	// defer the storage of start error in the dedicated label
@@ -232,6 +292,15 @@ func Start(ctx context.Context, container containerd.Container, isAttach bool, i
 		return err
 	}
 
+	if err := ReconfigUTSContainer(ctx, container, client, lab); err != nil {
+		return err
+	}
+
+	dataStore, err := clientutil.DataStore(cfg.DataRoot, cfg.Address)
+	if err != nil {
+		return err
+	}
+
 	process, err := container.Spec(ctx)
 	if err != nil {
 		return err
@@ -269,6 +338,15 @@ func Start(ctx context.Context, container containerd.Container, isAttach bool, i
 		// source: https://github.com/containerd/nerdctl/blob/main/docs/command-reference.md#whale-nerdctl-start
 		attachStreamOpt = []string{"STDOUT", "STDERR"}
 	}
+	if err := MountVolumes(lab, container.ID(), dataStore); err != nil {
+		return err
+	}
+	// The container may notify systemd as soon as its task starts
+	notifier := &sdnotify.Notifier{}
+	if isAttach {
+		notifier = sdnotify.Prepare(ctx, container, lab)
+	}
+	defer notifier.Stop()
 	task, err := taskutil.NewTask(ctx, client, container, taskutil.TaskOptions{
 		AttachStreamOpt: attachStreamOpt,
 		IsInteractive:   isInteractive,
@@ -282,6 +360,9 @@ func Start(ctx context.Context, container containerd.Container, isAttach bool, i
 		CheckpointDir:   checkpointDir,
 	})
 	if err != nil {
+		if unmountErr := UnmountVolumes(lab, container.ID(), dataStore); unmountErr != nil {
+			log.G(ctx).WithError(unmountErr).Warnf("failed to unmount the volumes of container %s", container.ID())
+		}
 		return err
 	}
 	statusC, err := task.Wait(ctx)
@@ -291,6 +372,7 @@ func Start(ctx context.Context, container containerd.Container, isAttach bool, i
 	if err := task.Start(ctx); err != nil {
 		return err
 	}
+	RecordEvent(ctx, container, dataStore, "start", nil)
 
 	// Set status label running should call after task is started.
 	_, restartPolicyExist := lab[restart.PolicyLabel]
@@ -314,6 +396,7 @@ func Start(ctx context.Context, container containerd.Container, isAttach bool, i
 	if !isAttach {
 		return nil
 	}
+	notifier.Start(ctx)
 	if isAttach && isTerminal {
 		if err := consoleutil.HandleConsoleResize(ctx, task, con); err != nil {
 			log.G(ctx).WithError(err).Error("console resize")
@@ -618,6 +701,47 @@ func GetContainerVolumes(containerLabels map[string]string) []*ContainerVolume {
 	return vols
 }
 
+// MountVolumes mounts the named volumes of the container that are backed by a driver requiring a mount,
+// e.g., `nerdctl volume create -o type=nfs`. The volumes are unmounted by the OCI hook when the container stops.
+func MountVolumes(containerLabels map[string]string, id, dataStore string) error {
+	return forEachVolume(containerLabels, dataStore, func(volStore volumestore.VolumeStore, name string) error {
+		if err := volStore.Mount(name, id); err != nil {
+			return fmt.Errorf("failed to mount volume %q: %w", name, err)
+		}
+		return nil
+	})
+}
+
+// UnmountVolumes reverts MountVolumes, for when the task of the container could not be created.
+func UnmountVolumes(containerLabels map[string]string, id, dataStore string) error {
+	return forEachVolume(containerLabels, dataStore, func(volStore volumestore.VolumeStore, name string) error {
+		if err := volStore.Unmount(name, id); err != nil {
+			return fmt.Errorf("failed to unmount volume %q: %w", name, err)
+		}
+		return nil
+	})
+}
+
+func forEachVolume(containerLabels map[string]string, dataStore string, fn func(volStore volumestore.VolumeStore, name string) error) error {
+	var volStore volumestore.VolumeStore
+	for _, vol := range GetContainerVolumes(containerLabels) {
+		if vol.Type != "volume" || vol.Name == "" {
+			continue
+		}
+		if volStore == nil {
+			var err error
+			volStore, err = volumestore.New(dataStore, containerLabels[labels.Namespace])
+			if err != nil {
+				return err
+			}
+		}
+		if err := fn(volStore, vol.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func GetContainerName(containerLabels map[string]string) string {
 	if name, ok := containerLabels[labels.Name]; ok {
 		return name
@@ -0,0 +1,337 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package containerutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/containernetworking/cni/libcni"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/pkg/oci"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/dnsutil/hostsstore"
+	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/netutil"
+	"github.com/containerd/nerdctl/v2/pkg/netutil/nettype"
+	"github.com/containerd/nerdctl/v2/pkg/ocihook/state"
+)
+
+// ConnectNetwork connects the container to a CNI network.
+// If the container is running, the network is attached to its network namespace right away,
+// otherwise it will be attached on the next start.
+// NOTE: the addresses set with IPAddress and IP6Address are not retained across restarts.
+func ConnectNetwork(ctx context.Context, container containerd.Container, options types.NetworkConnectOptions) error {
+	e, lbls, networks, err := containerCNINetworks(ctx, container, options.GOptions)
+	if err != nil {
+		return err
+	}
+	netw, err := e.NetworkByNameOrID(options.Network)
+	if err != nil {
+		return err
+	}
+	if idx, err := networkIndex(e, networks, netw.Name); err != nil {
+		return err
+	} else if idx >= 0 {
+		return fmt.Errorf("container %s is already connected to network %s", container.ID(), netw.Name)
+	}
+	aliases, err := containerNetworkAliases(container, lbls)
+	if err != nil {
+		return err
+	}
+	if len(options.Aliases) > 0 {
+		aliases[netw.Name] = options.Aliases
+	}
+
+	unlock, err := lockCNI(options.GOptions)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	nsPath, err := ContainerNetNSPath(ctx, container)
+	if err != nil {
+		// not running, the network will be set up by the OCI hook on the next start
+		return updateNetworksLabel(ctx, container, append(networks, netw.Name), aliases)
+	}
+	rt := &libcni.RuntimeConf{
+		ContainerID: options.GOptions.Namespace + "-" + container.ID(),
+		NetNS:       nsPath,
+		Args: [][2]string{
+			{"IgnoreUnknown", "1"},
+			{"NERDCTL_CNI_DHCP_HOSTNAME", lbls[labels.Hostname]},
+		},
+		CapabilityArgs: map[string]interface{}{},
+	}
+	if options.IPAddress != "" {
+		rt.Args = append(rt.Args, [2]string{"IP", options.IPAddress})
+	}
+	if options.IP6Address != "" {
+		rt.CapabilityArgs["ips"] = []string{options.IP6Address}
+	}
+	if err := attachNetwork(ctx, e, container, lbls, networks, netw, rt, options); err != nil {
+		return err
+	}
+	newNetworks := append(slices.Clone(networks), netw.Name)
+	if err := updateNetworksLabel(ctx, container, newNetworks, aliases); err != nil {
+		if detachErr := detachNetwork(ctx, e, container, lbls, newNetworks, netw.Name, netw, rt, options.GOptions); detachErr != nil {
+			log.G(ctx).WithError(detachErr).Warnf("failed to detach network %s from container %s", netw.Name, container.ID())
+		}
+		return err
+	}
+	return nil
+}
+
+// DisconnectNetwork disconnects the container from a CNI network.
+// The container cannot be disconnected from its last network.
+func DisconnectNetwork(ctx context.Context, container containerd.Container, options types.NetworkDisconnectOptions) error {
+	e, lbls, networks, err := containerCNINetworks(ctx, container, options.GOptions)
+	if err != nil {
+		return err
+	}
+	netw, err := e.NetworkByNameOrID(options.Network)
+	if err != nil {
+		return err
+	}
+	idx, err := networkIndex(e, networks, netw.Name)
+	if err != nil {
+		return err
+	}
+	if idx < 0 {
+		return fmt.Errorf("container %s is not connected to network %s", container.ID(), netw.Name)
+	}
+	if len(networks) == 1 {
+		return fmt.Errorf("container %s cannot be disconnected from its last network %s", container.ID(), netw.Name)
+	}
+	aliases, err := containerNetworkAliases(container, lbls)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := lockCNI(options.GOptions)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if nsPath, err := ContainerNetNSPath(ctx, container); err == nil {
+		rt := &libcni.RuntimeConf{
+			ContainerID: options.GOptions.Namespace + "-" + container.ID(),
+			NetNS:       nsPath,
+			Args:        [][2]string{{"IgnoreUnknown", "1"}},
+		}
+		if err := detachNetwork(ctx, e, container, lbls, networks, networks[idx], netw, rt, options.GOptions); err != nil {
+			return err
+		}
+	}
+
+	delete(aliases, networks[idx])
+	return updateNetworksLabel(ctx, container, slices.Delete(slices.Clone(networks), idx, idx+1), aliases)
+}
+
+// containerCNINetworks returns the CNI networks the container is connected to, as recorded in its labels.
+func containerCNINetworks(ctx context.Context, container containerd.Container, globalOptions types.GlobalCommandOptions) (*netutil.CNIEnv, map[string]string, []string, error) {
+	lbls, err := container.Labels(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var networks []string
+	if err := json.Unmarshal([]byte(lbls[labels.Networks]), &networks); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse the networks of container %s: %w", container.ID(), err)
+	}
+	netType, err := nettype.Detect(networks)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if netType != nettype.CNI {
+		return nil, nil, nil, fmt.Errorf("container %s is not using CNI networking (%v): %w", container.ID(), networks, errdefs.ErrInvalidArgument)
+	}
+	e, err := netutil.NewCNIEnv(globalOptions.CNIPath, globalOptions.CNINetConfPath, netutil.WithNamespace(globalOptions.Namespace), netutil.WithDefaultNetwork(globalOptions.BridgeIP))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return e, lbls, networks, nil
+}
+
+// networkIndex returns the index of the network named name in networks, which may contain
+// network names or IDs, or -1.
+func networkIndex(e *netutil.CNIEnv, networks []string, name string) (int, error) {
+	for i, n := range networks {
+		netw, err := e.NetworkByNameOrID(n)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return -1, err
+		}
+		if netw.Name == name {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// lockCNI takes the same lock as the OCI hook, as CNI plugins are not safe to use concurrently.
+func lockCNI(globalOptions types.GlobalCommandOptions) (func(), error) {
+	lock, err := filesystem.Lock(filepath.Join(globalOptions.CNINetConfPath, ".cni-concurrency.lock"))
+	if err != nil {
+		return nil, err
+	}
+	return func() { _ = filesystem.Unlock(lock) }, nil
+}
+
+// attachNetwork runs the CNI ADD operation for the running container and publishes the result through
+// the hostsstore.
+// The interface name is recorded in the container lifecycle state, so that the OCI hook can detach it
+// on postStop.
+func attachNetwork(ctx context.Context, e *netutil.CNIEnv, container containerd.Container, lbls map[string]string,
+	networks []string, netw *netutil.NetworkConfig, rt *libcni.RuntimeConf, options types.NetworkConnectOptions) error {
+	lf, err := state.New(lbls[labels.StateDir])
+	if err != nil {
+		return err
+	}
+	err = lf.Transform(func(lf *state.Store) error {
+		if lf.Networks == nil {
+			lf.Networks = defaultInterfaceNames(networks)
+		}
+		rt.IfName = nextInterfaceName(lf.Networks)
+		res, err := e.Attach(ctx, netw, rt)
+		if err != nil {
+			return err
+		}
+		dataStore, err := clientutil.DataStore(options.GOptions.DataRoot, options.GOptions.Address)
+		if err != nil {
+			return errors.Join(err, e.Detach(ctx, netw, rt))
+		}
+		hs, err := hostsstore.New(dataStore, options.GOptions.Namespace)
+		if err != nil {
+			return errors.Join(err, e.Detach(ctx, netw, rt))
+		}
+		if err := hs.AddNetwork(container.ID(), netw.Name, res, options.Aliases); err != nil {
+			return errors.Join(err, e.Detach(ctx, netw, rt))
+		}
+		lf.Networks[netw.Name] = rt.IfName
+		return nil
+	})
+	return err
+}
+
+// detachNetwork reverts attachNetwork. entry is the network as recorded in the container labels.
+func detachNetwork(ctx context.Context, e *netutil.CNIEnv, container containerd.Container, lbls map[string]string,
+	networks []string, entry string, netw *netutil.NetworkConfig, rt *libcni.RuntimeConf, globalOptions types.GlobalCommandOptions) error {
+	lf, err := state.New(lbls[labels.StateDir])
+	if err != nil {
+		return err
+	}
+	return lf.Transform(func(lf *state.Store) error {
+		if lf.Networks == nil {
+			lf.Networks = defaultInterfaceNames(networks)
+		}
+		ifName, ok := lf.Networks[entry]
+		if !ok {
+			return fmt.Errorf("no interface found for network %s in container %s", entry, container.ID())
+		}
+		rt.IfName = ifName
+		if err := e.Detach(ctx, netw, rt); err != nil {
+			return err
+		}
+		delete(lf.Networks, entry)
+		dataStore, err := clientutil.DataStore(globalOptions.DataRoot, globalOptions.Address)
+		if err != nil {
+			return err
+		}
+		hs, err := hostsstore.New(dataStore, globalOptions.Namespace)
+		if err != nil {
+			return err
+		}
+		if err := hs.RemoveNetwork(container.ID(), entry); err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to update the hosts files after disconnecting container %s", container.ID())
+		}
+		return nil
+	})
+}
+
+// defaultInterfaceNames returns the interface names assigned by go-cni when the OCI hook sets up the networks.
+func defaultInterfaceNames(networks []string) map[string]string {
+	res := make(map[string]string, len(networks))
+	for i, n := range networks {
+		res[n] = fmt.Sprintf("eth%d", i)
+	}
+	return res
+}
+
+func nextInterfaceName(used map[string]string) string {
+	for i := 0; ; i++ {
+		name := fmt.Sprintf("eth%d", i)
+		found := false
+		for _, ifName := range used {
+			if ifName == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return name
+		}
+	}
+}
+
+// containerNetworkAliases returns the network-scoped aliases of the container, as recorded in its labels.
+func containerNetworkAliases(container containerd.Container, lbls map[string]string) (map[string][]string, error) {
+	aliases := make(map[string][]string)
+	if aliasesJSON := lbls[labels.NetworkAliases]; aliasesJSON != "" {
+		if err := json.Unmarshal([]byte(aliasesJSON), &aliases); err != nil {
+			return nil, fmt.Errorf("failed to parse the network aliases of container %s: %w", container.ID(), err)
+		}
+	}
+	return aliases, nil
+}
+
+// updateNetworksLabel updates the networks and the network aliases of the container, both in its labels
+// and in the annotations of its spec, so that the OCI hook sets up the same networks on the next start.
+func updateNetworksLabel(ctx context.Context, container containerd.Container, networks []string, aliases map[string][]string) error {
+	networksJSON, err := json.Marshal(networks)
+	if err != nil {
+		return err
+	}
+	aliasesJSON, err := json.Marshal(aliases)
+	if err != nil {
+		return err
+	}
+	lbls := map[string]string{
+		labels.Networks:       string(networksJSON),
+		labels.NetworkAliases: string(aliasesJSON),
+	}
+	spec, err := container.Spec(ctx)
+	if err != nil {
+		return err
+	}
+	return container.Update(ctx,
+		containerd.UpdateContainerOpts(containerd.WithAdditionalContainerLabels(lbls)),
+		containerd.UpdateContainerOpts(containerd.WithSpec(spec, oci.WithAnnotations(lbls))),
+	)
+}
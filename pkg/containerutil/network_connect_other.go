@@ -0,0 +1,39 @@
+//go:build !linux
+
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package containerutil
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	containerd "github.com/containerd/containerd/v2/client"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+)
+
+// ConnectNetwork connects the container to a CNI network.
+func ConnectNetwork(_ context.Context, _ containerd.Container, _ types.NetworkConnectOptions) error {
+	return fmt.Errorf("network connect is currently unsupported on %s", runtime.GOOS)
+}
+
+// DisconnectNetwork disconnects the container from a CNI network.
+func DisconnectNetwork(_ context.Context, _ containerd.Container, _ types.NetworkDisconnectOptions) error {
+	return fmt.Errorf("network disconnect is currently unsupported on %s", runtime.GOOS)
+}
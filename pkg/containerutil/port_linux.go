@@ -0,0 +1,233 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package containerutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/containernetworking/cni/libcni"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/go-cni"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/bypass4netnsutil"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/dnsutil/hostsstore"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/netutil"
+	"github.com/containerd/nerdctl/v2/pkg/netutil/networkstore"
+	"github.com/containerd/nerdctl/v2/pkg/ocihook"
+	"github.com/containerd/nerdctl/v2/pkg/ocihook/state"
+	"github.com/containerd/nerdctl/v2/pkg/portutil"
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+)
+
+// UpdatePortMappings replaces the port mappings of the container with the ones returned by update,
+// which is called with the current port mappings.
+// If the container is running, the port mappings are applied right away by the portmap plugin of its
+// networks, otherwise they will be applied on the next start.
+func UpdatePortMappings(ctx context.Context, container containerd.Container, globalOptions types.GlobalCommandOptions,
+	update func(ports []cni.PortMapping) ([]cni.PortMapping, error)) error {
+	e, lbls, networks, err := containerCNINetworks(ctx, container, globalOptions)
+	if err != nil {
+		return err
+	}
+	dataStore, err := clientutil.DataStore(globalOptions.DataRoot, globalOptions.Address)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := lockCNI(globalOptions)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	oldPorts, err := portutil.LoadPortMappings(dataStore, globalOptions.Namespace, container.ID(), lbls)
+	if err != nil {
+		return err
+	}
+	newPorts, err := update(slices.Clone(oldPorts))
+	if err != nil {
+		return err
+	}
+
+	if nsPath, err := ContainerNetNSPath(ctx, container); err == nil {
+		if err := applyPortMappings(ctx, e, container, lbls, networks, nsPath, oldPorts, newPorts, globalOptions); err != nil {
+			return err
+		}
+		// The embedded DNS servers answer SRV queries with the published ports
+		hs, err := hostsstore.New(dataStore, globalOptions.Namespace)
+		if err != nil {
+			return err
+		}
+		if err := hs.SetPorts(container.ID(), newPorts); err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to record the ports of container %s for the embedded DNS servers", container.ID())
+		}
+	}
+	return storePortMappings(ctx, container, lbls, dataStore, newPorts, globalOptions)
+}
+
+// applyPortMappings replaces the port mappings of the running container.
+func applyPortMappings(ctx context.Context, e *netutil.CNIEnv, container containerd.Container, lbls map[string]string,
+	networks []string, nsPath string, oldPorts, newPorts []cni.PortMapping, globalOptions types.GlobalCommandOptions) error {
+	spec, err := container.Spec(ctx)
+	if err != nil {
+		return err
+	}
+	if b4nnEnabled, _, err := bypass4netnsutil.IsBypass4netnsEnabled(spec.Annotations); err != nil {
+		return err
+	} else if b4nnEnabled {
+		return errors.New("the port mappings of a running container cannot be updated with bypass4netns")
+	}
+
+	var rootlessPM *rootlessutil.RootlessCNIPortManager
+	cniOldPorts, cniNewPorts := oldPorts, newPorts
+	if rootlessutil.IsRootlessChild() {
+		rlkClient, err := rootlessutil.NewRootlessKitClient()
+		if err != nil {
+			return err
+		}
+		if rootlessPM, err = rootlessutil.NewRootlessCNIPortManager(rlkClient); err != nil {
+			return err
+		}
+		// The ports are set up by CNI in the child namespace, and exposed by the port driver of RootlessKit
+		cniOldPorts = rootlessPM.ChildPortMappings(ctx, oldPorts)
+		cniNewPorts = rootlessPM.ChildPortMappings(ctx, newPorts)
+	}
+
+	lf, err := state.New(lbls[labels.StateDir])
+	if err != nil {
+		return err
+	}
+	if err := lf.Load(); err != nil {
+		return err
+	}
+	ifNames := lf.Networks
+	if ifNames == nil {
+		ifNames = defaultInterfaceNames(networks)
+	}
+	type portMapNetwork struct {
+		netw   *netutil.NetworkConfig
+		ifName string
+	}
+	var applied []portMapNetwork
+	setPortMappings := func(n portMapNetwork, ports []cni.PortMapping) error {
+		rt := &libcni.RuntimeConf{
+			ContainerID: globalOptions.Namespace + "-" + container.ID(),
+			NetNS:       nsPath,
+			IfName:      n.ifName,
+			Args:        [][2]string{{"IgnoreUnknown", "1"}},
+		}
+		return e.SetPortMappings(ctx, n.netw, rt, ports)
+	}
+	rollback := func() {
+		for _, n := range applied {
+			if err := setPortMappings(n, cniOldPorts); err != nil {
+				log.G(ctx).WithError(err).Warnf("failed to restore the port mappings of network %s", n.netw.Name)
+			}
+		}
+	}
+	for _, entry := range networks {
+		netw, err := e.NetworkByNameOrID(entry)
+		if err != nil {
+			rollback()
+			return err
+		}
+		if !netw.HasPortMap() {
+			log.G(ctx).Debugf("network %s has no portmap plugin, skipping", netw.Name)
+			continue
+		}
+		ifName, ok := ifNames[entry]
+		if !ok {
+			rollback()
+			return fmt.Errorf("no interface found for network %s in container %s", entry, container.ID())
+		}
+		n := portMapNetwork{netw: netw, ifName: ifName}
+		applied = append(applied, n)
+		if err := setPortMappings(n, cniNewPorts); err != nil {
+			rollback()
+			return err
+		}
+	}
+	if len(applied) == 0 {
+		return fmt.Errorf("none of the networks of container %s has the portmap plugin", container.ID())
+	}
+
+	if rootlessPM != nil {
+		// Expose the new ports before unexposing the old ones, so that a failure leaves the old ports exposed
+		var exposed []cni.PortMapping
+		for _, p := range newPorts {
+			if slices.ContainsFunc(oldPorts, func(o cni.PortMapping) bool { return sameRootlessPort(o, p) }) {
+				continue
+			}
+			if err := rootlessPM.ExposePort(ctx, p); err != nil {
+				for _, p := range exposed {
+					if err := rootlessPM.UnexposePort(ctx, p); err != nil {
+						log.G(ctx).WithError(err).Warnf("failed to unexpose port %d/%s", p.HostPort, p.Protocol)
+					}
+				}
+				rollback()
+				return fmt.Errorf("failed to expose port %d/%s in rootless mode: %w", p.HostPort, p.Protocol, err)
+			}
+			exposed = append(exposed, p)
+		}
+		for _, p := range oldPorts {
+			if slices.ContainsFunc(newPorts, func(n cni.PortMapping) bool { return sameRootlessPort(n, p) }) {
+				continue
+			}
+			if err := rootlessPM.UnexposePort(ctx, p); err != nil {
+				log.G(ctx).WithError(err).Warnf("failed to unexpose port %d/%s", p.HostPort, p.Protocol)
+			}
+		}
+	} else if err := ocihook.ReplacePortReserverProcess(globalOptions.Namespace, container.ID(), newPorts); err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to reserve the ports of container %s", container.ID())
+	}
+	return nil
+}
+
+// sameRootlessPort returns whether the port mappings are exposed by the same port of RootlessKit,
+// which does not depend on the container port.
+func sameRootlessPort(a, b cni.PortMapping) bool {
+	return a.HostPort == b.HostPort && a.Protocol == b.Protocol && a.HostIP == b.HostIP
+}
+
+// storePortMappings records the port mappings of the container, for `nerdctl port`, `nerdctl inspect`,
+// and the OCI hook on the next start.
+func storePortMappings(ctx context.Context, container containerd.Container, lbls map[string]string, dataStore string,
+	ports []cni.PortMapping, globalOptions types.GlobalCommandOptions) error {
+	if err := portutil.StoreNetworkConfig(dataStore, globalOptions.Namespace, container.ID(), networkstore.NetworkConfig{PortMappings: ports}); err != nil {
+		return err
+	}
+	if lbls[labels.Ports] == "" {
+		return nil
+	}
+	// The legacy label is used when the network config has no port mappings
+	portsJSON, err := json.Marshal(ports)
+	if err != nil {
+		return err
+	}
+	return container.Update(ctx, containerd.UpdateContainerOpts(containerd.WithAdditionalContainerLabels(map[string]string{
+		labels.Ports: string(portsJSON),
+	})))
+}
@@ -0,0 +1,36 @@
+//go:build !linux
+
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package containerutil
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/go-cni"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+)
+
+// UpdatePortMappings replaces the port mappings of the container.
+func UpdatePortMappings(_ context.Context, _ containerd.Container, _ types.GlobalCommandOptions,
+	_ func(ports []cni.PortMapping) ([]cni.PortMapping, error)) error {
+	return fmt.Errorf("updating port mappings is currently unsupported on %s", runtime.GOOS)
+}
@@ -0,0 +1,522 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package embeddeddns provides a DNS server bound to the gateway of a bridge network.
+// It answers for the names of the containers attached to the network (container names, hostnames,
+// network aliases), as recorded by hostsstore, and forwards the other queries to upstream servers.
+// Unlike the /etc/hosts files, it returns all the addresses when several containers share a name
+// (e.g., the replicas of a compose service), in random order, and supports SRV queries, answered with the
+// published ports of the containers, and PTR queries.
+package embeddeddns
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/containerd/go-cni"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/dnsutil/hostsstore"
+)
+
+const (
+	// Port is the port the server listens on.
+	Port = 53
+	// recordTTL is the TTL of the records of the containers, kept short as containers come and go.
+	recordTTL = 10
+	// reloadInterval is the minimum interval between two reads of the hostsstore.
+	reloadInterval = time.Second
+	// upstreamTimeout is the timeout of a query forwarded to an upstream server.
+	upstreamTimeout = 5 * time.Second
+	// maxUDPSize is the maximum size of a response over UDP, as EDNS0 is not supported.
+	maxUDPSize = 512
+)
+
+// Server is the embedded DNS server of a network.
+type Server struct {
+	// DataStore is the nerdctl data store, where hostsstore lives.
+	DataStore string
+	// Address is the gateway address of the network to listen on.
+	Address net.IP
+	// Port overrides Port when set.
+	Port int
+	// Upstreams are the "host:port" servers to forward the other queries to.
+	Upstreams []string
+	// IdleTimeout, when set, makes Serve return once no container has been attached to the
+	// network for that long.
+	IdleTimeout time.Duration
+
+	mu       sync.Mutex
+	records  *records
+	loadedAt time.Time
+	// loadMetas is hostsstore.AllMetas, replaced in tests.
+	loadMetas func(dataStore string) ([]*hostsstore.Meta, error)
+}
+
+// Serve answers the queries received over UDP and TCP until ctx is cancelled.
+func (s *Server) Serve(ctx context.Context) error {
+	port := s.Port
+	if port == 0 {
+		port = Port
+	}
+	addr := net.JoinHostPort(s.Address.String(), strconv.Itoa(port))
+	pc, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return err
+	}
+	defer pc.Close()
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+	log.G(ctx).Infof("embedded DNS server listening on %s, forwarding to %v", addr, s.Upstreams)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go s.serveUDP(ctx, pc)
+	go s.serveTCP(ctx, ln)
+
+	if s.IdleTimeout == 0 {
+		<-ctx.Done()
+		return nil
+	}
+	ticker := time.NewTicker(s.IdleTimeout / 2)
+	defer ticker.Stop()
+	lastUsed := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			recs, err := s.currentRecords()
+			if err != nil {
+				log.G(ctx).WithError(err).Warn("failed to load the container records")
+				continue
+			}
+			if len(recs.names) > 0 {
+				lastUsed = time.Now()
+			} else if time.Since(lastUsed) >= s.IdleTimeout {
+				log.G(ctx).Infof("no container attached to %s anymore, exiting", s.Address)
+				return nil
+			}
+		}
+	}
+}
+
+func (s *Server) serveUDP(ctx context.Context, pc net.PacketConn) {
+	buf := make([]byte, 65535)
+	for {
+		n, raddr, err := pc.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.G(ctx).WithError(err).Error("failed to read a UDP query")
+			}
+			return
+		}
+		req := append([]byte(nil), buf[:n]...)
+		go func() {
+			if resp := s.respond(ctx, req, "udp"); resp != nil {
+				_, _ = pc.WriteTo(resp, raddr)
+			}
+		}()
+	}
+}
+
+func (s *Server) serveTCP(ctx context.Context, ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
+				log.G(ctx).WithError(err).Error("failed to accept a TCP connection")
+			}
+			return
+		}
+		go func() {
+			defer conn.Close()
+			for {
+				_ = conn.SetDeadline(time.Now().Add(upstreamTimeout * 2))
+				req, err := readTCPMessage(conn)
+				if err != nil {
+					return
+				}
+				resp := s.respond(ctx, req, "tcp")
+				if resp == nil || writeTCPMessage(conn, resp) != nil {
+					return
+				}
+			}
+		}()
+	}
+}
+
+// respond returns the response to the query, or nil if the query is not parseable.
+func (s *Server) respond(ctx context.Context, req []byte, network string) []byte {
+	var p dnsmessage.Parser
+	hdr, err := p.Start(req)
+	if err != nil {
+		return nil
+	}
+	q, err := p.Question()
+	if err != nil {
+		return nil
+	}
+	if hdr.Response || hdr.OpCode != 0 {
+		return reply(hdr, q, dnsmessage.RCodeNotImplemented, nil, nil)
+	}
+
+	recs, err := s.currentRecords()
+	if err != nil {
+		log.G(ctx).WithError(err).Warn("failed to load the container records")
+	} else if answers, additionals, ok := recs.answer(q); ok {
+		resp := reply(hdr, q, dnsmessage.RCodeSuccess, answers, additionals)
+		if network == "udp" && len(resp) > maxUDPSize {
+			hdr.Truncated = true
+			resp = reply(hdr, q, dnsmessage.RCodeSuccess, nil, nil)
+		}
+		return resp
+	}
+
+	resp, err := s.forward(ctx, req, network)
+	if err != nil {
+		log.G(ctx).WithError(err).Debugf("failed to forward the query for %s", q.Name)
+		return reply(hdr, q, dnsmessage.RCodeServerFailure, nil, nil)
+	}
+	return resp
+}
+
+func reply(hdr dnsmessage.Header, q dnsmessage.Question, rcode dnsmessage.RCode, answers, additionals []dnsmessage.Resource) []byte {
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{
+		ID:                 hdr.ID,
+		Response:           true,
+		OpCode:             hdr.OpCode,
+		Authoritative:      rcode == dnsmessage.RCodeSuccess,
+		Truncated:          hdr.Truncated,
+		RecursionDesired:   hdr.RecursionDesired,
+		RecursionAvailable: true,
+		RCode:              rcode,
+	})
+	b.EnableCompression()
+	_ = b.StartQuestions()
+	_ = b.Question(q)
+	_ = b.StartAnswers()
+	for _, r := range answers {
+		_ = addResource(&b, r)
+	}
+	_ = b.StartAdditionals()
+	for _, r := range additionals {
+		_ = addResource(&b, r)
+	}
+	msg, err := b.Finish()
+	if err != nil {
+		return nil
+	}
+	return msg
+}
+
+func addResource(b *dnsmessage.Builder, r dnsmessage.Resource) error {
+	switch body := r.Body.(type) {
+	case *dnsmessage.AResource:
+		return b.AResource(r.Header, *body)
+	case *dnsmessage.AAAAResource:
+		return b.AAAAResource(r.Header, *body)
+	case *dnsmessage.SRVResource:
+		return b.SRVResource(r.Header, *body)
+	case *dnsmessage.PTRResource:
+		return b.PTRResource(r.Header, *body)
+	}
+	return fmt.Errorf("unexpected resource type %T", r.Body)
+}
+
+// forward sends the query to the upstream servers in order, and returns the first response.
+func (s *Server) forward(ctx context.Context, req []byte, network string) ([]byte, error) {
+	if len(s.Upstreams) == 0 {
+		return nil, errors.New("no upstream server")
+	}
+	var errs []error
+	for _, upstream := range s.Upstreams {
+		resp, err := exchange(ctx, network, upstream, req)
+		if err == nil {
+			return resp, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+func exchange(ctx context.Context, network, upstream string, req []byte) ([]byte, error) {
+	d := net.Dialer{Timeout: upstreamTimeout}
+	conn, err := d.DialContext(ctx, network, upstream)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(upstreamTimeout)); err != nil {
+		return nil, err
+	}
+	if network == "tcp" {
+		if err := writeTCPMessage(conn, req); err != nil {
+			return nil, err
+		}
+		return readTCPMessage(conn)
+	}
+	if _, err := conn.Write(req); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 65535)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+func readTCPMessage(r io.Reader) ([]byte, error) {
+	var l uint16
+	if err := binary.Read(r, binary.BigEndian, &l); err != nil {
+		return nil, err
+	}
+	msg := make([]byte, l)
+	_, err := io.ReadFull(r, msg)
+	return msg, err
+}
+
+func writeTCPMessage(w io.Writer, msg []byte) error {
+	buf := make([]byte, 2+len(msg))
+	binary.BigEndian.PutUint16(buf, uint16(len(msg)))
+	copy(buf[2:], msg)
+	_, err := w.Write(buf)
+	return err
+}
+
+func (s *Server) currentRecords() (*records, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.records != nil && time.Since(s.loadedAt) < reloadInterval {
+		return s.records, nil
+	}
+	loadMetas := s.loadMetas
+	if loadMetas == nil {
+		loadMetas = hostsstore.AllMetas
+	}
+	metas, err := loadMetas(s.DataStore)
+	if err != nil {
+		return nil, err
+	}
+	s.records = newRecords(metas, s.Address)
+	s.loadedAt = time.Now()
+	return s.records, nil
+}
+
+// records are the names of the containers reachable through the gateway.
+type records struct {
+	// ips maps the lowercased names, without trailing dot, to the addresses
+	ips map[string][]net.IP
+	// names maps the addresses to the canonical name of the container
+	names map[string]string
+	// ports maps the addresses to the published ports of the container
+	ports map[string][]cni.PortMapping
+}
+
+func newRecords(metas []*hostsstore.Meta, gateway net.IP) *records {
+	r := &records{
+		ips:   make(map[string][]net.IP),
+		names: make(map[string]string),
+		ports: make(map[string][]cni.PortMapping),
+	}
+	for _, meta := range metas {
+		for netName, res := range meta.Networks {
+			if res == nil {
+				continue
+			}
+			for _, ipCfg := range res.IPs {
+				ip := ipCfg.Address.IP
+				if ip == nil || !ipCfg.Gateway.Equal(gateway) {
+					continue
+				}
+				names := meta.Names(netName)
+				for _, name := range names {
+					key := strings.ToLower(name)
+					if !containsIP(r.ips[key], ip) {
+						r.ips[key] = append(r.ips[key], ip)
+					}
+				}
+				canonical := meta.Name
+				if canonical == "" {
+					canonical = meta.Hostname
+				}
+				if canonical != "" {
+					r.names[ip.String()] = canonical
+				}
+				r.ports[ip.String()] = meta.Ports
+			}
+		}
+	}
+	return r
+}
+
+func containsIP(ips []net.IP, ip net.IP) bool {
+	for _, i := range ips {
+		if i.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// answer returns the records for the question, and false if the name is not a container name,
+// in which case the query should be forwarded.
+func (r *records) answer(q dnsmessage.Question) (answers, additionals []dnsmessage.Resource, ok bool) {
+	if q.Class != dnsmessage.ClassINET {
+		return nil, nil, false
+	}
+	name := strings.ToLower(strings.TrimSuffix(q.Name.String(), "."))
+	switch q.Type {
+	case dnsmessage.TypePTR:
+		ip := reverseIP(name)
+		if ip == nil {
+			return nil, nil, false
+		}
+		canonical, ok := r.names[ip.String()]
+		if !ok {
+			return nil, nil, false
+		}
+		target, err := dnsmessage.NewName(canonical + ".")
+		if err != nil {
+			return nil, nil, false
+		}
+		return []dnsmessage.Resource{{
+			Header: resourceHeader(q.Name),
+			Body:   &dnsmessage.PTRResource{PTR: target},
+		}}, nil, true
+	case dnsmessage.TypeSRV:
+		// _service._proto.name
+		parts := strings.SplitN(name, ".", 3)
+		if len(parts) != 3 || !strings.HasPrefix(parts[0], "_") || !strings.HasPrefix(parts[1], "_") {
+			return nil, nil, false
+		}
+		ips, ok := r.ips[parts[2]]
+		if !ok {
+			return nil, nil, false
+		}
+		proto := parts[1][1:]
+		// A known service (e.g., "_http") selects its port, otherwise all the ports of the protocol are returned
+		wantPort, _ := net.LookupPort(proto, parts[0][1:])
+		for _, ip := range shuffle(ips) {
+			canonical, ok := r.names[ip.String()]
+			if !ok {
+				continue
+			}
+			target, err := dnsmessage.NewName(canonical + ".")
+			if err != nil {
+				continue
+			}
+			var ports []int
+			for _, p := range r.ports[ip.String()] {
+				// The other containers of the network reach the container port, not the host port
+				if p.Protocol != proto || (wantPort != 0 && int(p.ContainerPort) != wantPort) || slices.Contains(ports, int(p.ContainerPort)) {
+					continue
+				}
+				ports = append(ports, int(p.ContainerPort))
+				answers = append(answers, dnsmessage.Resource{
+					Header: resourceHeader(q.Name),
+					Body:   &dnsmessage.SRVResource{Target: target, Port: uint16(p.ContainerPort)},
+				})
+			}
+			if len(ports) > 0 {
+				additionals = append(additionals, addressResources(target, []net.IP{ip}, dnsmessage.TypeA)...)
+				additionals = append(additionals, addressResources(target, []net.IP{ip}, dnsmessage.TypeAAAA)...)
+			}
+		}
+		return answers, additionals, true
+	}
+
+	ips, ok := r.ips[name]
+	if !ok {
+		return nil, nil, false
+	}
+	// The name is a container name: do not forward queries of other types, reply with no data.
+	return addressResources(q.Name, shuffle(ips), q.Type), nil, true
+}
+
+// addressResources returns the A or AAAA resources of the addresses, depending on typ.
+func addressResources(name dnsmessage.Name, ips []net.IP, typ dnsmessage.Type) []dnsmessage.Resource {
+	var res []dnsmessage.Resource
+	for _, ip := range ips {
+		ip4 := ip.To4()
+		switch {
+		case typ == dnsmessage.TypeA && ip4 != nil:
+			res = append(res, dnsmessage.Resource{
+				Header: resourceHeader(name),
+				Body:   &dnsmessage.AResource{A: [4]byte(ip4)},
+			})
+		case typ == dnsmessage.TypeAAAA && ip4 == nil:
+			res = append(res, dnsmessage.Resource{
+				Header: resourceHeader(name),
+				Body:   &dnsmessage.AAAAResource{AAAA: [16]byte(ip.To16())},
+			})
+		}
+	}
+	return res
+}
+
+func resourceHeader(name dnsmessage.Name) dnsmessage.ResourceHeader {
+	return dnsmessage.ResourceHeader{Name: name, Class: dnsmessage.ClassINET, TTL: recordTTL}
+}
+
+// shuffle returns the addresses in random order, for round-robin load balancing.
+func shuffle(ips []net.IP) []net.IP {
+	res := append([]net.IP(nil), ips...)
+	rand.Shuffle(len(res), func(i, j int) { res[i], res[j] = res[j], res[i] })
+	return res
+}
+
+// reverseIP parses a name of the in-addr.arpa or ip6.arpa domains.
+func reverseIP(name string) net.IP {
+	switch {
+	case strings.HasSuffix(name, ".in-addr.arpa"):
+		labels := strings.Split(strings.TrimSuffix(name, ".in-addr.arpa"), ".")
+		if len(labels) != 4 {
+			return nil
+		}
+		return net.ParseIP(labels[3] + "." + labels[2] + "." + labels[1] + "." + labels[0])
+	case strings.HasSuffix(name, ".ip6.arpa"):
+		labels := strings.Split(strings.TrimSuffix(name, ".ip6.arpa"), ".")
+		if len(labels) != 32 {
+			return nil
+		}
+		var sb strings.Builder
+		for i := 31; i >= 0; i-- {
+			sb.WriteString(labels[i])
+			if i%4 == 0 && i != 0 {
+				sb.WriteByte(':')
+			}
+		}
+		return net.ParseIP(sb.String())
+	}
+	return nil
+}
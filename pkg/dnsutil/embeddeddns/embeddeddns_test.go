@@ -0,0 +1,186 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package embeddeddns
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	types100 "github.com/containernetworking/cni/pkg/types/100"
+	"golang.org/x/net/dns/dnsmessage"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/go-cni"
+
+	"github.com/containerd/nerdctl/v2/pkg/dnsutil/hostsstore"
+)
+
+func testMeta(name, network, ip, gateway string, aliases ...string) *hostsstore.Meta {
+	_, ipNet, _ := net.ParseCIDR(ip + "/24")
+	ipNet.IP = net.ParseIP(ip)
+	return &hostsstore.Meta{
+		ID:       name + "-id",
+		Name:     name,
+		Hostname: name + "-id",
+		Networks: map[string]*types100.Result{
+			network: {
+				IPs: []*types100.IPConfig{{Address: *ipNet, Gateway: net.ParseIP(gateway)}},
+			},
+		},
+		Aliases: map[string][]string{network: aliases},
+	}
+}
+
+func testServer(upstreams ...string) *Server {
+	metas := []*hostsstore.Meta{
+		testMeta("web1", "n1", "10.4.0.2", "10.4.0.1", "web"),
+		testMeta("web2", "n1", "10.4.0.3", "10.4.0.1", "web"),
+		testMeta("other", "n2", "10.5.0.2", "10.5.0.1"),
+	}
+	for _, meta := range metas[:2] {
+		meta.Ports = []cni.PortMapping{
+			{HostPort: 8080, ContainerPort: 80, Protocol: "tcp", HostIP: "0.0.0.0"},
+			{HostPort: 8080, ContainerPort: 80, Protocol: "tcp", HostIP: "::"},
+			{HostPort: 8443, ContainerPort: 443, Protocol: "tcp"},
+			{HostPort: 5353, ContainerPort: 53, Protocol: "udp"},
+		}
+	}
+	metas[1].Ports = metas[1].Ports[:2]
+	return &Server{
+		Address:   net.ParseIP("10.4.0.1"),
+		Upstreams: upstreams,
+		loadMetas: func(string) ([]*hostsstore.Meta, error) { return metas, nil },
+	}
+}
+
+func query(t *testing.T, s *Server, name string, typ dnsmessage.Type) *dnsmessage.Message {
+	t.Helper()
+	msg := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: 42, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: dnsmessage.MustNewName(name), Type: typ, Class: dnsmessage.ClassINET}},
+	}
+	req, err := msg.Pack()
+	assert.NilError(t, err)
+	resp := s.respond(context.Background(), req, "udp")
+	assert.Assert(t, resp != nil)
+	var res dnsmessage.Message
+	assert.NilError(t, res.Unpack(resp))
+	assert.Equal(t, res.ID, uint16(42))
+	return &res
+}
+
+func TestRespondA(t *testing.T) {
+	s := testServer()
+
+	res := query(t, s, "web1.", dnsmessage.TypeA)
+	assert.Equal(t, res.RCode, dnsmessage.RCodeSuccess)
+	assert.Assert(t, res.Authoritative)
+	assert.Equal(t, len(res.Answers), 1)
+	assert.Equal(t, res.Answers[0].Body.(*dnsmessage.AResource).A, [4]byte{10, 4, 0, 2})
+
+	// Names are case-insensitive, and the network suffix is resolvable
+	res = query(t, s, "WEB2.n1.", dnsmessage.TypeA)
+	assert.Equal(t, len(res.Answers), 1)
+	assert.Equal(t, res.Answers[0].Body.(*dnsmessage.AResource).A, [4]byte{10, 4, 0, 3})
+
+	// Aliases shared by several containers are balanced between them
+	res = query(t, s, "web.", dnsmessage.TypeA)
+	assert.Equal(t, len(res.Answers), 2)
+
+	// Known names without records of the type have no data, and are not forwarded
+	res = query(t, s, "web1.", dnsmessage.TypeAAAA)
+	assert.Equal(t, res.RCode, dnsmessage.RCodeSuccess)
+	assert.Equal(t, len(res.Answers), 0)
+}
+
+func TestRespondOtherNetwork(t *testing.T) {
+	// Containers of other networks are not resolvable, the query is forwarded to the (missing) upstreams
+	res := query(t, testServer(), "other.", dnsmessage.TypeA)
+	assert.Equal(t, res.RCode, dnsmessage.RCodeServerFailure)
+}
+
+func TestRespondPTR(t *testing.T) {
+	res := query(t, testServer(), "2.0.4.10.in-addr.arpa.", dnsmessage.TypePTR)
+	assert.Equal(t, len(res.Answers), 1)
+	assert.Equal(t, res.Answers[0].Body.(*dnsmessage.PTRResource).PTR.String(), "web1.")
+}
+
+func TestRespondSRV(t *testing.T) {
+	s := testServer()
+
+	// the container port, not the host port, once per container
+	res := query(t, s, "_http._tcp.web.", dnsmessage.TypeSRV)
+	assert.Equal(t, len(res.Answers), 2)
+	for _, a := range res.Answers {
+		assert.Equal(t, a.Body.(*dnsmessage.SRVResource).Port, uint16(80))
+	}
+	assert.Equal(t, len(res.Additionals), 2)
+
+	// only web1 publishes 443
+	res = query(t, s, "_https._tcp.web.", dnsmessage.TypeSRV)
+	assert.Equal(t, len(res.Answers), 1)
+	assert.Equal(t, res.Answers[0].Body.(*dnsmessage.SRVResource).Target.String(), "web1.")
+
+	// an unknown service returns all the ports of the protocol
+	res = query(t, s, "_app._tcp.web1.", dnsmessage.TypeSRV)
+	assert.Equal(t, len(res.Answers), 2)
+	res = query(t, s, "_app._udp.web1.", dnsmessage.TypeSRV)
+	assert.Equal(t, len(res.Answers), 1)
+	assert.Equal(t, res.Answers[0].Body.(*dnsmessage.SRVResource).Port, uint16(53))
+
+	// by hostname
+	res = query(t, s, "_http._tcp.web2-id.", dnsmessage.TypeSRV)
+	assert.Equal(t, len(res.Answers), 1)
+
+	// no published port, but a container name: no data rather than forwarded
+	res = query(t, s, "_http._udp.web2.", dnsmessage.TypeSRV)
+	assert.Equal(t, res.RCode, dnsmessage.RCodeSuccess)
+	assert.Equal(t, len(res.Answers), 0)
+}
+
+func TestRespondForward(t *testing.T) {
+	upstream, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer upstream.Close()
+	go func() {
+		buf := make([]byte, 512)
+		n, addr, err := upstream.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		var msg dnsmessage.Message
+		if err := msg.Unpack(buf[:n]); err != nil {
+			return
+		}
+		msg.Response = true
+		msg.Answers = []dnsmessage.Resource{{
+			Header: dnsmessage.ResourceHeader{Name: msg.Questions[0].Name, Class: dnsmessage.ClassINET, TTL: 60},
+			Body:   &dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}},
+		}}
+		resp, err := msg.Pack()
+		if err != nil {
+			return
+		}
+		_, _ = upstream.WriteTo(resp, addr)
+	}()
+
+	res := query(t, testServer(upstream.LocalAddr().String()), "example.com.", dnsmessage.TypeA)
+	assert.Equal(t, res.RCode, dnsmessage.RCodeSuccess)
+	assert.Equal(t, len(res.Answers), 1)
+	assert.Equal(t, res.Answers[0].Body.(*dnsmessage.AResource).A, [4]byte{192, 0, 2, 1})
+}
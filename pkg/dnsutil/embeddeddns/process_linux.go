@@ -0,0 +1,97 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package embeddeddns
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// dirBasename is the base name of /var/lib/nerdctl/<ADDRHASH>/embeddeddns,
+// where the pid and log files of the servers are stored.
+const dirBasename = "embeddeddns"
+
+// EnsureServer starts the server listening on address as a detached process, unless it is already running.
+// cmd is the command running the server, e.g., {"/usr/local/bin/nerdctl", "--data-root=/foo", "internal", "dns-server"},
+// to which the address is appended.
+func EnsureServer(cmd []string, dataStore string, address net.IP) error {
+	if len(cmd) == 0 {
+		return errors.New("the command of the embedded DNS server must be set")
+	}
+	dir := filepath.Join(dataStore, dirBasename)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	base := filepath.Join(dir, address.String())
+	if pid, err := readPIDFile(base + ".pid"); err == nil && isServerProcess(pid) {
+		return nil
+	}
+
+	logFile, err := os.OpenFile(base+".log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	c := exec.Command(cmd[0], append(cmd[1:], "--address="+address.String())...)
+	c.Stdout = logFile
+	c.Stderr = logFile
+	// Detach from the OCI hook, which is waited for by the runtime
+	c.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := c.Start(); err != nil {
+		return fmt.Errorf("failed to start the embedded DNS server for %s: %w", address, err)
+	}
+	pid := c.Process.Pid
+	if err := os.WriteFile(base+".pid", []byte(strconv.Itoa(pid)), 0o600); err != nil {
+		_ = c.Process.Kill()
+		return err
+	}
+	return c.Process.Release()
+}
+
+// RemovePIDFile removes the pid file of the server listening on address, if it belongs to the current process.
+func RemovePIDFile(dataStore string, address net.IP) error {
+	pidFile := filepath.Join(dataStore, dirBasename, address.String()+".pid")
+	if pid, err := readPIDFile(pidFile); err != nil || pid != os.Getpid() {
+		return nil
+	}
+	return os.Remove(pidFile)
+}
+
+func readPIDFile(path string) (int, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(b)))
+}
+
+// isServerProcess checks that the process exists and is a server, as the pid may have been reused.
+func isServerProcess(pid int) bool {
+	cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		return false
+	}
+	return bytes.Contains(cmdline, []byte("dns-server"))
+}
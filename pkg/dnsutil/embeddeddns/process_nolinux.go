@@ -0,0 +1,35 @@
+//go:build !linux
+
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package embeddeddns
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+)
+
+// EnsureServer starts the server listening on address as a detached process, unless it is already running.
+func EnsureServer(_ []string, _ string, _ net.IP) error {
+	return fmt.Errorf("the embedded DNS server is currently unsupported on %s", runtime.GOOS)
+}
+
+// RemovePIDFile removes the pid file of the server listening on address, if it belongs to the current process.
+func RemovePIDFile(_ string, _ net.IP) error {
+	return nil
+}
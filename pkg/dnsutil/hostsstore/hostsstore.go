@@ -38,6 +38,7 @@ import (
 	types100 "github.com/containernetworking/cni/pkg/types/100"
 
 	"github.com/containerd/errdefs"
+	"github.com/containerd/go-cni"
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
@@ -84,12 +85,51 @@ type Meta struct {
 	ExtraHosts map[string]string // host:ip
 	Name       string
 	Domainname string
+	Aliases    map[string][]string // network:aliases
+	Ports      []cni.PortMapping   // published ports, for the SRV records of the embedded DNS server
+}
+
+// Names returns the names under which the container is reachable from the network,
+// as written in the hosts files of the other containers.
+func (meta *Meta) Names(network string) []string {
+	return createLine(network, meta, map[string]struct{}{network: {}})
+}
+
+// AllMetas returns the metadata of the running containers of all the namespaces.
+func AllMetas(dataStore string) ([]*Meta, error) {
+	entries, err := os.ReadDir(filepath.Join(dataStore, hostsDirBasename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Join(ErrHostsStore, err)
+	}
+
+	var metas []*Meta
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		st, err := New(dataStore, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		nsMetas, err := st.(*hostsStore).metas()
+		if err != nil {
+			return nil, err
+		}
+		metas = append(metas, nsMetas...)
+	}
+	return metas, nil
 }
 
 type Store interface {
 	Acquire(Meta) error
 	Release(id string) error
 	Update(id, newName string) error
+	AddNetwork(id, network string, result *types100.Result, aliases []string) error
+	RemoveNetwork(id, network string) error
+	SetPorts(id string, ports []cni.PortMapping) error
 	HostsPath(id string) (location string, err error)
 	Delete(id string) (err error)
 	AllocHostsFile(id string, content []byte) (location string, err error)
@@ -259,6 +299,99 @@ func (x *hostsStore) Update(id, newName string) (err error) {
 	})
 }
 
+// AddNetwork records a network attached to a running container, e.g., by `nerdctl network connect`.
+func (x *hostsStore) AddNetwork(id, network string, result *types100.Result, aliases []string) error {
+	return x.transformMeta(id, func(meta *Meta) {
+		if meta.Networks == nil {
+			meta.Networks = make(map[string]*types100.Result)
+		}
+		meta.Networks[network] = result
+		if len(aliases) > 0 {
+			if meta.Aliases == nil {
+				meta.Aliases = make(map[string][]string)
+			}
+			meta.Aliases[network] = aliases
+		}
+	})
+}
+
+// RemoveNetwork forgets a network detached from a running container, e.g., by `nerdctl network disconnect`.
+func (x *hostsStore) RemoveNetwork(id, network string) error {
+	return x.transformMeta(id, func(meta *Meta) {
+		delete(meta.Networks, network)
+		delete(meta.Aliases, network)
+	})
+}
+
+// SetPorts records the published ports of a running container, e.g., updated by `nerdctl port add`.
+func (x *hostsStore) SetPorts(id string, ports []cni.PortMapping) error {
+	return x.transformMeta(id, func(meta *Meta) {
+		meta.Ports = ports
+	})
+}
+
+func (x *hostsStore) transformMeta(id string, fun func(meta *Meta)) (err error) {
+	defer func() {
+		if err != nil {
+			err = errors.Join(ErrHostsStore, err)
+		}
+	}()
+
+	return x.safeStore.WithLock(func() error {
+		var content []byte
+		if content, err = x.safeStore.Get(id, metaJSON); err != nil {
+			return err
+		}
+
+		meta := &Meta{}
+		if err = json.Unmarshal(content, meta); err != nil {
+			return err
+		}
+
+		fun(meta)
+		content, err = json.Marshal(meta)
+		if err != nil {
+			return err
+		}
+
+		if err = x.safeStore.Set(content, id, metaJSON); err != nil {
+			return err
+		}
+
+		return x.updateAllHosts()
+	})
+}
+
+func (x *hostsStore) metas() (metas []*Meta, err error) {
+	defer func() {
+		if err != nil {
+			err = errors.Join(ErrHostsStore, err)
+		}
+	}()
+
+	err = x.safeStore.WithLock(func() error {
+		entries, err := x.safeStore.List()
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			content, err := x.safeStore.Get(entry, metaJSON)
+			if err != nil {
+				// the container is not running
+				continue
+			}
+			meta := &Meta{}
+			if err := json.Unmarshal(content, meta); err != nil {
+				log.L.WithError(err).Warnf("unable to unmarshal %q", entry)
+				continue
+			}
+			metas = append(metas, meta)
+		}
+		return nil
+	})
+	return metas, err
+}
+
 func (x *hostsStore) updateAllHosts() (err error) {
 	entries, err := x.safeStore.List()
 	if err != nil {
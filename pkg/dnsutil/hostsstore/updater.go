@@ -52,5 +52,8 @@ func createLine(thatNetwork string, meta *Meta, myNetworks map[string]struct{})
 			line = append(line, baseHostname+"."+thatNetwork)
 		}
 	}
+
+	// Aliases are only resolvable from the network they were declared on
+	line = append(line, meta.Aliases[thatNetwork]...)
 	return line
 }
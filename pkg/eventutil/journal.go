@@ -0,0 +1,275 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/containerd/containerd/v2/pkg/namespaces"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
+)
+
+// Types of the journal events
+const (
+	TypeContainer = "container"
+	TypeImage     = "image"
+	TypeNetwork   = "network"
+	TypeVolume    = "volume"
+)
+
+const (
+	journalDirName  = "events"
+	journalFileName = "journal.jsonl"
+)
+
+// maxJournalSize is the size above which the journal is rotated.
+// Only the previous journal file is kept, so that the journal is bounded.
+var maxJournalSize int64 = 4 * 1024 * 1024
+
+// JournalEvent is an event recorded in the event journal by the nerdctl commands,
+// so that `nerdctl events --since` can replay it.
+type JournalEvent struct {
+	Timestamp time.Time
+	Namespace string
+	// Type is the type of the object, e.g. "container"
+	Type string
+	// Action is the Docker-compatible action, e.g. "create" or "die"
+	Action string
+	// ID is the ID of the object, or its name for volumes and images
+	ID         string
+	Attributes map[string]string `json:",omitempty"`
+}
+
+// Topic returns the topic of the event, in the style of the containerd topics, e.g. "/containers/create".
+func (e *JournalEvent) Topic() string {
+	return "/" + e.Type + "s/" + e.Action
+}
+
+// JournalPath returns the path of the event journal of the data store.
+func JournalPath(dataStore string) string {
+	return filepath.Join(dataStore, journalDirName, journalFileName)
+}
+
+// Record appends the event to the event journal of the data store.
+// The timestamp and the namespace default to the current time and the namespace of the context.
+// Failures are only logged, as the journal must not fail the commands.
+func Record(ctx context.Context, dataStore string, e JournalEvent) {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	if e.Namespace == "" {
+		e.Namespace, _ = namespaces.Namespace(ctx)
+	}
+	if err := appendJournal(JournalPath(dataStore), &e); err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to record the %s %s event of %s", e.Type, e.Action, e.ID)
+	}
+}
+
+func appendJournal(journalPath string, e *JournalEvent) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(journalPath)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	return filesystem.WithLock(dir, func() error {
+		if st, err := os.Stat(journalPath); err == nil && st.Size() >= maxJournalSize {
+			if err := os.Rename(journalPath, journalPath+".1"); err != nil {
+				return err
+			}
+		}
+		f, err := os.OpenFile(journalPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(append(b, '\n'))
+		return errors.Join(err, f.Close())
+	})
+}
+
+// ReadJournal returns the events of the event journal of the data store between since and until (inclusive),
+// ordered by time. A zero since or until is unbounded.
+func ReadJournal(dataStore string, since, until time.Time) ([]JournalEvent, error) {
+	journalPath := JournalPath(dataStore)
+	var res []JournalEvent
+	for _, p := range []string{journalPath + ".1", journalPath} {
+		events, err := readJournalFile(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range events {
+			if (!since.IsZero() && e.Timestamp.Before(since)) || (!until.IsZero() && e.Timestamp.After(until)) {
+				continue
+			}
+			res = append(res, e)
+		}
+	}
+	// The events are appended when they are recorded, which may differ slightly from their time (e.g. "die")
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Timestamp.Before(res[j].Timestamp)
+	})
+	return res, nil
+}
+
+// LastExitCode returns the exit code of the last "die" event of the container recorded since since,
+// and whether there is one. It is the exit code of a container whose task was deleted.
+func LastExitCode(dataStore, id string, since time.Time) (uint32, bool, error) {
+	journal, err := ReadJournal(dataStore, since, time.Time{})
+	if err != nil {
+		return 0, false, err
+	}
+	for i := len(journal) - 1; i >= 0; i-- {
+		e := journal[i]
+		if e.Type != TypeContainer || e.ID != id || e.Action != "die" {
+			continue
+		}
+		exitCode, err := strconv.ParseUint(e.Attributes["exitCode"], 10, 32)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid exit code in the event journal: %w", err)
+		}
+		return uint32(exitCode), true, nil
+	}
+	return 0, false, nil
+}
+
+func readJournalFile(p string) ([]JournalEvent, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	var events []JournalEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e JournalEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			// e.g., a line being written
+			log.L.WithError(err).Debugf("ignoring a malformed line of the event journal %q", p)
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, scanner.Err()
+}
+
+// JournalTail reads the events appended to the event journal after it was created, across the rotations of the journal.
+type JournalTail struct {
+	path string
+	// info is the journal file being read, nil if there was none
+	info   os.FileInfo
+	offset int64
+}
+
+// TailJournal returns a JournalTail at the end of the event journal of the data store.
+func TailJournal(dataStore string) (*JournalTail, error) {
+	t := &JournalTail{path: JournalPath(dataStore)}
+	st, err := os.Stat(t.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return t, nil
+		}
+		return nil, err
+	}
+	t.info, t.offset = st, st.Size()
+	return t, nil
+}
+
+// Next returns the events appended since the previous call, in the order they were recorded.
+// The lines being written are left for the next call.
+func (t *JournalTail) Next() ([]JournalEvent, error) {
+	cur, err := os.Stat(t.path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		cur = nil
+	}
+	var res []JournalEvent
+	if t.info != nil && (cur == nil || !os.SameFile(t.info, cur)) {
+		// Rotated: read the end of the previous journal file first
+		if prev, err := os.Stat(t.path + ".1"); err == nil && os.SameFile(t.info, prev) {
+			events, _, err := readJournalFrom(t.path+".1", t.offset)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, events...)
+		}
+		t.info, t.offset = nil, 0
+	}
+	if cur == nil {
+		return res, nil
+	}
+	events, offset, err := readJournalFrom(t.path, t.offset)
+	if err != nil {
+		return nil, err
+	}
+	t.info, t.offset = cur, offset
+	return append(res, events...), nil
+}
+
+// readJournalFrom returns the events of the complete lines of the journal file after offset,
+// and the offset following them.
+func readJournalFrom(p string, offset int64) ([]JournalEvent, int64, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, offset, nil
+		}
+		return nil, offset, err
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, err
+	}
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return nil, offset, err
+	}
+	end := bytes.LastIndexByte(b, '\n') + 1
+	var events []JournalEvent
+	for _, line := range bytes.Split(b[:end], []byte{'\n'}) {
+		if len(line) == 0 {
+			continue
+		}
+		var e JournalEvent
+		if err := json.Unmarshal(line, &e); err != nil {
+			log.L.WithError(err).Debugf("ignoring a malformed line of the event journal %q", p)
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, offset + int64(end), nil
+}
@@ -0,0 +1,171 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/pkg/namespaces"
+)
+
+func TestJournal(t *testing.T) {
+	dataStore := t.TempDir()
+	ctx := namespaces.WithNamespace(context.Background(), "test")
+	start := time.Now()
+
+	events, err := ReadJournal(dataStore, time.Time{}, time.Time{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(events), 0)
+
+	Record(ctx, dataStore, JournalEvent{Type: TypeContainer, Action: "create", ID: "c1", Attributes: map[string]string{"name": "foo"}})
+	Record(ctx, dataStore, JournalEvent{Type: TypeNetwork, Action: "create", ID: "n1", Namespace: "other"})
+	// recorded after the event happened
+	Record(ctx, dataStore, JournalEvent{Type: TypeContainer, Action: "die", ID: "c0", Timestamp: start.Add(-time.Minute)})
+
+	events, err = ReadJournal(dataStore, time.Time{}, time.Time{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(events), 3)
+	assert.Equal(t, events[0].ID, "c0")
+	assert.Equal(t, events[1].Topic(), "/containers/create")
+	assert.Equal(t, events[1].Namespace, "test")
+	assert.Equal(t, events[1].Attributes["name"], "foo")
+	assert.Equal(t, events[2].Namespace, "other")
+
+	events, err = ReadJournal(dataStore, start, time.Time{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(events), 2)
+
+	events, err = ReadJournal(dataStore, time.Time{}, start)
+	assert.NilError(t, err)
+	assert.Equal(t, len(events), 1)
+	assert.Equal(t, events[0].ID, "c0")
+}
+
+func TestLastExitCode(t *testing.T) {
+	dataStore := t.TempDir()
+	ctx := namespaces.WithNamespace(context.Background(), "test")
+	start := time.Now()
+
+	_, ok, err := LastExitCode(dataStore, "c1", time.Time{})
+	assert.NilError(t, err)
+	assert.Assert(t, !ok)
+
+	Record(ctx, dataStore, JournalEvent{Type: TypeContainer, Action: "die", ID: "c1", Timestamp: start.Add(-time.Minute),
+		Attributes: map[string]string{"exitCode": "1"}})
+	Record(ctx, dataStore, JournalEvent{Type: TypeContainer, Action: "die", ID: "c1", Attributes: map[string]string{"exitCode": "2"}})
+	Record(ctx, dataStore, JournalEvent{Type: TypeContainer, Action: "die", ID: "c2", Attributes: map[string]string{"exitCode": "3"}})
+
+	code, ok, err := LastExitCode(dataStore, "c1", time.Time{})
+	assert.NilError(t, err)
+	assert.Assert(t, ok)
+	assert.Equal(t, code, uint32(2))
+
+	_, ok, err = LastExitCode(dataStore, "c1", start.Add(time.Hour))
+	assert.NilError(t, err)
+	assert.Assert(t, !ok)
+}
+
+func TestJournalRotation(t *testing.T) {
+	defer func(size int64) { maxJournalSize = size }(maxJournalSize)
+	maxJournalSize = 1024
+
+	dataStore := t.TempDir()
+	ctx := namespaces.WithNamespace(context.Background(), "test")
+	for i := 0; i < 100; i++ {
+		Record(ctx, dataStore, JournalEvent{Type: TypeVolume, Action: "create", ID: fmt.Sprintf("vol%d", i)})
+	}
+	st, err := os.Stat(JournalPath(dataStore))
+	assert.NilError(t, err)
+	assert.Assert(t, st.Size() < 2*maxJournalSize)
+
+	events, err := ReadJournal(dataStore, time.Time{}, time.Time{})
+	assert.NilError(t, err)
+	assert.Assert(t, len(events) < 100)
+	// the latest events are kept
+	assert.Equal(t, events[len(events)-1].ID, "vol99")
+}
+
+func TestJournalMalformedLine(t *testing.T) {
+	dataStore := t.TempDir()
+	ctx := namespaces.WithNamespace(context.Background(), "test")
+	Record(ctx, dataStore, JournalEvent{Type: TypeImage, Action: "pull", ID: "alpine"})
+	f, err := os.OpenFile(JournalPath(dataStore), os.O_WRONLY|os.O_APPEND, 0o600)
+	assert.NilError(t, err)
+	_, err = f.WriteString(`{"Type":"ima`)
+	assert.NilError(t, err)
+	assert.NilError(t, f.Close())
+
+	events, err := ReadJournal(dataStore, time.Time{}, time.Time{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(events), 1)
+}
+
+func TestTailJournal(t *testing.T) {
+	defer func(size int64) { maxJournalSize = size }(maxJournalSize)
+	maxJournalSize = 1024
+
+	dataStore := t.TempDir()
+	ctx := namespaces.WithNamespace(context.Background(), "test")
+	Record(ctx, dataStore, JournalEvent{Type: TypeImage, Action: "pull", ID: "before"})
+
+	tail, err := TailJournal(dataStore)
+	assert.NilError(t, err)
+	events, err := tail.Next()
+	assert.NilError(t, err)
+	assert.Equal(t, len(events), 0)
+
+	Record(ctx, dataStore, JournalEvent{Type: TypeImage, Action: "tag", ID: "img0"})
+	f, err := os.OpenFile(JournalPath(dataStore), os.O_WRONLY|os.O_APPEND, 0o600)
+	assert.NilError(t, err)
+	_, err = f.WriteString(`{"Type":"ima`)
+	assert.NilError(t, err)
+	events, err = tail.Next()
+	assert.NilError(t, err)
+	assert.Equal(t, len(events), 1)
+	assert.Equal(t, events[0].ID, "img0")
+
+	// the line being written is read once complete
+	_, err = f.WriteString(`ge","Action":"push","ID":"img1"}` + "\n")
+	assert.NilError(t, err)
+	assert.NilError(t, f.Close())
+	events, err = tail.Next()
+	assert.NilError(t, err)
+	assert.Equal(t, len(events), 1)
+	assert.Equal(t, events[0].ID, "img1")
+
+	// across a rotation
+	var n int
+	for ; ; n++ {
+		Record(ctx, dataStore, JournalEvent{Type: TypeVolume, Action: "create", ID: fmt.Sprintf("vol%d", n)})
+		if _, err := os.Stat(JournalPath(dataStore) + ".1"); err == nil {
+			break
+		}
+	}
+	Record(ctx, dataStore, JournalEvent{Type: TypeVolume, Action: "create", ID: fmt.Sprintf("vol%d", n+1)})
+	events, err = tail.Next()
+	assert.NilError(t, err)
+	assert.Equal(t, len(events), n+2)
+	for i, e := range events {
+		assert.Equal(t, e.ID, fmt.Sprintf("vol%d", i))
+	}
+}
@@ -0,0 +1,35 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"github.com/containerd/typeurl/v2"
+)
+
+// OnFailureEventTopic is the topic of the events published when a failure action is applied
+const OnFailureEventTopic = "/containers/health-on-failure"
+
+// OnFailureEvent records the failure action applied to a container that became unhealthy
+type OnFailureEvent struct {
+	ContainerID string          `json:"container_id"`
+	Status      HealthStatus    `json:"status"`
+	Action      OnFailureAction `json:"action"`
+}
+
+func init() {
+	typeurl.Register(&OnFailureEvent{}, "github.com/containerd/nerdctl/v2/pkg/healthcheck", "OnFailureEvent")
+}
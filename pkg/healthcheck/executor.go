@@ -30,10 +30,15 @@ import (
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/idgen"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
 )
 
-// ExecuteHealthCheck executes the health check command for a container
-func ExecuteHealthCheck(ctx context.Context, task containerd.Task, container containerd.Container, hc *Healthcheck) error {
+// OnFailureFunc applies the failure action (--health-on-failure) to a container that became unhealthy
+type OnFailureFunc func(ctx context.Context, container containerd.Container, action OnFailureAction) error
+
+// ExecuteHealthCheck executes the health check command for a container.
+// onFailure is called when the container becomes unhealthy and a failure action other than "none" is configured.
+func ExecuteHealthCheck(ctx context.Context, task containerd.Task, container containerd.Container, hc *Healthcheck, onFailure OnFailureFunc) error {
 	// Prepare process spec for health check command
 	processSpec, err := prepareProcessSpec(ctx, container, hc)
 	if err != nil {
@@ -51,13 +56,13 @@ func ExecuteHealthCheck(ctx context.Context, task containerd.Task, container con
 			End:      time.Now(),
 			ExitCode: -1,
 			Output:   err.Error(),
-		})
+		}, onFailure)
 		return fmt.Errorf("health check probe failed: %w", err)
 	}
 
 	// Success case, update health status
 	result.Start = startTime
-	if err := updateHealthStatus(ctx, container, hc, result); err != nil {
+	if err := updateHealthStatus(ctx, container, hc, result, onFailure); err != nil {
 		return fmt.Errorf("failed to update health status: %w", err)
 	}
 	return nil
@@ -123,7 +128,7 @@ func probeHealthCheck(ctx context.Context, task containerd.Task, hc *Healthcheck
 }
 
 // updateHealthStatus updates the health status based on the health check result
-func updateHealthStatus(ctx context.Context, container containerd.Container, hcConfig *Healthcheck, hcResult *HealthcheckResult) error {
+func updateHealthStatus(ctx context.Context, container containerd.Container, hcConfig *Healthcheck, hcResult *HealthcheckResult, onFailure OnFailureFunc) error {
 	// Get current health state from labels
 	currentHealth, err := readHealthStateFromLabels(ctx, container)
 	if err != nil {
@@ -149,6 +154,8 @@ func updateHealthStatus(ctx context.Context, container containerd.Container, hcC
 	// Check if we're in start period workflow
 	inStartPeriodTime := hcResult.Start.Sub(containerCreated) < hcConfig.StartPeriod
 	inStartPeriodState := currentHealth.InStartPeriod
+	currentHealth.LastProbe = hcResult.Start
+	becameUnhealthy := false
 
 	if inStartPeriodTime && inStartPeriodState {
 		// Start Period Workflow
@@ -170,6 +177,7 @@ func updateHealthStatus(ctx context.Context, container containerd.Container, hcC
 			currentHealth.FailingStreak++
 			if currentHealth.FailingStreak >= hcConfig.Retries && currentHealth.Status != Unhealthy {
 				currentHealth.Status = Unhealthy
+				becameUnhealthy = true
 			}
 		}
 	}
@@ -183,6 +191,33 @@ func updateHealthStatus(ctx context.Context, container containerd.Container, hcC
 	if err := writeHealthLog(ctx, container, hcResult); err != nil {
 		return fmt.Errorf("failed to write health log: %w", err)
 	}
+
+	if becameUnhealthy {
+		return applyOnFailureAction(ctx, container, onFailure)
+	}
+	return nil
+}
+
+// applyOnFailureAction applies the failure action configured for the container that became unhealthy
+func applyOnFailureAction(ctx context.Context, container containerd.Container, onFailure OnFailureFunc) error {
+	lbs, err := container.Labels(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get container labels: %w", err)
+	}
+	action, ok := lbs[labels.HealthOnFailure]
+	if !ok || action == OnFailureNone || onFailure == nil {
+		return nil
+	}
+	log.G(ctx).Infof("container %s is unhealthy, applying health check failure action %q", container.ID(), action)
+	if err := onFailure(ctx, container, action); err != nil {
+		return fmt.Errorf("failed to apply health check failure action %q: %w", action, err)
+	}
+	if action == OnFailureRestart {
+		// The restarted container starts over, so that it can be acted on again when it becomes unhealthy
+		if err := writeHealthStateToLabels(ctx, container, &HealthState{Status: Starting}); err != nil {
+			return fmt.Errorf("failed to write health state to labels: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -18,6 +18,7 @@ package healthcheck
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -50,6 +51,27 @@ const (
 	HealthLogFilename      = "health.json"    // HealthLogFilename is the name of the file used to persist health check status for a container.
 )
 
+// OnFailureAction is the action taken when a container becomes unhealthy (--health-on-failure).
+type OnFailureAction = string
+
+// Health check failure actions
+const (
+	OnFailureNone    OnFailureAction = "none"    // Only mark the container as unhealthy
+	OnFailureKill    OnFailureAction = "kill"    // Kill the container
+	OnFailureRestart OnFailureAction = "restart" // Restart the container
+	OnFailureStop    OnFailureAction = "stop"    // Stop the container
+)
+
+// ValidateOnFailureAction checks that the action is one of the supported failure actions
+func ValidateOnFailureAction(action string) error {
+	switch action {
+	case OnFailureNone, OnFailureKill, OnFailureRestart, OnFailureStop:
+		return nil
+	}
+	return fmt.Errorf("invalid health check failure action %q, must be one of %q, %q, %q or %q",
+		action, OnFailureNone, OnFailureKill, OnFailureRestart, OnFailureStop)
+}
+
 // NOTE: Health, HealthcheckResult and Healthcheck types are kept Docker-compatible.
 // See: https://github.com/moby/moby/blob/9d1b069a4bfdcee368e67767978eff596b696d4c/api/types/container/health.go
 // Health stores information about the container's healthcheck results
@@ -69,11 +91,12 @@ type HealthcheckResult struct {
 
 // Healthcheck represents the health check configuration
 type Healthcheck struct {
-	Test        []string      `json:"Test,omitempty"`        // Test is the check to perform that the container is healthy
-	Interval    time.Duration `json:"Interval,omitempty"`    // Interval is the time to wait between checks
-	Timeout     time.Duration `json:"Timeout,omitempty"`     // Timeout is the time to wait before considering the check to have hung
-	Retries     int           `json:"Retries,omitempty"`     // Retries is the number of consecutive failures needed to consider a container as unhealthy
-	StartPeriod time.Duration `json:"StartPeriod,omitempty"` // StartPeriod is the period for the container to initialize before the health check starts
+	Test          []string      `json:"Test,omitempty"`          // Test is the check to perform that the container is healthy
+	Interval      time.Duration `json:"Interval,omitempty"`      // Interval is the time to wait between checks
+	Timeout       time.Duration `json:"Timeout,omitempty"`       // Timeout is the time to wait before considering the check to have hung
+	Retries       int           `json:"Retries,omitempty"`       // Retries is the number of consecutive failures needed to consider a container as unhealthy
+	StartPeriod   time.Duration `json:"StartPeriod,omitempty"`   // StartPeriod is the period for the container to initialize before the health check starts
+	StartInterval time.Duration `json:"StartInterval,omitempty"` // StartInterval is the time to wait between checks during the start period
 }
 
 // HealthState stores the current health state of a container
@@ -81,6 +104,7 @@ type HealthState struct {
 	Status        HealthStatus // Status is one of [Starting], [Healthy] or [Unhealthy]
 	FailingStreak int          // FailingStreak is the number of consecutive failures
 	InStartPeriod bool         // InStartPeriod indicates if we're in the start period workflow
+	LastProbe     time.Time    // LastProbe is the time the last check started
 }
 
 // ToJSONString serializes HealthState to a JSON string for label storage
@@ -152,3 +176,27 @@ func (hc *Healthcheck) ApplyDefaults() {
 		hc.Retries = DefaultProbeRetries
 	}
 }
+
+// TimerInterval returns the interval between the runs of the health check timer.
+// It is the start interval when one was set to run the checks faster during the start period.
+// The start interval has no default, as the timer keeps running at it after the start period.
+func (hc *Healthcheck) TimerInterval() time.Duration {
+	if hc.StartPeriod > 0 && hc.StartInterval > 0 && hc.StartInterval < hc.Interval {
+		return hc.StartInterval
+	}
+	return hc.Interval
+}
+
+// IsProbeDue returns whether a check run by the health check timer is due.
+// During the start period, every run of the timer checks the container, while afterwards
+// the checks are spaced by the interval.
+func IsProbeDue(hc *Healthcheck, state *HealthState, created, now time.Time) bool {
+	if state == nil || state.LastProbe.IsZero() {
+		return true
+	}
+	if state.InStartPeriod && now.Sub(created) < hc.StartPeriod {
+		return true
+	}
+	// the timer runs every TimerInterval, so allow for half of it to keep the checks around the interval
+	return now.Sub(state.LastProbe) >= hc.Interval-hc.TimerInterval()/2
+}
@@ -0,0 +1,74 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTimerInterval(t *testing.T) {
+	hc := &Healthcheck{Interval: 30 * time.Second, StartInterval: 5 * time.Second}
+	assert.Equal(t, hc.TimerInterval(), 30*time.Second, "no start period")
+
+	hc.StartPeriod = time.Minute
+	assert.Equal(t, hc.TimerInterval(), 5*time.Second)
+
+	hc.StartInterval = time.Minute
+	assert.Equal(t, hc.TimerInterval(), 30*time.Second, "start interval longer than interval")
+}
+
+func TestTimerIntervalAfterStartPeriod(t *testing.T) {
+	// The timer keeps its interval after the start period, so it only runs faster when asked to
+	hc := &Healthcheck{Test: []string{"CMD", "true"}, StartPeriod: time.Minute}
+	hc.ApplyDefaults()
+	assert.Equal(t, hc.StartInterval, time.Duration(0))
+	assert.Equal(t, hc.TimerInterval(), DefaultProbeInterval)
+
+	created := time.Now()
+	healthy := &HealthState{Status: Healthy, LastProbe: created.Add(2 * time.Minute)}
+	assert.Assert(t, !IsProbeDue(hc, healthy, created, created.Add(2*time.Minute+hc.TimerInterval()/3)))
+	assert.Assert(t, IsProbeDue(hc, healthy, created, created.Add(2*time.Minute+hc.TimerInterval())))
+}
+
+func TestIsProbeDue(t *testing.T) {
+	created := time.Now()
+	hc := &Healthcheck{Interval: 30 * time.Second, StartPeriod: time.Minute, StartInterval: 5 * time.Second}
+
+	assert.Assert(t, IsProbeDue(hc, nil, created, created.Add(time.Second)), "first check")
+
+	starting := &HealthState{Status: Starting, InStartPeriod: true, LastProbe: created.Add(10 * time.Second)}
+	assert.Assert(t, IsProbeDue(hc, starting, created, created.Add(15*time.Second)), "start period")
+
+	healthy := &HealthState{Status: Healthy, LastProbe: created.Add(20 * time.Second)}
+	assert.Assert(t, !IsProbeDue(hc, healthy, created, created.Add(25*time.Second)))
+	assert.Assert(t, IsProbeDue(hc, healthy, created, created.Add(48*time.Second)))
+
+	// start period elapsed without a healthy check
+	starting.LastProbe = created.Add(62 * time.Second)
+	assert.Assert(t, !IsProbeDue(hc, starting, created, created.Add(65*time.Second)))
+	assert.Assert(t, IsProbeDue(hc, starting, created, created.Add(90*time.Second)))
+}
+
+func TestValidateOnFailureAction(t *testing.T) {
+	for _, action := range []string{OnFailureNone, OnFailureKill, OnFailureRestart, OnFailureStop} {
+		assert.NilError(t, ValidateOnFailureAction(action))
+	}
+	assert.ErrorContains(t, ValidateOnFailureAction("pause"), "invalid health check failure action")
+}
@@ -24,6 +24,11 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/config"
 )
 
+// TimersEnabled returns whether the health checks are run by systemd timers.
+func TimersEnabled(cfg *config.Config) bool {
+	return false
+}
+
 // CreateTimer sets up the transient systemd timer and service for healthchecks.
 func CreateTimer(ctx context.Context, container containerd.Container, cfg *config.Config, nerdctlCmd string, nerdctlArgs []string) error {
 	return nil
@@ -39,6 +44,11 @@ func RemoveTransientHealthCheckFiles(ctx context.Context, container containerd.C
 	return nil
 }
 
+// RemoveTimer stops the transient timer of the container, but not its service.
+func RemoveTimer(ctx context.Context, container containerd.Container) error {
+	return nil
+}
+
 // CleanupStaleHealthcheckTimer removes any pre-existing transient timer unit for the given container.
 func CleanupStaleHealthcheckTimer(ctx context.Context, containerID string) {}
 
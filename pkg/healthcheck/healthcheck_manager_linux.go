@@ -62,18 +62,19 @@ func CreateTimer(ctx context.Context, container containerd.Container, cfg *confi
 		cmdOpts = append(cmdOpts, "--setenv=BUILDKIT_HOST="+buildKitHost)
 	}
 
-	// Always use health-interval for timer frequency
+	// Use health-interval for timer frequency, or health-start-interval when the checks run faster
+	// during the start period. In the latter case, the runs that are not due are skipped (see IsProbeDue).
 	//
 	// --collect:
 	// Even when the healthcheck fails with the error "container is not running" after the container has
 	// stopped, and the transient service unit enters a failed state, it will still be subject to garbage
 	// collection due to the --collect option. Without this option, `systemctl reset-failed` would explicitly be needed.
 	// See: https://www.freedesktop.org/software/systemd/man/latest/systemd-run.html#-G
-	cmdOpts = append(cmdOpts, "--unit", containerID, "--on-unit-inactive="+hc.Interval.String(), "--timer-property=AccuracySec=1s", "--collect")
+	cmdOpts = append(cmdOpts, "--unit", containerID, "--on-unit-inactive="+hc.TimerInterval().String(), "--timer-property=AccuracySec=1s", "--collect")
 
 	cmdOpts = append(cmdOpts, nerdctlCmd)
 	cmdOpts = append(cmdOpts, nerdctlArgs...)
-	cmdOpts = append(cmdOpts, "container", "healthcheck", containerID)
+	cmdOpts = append(cmdOpts, "container", "healthcheck", "--timer", containerID)
 
 	// Defensively remove any pre-existing transient timer unit that may have leaked from a previous run
 	// (e.g. when restarted before the self-cleanup in HealthCheck has a chance to run). Without this,
@@ -143,6 +144,23 @@ func RemoveTransientHealthCheckFiles(ctx context.Context, container containerd.C
 	return ForceRemoveTransientHealthCheckFiles(ctx, container.ID())
 }
 
+// RemoveTimer stops the transient timer of the container, but not its service, so that it can be called
+// from the health check run by the service, e.g. when the failure action stops the container.
+func RemoveTimer(ctx context.Context, container containerd.Container) error {
+	if extractHealthcheck(ctx, container) == nil {
+		return nil
+	}
+	conn, err := createDbusConn(ctx)
+	if err != nil {
+		return fmt.Errorf("systemd DBUS connect error: %w", err)
+	}
+	defer conn.Close()
+	timeoutCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	stopSystemdUnit(ctx, timeoutCtx, conn, container.ID()+".timer")
+	return nil
+}
+
 func CleanupStaleHealthcheckTimer(ctx context.Context, containerID string) {
 	conn, err := createDbusConn(ctx)
 	if err != nil {
@@ -272,10 +290,16 @@ func extractHealthcheck(ctx context.Context, container containerd.Container) *He
 	return hc
 }
 
+// TimersEnabled returns whether the health checks are run by systemd timers.
+// When they are not, the health state of the containers is only updated by `nerdctl container healthcheck`.
+func TimersEnabled(cfg *config.Config) bool {
+	return defaults.IsSystemdAvailable() && !cfg.DisableHCSystemd && !rootlessutil.IsRootless()
+}
+
 // shouldSkipHealthCheckSystemd determines if healthcheck timers should be skipped.
 func shouldSkipHealthCheckSystemd(hc *Healthcheck, cfg *config.Config) bool {
 	// Don't proceed if systemd is unavailable or disabled
-	if !defaults.IsSystemdAvailable() || cfg.DisableHCSystemd || rootlessutil.IsRootless() {
+	if !TimersEnabled(cfg) {
 		return true
 	}
 
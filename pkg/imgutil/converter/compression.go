@@ -0,0 +1,249 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package converter
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/images/converter"
+	"github.com/containerd/log"
+	"github.com/containerd/stargz-snapshotter/estargz"
+	"github.com/containerd/stargz-snapshotter/estargz/zstdchunked"
+	estargzconvert "github.com/containerd/stargz-snapshotter/nativeconverter/estargz"
+	zstdchunkedconvert "github.com/containerd/stargz-snapshotter/nativeconverter/zstdchunked"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+)
+
+// Layer compressions supported by CompressionLayerConvertFunc.
+const (
+	CompressionGzip        = "gzip"
+	CompressionZstd        = "zstd"
+	CompressionZstdChunked = "zstd:chunked"
+	CompressionEstargz     = "estargz"
+)
+
+const (
+	// convertedLabelPrefix is the prefix of the content label that records,
+	// on a source blob, the descriptor of its converted counterpart.
+	convertedLabelPrefix = "nerdctl/converted."
+	// convertedGCLabelPrefix is the prefix of the content label that keeps the
+	// converted blob alive as long as the source blob exists.
+	convertedGCLabelPrefix = "containerd.io/gc.ref.content.nerdctl.converted."
+)
+
+// Compressions returns the layer compressions supported by CompressionLayerConvertFunc.
+func Compressions() []string {
+	return []string{CompressionGzip, CompressionZstd, CompressionZstdChunked, CompressionEstargz}
+}
+
+// ValidateCompression returns an error if compression is not supported.
+func ValidateCompression(compression string) error {
+	for _, c := range Compressions() {
+		if c == compression {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported compression %q, must be one of %s", compression, strings.Join(Compressions(), ", "))
+}
+
+// DefaultCompressionLevel returns the compression level used when none is specified.
+func DefaultCompressionLevel(compression string) int {
+	switch compression {
+	case CompressionGzip:
+		return gzip.DefaultCompression
+	case CompressionEstargz:
+		return gzip.BestCompression
+	default:
+		return 3 // zstd.SpeedDefault
+	}
+}
+
+// CompressionLayerConvertFunc returns a ConvertFunc that recompresses the layers with the given
+// compression and level. A negative level selects DefaultCompressionLevel.
+//
+// Layers that are already compressed with the requested compression are left untouched,
+// unless force is set.
+//
+// Converted blobs are cached in the content store: the source blob records the descriptor
+// of its converted counterpart in its labels, so that pushing the same layer again, or as
+// part of another image, does not convert it twice.
+func CompressionLayerConvertFunc(compression string, level int, force bool) (converter.ConvertFunc, error) {
+	if err := ValidateCompression(compression); err != nil {
+		return nil, err
+	}
+	if level < 0 {
+		level = DefaultCompressionLevel(compression)
+	}
+	var convertFunc converter.ConvertFunc
+	switch compression {
+	case CompressionGzip:
+		convertFunc = gzipLayerConvertFunc(level)
+	case CompressionZstd:
+		f, err := ZstdLayerConvertFunc(types.ImageConvertOptions{ZstdOptions: types.ZstdOptions{ZstdCompressionLevel: level}})
+		if err != nil {
+			return nil, err
+		}
+		convertFunc = f
+	case CompressionZstdChunked:
+		convertFunc = zstdchunkedconvert.LayerConvertFuncWithCompressionLevel(zstd.EncoderLevelFromZstd(level))
+	case CompressionEstargz:
+		convertFunc = estargzconvert.LayerConvertFunc(estargz.WithCompressionLevel(level))
+	}
+
+	key := strings.ReplaceAll(compression, ":", "-") + "." + strconv.Itoa(level)
+	convertedLabel := convertedLabelPrefix + key
+	gcLabel := convertedGCLabelPrefix + key
+
+	return func(ctx context.Context, cs content.Store, desc ocispec.Descriptor) (*ocispec.Descriptor, error) {
+		if !isTarLayer(desc.MediaType) {
+			return nil, nil
+		}
+		if !force && isCompressedWith(ctx, cs, desc, compression) {
+			log.G(ctx).Debugf("layer %s is already compressed with %s", desc.Digest, compression)
+			return nil, nil
+		}
+		info, err := cs.Info(ctx, desc.Digest)
+		if err != nil {
+			return nil, err
+		}
+		if cached := cachedDescriptor(ctx, cs, info, convertedLabel); cached != nil {
+			log.G(ctx).Debugf("reusing converted layer %s for %s", cached.Digest, desc.Digest)
+			return cached, nil
+		}
+		newDesc, err := convertFunc(ctx, cs, desc)
+		if err != nil {
+			return nil, err
+		}
+		if newDesc == nil {
+			return nil, nil
+		}
+		log.G(ctx).Infof("converted %q (%s) to %s (%s)", desc.MediaType, desc.Digest, compression, newDesc.Digest)
+		b, err := json.Marshal(newDesc)
+		if err != nil {
+			return nil, err
+		}
+		update := content.Info{
+			Digest: desc.Digest,
+			Labels: map[string]string{
+				convertedLabel: string(b),
+				gcLabel:        newDesc.Digest.String(),
+			},
+		}
+		if _, err := cs.Update(ctx, update, "labels."+convertedLabel, "labels."+gcLabel); err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to record the converted layer of %s", desc.Digest)
+		}
+		return newDesc, nil
+	}, nil
+}
+
+// cachedDescriptor returns the converted descriptor recorded in label, if
+// the converted blob is still in the content store.
+func cachedDescriptor(ctx context.Context, cs content.Store, info content.Info, label string) *ocispec.Descriptor {
+	v, ok := info.Labels[label]
+	if !ok {
+		return nil
+	}
+	var desc ocispec.Descriptor
+	if err := json.Unmarshal([]byte(v), &desc); err != nil {
+		return nil
+	}
+	if _, err := cs.Info(ctx, desc.Digest); err != nil {
+		return nil
+	}
+	return &desc
+}
+
+// isTarLayer returns true for the distributable tar layers that can be recompressed.
+func isTarLayer(mediaType string) bool {
+	switch mediaType {
+	case ocispec.MediaTypeImageLayer, ocispec.MediaTypeImageLayerGzip, ocispec.MediaTypeImageLayerZstd,
+		images.MediaTypeDockerSchema2Layer, images.MediaTypeDockerSchema2LayerGzip:
+		return true
+	}
+	return false
+}
+
+// isCompressedWith returns true if the layer can be pushed as-is for compression.
+func isCompressedWith(ctx context.Context, cs content.Store, desc ocispec.Descriptor, compression string) bool {
+	switch compression {
+	case CompressionGzip:
+		// eStargz layers are valid gzip streams too
+		return desc.MediaType == ocispec.MediaTypeImageLayerGzip || desc.MediaType == images.MediaTypeDockerSchema2LayerGzip
+	case CompressionZstd:
+		return desc.MediaType == ocispec.MediaTypeImageLayerZstd
+	case CompressionZstdChunked:
+		_, ok := desc.Annotations[zstdchunked.ManifestChecksumAnnotation]
+		return ok && desc.MediaType == ocispec.MediaTypeImageLayerZstd
+	case CompressionEstargz:
+		return isReusableESGZ(ctx, cs, desc)
+	}
+	return false
+}
+
+func isReusableESGZ(ctx context.Context, cs content.Store, desc ocispec.Descriptor) bool {
+	dgstStr, ok := desc.Annotations[estargz.TOCJSONDigestAnnotation]
+	if !ok {
+		return false
+	}
+	tocdgst, err := digest.Parse(dgstStr)
+	if err != nil {
+		return false
+	}
+	ra, err := cs.ReaderAt(ctx, desc)
+	if err != nil {
+		return false
+	}
+	defer ra.Close()
+	r, err := estargz.Open(io.NewSectionReader(ra, 0, desc.Size), estargz.WithDecompressors(new(zstdchunked.Decompressor)))
+	if err != nil {
+		return false
+	}
+	if _, err := r.VerifyTOC(tocdgst); err != nil {
+		return false
+	}
+	return true
+}
+
+// gzipLayerConvertFunc converts layers into gzip layers with the specified compression level.
+func gzipLayerConvertFunc(level int) converter.ConvertFunc {
+	return func(ctx context.Context, cs content.Store, desc ocispec.Descriptor) (*ocispec.Descriptor, error) {
+		if !images.IsLayerType(desc.MediaType) {
+			// No conversion. No need to return an error here.
+			return nil, nil
+		}
+		mediaType := ocispec.MediaTypeImageLayerGzip
+		if images.IsDockerType(desc.MediaType) {
+			mediaType = images.MediaTypeDockerSchema2LayerGzip
+		}
+		return recompressLayer(ctx, cs, desc, "gzip", mediaType, func(w io.Writer) (io.WriteCloser, error) {
+			return gzip.NewWriterLevel(w, level)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package converter
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/plugins/content/local"
+	"github.com/containerd/stargz-snapshotter/estargz"
+)
+
+// labelStore is an in-memory local.LabelStore, so that the content store accepts label updates.
+type labelStore struct {
+	labels map[digest.Digest]map[string]string
+}
+
+func (s *labelStore) Get(d digest.Digest) (map[string]string, error) {
+	return s.labels[d], nil
+}
+
+func (s *labelStore) Set(d digest.Digest, labels map[string]string) error {
+	s.labels[d] = labels
+	return nil
+}
+
+func (s *labelStore) Update(d digest.Digest, update map[string]string) (map[string]string, error) {
+	labels, ok := s.labels[d]
+	if !ok {
+		labels = map[string]string{}
+	}
+	for k, v := range update {
+		if v == "" {
+			delete(labels, k)
+		} else {
+			labels[k] = v
+		}
+	}
+	s.labels[d] = labels
+	return labels, nil
+}
+
+func writeLayer(ctx context.Context, t *testing.T, cs content.Store, mediaType string, compress bool) ocispec.Descriptor {
+	t.Helper()
+	var tarBuf bytes.Buffer
+	tw := tar.NewWriter(&tarBuf)
+	data := []byte("hello, world\n")
+	assert.NilError(t, tw.WriteHeader(&tar.Header{Name: "hello", Mode: 0o644, Size: int64(len(data))}))
+	_, err := tw.Write(data)
+	assert.NilError(t, err)
+	assert.NilError(t, tw.Close())
+
+	blob := tarBuf.Bytes()
+	if compress {
+		var gzBuf bytes.Buffer
+		gw := gzip.NewWriter(&gzBuf)
+		_, err = gw.Write(blob)
+		assert.NilError(t, err)
+		assert.NilError(t, gw.Close())
+		blob = gzBuf.Bytes()
+	}
+	desc := ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(blob),
+		Size:      int64(len(blob)),
+	}
+	assert.NilError(t, content.WriteBlob(ctx, cs, desc.Digest.String(), bytes.NewReader(blob), desc))
+	return desc
+}
+
+func TestCompressionLayerConvertFunc(t *testing.T) {
+	ctx := context.Background()
+	cs, err := local.NewLabeledStore(t.TempDir(), &labelStore{labels: map[digest.Digest]map[string]string{}})
+	assert.NilError(t, err)
+
+	tarDesc := writeLayer(ctx, t, cs, images.MediaTypeDockerSchema2Layer, false)
+	gzipDesc := writeLayer(ctx, t, cs, ocispec.MediaTypeImageLayerGzip, true)
+
+	t.Run("gzip keeps docker media types", func(t *testing.T) {
+		convert, err := CompressionLayerConvertFunc(CompressionGzip, -1, false)
+		assert.NilError(t, err)
+		newDesc, err := convert(ctx, cs, tarDesc)
+		assert.NilError(t, err)
+		assert.Assert(t, newDesc != nil)
+		assert.Equal(t, newDesc.MediaType, images.MediaTypeDockerSchema2LayerGzip)
+	})
+
+	t.Run("already compressed layers are skipped", func(t *testing.T) {
+		convert, err := CompressionLayerConvertFunc(CompressionGzip, -1, false)
+		assert.NilError(t, err)
+		newDesc, err := convert(ctx, cs, gzipDesc)
+		assert.NilError(t, err)
+		assert.Assert(t, newDesc == nil)
+	})
+
+	t.Run("force recompresses", func(t *testing.T) {
+		convert, err := CompressionLayerConvertFunc(CompressionGzip, gzip.BestSpeed, true)
+		assert.NilError(t, err)
+		newDesc, err := convert(ctx, cs, gzipDesc)
+		assert.NilError(t, err)
+		assert.Assert(t, newDesc != nil)
+		assert.Equal(t, newDesc.MediaType, ocispec.MediaTypeImageLayerGzip)
+	})
+
+	t.Run("zstd conversion is cached", func(t *testing.T) {
+		convert, err := CompressionLayerConvertFunc(CompressionZstd, 5, false)
+		assert.NilError(t, err)
+		newDesc, err := convert(ctx, cs, gzipDesc)
+		assert.NilError(t, err)
+		assert.Assert(t, newDesc != nil)
+		assert.Equal(t, newDesc.MediaType, ocispec.MediaTypeImageLayerZstd)
+
+		info, err := cs.Info(ctx, gzipDesc.Digest)
+		assert.NilError(t, err)
+		assert.Equal(t, info.Labels[convertedGCLabelPrefix+"zstd.5"], newDesc.Digest.String())
+
+		cached, err := convert(ctx, cs, gzipDesc)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, cached, newDesc)
+
+		// the zstd layer itself is not converted again
+		again, err := convert(ctx, cs, *newDesc)
+		assert.NilError(t, err)
+		assert.Assert(t, again == nil)
+	})
+
+	t.Run("compression annotations are dropped", func(t *testing.T) {
+		annotated := gzipDesc
+		annotated.Annotations = map[string]string{
+			estargz.TOCJSONDigestAnnotation:         "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			estargz.StoreUncompressedSizeAnnotation: "1024",
+			"org.example.layer":                     "kept",
+		}
+		convert, err := CompressionLayerConvertFunc(CompressionZstd, 7, true)
+		assert.NilError(t, err)
+		newDesc, err := convert(ctx, cs, annotated)
+		assert.NilError(t, err)
+		assert.Assert(t, newDesc != nil)
+		assert.DeepEqual(t, newDesc.Annotations, map[string]string{"org.example.layer": "kept"})
+	})
+
+	t.Run("non-layer descriptors are ignored", func(t *testing.T) {
+		convert, err := CompressionLayerConvertFunc(CompressionZstd, -1, true)
+		assert.NilError(t, err)
+		newDesc, err := convert(ctx, cs, ocispec.Descriptor{MediaType: ocispec.MediaTypeImageConfig})
+		assert.NilError(t, err)
+		assert.Assert(t, newDesc == nil)
+	})
+}
+
+func TestValidateCompression(t *testing.T) {
+	for _, c := range Compressions() {
+		assert.NilError(t, ValidateCompression(c))
+	}
+	assert.ErrorContains(t, ValidateCompression("lz4"), "unsupported compression")
+}
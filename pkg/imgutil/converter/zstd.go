@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"maps"
 
 	"github.com/klauspost/compress/zstd"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
@@ -30,6 +31,8 @@ import (
 	"github.com/containerd/containerd/v2/core/images/converter/uncompress"
 	"github.com/containerd/containerd/v2/pkg/archive/compression"
 	"github.com/containerd/errdefs"
+	"github.com/containerd/stargz-snapshotter/estargz"
+	"github.com/containerd/stargz-snapshotter/estargz/zstdchunked"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 )
@@ -42,82 +45,108 @@ func ZstdLayerConvertFunc(options types.ImageConvertOptions) (converter.ConvertF
 			// No conversion. No need to return an error here.
 			return nil, nil
 		}
-		var err error
-		// Read it
-		readerAt, err := cs.ReaderAt(ctx, desc)
-		if err != nil {
-			return nil, err
-		}
-		defer readerAt.Close()
-		sectionReader := io.NewSectionReader(readerAt, 0, desc.Size)
-
-		info, err := cs.Info(ctx, desc.Digest)
-		if err != nil {
-			return nil, err
-		}
-
-		var oldReader io.Reader
-		// If it is compressed, get a decompressed stream
-		if !uncompress.IsUncompressedType(desc.MediaType) {
-			decompStream, err := compression.DecompressStream(sectionReader)
-			if err != nil {
-				return nil, err
-			}
-			defer decompStream.Close()
-			oldReader = decompStream
-		} else {
-			oldReader = sectionReader
-		}
-
-		ref := fmt.Sprintf("convert-zstd-from-%s", desc.Digest)
-		w, err := content.OpenWriter(ctx, cs, content.WithRef(ref))
-		if err != nil {
-			return nil, err
-		}
-		defer w.Close()
+		return recompressLayer(ctx, cs, desc, "zstd", ocispec.MediaTypeImageLayerZstd, func(w io.Writer) (io.WriteCloser, error) {
+			return zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(options.ZstdCompressionLevel)))
+		})
+	}, nil
+}
 
-		// Reset the writing position
-		// Old writer possibly remains without aborted
-		// (e.g. conversion interrupted by a signal)
-		if err := w.Truncate(0); err != nil {
-			return nil, err
-		}
+// compressionAnnotations are the annotations of eStargz and zstd:chunked layers that describe
+// their compressed blob, and no longer apply once the layer is recompressed.
+var compressionAnnotations = []string{
+	estargz.TOCJSONDigestAnnotation,
+	estargz.StoreUncompressedSizeAnnotation,
+	zstdchunked.ManifestChecksumAnnotation,
+	zstdchunked.ManifestPositionAnnotation,
+}
 
-		pr, pw := io.Pipe()
-		enc, err := zstd.NewWriter(pw, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(options.ZstdCompressionLevel)))
+// recompressLayer decompresses the layer blob `desc` and writes it back to the
+// content store through the encoder returned by `newEncoder`.
+// The new blob inherits the labels of the original one, and its descriptor the annotations
+// that do not depend on the compression.
+func recompressLayer(ctx context.Context, cs content.Store, desc ocispec.Descriptor, name, mediaType string, newEncoder func(io.Writer) (io.WriteCloser, error)) (*ocispec.Descriptor, error) {
+	var err error
+	// Read it
+	readerAt, err := cs.ReaderAt(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer readerAt.Close()
+	sectionReader := io.NewSectionReader(readerAt, 0, desc.Size)
+
+	info, err := cs.Info(ctx, desc.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	var oldReader io.Reader
+	// If it is compressed, get a decompressed stream
+	if !uncompress.IsUncompressedType(desc.MediaType) {
+		decompStream, err := compression.DecompressStream(sectionReader)
 		if err != nil {
 			return nil, err
 		}
-		go func() {
-			if _, err := io.Copy(enc, oldReader); err != nil {
-				pr.CloseWithError(err)
-				return
-			}
-			if err = enc.Close(); err != nil {
-				pr.CloseWithError(err)
-				return
-			}
-			if err = pw.Close(); err != nil {
-				pr.CloseWithError(err)
-				return
-			}
-		}()
-
-		n, err := io.Copy(w, pr)
-		if err != nil {
-			return nil, err
+		defer decompStream.Close()
+		oldReader = decompStream
+	} else {
+		oldReader = sectionReader
+	}
+
+	ref := fmt.Sprintf("convert-%s-from-%s", name, desc.Digest)
+	w, err := content.OpenWriter(ctx, cs, content.WithRef(ref))
+	if err != nil {
+		return nil, err
+	}
+	defer w.Close()
+
+	// Reset the writing position
+	// Old writer possibly remains without aborted
+	// (e.g. conversion interrupted by a signal)
+	if err := w.Truncate(0); err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	enc, err := newEncoder(pw)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		if _, err := io.Copy(enc, oldReader); err != nil {
+			pr.CloseWithError(err)
+			return
 		}
-
-		if err = w.Commit(ctx, 0, "", content.WithLabels(info.Labels)); err != nil && !errdefs.IsAlreadyExists(err) {
-			return nil, err
+		if err = enc.Close(); err != nil {
+			pr.CloseWithError(err)
+			return
 		}
-		if err := w.Close(); err != nil {
-			return nil, err
+		if err = pw.Close(); err != nil {
+			pr.CloseWithError(err)
+			return
 		}
-		newDesc := desc
-		newDesc.Digest = w.Digest()
-		newDesc.Size = n
-		newDesc.MediaType = ocispec.MediaTypeImageLayerZstd
-		return &newDesc, nil
-	}, nil
+	}()
+
+	n, err := io.Copy(w, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = w.Commit(ctx, 0, "", content.WithLabels(info.Labels)); err != nil && !errdefs.IsAlreadyExists(err) {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	newDesc := desc
+	newDesc.Digest = w.Digest()
+	newDesc.Size = n
+	newDesc.MediaType = mediaType
+	newDesc.Annotations = maps.Clone(desc.Annotations)
+	for _, a := range compressionAnnotations {
+		delete(newDesc.Annotations, a)
+	}
+	if len(newDesc.Annotations) == 0 {
+		newDesc.Annotations = nil
+	}
+	return &newDesc, nil
 }
@@ -437,6 +437,69 @@ func UnpackedImageSize(ctx context.Context, s snapshots.Snapshotter, img contain
 	return total.Size, err
 }
 
+// DiskUsage is the disk usage of an image, keyed so that the usage shared with other images can be accounted once.
+type DiskUsage struct {
+	// Blobs are the sizes of the blobs of the image in the content store, by digest
+	Blobs map[string]int64
+	// Layers are the sizes of the unpacked layers of the image, by snapshot chain ID
+	Layers map[string]int64
+}
+
+// Size returns the size of the blobs and of the unpacked layers.
+func (u *DiskUsage) Size() int64 {
+	var size int64
+	for _, s := range u.Blobs {
+		size += s
+	}
+	for _, s := range u.Layers {
+		size += s
+	}
+	return size
+}
+
+// ImageDiskUsage returns the disk usage of the blobs of the image present in the content store,
+// and of its layers unpacked in the snapshotter.
+func ImageDiskUsage(ctx context.Context, cs content.Store, s snapshots.Snapshotter, img containerd.Image) (*DiskUsage, error) {
+	usage := &DiskUsage{
+		Blobs:  map[string]int64{},
+		Layers: map[string]int64{},
+	}
+	handler := images.HandlerFunc(func(ctx context.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		info, err := cs.Info(ctx, desc.Digest)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				// e.g. the blobs of the other platforms
+				return nil, images.ErrSkipDesc
+			}
+			return nil, err
+		}
+		usage.Blobs[desc.Digest.String()] = info.Size
+		return images.Children(ctx, cs, desc)
+	})
+	if err := images.Walk(ctx, handler, img.Target()); err != nil {
+		return nil, err
+	}
+
+	diffIDs, err := img.RootFS(ctx)
+	if err != nil {
+		// The image is not available for the platform, so it cannot be unpacked
+		log.G(ctx).WithError(err).Debugf("failed to get the layers of image %q", img.Name())
+		return usage, nil
+	}
+	for _, chainID := range identity.ChainIDs(diffIDs) {
+		u, err := s.Usage(ctx, chainID.String())
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				// Not unpacked
+				continue
+			}
+			return nil, err
+		}
+		usage.Layers[chainID.String()] = u.Size
+	}
+	return usage, nil
+}
+
 // GetUnusedImages returns the list of all images which are not referenced by a container.
 func GetUnusedImages(ctx context.Context, client *containerd.Client, filters ...Filter) ([]images.Image, error) {
 	var (
@@ -32,6 +32,8 @@ import (
 	"github.com/containerd/platforms"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/ocilayout"
 	"github.com/containerd/nerdctl/v2/pkg/platformutil"
 	"github.com/containerd/nerdctl/v2/pkg/transferutil"
 )
@@ -39,6 +41,9 @@ import (
 // FromArchive loads and unpacks the images from the tar archive specified in image load options.
 func FromArchive(ctx context.Context, client *containerd.Client, options types.ImageLoadOptions) ([]images.Image, error) {
 	if options.Input != "" {
+		if st, err := os.Stat(options.Input); err == nil && st.IsDir() {
+			return FromOCILayout(ctx, client, options.Input, options)
+		}
 		f, err := os.Open(options.Input)
 		if err != nil {
 			return nil, err
@@ -114,3 +119,40 @@ func FromOCIArchive(ctx context.Context, client *containerd.Client, pathToOCIArc
 
 	return FromArchive(ctx, client, options)
 }
+
+// FromOCILayout loads and unpacks all the images of the OCI image layout directory at dir.
+// The content is read straight from the blobs of the layout.
+func FromOCILayout(ctx context.Context, client *containerd.Client, dir string, options types.ImageLoadOptions) ([]images.Image, error) {
+	idx, err := ocilayout.ReadIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+	ocispecPlatforms, err := platformutil.NewOCISpecPlatformSlice(options.AllPlatforms, options.Platform)
+	if err != nil {
+		return nil, err
+	}
+	pullOptions := types.ImagePullOptions{
+		GOptions:        options.GOptions,
+		OCISpecPlatform: ocispecPlatforms,
+		Mode:            "always",
+		Quiet:           options.Quiet,
+		Stdout:          options.Stdout,
+		Stderr:          options.Stdout,
+	}
+	var loaded []images.Image
+	for _, desc := range idx.Manifests {
+		name, err := ocilayout.ImageName(dir, desc)
+		if err != nil {
+			return loaded, err
+		}
+		ensured, err := imgutil.PullImage(ctx, client, ocilayout.NewResolver(dir, desc), name, pullOptions)
+		if err != nil {
+			return loaded, err
+		}
+		loaded = append(loaded, ensured.Image.Metadata())
+		if !options.Quiet {
+			fmt.Fprintf(options.Stdout, "Loaded image: %s\n", name)
+		}
+	}
+	return loaded, nil
+}
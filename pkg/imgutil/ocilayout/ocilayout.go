@@ -0,0 +1,235 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package ocilayout reads and writes OCI image layout directories.
+// See https://github.com/opencontainers/image-spec/blob/main/image-layout.md
+package ocilayout
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/remotes"
+	"github.com/containerd/containerd/v2/plugins/content/local"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/platforms"
+
+	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+)
+
+// IsLayout returns true if dir is an OCI image layout, i.e., a directory containing an "oci-layout" file.
+func IsLayout(dir string) bool {
+	st, err := os.Stat(filepath.Join(dir, ocispec.ImageLayoutFile))
+	return err == nil && st.Mode().IsRegular()
+}
+
+// ReadIndex reads the index.json of the layout at dir.
+func ReadIndex(dir string) (*ocispec.Index, error) {
+	if !IsLayout(dir) {
+		return nil, fmt.Errorf("%q is not an OCI image layout: %w", dir, errdefs.ErrNotFound)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, ocispec.ImageIndexFile))
+	if err != nil {
+		return nil, err
+	}
+	var idx ocispec.Index
+	if err := json.Unmarshal(b, &idx); err != nil {
+		return nil, fmt.Errorf("failed to parse the index of %q: %w", dir, err)
+	}
+	return &idx, nil
+}
+
+// Select returns the index entry of the layout selected by ref.
+// Without a tag nor a digest, the layout must contain a single image.
+func Select(idx *ocispec.Index, ref *referenceutil.OCILayoutReference) (ocispec.Descriptor, error) {
+	var found []ocispec.Descriptor
+	for _, desc := range idx.Manifests {
+		switch {
+		case ref.Digest != "":
+			if desc.Digest == ref.Digest {
+				return desc, nil
+			}
+		case ref.Tag != "":
+			if desc.Annotations[ocispec.AnnotationRefName] == ref.Tag {
+				found = append(found, desc)
+			}
+		default:
+			found = append(found, desc)
+		}
+	}
+	switch {
+	case len(found) == 1:
+		return found[0], nil
+	case len(found) > 1 && ref.Tag != "":
+		return ocispec.Descriptor{}, fmt.Errorf("multiple images are tagged %q in %q", ref.Tag, ref.Path)
+	case len(found) > 1:
+		return ocispec.Descriptor{}, fmt.Errorf("%q contains multiple images, specify one with a tag or a digest", ref.Path)
+	}
+	return ocispec.Descriptor{}, fmt.Errorf("image %q: %w", ref.String(), errdefs.ErrNotFound)
+}
+
+var invalidRepoChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// ImageName returns the name of the local image for the index entry desc of the layout at dir.
+//
+// The name recorded by containerd ("io.containerd.image.name") is used when present.
+// Otherwise, the image is named after the layout directory, with the "org.opencontainers.image.ref.name"
+// annotation as the tag, or the digest when the entry is not tagged.
+func ImageName(dir string, desc ocispec.Descriptor) (string, error) {
+	if name := desc.Annotations[images.AnnotationImageName]; name != "" {
+		parsed, err := referenceutil.Parse(name)
+		if err != nil {
+			return "", fmt.Errorf("invalid image name %q in %q: %w", name, dir, err)
+		}
+		return parsed.String(), nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	repo := strings.Trim(invalidRepoChars.ReplaceAllString(strings.ToLower(filepath.Base(abs)), "-"), "._-")
+	if repo == "" {
+		repo = "oci-layout"
+	}
+	name := repo + "@" + desc.Digest.String()
+	if tag := desc.Annotations[ocispec.AnnotationRefName]; tag != "" && reference.TagRegexp.FindString(tag) == tag {
+		name = repo + ":" + tag
+	}
+	parsed, err := referenceutil.Parse(name)
+	if err != nil {
+		return "", err
+	}
+	return parsed.String(), nil
+}
+
+// NewResolver returns a resolver that resolves any reference to target,
+// and fetches the content straight from the blobs of the layout at dir.
+func NewResolver(dir string, target ocispec.Descriptor) remotes.Resolver {
+	return &resolver{dir: dir, target: target}
+}
+
+type resolver struct {
+	dir    string
+	target ocispec.Descriptor
+}
+
+func (r *resolver) Resolve(ctx context.Context, ref string) (string, ocispec.Descriptor, error) {
+	return ref, r.target, nil
+}
+
+func (r *resolver) Fetcher(ctx context.Context, ref string) (remotes.Fetcher, error) {
+	return remotes.FetcherFunc(func(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+		f, err := os.Open(blobPath(r.dir, desc.Digest))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, fmt.Errorf("blob %s is missing from %q: %w", desc.Digest, r.dir, errdefs.ErrNotFound)
+			}
+			return nil, err
+		}
+		return f, nil
+	}), nil
+}
+
+func (r *resolver) Pusher(ctx context.Context, ref string) (remotes.Pusher, error) {
+	return nil, fmt.Errorf("pushing through an OCI image layout resolver: %w", errdefs.ErrNotImplemented)
+}
+
+func blobPath(dir string, dgst digest.Digest) string {
+	return filepath.Join(dir, ocispec.ImageBlobsDir, dgst.Algorithm().String(), dgst.Encoded())
+}
+
+// Save copies img into the layout at dir, creating the layout if needed.
+// Only the content of the platforms matched by platMC is copied.
+//
+// The image is recorded in index.json with the "org.opencontainers.image.ref.name" annotation set to tag,
+// replacing the image previously tagged the same.
+func Save(ctx context.Context, provider content.Provider, dir string, img images.Image, tag string, platMC platforms.MatchComparer) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	// the layout of a local content store matches the one of OCI image layouts ("blobs/<alg>/<encoded>")
+	cs, err := local.NewStore(dir)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(filepath.Join(dir, "ingest"))
+
+	copyHandler := images.HandlerFunc(func(ctx context.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		ra, err := provider.ReaderAt(ctx, desc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", desc.Digest, err)
+		}
+		defer ra.Close()
+		if err := content.WriteBlob(ctx, cs, "oci-layout-"+desc.Digest.String(), content.NewReader(ra), desc); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	})
+	handler := images.Handlers(copyHandler, images.FilterPlatforms(images.ChildrenHandler(provider), platMC))
+	if err := images.Dispatch(ctx, handler, nil, img.Target); err != nil {
+		return err
+	}
+
+	layout, err := json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+	if err := filesystem.WriteFile(filepath.Join(dir, ocispec.ImageLayoutFile), layout, 0o644); err != nil {
+		return err
+	}
+
+	idx := &ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+	}
+	if existing, err := ReadIndex(dir); err == nil {
+		idx = existing
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	manifests := idx.Manifests[:0]
+	for _, desc := range idx.Manifests {
+		if desc.Annotations[ocispec.AnnotationRefName] != tag {
+			manifests = append(manifests, desc)
+		}
+	}
+	entry := img.Target
+	entry.Annotations = map[string]string{
+		images.AnnotationImageName: img.Name,
+		ocispec.AnnotationRefName:  tag,
+	}
+	idx.Manifests = append(manifests, entry)
+	b, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	return filesystem.WriteFile(filepath.Join(dir, ocispec.ImageIndexFile), b, 0o644)
+}
@@ -0,0 +1,151 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ocilayout
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/plugins/content/local"
+	"github.com/containerd/platforms"
+
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+)
+
+func writeBlob(ctx context.Context, t *testing.T, cs content.Store, mediaType string, b []byte) ocispec.Descriptor {
+	t.Helper()
+	desc := ocispec.Descriptor{MediaType: mediaType, Digest: digest.FromBytes(b), Size: int64(len(b))}
+	assert.NilError(t, content.WriteBlob(ctx, cs, desc.Digest.String(), strings.NewReader(string(b)), desc))
+	return desc
+}
+
+func writeImage(ctx context.Context, t *testing.T, cs content.Store) ocispec.Descriptor {
+	t.Helper()
+	config, err := json.Marshal(ocispec.Image{Platform: platforms.DefaultSpec()})
+	assert.NilError(t, err)
+	configDesc := writeBlob(ctx, t, cs, ocispec.MediaTypeImageConfig, config)
+	layerDesc := writeBlob(ctx, t, cs, ocispec.MediaTypeImageLayer, []byte("layer"))
+	manifest, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    configDesc,
+		Layers:    []ocispec.Descriptor{layerDesc},
+	})
+	assert.NilError(t, err)
+	return writeBlob(ctx, t, cs, ocispec.MediaTypeImageManifest, manifest)
+}
+
+func TestSaveAndResolve(t *testing.T) {
+	ctx := context.Background()
+	cs, err := local.NewStore(t.TempDir())
+	assert.NilError(t, err)
+	target := writeImage(ctx, t, cs)
+	img := images.Image{Name: "docker.io/library/foo:1.0", Target: target}
+
+	dir := filepath.Join(t.TempDir(), "layout")
+	assert.NilError(t, Save(ctx, cs, dir, img, "1.0", platforms.All))
+	// saving again under the same tag replaces the entry
+	assert.NilError(t, Save(ctx, cs, dir, img, "1.0", platforms.All))
+	assert.Assert(t, IsLayout(dir))
+	_, err = os.Stat(filepath.Join(dir, "ingest"))
+	assert.Assert(t, os.IsNotExist(err))
+
+	idx, err := ReadIndex(dir)
+	assert.NilError(t, err)
+	assert.Equal(t, len(idx.Manifests), 1)
+
+	desc, err := Select(idx, &referenceutil.OCILayoutReference{Path: dir, Tag: "1.0"})
+	assert.NilError(t, err)
+	assert.Equal(t, desc.Digest, target.Digest)
+	name, err := ImageName(dir, desc)
+	assert.NilError(t, err)
+	assert.Equal(t, name, "docker.io/library/foo:1.0")
+
+	resolver := NewResolver(dir, desc)
+	_, resolved, err := resolver.Resolve(ctx, name)
+	assert.NilError(t, err)
+	assert.Equal(t, resolved.Digest, target.Digest)
+	fetcher, err := resolver.Fetcher(ctx, name)
+	assert.NilError(t, err)
+	rc, err := fetcher.Fetch(ctx, target)
+	assert.NilError(t, err)
+	b, err := io.ReadAll(rc)
+	assert.NilError(t, rc.Close())
+	assert.NilError(t, err)
+	assert.Equal(t, digest.FromBytes(b), target.Digest)
+
+	_, err = fetcher.Fetch(ctx, ocispec.Descriptor{Digest: digest.FromString("missing")})
+	assert.ErrorContains(t, err, "missing")
+}
+
+func TestSelect(t *testing.T) {
+	a := ocispec.Descriptor{Digest: digest.FromString("a"), Annotations: map[string]string{ocispec.AnnotationRefName: "v1"}}
+	b := ocispec.Descriptor{Digest: digest.FromString("b"), Annotations: map[string]string{ocispec.AnnotationRefName: "v2"}}
+	c := ocispec.Descriptor{Digest: digest.FromString("c"), Annotations: map[string]string{ocispec.AnnotationRefName: "v2"}}
+	idx := &ocispec.Index{Manifests: []ocispec.Descriptor{a, b, c}}
+
+	desc, err := Select(idx, &referenceutil.OCILayoutReference{Path: "l", Tag: "v1"})
+	assert.NilError(t, err)
+	assert.Equal(t, desc.Digest, a.Digest)
+
+	desc, err = Select(idx, &referenceutil.OCILayoutReference{Path: "l", Digest: c.Digest})
+	assert.NilError(t, err)
+	assert.Equal(t, desc.Digest, c.Digest)
+
+	_, err = Select(idx, &referenceutil.OCILayoutReference{Path: "l", Tag: "v2"})
+	assert.ErrorContains(t, err, "multiple images are tagged")
+
+	_, err = Select(idx, &referenceutil.OCILayoutReference{Path: "l"})
+	assert.ErrorContains(t, err, "specify one with a tag or a digest")
+
+	_, err = Select(idx, &referenceutil.OCILayoutReference{Path: "l", Tag: "v3"})
+	assert.ErrorContains(t, err, "not found")
+
+	desc, err = Select(&ocispec.Index{Manifests: []ocispec.Descriptor{a}}, &referenceutil.OCILayoutReference{Path: "l"})
+	assert.NilError(t, err)
+	assert.Equal(t, desc.Digest, a.Digest)
+}
+
+func TestImageName(t *testing.T) {
+	dgst := digest.FromString("image")
+	testCases := []struct {
+		dir         string
+		annotations map[string]string
+		expected    string
+	}{
+		{"/srv/My Layout", map[string]string{ocispec.AnnotationRefName: "v1"}, "docker.io/library/my-layout:v1"},
+		{"/srv/layout", nil, "docker.io/library/layout@" + dgst.String()},
+		{"/srv/layout", map[string]string{images.AnnotationImageName: "example.com/foo:bar", ocispec.AnnotationRefName: "bar"}, "example.com/foo:bar"},
+	}
+	for _, tc := range testCases {
+		name, err := ImageName(tc.dir, ocispec.Descriptor{Digest: dgst, Annotations: tc.annotations})
+		assert.NilError(t, err)
+		assert.Equal(t, name, tc.expected)
+	}
+}
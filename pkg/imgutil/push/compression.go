@@ -0,0 +1,50 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package push
+
+import (
+	"context"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/images/converter"
+	"github.com/containerd/platforms"
+
+	nerdconverter "github.com/containerd/nerdctl/v2/pkg/imgutil/converter"
+)
+
+// Recompress creates the image dstRef from srcRef, with the layers of the platforms matched by
+// platform recompressed with compression (see converter.CompressionLayerConvertFunc).
+//
+// Manifests and indexes are rewritten along with their layers, so multi-platform images stay
+// consistent. Since Docker schema2 manifests cannot carry zstd layers, zstd compressions also
+// convert the image to OCI media types.
+func Recompress(ctx context.Context, client *containerd.Client, dstRef, srcRef string, platform platforms.MatchComparer,
+	compression string, level int, force bool) (*images.Image, error) {
+	layerConvertFunc, err := nerdconverter.CompressionLayerConvertFunc(compression, level, force)
+	if err != nil {
+		return nil, err
+	}
+	opts := []converter.Opt{
+		converter.WithPlatform(platform),
+		converter.WithLayerConvertFunc(layerConvertFunc),
+	}
+	if compression == nerdconverter.CompressionZstd || compression == nerdconverter.CompressionZstdChunked {
+		opts = append(opts, converter.WithDockerToOCI(true))
+	}
+	return nerdconverter.Convert(ctx, client, dstRef, srcRef, opts...)
+}
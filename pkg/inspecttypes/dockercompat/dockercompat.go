@@ -306,7 +306,7 @@ type NetworkEndpointSettings struct {
 	// Configurations
 	// TODO IPAMConfig *EndpointIPAMConfig
 	// TODO Links      []string
-	// TODO Aliases    []string
+	Aliases []string
 	// Operational data
 	// TODO NetworkID           string
 	// TODO EndpointID          string
@@ -471,6 +471,8 @@ func ContainerFromNative(n *native.Container) (*Container, error) {
 	cs := new(ContainerState)
 	cs.Restarting = n.Labels[restart.StatusLabel] == string(containerd.Running)
 	cs.Error = n.Labels[labels.Error]
+	// attachedNetworks maps the networks to their interface, once modified by `nerdctl network connect`
+	var attachedNetworks map[string]string
 	if n.Process != nil {
 		cs.Status = statusFromNative(n.Process.Status, n.Labels)
 		cs.Running = n.Process.Status.Status == containerd.Running
@@ -482,8 +484,11 @@ func ContainerFromNative(n *native.Container) (*Container, error) {
 				log.L.WithError(err).Errorf("failed retrieving state")
 			} else if err = lf.Load(); err != nil {
 				log.L.WithError(err).Errorf("failed retrieving StartedAt from state")
-			} else if !time.Time.IsZero(lf.StartedAt) {
-				cs.StartedAt = lf.StartedAt.UTC().Format(time.RFC3339Nano)
+			} else {
+				if !time.Time.IsZero(lf.StartedAt) {
+					cs.StartedAt = lf.StartedAt.UTC().Format(time.RFC3339Nano)
+				}
+				attachedNetworks = lf.Networks
 			}
 		}
 		if !n.Process.Status.ExitTime.IsZero() {
@@ -493,6 +498,9 @@ func ContainerFromNative(n *native.Container) (*Container, error) {
 		if err != nil {
 			return nil, err
 		}
+		if err := setNetworkAliases(nSettings, n.Labels, attachedNetworks); err != nil {
+			return nil, err
+		}
 		c.NetworkSettings = nSettings
 		c.HostConfig.PortBindings = *nSettings.Ports
 	} else {
@@ -854,6 +862,37 @@ func networkSettingsFromNative(n *native.NetNS, _ *specs.Spec) (*NetworkSettings
 	return res, nil
 }
 
+// setNetworkAliases sets the network-scoped aliases of the container on the endpoints of their network.
+// The interfaces are named after the index of their network, unless the networks were modified at runtime,
+// in which case attachedNetworks maps them to their interface.
+func setNetworkAliases(res *NetworkSettings, containerLabels map[string]string, attachedNetworks map[string]string) error {
+	aliasesJSON := containerLabels[labels.NetworkAliases]
+	if aliasesJSON == "" {
+		return nil
+	}
+	var aliases map[string][]string
+	if err := json.Unmarshal([]byte(aliasesJSON), &aliases); err != nil {
+		return fmt.Errorf("failed to parse network aliases label: %v", err)
+	}
+	ifNames := attachedNetworks
+	if ifNames == nil {
+		var networks []string
+		if err := json.Unmarshal([]byte(containerLabels[labels.Networks]), &networks); err != nil {
+			return fmt.Errorf("failed to parse networks label: %v", err)
+		}
+		ifNames = make(map[string]string, len(networks))
+		for i, network := range networks {
+			ifNames[network] = fmt.Sprintf("eth%d", i)
+		}
+	}
+	for network, networkAliases := range aliases {
+		if nes, ok := res.Networks["unknown-"+ifNames[network]]; ok {
+			nes.Aliases = networkAliases
+		}
+	}
+	return nil
+}
+
 func cpuSettingsFromNative(sp *specs.Spec) (*CPUSettings, error) {
 	res := &CPUSettings{}
 	if sp.Linux != nil && sp.Linux.Resources != nil && sp.Linux.Resources.CPU != nil {
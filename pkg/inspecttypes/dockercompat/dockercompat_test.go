@@ -764,6 +764,32 @@ func TestNetworkSettingsFromNative(t *testing.T) {
 	}
 }
 
+func TestSetNetworkAliases(t *testing.T) {
+	newSettings := func() *NetworkSettings {
+		return &NetworkSettings{
+			Networks: map[string]*NetworkEndpointSettings{
+				"unknown-eth0": {IPAddress: "10.4.0.2"},
+				"unknown-eth1": {IPAddress: "10.5.0.2"},
+			},
+		}
+	}
+	containerLabels := map[string]string{
+		labels.Networks:       `["n1","n2"]`,
+		labels.NetworkAliases: `{"n2":["db","database"]}`,
+	}
+
+	res := newSettings()
+	assert.NilError(t, setNetworkAliases(res, containerLabels, nil))
+	assert.Assert(t, res.Networks["unknown-eth0"].Aliases == nil)
+	assert.DeepEqual(t, res.Networks["unknown-eth1"].Aliases, []string{"db", "database"})
+
+	// n2 was connected at runtime after n1 was disconnected
+	res = newSettings()
+	assert.NilError(t, setNetworkAliases(res, containerLabels, map[string]string{"n2": "eth0"}))
+	assert.DeepEqual(t, res.Networks["unknown-eth0"].Aliases, []string{"db", "database"})
+	assert.Assert(t, res.Networks["unknown-eth1"].Aliases == nil)
+}
+
 func TestCpuSettingsFromNative(t *testing.T) {
 	// Helper function to create uint64 pointer
 	uint64Ptr := func(i uint64) *uint64 {
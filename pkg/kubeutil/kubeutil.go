@@ -0,0 +1,154 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package kubeutil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Manifest is the set of resources of a manifest.
+type Manifest struct {
+	// Pods contains the Pods of the manifest, then the pods of its Deployments
+	Pods []Pod
+	// ConfigMaps maps the names of the ConfigMaps of the manifest to their data
+	ConfigMaps map[string]map[string]string
+}
+
+// Parse parses the (multi-document) YAML manifest.
+// Pod, Deployment and ConfigMap resources are supported.
+func Parse(r io.Reader) (*Manifest, error) {
+	m := &Manifest{ConfigMaps: make(map[string]map[string]string)}
+	var deployments []Deployment
+	dec := yaml.NewDecoder(r)
+	for {
+		var node yaml.Node
+		if err := dec.Decode(&node); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		var meta struct {
+			Kind     string     `yaml:"kind"`
+			Metadata ObjectMeta `yaml:"metadata"`
+		}
+		if err := node.Decode(&meta); err != nil {
+			return nil, err
+		}
+		switch meta.Kind {
+		case "":
+			// empty document
+			continue
+		case "Pod":
+			var pod Pod
+			if err := node.Decode(&pod); err != nil {
+				return nil, fmt.Errorf("failed to parse pod %q: %w", meta.Metadata.Name, err)
+			}
+			m.Pods = append(m.Pods, pod)
+		case "Deployment":
+			var deployment Deployment
+			if err := node.Decode(&deployment); err != nil {
+				return nil, fmt.Errorf("failed to parse deployment %q: %w", meta.Metadata.Name, err)
+			}
+			deployments = append(deployments, deployment)
+		case "ConfigMap":
+			var cm ConfigMap
+			if err := node.Decode(&cm); err != nil {
+				return nil, fmt.Errorf("failed to parse config map %q: %w", meta.Metadata.Name, err)
+			}
+			m.ConfigMaps[cm.Metadata.Name] = cm.Data
+		default:
+			return nil, fmt.Errorf("unsupported kind %q (supported kinds: Pod, Deployment, ConfigMap)", meta.Kind)
+		}
+	}
+	for _, d := range deployments {
+		m.Pods = append(m.Pods, d.pods()...)
+	}
+	for _, pod := range m.Pods {
+		if err := validatePod(pod); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// pods returns the pods of the deployment, named "<deployment>-pod" (followed by "-<replica>" when there are several replicas).
+func (d Deployment) pods() []Pod {
+	replicas := 1
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	var pods []Pod
+	for i := 1; i <= replicas; i++ {
+		name := d.Metadata.Name + "-pod"
+		if replicas > 1 {
+			name = fmt.Sprintf("%s-%d", name, i)
+		}
+		labels := make(map[string]string)
+		for k, v := range d.Spec.Template.Metadata.Labels {
+			labels[k] = v
+		}
+		pods = append(pods, Pod{
+			APIVersion: APIVersion,
+			Kind:       "Pod",
+			Metadata: ObjectMeta{
+				Name:        name,
+				Labels:      labels,
+				Annotations: d.Spec.Template.Metadata.Annotations,
+			},
+			Spec: d.Spec.Template.Spec,
+		})
+	}
+	return pods
+}
+
+func validatePod(pod Pod) error {
+	if pod.Metadata.Name == "" {
+		return errors.New("pod name must not be empty")
+	}
+	if len(pod.Spec.Containers) == 0 {
+		return fmt.Errorf("pod %q has no containers", pod.Metadata.Name)
+	}
+	for _, c := range pod.Spec.Containers {
+		if c.Name == "" {
+			return fmt.Errorf("pod %q has a container without name", pod.Metadata.Name)
+		}
+		if c.Image == "" {
+			return fmt.Errorf("container %q of pod %q has no image", c.Name, pod.Metadata.Name)
+		}
+	}
+	return nil
+}
+
+// Marshal returns the YAML representation of the pod.
+func Marshal(pod *Pod) ([]byte, error) {
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(pod); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,106 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package kubeutil
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParse(t *testing.T) {
+	const manifest = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  greeting: hello
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  labels:
+    app: web
+spec:
+  containers:
+  - name: nginx
+    image: nginx:alpine
+    ports:
+    - containerPort: 80
+      hostPort: 8080
+    livenessProbe:
+      httpGet:
+        path: /
+        port: 80
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  replicas: 2
+  template:
+    metadata:
+      labels:
+        app: worker
+    spec:
+      containers:
+      - name: alpine
+        image: alpine
+        command: ["sleep", "infinity"]
+`
+	m, err := Parse(strings.NewReader(manifest))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, m.ConfigMaps, map[string]map[string]string{"config": {"greeting": "hello"}})
+	assert.Equal(t, len(m.Pods), 3)
+
+	assert.Equal(t, m.Pods[0].Metadata.Name, "web")
+	assert.Equal(t, m.Pods[0].Spec.Containers[0].Ports[0].HostPort, int32(8080))
+	assert.Equal(t, m.Pods[0].Spec.Containers[0].LivenessProbe.HTTPGet.Port, "80")
+
+	assert.Equal(t, m.Pods[1].Metadata.Name, "worker-pod-1")
+	assert.Equal(t, m.Pods[2].Metadata.Name, "worker-pod-2")
+	assert.DeepEqual(t, m.Pods[2].Metadata.Labels, map[string]string{"app": "worker"})
+	assert.DeepEqual(t, m.Pods[2].Spec.Containers[0].Command, []string{"sleep", "infinity"})
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse(strings.NewReader("apiVersion: v1\nkind: Service\nmetadata:\n  name: svc\n"))
+	assert.ErrorContains(t, err, `unsupported kind "Service"`)
+
+	_, err = Parse(strings.NewReader("apiVersion: v1\nkind: Pod\nmetadata:\n  name: empty\nspec:\n  containers: []\n"))
+	assert.ErrorContains(t, err, "has no containers")
+}
+
+func TestMarshal(t *testing.T) {
+	pod := &Pod{
+		APIVersion: APIVersion,
+		Kind:       "Pod",
+		Metadata:   ObjectMeta{Name: "web"},
+		Spec: PodSpec{
+			Containers: []Container{{Name: "nginx", Image: "nginx:alpine"}},
+		},
+	}
+	b, err := Marshal(pod)
+	assert.NilError(t, err)
+	parsed, err := Parse(strings.NewReader(string(b)))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, parsed.Pods, []Pod{*pod})
+}
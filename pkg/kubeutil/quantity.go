@@ -0,0 +1,88 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package kubeutil
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseCPU parses a Kubernetes CPU quantity, e.g., "500m" or "1.5", into a number of CPUs.
+func ParseCPU(q string) (float64, error) {
+	if strings.HasSuffix(q, "m") {
+		milli, err := strconv.ParseFloat(strings.TrimSuffix(q, "m"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid CPU quantity %q: %w", q, err)
+		}
+		return milli / 1000, nil
+	}
+	cpus, err := strconv.ParseFloat(q, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CPU quantity %q: %w", q, err)
+	}
+	return cpus, nil
+}
+
+var memorySuffixes = []struct {
+	suffix     string
+	multiplier float64
+}{
+	// binary suffixes must be checked before the decimal ones
+	{"Ki", 1 << 10}, {"Mi", 1 << 20}, {"Gi", 1 << 30}, {"Ti", 1 << 40}, {"Pi", 1 << 50}, {"Ei", 1 << 60},
+	{"k", 1e3}, {"M", 1e6}, {"G", 1e9}, {"T", 1e12}, {"P", 1e15}, {"E", 1e18},
+}
+
+// ParseMemory parses a Kubernetes memory quantity, e.g., "128Mi" or "1G", into bytes.
+func ParseMemory(q string) (int64, error) {
+	multiplier := 1.0
+	number := q
+	for _, s := range memorySuffixes {
+		if strings.HasSuffix(q, s.suffix) {
+			multiplier = s.multiplier
+			number = strings.TrimSuffix(q, s.suffix)
+			break
+		}
+	}
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid memory quantity %q", q)
+	}
+	return int64(math.Ceil(f * multiplier)), nil
+}
+
+// FormatMemory formats bytes into a Kubernetes memory quantity, using the largest exact binary suffix.
+func FormatMemory(bytes int64) string {
+	suffixes := []string{"Ei", "Pi", "Ti", "Gi", "Mi", "Ki"}
+	for i, s := range suffixes {
+		unit := int64(1) << (10 * (len(suffixes) - i))
+		if bytes >= unit && bytes%unit == 0 {
+			return fmt.Sprintf("%d%s", bytes/unit, s)
+		}
+	}
+	return strconv.FormatInt(bytes, 10)
+}
+
+// FormatCPU formats a number of CPUs into a Kubernetes CPU quantity, e.g., "500m".
+func FormatCPU(cpus float64) string {
+	milli := int64(math.Round(cpus * 1000))
+	if milli%1000 == 0 {
+		return strconv.FormatInt(milli/1000, 10)
+	}
+	return fmt.Sprintf("%dm", milli)
+}
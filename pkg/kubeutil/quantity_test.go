@@ -0,0 +1,51 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package kubeutil
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseCPU(t *testing.T) {
+	for q, expected := range map[string]float64{"500m": 0.5, "2": 2, "1.5": 1.5, "100m": 0.1} {
+		cpus, err := ParseCPU(q)
+		assert.NilError(t, err)
+		assert.Equal(t, cpus, expected, q)
+	}
+	_, err := ParseCPU("lots")
+	assert.ErrorContains(t, err, "invalid CPU quantity")
+}
+
+func TestParseMemory(t *testing.T) {
+	for q, expected := range map[string]int64{"128Mi": 128 << 20, "1Gi": 1 << 30, "1G": 1e9, "500k": 500e3, "1024": 1024} {
+		bytes, err := ParseMemory(q)
+		assert.NilError(t, err)
+		assert.Equal(t, bytes, expected, q)
+	}
+	_, err := ParseMemory("-1Mi")
+	assert.ErrorContains(t, err, "invalid memory quantity")
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, FormatMemory(128<<20), "128Mi")
+	assert.Equal(t, FormatMemory(1<<30), "1Gi")
+	assert.Equal(t, FormatMemory(1000), "1000")
+	assert.Equal(t, FormatCPU(0.5), "500m")
+	assert.Equal(t, FormatCPU(2), "2")
+}
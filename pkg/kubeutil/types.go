@@ -0,0 +1,228 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package kubeutil implements the subset of the Kubernetes Pod, Deployment and ConfigMap
+// resources that is used by `nerdctl kube play` and `nerdctl kube generate`.
+package kubeutil
+
+// APIVersion is the API version of the generated Pod resources.
+const APIVersion = "v1"
+
+// ObjectMeta is the metadata of a resource.
+type ObjectMeta struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// Pod is a Kubernetes Pod.
+type Pod struct {
+	APIVersion string     `yaml:"apiVersion"`
+	Kind       string     `yaml:"kind"`
+	Metadata   ObjectMeta `yaml:"metadata"`
+	Spec       PodSpec    `yaml:"spec"`
+}
+
+// Deployment is a Kubernetes Deployment.
+type Deployment struct {
+	APIVersion string         `yaml:"apiVersion"`
+	Kind       string         `yaml:"kind"`
+	Metadata   ObjectMeta     `yaml:"metadata"`
+	Spec       DeploymentSpec `yaml:"spec"`
+}
+
+// DeploymentSpec is the specification of a Deployment.
+type DeploymentSpec struct {
+	Replicas *int            `yaml:"replicas,omitempty"`
+	Template PodTemplateSpec `yaml:"template"`
+}
+
+// PodTemplateSpec is the template of the pods of a Deployment.
+type PodTemplateSpec struct {
+	Metadata ObjectMeta `yaml:"metadata,omitempty"`
+	Spec     PodSpec    `yaml:"spec"`
+}
+
+// ConfigMap is a Kubernetes ConfigMap.
+type ConfigMap struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   ObjectMeta        `yaml:"metadata"`
+	Data       map[string]string `yaml:"data,omitempty"`
+}
+
+// PodSpec is the specification of a Pod.
+type PodSpec struct {
+	Containers      []Container         `yaml:"containers"`
+	Volumes         []Volume            `yaml:"volumes,omitempty"`
+	RestartPolicy   string              `yaml:"restartPolicy,omitempty"`
+	Hostname        string              `yaml:"hostname,omitempty"`
+	SecurityContext *PodSecurityContext `yaml:"securityContext,omitempty"`
+}
+
+// PodSecurityContext holds the security attributes applied to all the containers of a Pod.
+type PodSecurityContext struct {
+	RunAsUser  *int64 `yaml:"runAsUser,omitempty"`
+	RunAsGroup *int64 `yaml:"runAsGroup,omitempty"`
+}
+
+// Container is a container of a Pod.
+type Container struct {
+	Name            string               `yaml:"name"`
+	Image           string               `yaml:"image"`
+	Command         []string             `yaml:"command,omitempty"`
+	Args            []string             `yaml:"args,omitempty"`
+	WorkingDir      string               `yaml:"workingDir,omitempty"`
+	Ports           []ContainerPort      `yaml:"ports,omitempty"`
+	Env             []EnvVar             `yaml:"env,omitempty"`
+	Resources       ResourceRequirements `yaml:"resources,omitempty"`
+	VolumeMounts    []VolumeMount        `yaml:"volumeMounts,omitempty"`
+	LivenessProbe   *Probe               `yaml:"livenessProbe,omitempty"`
+	ReadinessProbe  *Probe               `yaml:"readinessProbe,omitempty"`
+	ImagePullPolicy string               `yaml:"imagePullPolicy,omitempty"`
+	SecurityContext *SecurityContext     `yaml:"securityContext,omitempty"`
+	Stdin           bool                 `yaml:"stdin,omitempty"`
+	TTY             bool                 `yaml:"tty,omitempty"`
+}
+
+// ContainerPort is a port of a container.
+type ContainerPort struct {
+	Name          string `yaml:"name,omitempty"`
+	HostPort      int32  `yaml:"hostPort,omitempty"`
+	ContainerPort int32  `yaml:"containerPort"`
+	Protocol      string `yaml:"protocol,omitempty"`
+	HostIP        string `yaml:"hostIP,omitempty"`
+}
+
+// EnvVar is an environment variable of a container.
+type EnvVar struct {
+	Name      string        `yaml:"name"`
+	Value     string        `yaml:"value,omitempty"`
+	ValueFrom *EnvVarSource `yaml:"valueFrom,omitempty"`
+}
+
+// EnvVarSource is the source of the value of an environment variable.
+type EnvVarSource struct {
+	ConfigMapKeyRef *ConfigMapKeySelector `yaml:"configMapKeyRef,omitempty"`
+}
+
+// ConfigMapKeySelector selects a key of a ConfigMap.
+type ConfigMapKeySelector struct {
+	Name     string `yaml:"name"`
+	Key      string `yaml:"key"`
+	Optional *bool  `yaml:"optional,omitempty"`
+}
+
+// ResourceRequirements are the compute resources of a container, e.g., {"cpu": "500m", "memory": "128Mi"}.
+type ResourceRequirements struct {
+	Limits   map[string]string `yaml:"limits,omitempty"`
+	Requests map[string]string `yaml:"requests,omitempty"`
+}
+
+// VolumeMount mounts a volume of the Pod into a container.
+type VolumeMount struct {
+	Name      string `yaml:"name"`
+	MountPath string `yaml:"mountPath"`
+	ReadOnly  bool   `yaml:"readOnly,omitempty"`
+}
+
+// Volume is a volume of a Pod.
+type Volume struct {
+	Name                  string                             `yaml:"name"`
+	EmptyDir              *EmptyDirVolumeSource              `yaml:"emptyDir,omitempty"`
+	HostPath              *HostPathVolumeSource              `yaml:"hostPath,omitempty"`
+	ConfigMap             *ConfigMapVolumeSource             `yaml:"configMap,omitempty"`
+	PersistentVolumeClaim *PersistentVolumeClaimVolumeSource `yaml:"persistentVolumeClaim,omitempty"`
+}
+
+// EmptyDirVolumeSource is an empty directory shared by the containers of a Pod.
+type EmptyDirVolumeSource struct {
+	Medium string `yaml:"medium,omitempty"`
+}
+
+// HostPathVolumeSource is a file or a directory of the host.
+type HostPathVolumeSource struct {
+	Path string `yaml:"path"`
+	Type string `yaml:"type,omitempty"`
+}
+
+// ConfigMapVolumeSource populates a volume with the data of a ConfigMap.
+type ConfigMapVolumeSource struct {
+	Name     string      `yaml:"name"`
+	Items    []KeyToPath `yaml:"items,omitempty"`
+	Optional *bool       `yaml:"optional,omitempty"`
+}
+
+// KeyToPath maps a key of a ConfigMap to a relative path in the volume.
+type KeyToPath struct {
+	Key  string `yaml:"key"`
+	Path string `yaml:"path"`
+}
+
+// PersistentVolumeClaimVolumeSource references a persistent volume, which is a named volume for nerdctl.
+type PersistentVolumeClaimVolumeSource struct {
+	ClaimName string `yaml:"claimName"`
+	ReadOnly  bool   `yaml:"readOnly,omitempty"`
+}
+
+// Probe describes a health check of a container.
+type Probe struct {
+	Exec                *ExecAction      `yaml:"exec,omitempty"`
+	HTTPGet             *HTTPGetAction   `yaml:"httpGet,omitempty"`
+	TCPSocket           *TCPSocketAction `yaml:"tcpSocket,omitempty"`
+	InitialDelaySeconds int32            `yaml:"initialDelaySeconds,omitempty"`
+	TimeoutSeconds      int32            `yaml:"timeoutSeconds,omitempty"`
+	PeriodSeconds       int32            `yaml:"periodSeconds,omitempty"`
+	FailureThreshold    int32            `yaml:"failureThreshold,omitempty"`
+}
+
+// ExecAction runs a command in the container.
+type ExecAction struct {
+	Command []string `yaml:"command,omitempty"`
+}
+
+// HTTPGetAction sends an HTTP GET request to the container.
+// Port is either a port number or the name of a port of the container.
+type HTTPGetAction struct {
+	Path   string `yaml:"path,omitempty"`
+	Port   string `yaml:"port"`
+	Host   string `yaml:"host,omitempty"`
+	Scheme string `yaml:"scheme,omitempty"`
+}
+
+// TCPSocketAction opens a TCP connection to the container.
+// Port is either a port number or the name of a port of the container.
+type TCPSocketAction struct {
+	Port string `yaml:"port"`
+	Host string `yaml:"host,omitempty"`
+}
+
+// SecurityContext holds the security attributes of a container.
+type SecurityContext struct {
+	Privileged               *bool         `yaml:"privileged,omitempty"`
+	RunAsUser                *int64        `yaml:"runAsUser,omitempty"`
+	RunAsGroup               *int64        `yaml:"runAsGroup,omitempty"`
+	ReadOnlyRootFilesystem   *bool         `yaml:"readOnlyRootFilesystem,omitempty"`
+	AllowPrivilegeEscalation *bool         `yaml:"allowPrivilegeEscalation,omitempty"`
+	Capabilities             *Capabilities `yaml:"capabilities,omitempty"`
+}
+
+// Capabilities are the Linux capabilities added to and dropped from a container.
+type Capabilities struct {
+	Add  []string `yaml:"add,omitempty"`
+	Drop []string `yaml:"drop,omitempty"`
+}
@@ -44,6 +44,18 @@ const (
 	// ComposeConfigHash stores the service configuration hash used for convergence decisions
 	ComposeConfigHash = "com.docker.compose.config-hash"
 
+	// ComposeConfigFiles stores the comma-separated compose files of the project
+	ComposeConfigFiles = "com.docker.compose.project.config_files"
+
+	// ComposeWorkingDir stores the working directory of the project
+	ComposeWorkingDir = "com.docker.compose.project.working_dir"
+
+	// ComposeDependsOn stores the dependencies of the service, as comma-separated "<service>:<condition>:<restart>"
+	ComposeDependsOn = "com.docker.compose.depends_on"
+
+	// KubeFile stores the absolute path of the manifest played with `nerdctl kube play`
+	KubeFile = "nerdctl.kube.file"
+
 	// Hostname
 	Hostname = Prefix + "hostname"
 
@@ -70,6 +82,10 @@ const (
 	// IP6Address is the static IP6 address of the container assigned by the user
 	IP6Address = Prefix + "ip6"
 
+	// NetworkAliases is a JSON-marshalled string of map[string][]string, mapping the networks
+	// of the container to its network-scoped aliases (`nerdctl run --network-alias`).
+	NetworkAliases = Prefix + "network-aliases"
+
 	// LogURI is the log URI
 	LogURI = Prefix + "log-uri"
 
@@ -105,6 +121,18 @@ const (
 	// IPC indicates ipc victim container.
 	IPC = Prefix + "ipc"
 
+	// UTSContainer is the ID of the container whose UTS namespace is shared (e.g. the pod infra container).
+	UTSContainer = Prefix + "uts-container"
+
+	// Pod is the name of the pod the container belongs to.
+	Pod = Prefix + "pod"
+
+	// PodInfra is set to "true" on the infra container of a pod.
+	PodInfra = Prefix + "pod-infra"
+
+	// SdNotify is the `--sdnotify` mode of the container.
+	SdNotify = Prefix + "sdnotify"
+
 	// Error encapsulates a container human-readable string
 	// that describes container error.
 	Error = Prefix + "error"
@@ -143,8 +171,15 @@ const (
 	// HealthState stores the current health state (status and failing streak).
 	HealthState = Prefix + "healthstate"
 
+	// HealthOnFailure is the action taken when the container becomes unhealthy (--health-on-failure).
+	HealthOnFailure = Prefix + "health-on-failure"
+
 	// Privileged indicates whether the container was created with --privileged.
 	Privileged = Prefix + "privileged"
 	// ExposedPorts is a JSON-marshalled string of nat.PortSet.
 	ExposedPorts = Prefix + "exposed-ports"
+
+	// SeccompRecord is the path of the seccomp profile recorded from the syscalls of the container
+	// (--security-opt seccomp=record:PATH). Set as an OCI annotation, for the postStop hook.
+	SeccompRecord = Prefix + "seccomp-record"
 )
@@ -43,6 +43,7 @@ var JSONDriverLogOpts = []string{
 	LogPath,
 	MaxSize,
 	MaxFile,
+	Compress,
 	Env,
 	Labels,
 }
@@ -59,6 +60,11 @@ func JSONFileLogOptsValidate(logOptMap map[string]string) error {
 			log.L.Warnf("log-opt %s is ignored for json-file log driver", key)
 		}
 	}
+	if v, ok := logOptMap[Compress]; ok {
+		if _, err := strconv.ParseBool(v); err != nil {
+			return fmt.Errorf("failed to parse log-opt %s %q: %w", Compress, v, err)
+		}
+	}
 	return nil
 }
 
@@ -119,6 +125,13 @@ func (jsonLogger *JSONLogger) PreProcess(ctx context.Context, dataStore string,
 	}
 	// MaxBackups does not include file to write logs to
 	l.MaxBackups = maxFile - 1
+	if compress, ok := jsonLogger.Opts[Compress]; ok {
+		var err error
+		l.Compress, err = strconv.ParseBool(compress)
+		if err != nil {
+			return err
+		}
+	}
 	jsonLogger.logger = l
 	jsonLogger.encoder = jsonfile.NewSyncEncoder(l)
 	return nil
@@ -164,6 +177,11 @@ func viewLogsJSONFile(lvopts LogViewOptions, stdout, stderr io.Writer, stopChann
 // If `LogViewOptions.Follow` is provided, it will refresh and re-read the file until
 // it receives something through the stopChannel.
 func viewLogsJSONFileDirect(lvopts LogViewOptions, jsonLogFilePath string, stdout, stderr io.Writer, stopChannel chan os.Signal) error {
+	// The rotated segments, possibly compressed, precede the current log file
+	if err := viewRotatedJSONLogFiles(lvopts, jsonLogFilePath, stdout, stderr); err != nil {
+		return fmt.Errorf("failed to read the rotated segments of JSON logfile %q: %w", jsonLogFilePath, err)
+	}
+
 	fin, err := os.OpenFile(jsonLogFilePath, os.O_RDONLY, 0400)
 	if err != nil {
 		return err
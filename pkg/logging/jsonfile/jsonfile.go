@@ -95,7 +95,8 @@ func Encode(stdout <-chan string, stderr <-chan string, writer io.Writer) error
 	return nil
 }
 
-func writeEntry(e *Entry, stdout, stderr io.Writer, refTime time.Time, timestamps bool, since string, until string) error {
+// WriteEntry writes the entry to the writer of its stream, unless it is filtered out by since and until.
+func WriteEntry(e *Entry, stdout, stderr io.Writer, refTime time.Time, timestamps bool, since string, until string) error {
 	output := []byte{}
 
 	if since != "" {
@@ -168,7 +169,7 @@ func Decode(stdout, stderr io.Writer, r io.Reader, timestamps bool, since string
 		}
 
 		// Write out the entry directly
-		err := writeEntry(&e, stdout, stderr, now, timestamps, since, until)
+		err := WriteEntry(&e, stdout, stderr, now, timestamps, since, until)
 		if err != nil {
 			log.L.WithError(err).Errorf("error while writing log entry to output stream")
 		}
@@ -0,0 +1,282 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	units "github.com/docker/go-units"
+	"github.com/fahedouch/go-logrotate"
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
+	"github.com/containerd/nerdctl/v2/pkg/logging/localfile"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+var LocalDriverLogOpts = []string{
+	MaxSize,
+	MaxFile,
+	Compress,
+}
+
+const (
+	// the defaults correspond to Docker
+	defaultLocalMaxSize  = "20m"
+	defaultLocalMaxFile  = 5
+	defaultLocalCompress = true
+)
+
+// LocalLogger writes the logs in the compact protobuf-based format of the Docker "local" logging driver.
+type LocalLogger struct {
+	Opts    map[string]string
+	logger  *logrotate.Logger
+	encoder *localfile.SyncEncoder
+}
+
+func LocalLogOptsValidate(logOptMap map[string]string) error {
+	for key := range logOptMap {
+		if !strutil.InStringSlice(LocalDriverLogOpts, key) {
+			log.L.Warnf("log-opt %s is ignored for local log driver", key)
+		}
+	}
+	_, err := newLocalRotateLogger("", logOptMap)
+	return err
+}
+
+// newLocalRotateLogger returns the rotating writer of the log file, configured by the log-opts.
+func newLocalRotateLogger(logFilePath string, logOptMap map[string]string) (*logrotate.Logger, error) {
+	l := &logrotate.Logger{
+		Filename: logFilePath,
+		Compress: defaultLocalCompress,
+	}
+	maxSize := defaultLocalMaxSize
+	if v, ok := logOptMap[MaxSize]; ok {
+		maxSize = v
+	}
+	var err error
+	if l.MaxBytes, err = units.FromHumanSize(maxSize); err != nil {
+		return nil, fmt.Errorf("failed to parse log-opt %s %q: %w", MaxSize, maxSize, err)
+	}
+	if l.MaxBytes <= 0 {
+		return nil, fmt.Errorf("max-size must be a positive number")
+	}
+	maxFile := defaultLocalMaxFile
+	if v, ok := logOptMap[MaxFile]; ok {
+		if maxFile, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("failed to parse log-opt %s %q: %w", MaxFile, v, err)
+		}
+		if maxFile < 1 {
+			return nil, fmt.Errorf("max-file cannot be less than 1")
+		}
+	}
+	// MaxBackups does not include file to write logs to
+	l.MaxBackups = maxFile - 1
+	if v, ok := logOptMap[Compress]; ok {
+		if l.Compress, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("failed to parse log-opt %s %q: %w", Compress, v, err)
+		}
+	}
+	return l, nil
+}
+
+func (localLogger *LocalLogger) Init(dataStore, ns, id string) error {
+	logFilePath := localfile.Path(dataStore, ns, id)
+	if err := os.MkdirAll(filepath.Dir(logFilePath), 0700); err != nil {
+		return err
+	}
+	if _, err := os.Stat(logFilePath); errors.Is(err, os.ErrNotExist) {
+		if writeErr := filesystem.WriteFile(logFilePath, []byte{}, 0600); writeErr != nil {
+			return writeErr
+		}
+	}
+	return nil
+}
+
+func (localLogger *LocalLogger) PreProcess(ctx context.Context, dataStore string, config *logging.Config) error {
+	l, err := newLocalRotateLogger(localfile.Path(dataStore, config.Namespace, config.ID), localLogger.Opts)
+	if err != nil {
+		return err
+	}
+	localLogger.logger = l
+	localLogger.encoder = localfile.NewSyncEncoder(l)
+	return nil
+}
+
+func (localLogger *LocalLogger) Process(stdout <-chan string, stderr <-chan string) error {
+	errs := make(chan error, 2)
+	encode := func(stream string, dataChan <-chan string) {
+		var err error
+		for line := range dataChan {
+			if encErr := localLogger.encoder.Encode(stream, line); encErr != nil && err == nil {
+				err = encErr
+			}
+		}
+		errs <- err
+	}
+	go encode(streamStdout, stdout)
+	go encode(streamStderr, stderr)
+	return errors.Join(<-errs, <-errs)
+}
+
+// WriteLogEntry writes a single log line synchronously, implementing SyncDriver.
+func (localLogger *LocalLogger) WriteLogEntry(stream, line string) error {
+	return localLogger.encoder.Encode(stream, line)
+}
+
+func (localLogger *LocalLogger) PostProcess() error {
+	return localLogger.logger.Close()
+}
+
+// viewLogsLocal loads the log entries written by the local driver, including its rotated segments,
+// and forwards them to the provided io.Writers after applying the provided logging options.
+func viewLogsLocal(lvopts LogViewOptions, stdout, stderr io.Writer, stopChannel chan os.Signal) error {
+	logFilePath := localfile.Path(lvopts.DatastoreRootPath, lvopts.Namespace, lvopts.ContainerID)
+	fin, err := os.Open(logFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to open local log file: %w", err)
+	}
+	defer func() { fin.Close() }()
+
+	start, count, err := localfile.FindTailEntryStartIndex(fin, lvopts.Tail)
+	if err != nil {
+		return fmt.Errorf("failed to tail %d entries of local log file %q: %w", lvopts.Tail, logFilePath, err)
+	}
+	if lvopts.Tail == 0 || count < lvopts.Tail {
+		if err := viewRotatedLocalLogFiles(lvopts, logFilePath, lvopts.Tail-count, stdout, stderr); err != nil {
+			return fmt.Errorf("failed to read the rotated segments of local log file %q: %w", logFilePath, err)
+		}
+	}
+
+	pos := start
+	decode := func() error {
+		if _, err := fin.Seek(pos, io.SeekStart); err != nil {
+			return err
+		}
+		n, err := localfile.Decode(stdout, stderr, fin, lvopts.Timestamps, lvopts.Since, lvopts.Until)
+		pos += n
+		return err
+	}
+	if err := decode(); err != nil {
+		return fmt.Errorf("failed to read local log file %q: %w", logFilePath, err)
+	}
+	if !lvopts.Follow {
+		return nil
+	}
+
+	watcher, err := NewLogFileWatcher(filepath.Dir(logFilePath))
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	for {
+		// read what may have been written before the watcher was set up
+		if err := decode(); err != nil {
+			return fmt.Errorf("failed to read local log file %q: %w", logFilePath, err)
+		}
+		select {
+		case <-stopChannel:
+			log.L.Debug("received stop signal while re-reading local logfile, draining remaining logs and returning")
+			return decode()
+		default:
+		}
+		recreated, err := waitLocalLogWrite(watcher, filepath.Base(logFilePath), stopChannel)
+		if err != nil {
+			return err
+		}
+		if recreated {
+			// the file was rotated: read the end of the previous one, then switch to the new one
+			if err := decode(); err != nil {
+				return err
+			}
+			newF, err := openFileShareDelete(logFilePath)
+			if err != nil {
+				return fmt.Errorf("failed to open local log file %q: %w", logFilePath, err)
+			}
+			fin.Close()
+			fin = newF
+			pos = 0
+		}
+	}
+}
+
+// waitLocalLogWrite waits for the next write to the log file, and returns whether it was recreated.
+func waitLocalLogWrite(w *fsnotify.Watcher, logName string, stopChannel chan os.Signal) (bool, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stopChannel:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	recreated, err := startTail(ctx, logName, w)
+	if err != nil && ctx.Err() != nil {
+		// stopped: the caller drains the remaining entries
+		return false, nil
+	}
+	return recreated, err
+}
+
+// viewRotatedLocalLogFiles writes the last n entries of the rotated segments of the local log file,
+// or all of them if n is 0.
+func viewRotatedLocalLogFiles(lvopts LogViewOptions, logFilePath string, n uint, stdout, stderr io.Writer) error {
+	segments, err := rotatedLogFiles(logFilePath)
+	if err != nil || len(segments) == 0 {
+		return err
+	}
+	var contents [][]byte
+	for i := len(segments) - 1; i >= 0; i-- {
+		content, err := readRotatedLogFile(segments[i])
+		if err != nil {
+			if os.IsNotExist(err) {
+				// removed by the rotation meanwhile
+				continue
+			}
+			return err
+		}
+		if n > 0 {
+			start, count, err := localfile.FindTailEntryStartIndex(bytes.NewReader(content), n)
+			if err != nil {
+				return err
+			}
+			content = content[start:]
+			n -= count
+		}
+		contents = append([][]byte{content}, contents...)
+		if lvopts.Tail > 0 && n == 0 {
+			break
+		}
+	}
+	for _, content := range contents {
+		if _, err := localfile.Decode(stdout, stderr, bytes.NewReader(content), lvopts.Timestamps, lvopts.Since, lvopts.Until); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+
+	"github.com/containerd/nerdctl/v2/pkg/logging/localfile"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestLocalLogOptsValidate(t *testing.T) {
+	assert.NilError(t, LocalLogOptsValidate(map[string]string{MaxSize: "10m", MaxFile: "3", Compress: "false"}))
+	assert.ErrorContains(t, LocalLogOptsValidate(map[string]string{MaxSize: "foo"}), "failed to parse log-opt")
+	assert.ErrorContains(t, LocalLogOptsValidate(map[string]string{MaxFile: "0"}), "max-file cannot be less than 1")
+	assert.ErrorContains(t, LocalLogOptsValidate(map[string]string{Compress: "foo"}), "failed to parse log-opt")
+}
+
+func TestLocalLogger(t *testing.T) {
+	dataStore := t.TempDir()
+	const ns, id = "default", "0123456789abcdef"
+	logFilePath := localfile.Path(dataStore, ns, id)
+
+	driver := &LocalLogger{Opts: map[string]string{MaxSize: "200", MaxFile: "10"}}
+	assert.NilError(t, driver.Init(dataStore, ns, id))
+	assert.NilError(t, driver.PreProcess(context.Background(), dataStore, &logging.Config{Namespace: ns, ID: id}))
+	var expected strings.Builder
+	for i := 0; i < 20; i++ {
+		assert.NilError(t, driver.WriteLogEntry(streamStdout, fmt.Sprintf("line%d\n", i)))
+		fmt.Fprintf(&expected, "line%d\n", i)
+	}
+	assert.NilError(t, driver.WriteLogEntry(streamStderr, "error\n"))
+	assert.NilError(t, driver.PostProcess())
+	waitCompressed(t, logFilePath)
+
+	segments, err := rotatedLogFiles(logFilePath)
+	assert.NilError(t, err)
+	assert.Assert(t, len(segments) > 1)
+
+	lvopts := LogViewOptions{
+		ContainerID:       id,
+		Namespace:         ns,
+		DatastoreRootPath: dataStore,
+	}
+	var stdout, stderr bytes.Buffer
+	assert.NilError(t, viewLogsLocal(lvopts, &stdout, &stderr, make(chan os.Signal)))
+	assert.Equal(t, stdout.String(), expected.String())
+	assert.Equal(t, stderr.String(), "error\n")
+
+	lvopts.Tail = 12
+	stdout.Reset()
+	stderr.Reset()
+	assert.NilError(t, viewLogsLocal(lvopts, &stdout, &stderr, make(chan os.Signal)))
+	assert.Equal(t, stdout.String(), "line9\nline10\nline11\nline12\nline13\nline14\nline15\nline16\nline17\nline18\nline19\n")
+	assert.Equal(t, stderr.String(), "error\n")
+}
+
+func TestLocalLoggerFollow(t *testing.T) {
+	dataStore := t.TempDir()
+	const ns, id = "default", "0123456789abcdef"
+
+	driver := &LocalLogger{Opts: map[string]string{MaxSize: "100", Compress: "false"}}
+	assert.NilError(t, driver.Init(dataStore, ns, id))
+	assert.NilError(t, driver.PreProcess(context.Background(), dataStore, &logging.Config{Namespace: ns, ID: id}))
+	assert.NilError(t, driver.WriteLogEntry(streamStdout, "line0\n"))
+
+	lvopts := LogViewOptions{
+		ContainerID:       id,
+		Namespace:         ns,
+		DatastoreRootPath: dataStore,
+		Follow:            true,
+	}
+	stdout := &syncBuffer{}
+	stopChannel := make(chan os.Signal)
+	done := make(chan error)
+	go func() {
+		done <- viewLogsLocal(lvopts, stdout, stdout, stopChannel)
+	}()
+
+	var expected strings.Builder
+	expected.WriteString("line0\n")
+	for i := 1; i < 10; i++ {
+		// let the follower catch up, across the rotations
+		time.Sleep(20 * time.Millisecond)
+		assert.NilError(t, driver.WriteLogEntry(streamStdout, fmt.Sprintf("line%d\n", i)))
+		fmt.Fprintf(&expected, "line%d\n", i)
+	}
+	assert.NilError(t, driver.PostProcess())
+	time.Sleep(200 * time.Millisecond)
+	close(stopChannel)
+	assert.NilError(t, <-done)
+	assert.Equal(t, stdout.String(), expected.String())
+}
@@ -0,0 +1,254 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package localfile implements the log file format of the Docker "local" logging driver.
+// Each entry is a protobuf-encoded LogEntry message, framed by its size as a big-endian uint32
+// both before and after it, so that the file can be read backwards.
+package localfile
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/containerd/nerdctl/v2/pkg/logging/jsonfile"
+)
+
+const (
+	// frameSize is the size of the size prefix and suffix of an entry
+	frameSize = 4
+	// maxLineSize is the size above which a line is split into partial entries, like Docker
+	maxLineSize = 1 << 20
+	// maxEntrySize guards against reading a corrupted size
+	maxEntrySize = maxLineSize + 1024
+
+	// field numbers of the LogEntry message
+	// https://github.com/moby/moby/blob/v28.0.0/api/types/plugins/logdriver/entry.proto
+	fieldSource   = 1
+	fieldTimeNano = 2
+	fieldLine     = 3
+	fieldPartial  = 4
+)
+
+// ErrCorrupted is returned when an entry cannot be decoded.
+var ErrCorrupted = errors.New("corrupted log entry")
+
+// Entry is compatible with the LogEntry message of Docker "local" logs.
+type Entry struct {
+	Source   string // "stdout" or "stderr"
+	TimeNano int64
+	Line     []byte // line, without the trailing newline
+	Partial  bool   // the line did not end with a newline
+}
+
+func Path(dataStore, ns, id string) string {
+	// the directory and file names correspond to Docker
+	return filepath.Join(dataStore, "containers", ns, id, "local-logs", "container.log")
+}
+
+// Marshal returns the framed protobuf encoding of the entry.
+func Marshal(e *Entry) []byte {
+	msg := make([]byte, 0, len(e.Line)+len(e.Source)+32)
+	msg = protowire.AppendTag(msg, fieldSource, protowire.BytesType)
+	msg = protowire.AppendString(msg, e.Source)
+	msg = protowire.AppendTag(msg, fieldTimeNano, protowire.VarintType)
+	msg = protowire.AppendVarint(msg, uint64(e.TimeNano))
+	msg = protowire.AppendTag(msg, fieldLine, protowire.BytesType)
+	msg = protowire.AppendBytes(msg, e.Line)
+	if e.Partial {
+		msg = protowire.AppendTag(msg, fieldPartial, protowire.VarintType)
+		msg = protowire.AppendVarint(msg, protowire.EncodeBool(true))
+	}
+	b := make([]byte, 0, len(msg)+2*frameSize)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(msg)))
+	b = append(b, msg...)
+	return binary.BigEndian.AppendUint32(b, uint32(len(msg)))
+}
+
+// Unmarshal decodes the protobuf encoding of an entry, without its frame.
+func Unmarshal(msg []byte, e *Entry) error {
+	*e = Entry{}
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		if n < 0 {
+			return ErrCorrupted
+		}
+		msg = msg[n:]
+		switch {
+		case num == fieldSource && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(msg)
+			if n < 0 {
+				return ErrCorrupted
+			}
+			e.Source = v
+			msg = msg[n:]
+		case num == fieldTimeNano && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(msg)
+			if n < 0 {
+				return ErrCorrupted
+			}
+			e.TimeNano = int64(v)
+			msg = msg[n:]
+		case num == fieldLine && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(msg)
+			if n < 0 {
+				return ErrCorrupted
+			}
+			e.Line = append([]byte(nil), v...)
+			msg = msg[n:]
+		case num == fieldPartial && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(msg)
+			if n < 0 {
+				return ErrCorrupted
+			}
+			e.Partial = protowire.DecodeBool(v)
+			msg = msg[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, msg)
+			if n < 0 {
+				return ErrCorrupted
+			}
+			msg = msg[n:]
+		}
+	}
+	return nil
+}
+
+// SyncEncoder writes individual local log entries to a writer. Its Encode
+// method is safe for concurrent use, so it can be shared between the goroutines
+// reading a container's stdout and stderr.
+type SyncEncoder struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewSyncEncoder returns a SyncEncoder that writes to w.
+func NewSyncEncoder(w io.Writer) *SyncEncoder {
+	return &SyncEncoder{w: w}
+}
+
+// Encode writes the log entries of a line for the given stream.
+// A line larger than 1MiB is split into partial entries.
+// Each entry is written with a single call to Write, so that a rotating writer never splits it.
+func (s *SyncEncoder) Encode(stream, line string) error {
+	timeNano := time.Now().UnixNano()
+	partial := !strings.HasSuffix(line, "\n")
+	line = strings.TrimSuffix(line, "\n")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for {
+		chunk := line
+		if len(chunk) > maxLineSize {
+			chunk = chunk[:maxLineSize]
+		}
+		line = line[len(chunk):]
+		e := &Entry{
+			Source:   stream,
+			TimeNano: timeNano,
+			Line:     []byte(chunk),
+			Partial:  partial || line != "",
+		}
+		if _, err := s.w.Write(Marshal(e)); err != nil {
+			return err
+		}
+		if line == "" {
+			return nil
+		}
+	}
+}
+
+// Decode writes the entries read from r to stdout and stderr, and returns the number of bytes
+// of the complete entries, so that the caller can resume reading after them.
+func Decode(stdout, stderr io.Writer, r io.Reader, timestamps bool, since string, until string) (int64, error) {
+	now := time.Now()
+	var read int64
+	var frame [frameSize]byte
+	for {
+		if _, err := io.ReadFull(r, frame[:]); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return read, nil
+			}
+			return read, err
+		}
+		size := binary.BigEndian.Uint32(frame[:])
+		if size > maxEntrySize {
+			return read, fmt.Errorf("%w: size %d", ErrCorrupted, size)
+		}
+		buf := make([]byte, int(size)+frameSize)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				// the entry is being written
+				return read, nil
+			}
+			return read, err
+		}
+		var e Entry
+		if err := Unmarshal(buf[:size], &e); err != nil {
+			return read, err
+		}
+		read += int64(size) + 2*frameSize
+
+		logLine := string(e.Line)
+		if !e.Partial {
+			logLine += "\n"
+		}
+		if err := jsonfile.WriteEntry(&jsonfile.Entry{
+			Log:    logLine,
+			Stream: e.Source,
+			Time:   time.Unix(0, e.TimeNano).UTC(),
+		}, stdout, stderr, now, timestamps, since, until); err != nil {
+			return read, err
+		}
+	}
+}
+
+// FindTailEntryStartIndex returns the start of the last nth entry, and the number of entries from there,
+// which is less than n if the file has fewer entries.
+// If n is 0, it returns the beginning of the file.
+func FindTailEntryStartIndex(f io.ReadSeeker, n uint) (int64, uint, error) {
+	if n == 0 {
+		return 0, 0, nil
+	}
+	pos, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, 0, err
+	}
+	var count uint
+	var frame [frameSize]byte
+	for count < n && pos >= 2*frameSize {
+		if _, err := f.Seek(pos-frameSize, io.SeekStart); err != nil {
+			return 0, 0, err
+		}
+		if _, err := io.ReadFull(f, frame[:]); err != nil {
+			return 0, 0, err
+		}
+		size := int64(binary.BigEndian.Uint32(frame[:]))
+		start := pos - size - 2*frameSize
+		if size > maxEntrySize || start < 0 {
+			return 0, 0, fmt.Errorf("%w: size %d at offset %d", ErrCorrupted, size, pos)
+		}
+		pos = start
+		count++
+	}
+	return pos, count, nil
+}
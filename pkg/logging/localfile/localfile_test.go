@@ -0,0 +1,115 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package localfile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestMarshalUnmarshal(t *testing.T) {
+	in := &Entry{
+		Source:   "stderr",
+		TimeNano: 1700000000123456789,
+		Line:     []byte("hello"),
+		Partial:  true,
+	}
+	b := Marshal(in)
+	assert.DeepEqual(t, b[:frameSize], b[len(b)-frameSize:])
+	var out Entry
+	assert.NilError(t, Unmarshal(b[frameSize:len(b)-frameSize], &out))
+	assert.DeepEqual(t, *in, out)
+
+	assert.ErrorIs(t, Unmarshal([]byte{0x1a, 0x05, 'a'}, &out), ErrCorrupted)
+}
+
+func TestEncodeDecode(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewSyncEncoder(&buf)
+	assert.NilError(t, enc.Encode("stdout", "out1\n"))
+	assert.NilError(t, enc.Encode("stderr", "err1\n"))
+	assert.NilError(t, enc.Encode("stdout", "partial"))
+	assert.NilError(t, enc.Encode("stdout", "-end\n"))
+
+	var stdout, stderr bytes.Buffer
+	n, err := Decode(&stdout, &stderr, bytes.NewReader(buf.Bytes()), false, "", "")
+	assert.NilError(t, err)
+	assert.Equal(t, n, int64(buf.Len()))
+	assert.Equal(t, stdout.String(), "out1\npartial-end\n")
+	assert.Equal(t, stderr.String(), "err1\n")
+}
+
+func TestEncodeLongLine(t *testing.T) {
+	var buf bytes.Buffer
+	line := strings.Repeat("a", maxLineSize+10) + "\n"
+	assert.NilError(t, NewSyncEncoder(&buf).Encode("stdout", line))
+
+	_, count, err := FindTailEntryStartIndex(bytes.NewReader(buf.Bytes()), 5)
+	assert.NilError(t, err)
+	assert.Equal(t, count, uint(2))
+
+	var stdout bytes.Buffer
+	_, err = Decode(&stdout, &stdout, bytes.NewReader(buf.Bytes()), false, "", "")
+	assert.NilError(t, err)
+	assert.Equal(t, stdout.String(), line)
+}
+
+func TestDecodeIncompleteEntry(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewSyncEncoder(&buf)
+	assert.NilError(t, enc.Encode("stdout", "complete\n"))
+	complete := buf.Len()
+	assert.NilError(t, enc.Encode("stdout", "being written\n"))
+
+	var stdout bytes.Buffer
+	n, err := Decode(&stdout, &stdout, bytes.NewReader(buf.Bytes()[:buf.Len()-3]), false, "", "")
+	assert.NilError(t, err)
+	assert.Equal(t, n, int64(complete))
+	assert.Equal(t, stdout.String(), "complete\n")
+}
+
+func TestFindTailEntryStartIndex(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewSyncEncoder(&buf)
+	for _, line := range []string{"1\n", "2\n", "3\n"} {
+		assert.NilError(t, enc.Encode("stdout", line))
+	}
+	r := bytes.NewReader(buf.Bytes())
+
+	testCases := []struct {
+		n             uint
+		expectedCount uint
+		expected      string
+	}{
+		{0, 0, "1\n2\n3\n"},
+		{1, 1, "3\n"},
+		{2, 2, "2\n3\n"},
+		{5, 3, "1\n2\n3\n"},
+	}
+	for _, tc := range testCases {
+		start, count, err := FindTailEntryStartIndex(r, tc.n)
+		assert.NilError(t, err)
+		assert.Equal(t, count, tc.expectedCount)
+		var stdout bytes.Buffer
+		_, err = Decode(&stdout, &stdout, bytes.NewReader(buf.Bytes()[start:]), false, "", "")
+		assert.NilError(t, err)
+		assert.Equal(t, stdout.String(), tc.expected)
+	}
+}
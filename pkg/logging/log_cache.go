@@ -0,0 +1,197 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	units "github.com/docker/go-units"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+	"github.com/containerd/log"
+)
+
+const (
+	// CacheDisabled disables the local cache of the drivers that cannot be read by `nerdctl logs`.
+	CacheDisabled = "cache-disabled"
+	// CacheMaxSize is the maximum size of the local cache before it is rotated.
+	CacheMaxSize = "cache-max-size"
+	// CacheMaxFile is the maximum number of files of the local cache.
+	CacheMaxFile = "cache-max-file"
+	// CacheCompress enables the compression of the rotated files of the local cache.
+	CacheCompress = "cache-compress"
+
+	defaultCacheMaxSize  = "20m"
+	defaultCacheMaxFile  = "5"
+	defaultCacheCompress = "true"
+)
+
+// cacheLogOpts are the log-opts of the local cache, that apply to all the drivers.
+var cacheLogOpts = []string{
+	CacheDisabled,
+	CacheMaxSize,
+	CacheMaxFile,
+	CacheCompress,
+}
+
+// CachePath returns the path of the local cache of the container logs.
+func CachePath(dataStore, ns, id string) string {
+	// the file name corresponds to Docker
+	return filepath.Join(dataStore, "containers", ns, id, "container-cached.log")
+}
+
+// usesLogCache returns whether the logs of the driver are written to the local cache too,
+// that is when the driver cannot be read by `nerdctl logs` and the cache is not disabled.
+func usesLogCache(driverName string, logOptMap map[string]string) bool {
+	if driverName == "none" {
+		return false
+	}
+	if _, ok := logViewers[driverName]; ok {
+		return false
+	}
+	disabled, _ := strconv.ParseBool(logOptMap[CacheDisabled])
+	return !disabled
+}
+
+// validateCacheLogOpts validates the log-opts of the local cache.
+func validateCacheLogOpts(driverName string, logOptMap map[string]string) error {
+	for _, key := range []string{CacheDisabled, CacheCompress} {
+		if v, ok := logOptMap[key]; ok {
+			if _, err := strconv.ParseBool(v); err != nil {
+				return fmt.Errorf("failed to parse log-opt %s %q: %w", key, v, err)
+			}
+		}
+	}
+	if v, ok := logOptMap[CacheMaxSize]; ok {
+		size, err := units.FromHumanSize(v)
+		if err != nil {
+			return fmt.Errorf("failed to parse log-opt %s %q: %w", CacheMaxSize, v, err)
+		}
+		if size <= 0 {
+			return fmt.Errorf("log-opt %s must be a positive number", CacheMaxSize)
+		}
+	}
+	if v, ok := logOptMap[CacheMaxFile]; ok {
+		maxFile, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("failed to parse log-opt %s %q: %w", CacheMaxFile, v, err)
+		}
+		if maxFile < 1 {
+			return fmt.Errorf("log-opt %s cannot be less than 1", CacheMaxFile)
+		}
+	}
+	if !usesLogCache(driverName, logOptMap) {
+		for _, key := range []string{CacheMaxSize, CacheMaxFile, CacheCompress} {
+			if _, ok := logOptMap[key]; ok {
+				log.L.Warnf("log-opt %s is ignored for %s log driver", key, driverName)
+			}
+		}
+	}
+	return nil
+}
+
+// withLogCache wraps the driver in a DualLogger when it uses the local cache.
+func withLogCache(driverName string, driver Driver, logOptMap map[string]string) Driver {
+	if !usesLogCache(driverName, logOptMap) {
+		return driver
+	}
+	cacheOpts := map[string]string{
+		MaxSize:  defaultCacheMaxSize,
+		MaxFile:  defaultCacheMaxFile,
+		Compress: defaultCacheCompress,
+	}
+	if v, ok := logOptMap[CacheMaxSize]; ok {
+		cacheOpts[MaxSize] = v
+	}
+	if v, ok := logOptMap[CacheMaxFile]; ok {
+		cacheOpts[MaxFile] = v
+	}
+	if v, ok := logOptMap[CacheCompress]; ok {
+		cacheOpts[Compress] = v
+	}
+	return &DualLogger{Driver: driver, cache: &JSONLogger{Opts: cacheOpts}}
+}
+
+// DualLogger writes the logs to a local cache in the json-file format alongside the wrapped driver,
+// so that `nerdctl logs` can read the logs of the drivers that do not support reading, like Docker does.
+type DualLogger struct {
+	Driver
+	cache *JSONLogger
+}
+
+func (d *DualLogger) Init(dataStore, ns, id string) error {
+	if err := d.Driver.Init(dataStore, ns, id); err != nil {
+		return err
+	}
+	d.cache.Opts[LogPath] = CachePath(dataStore, ns, id)
+	return d.cache.Init(dataStore, ns, id)
+}
+
+func (d *DualLogger) PreProcess(ctx context.Context, dataStore string, config *logging.Config) error {
+	if err := d.Driver.PreProcess(ctx, dataStore, config); err != nil {
+		return err
+	}
+	d.cache.Opts[LogPath] = CachePath(dataStore, config.Namespace, config.ID)
+	return d.cache.PreProcess(ctx, dataStore, config)
+}
+
+// Process writes each line to the cache, then hands it to the wrapped driver.
+func (d *DualLogger) Process(stdout <-chan string, stderr <-chan string) error {
+	driverStdout := make(chan string, cap(stdout))
+	driverStderr := make(chan string, cap(stderr))
+	processed := make(chan error, 1)
+	go func() {
+		processed <- d.Driver.Process(driverStdout, driverStderr)
+	}()
+
+	var wg sync.WaitGroup
+	tee := func(stream string, dataChan <-chan string, driverChan chan<- string) {
+		defer wg.Done()
+		defer close(driverChan)
+		for line := range dataChan {
+			if err := d.cache.WriteLogEntry(stream, line); err != nil {
+				log.L.WithError(err).Error("failed to write the log cache")
+			}
+			driverChan <- line
+		}
+	}
+	wg.Add(2)
+	go tee(streamStdout, stdout, driverStdout)
+	go tee(streamStderr, stderr, driverStderr)
+	wg.Wait()
+	return <-processed
+}
+
+func (d *DualLogger) PostProcess() error {
+	return errors.Join(d.Driver.PostProcess(), d.cache.PostProcess())
+}
+
+// viewLogsCache reads the local cache of the logs of a driver that does not support reading.
+func viewLogsCache(lvopts LogViewOptions, stdout, stderr io.Writer, stopChannel chan os.Signal) error {
+	cachePath := CachePath(lvopts.DatastoreRootPath, lvopts.Namespace, lvopts.ContainerID)
+	if _, err := os.Stat(cachePath); err != nil {
+		return fmt.Errorf("failed to stat the log cache: %w", err)
+	}
+	return viewLogsJSONFileDirect(lvopts, cachePath, stdout, stderr, stopChannel)
+}
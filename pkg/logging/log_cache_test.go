@@ -0,0 +1,76 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+)
+
+func TestUsesLogCache(t *testing.T) {
+	assert.Assert(t, usesLogCache("fluentd", map[string]string{}))
+	assert.Assert(t, usesLogCache("syslog", map[string]string{CacheDisabled: "false"}))
+	assert.Assert(t, !usesLogCache("syslog", map[string]string{CacheDisabled: "true"}))
+	assert.Assert(t, !usesLogCache("json-file", map[string]string{}))
+	assert.Assert(t, !usesLogCache("journald", map[string]string{}))
+	assert.Assert(t, !usesLogCache("none", map[string]string{}))
+
+	assert.NilError(t, ValidateLogOpts("syslog", map[string]string{CacheMaxSize: "1m", CacheMaxFile: "2"}))
+	assert.ErrorContains(t, ValidateLogOpts("syslog", map[string]string{CacheDisabled: "maybe"}), CacheDisabled)
+	assert.ErrorContains(t, ValidateLogOpts("syslog", map[string]string{CacheMaxSize: "-1"}), CacheMaxSize)
+	assert.ErrorContains(t, ValidateLogOpts("syslog", map[string]string{CacheMaxFile: "0"}), CacheMaxFile)
+}
+
+func TestDualLogger(t *testing.T) {
+	const (
+		ns = "testing"
+		id = "dual"
+	)
+	dataStore := t.TempDir()
+	inner := &MockDriver{}
+	driver := withLogCache("test-remote", inner, map[string]string{})
+	dual, ok := driver.(*DualLogger)
+	assert.Assert(t, ok)
+
+	assert.NilError(t, dual.Init(dataStore, ns, id))
+	assert.NilError(t, dual.PreProcess(context.Background(), dataStore, &logging.Config{Namespace: ns, ID: id}))
+
+	stdout := make(chan string, 10)
+	stderr := make(chan string, 10)
+	stdout <- "hello\n"
+	stderr <- "world\n"
+	close(stdout)
+	close(stderr)
+	assert.NilError(t, dual.Process(stdout, stderr))
+	assert.NilError(t, dual.PostProcess())
+
+	// the wrapped driver received the logs
+	assert.DeepEqual(t, inner.receivedStdout, []string{"hello\n"})
+	assert.DeepEqual(t, inner.receivedStderr, []string{"world\n"})
+
+	// and they can be read from the cache
+	var viewedStdout, viewedStderr bytes.Buffer
+	lvopts := LogViewOptions{ContainerID: id, Namespace: ns, DatastoreRootPath: dataStore}
+	assert.NilError(t, viewLogsCache(lvopts, &viewedStdout, &viewedStderr, nil))
+	assert.Equal(t, viewedStdout.String(), "hello\n")
+	assert.Equal(t, viewedStderr.String(), "world\n")
+}
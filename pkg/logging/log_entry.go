@@ -0,0 +1,124 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+// LogEntry is a single line of the logs of a container.
+type LogEntry struct {
+	// Stream is either "stdout" or "stderr".
+	Stream string `json:"stream"`
+	// Time is the time the line was logged at. For logging drivers which do not
+	// support timestamps (journald), it is the time the line was read at.
+	Time time.Time `json:"time"`
+	// Log is the content of the line, without the trailing newline.
+	Log string `json:"log"`
+}
+
+// Prints all logs for this LogViewer's container as LogEntry values to fn, so
+// that they can be merged with the logs of other containers or re-formatted.
+// The `Timestamps` option is ignored, as every LogEntry carries its time.
+// Calls to fn are serialized.
+func (lv *ContainerLogViewer) PrintLogEntriesTo(fn func(LogEntry)) error {
+	lvopts := lv.logViewingOptions
+	// journald does not support the option and would only warn about it.
+	lvopts.Timestamps = lv.loggingConfig.Driver != "journald"
+
+	var prefix string
+	if lvopts.Details && lvopts.DetailPrefix != nil {
+		prefix = *lvopts.DetailPrefix + " "
+	}
+
+	var mu sync.Mutex
+	emit := func(e LogEntry) {
+		mu.Lock()
+		defer mu.Unlock()
+		fn(e)
+	}
+	stdout := &entryWriter{stream: "stdout", timestamps: lvopts.Timestamps, prefix: prefix, emit: emit}
+	stderr := &entryWriter{stream: "stderr", timestamps: lvopts.Timestamps, prefix: prefix, emit: emit}
+
+	viewerFunc, err := lv.viewerFunc()
+	if err != nil {
+		return err
+	}
+	err = viewerFunc(lvopts, stdout, stderr, lv.stopChannel)
+	stdout.flush()
+	stderr.flush()
+	return err
+}
+
+// entryWriter splits the output of a LogViewerFunc into LogEntry values.
+//
+// When timestamps is set, the viewer prefixes each entry it writes with an
+// RFC3339 timestamp and a space, which is parsed off into LogEntry.Time.
+// Writes without such a prefix continue the current entry (e.g. partial lines
+// of the cri driver).
+type entryWriter struct {
+	stream     string
+	timestamps bool
+	prefix     string
+	emit       func(LogEntry)
+
+	time time.Time
+	buf  []byte
+}
+
+func (ew *entryWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if ew.timestamps {
+		if ts, rest, ok := bytes.Cut(p, []byte(" ")); ok {
+			if t, err := time.Parse(time.RFC3339Nano, string(ts)); err == nil {
+				ew.time = t
+				p = rest
+			}
+		}
+	}
+	ew.buf = append(ew.buf, p...)
+	for {
+		i := bytes.IndexByte(ew.buf, '\n')
+		if i < 0 {
+			break
+		}
+		ew.emitLine(ew.buf[:i])
+		ew.buf = ew.buf[i+1:]
+	}
+	return n, nil
+}
+
+func (ew *entryWriter) flush() {
+	if len(ew.buf) > 0 {
+		ew.emitLine(ew.buf)
+		ew.buf = nil
+	}
+}
+
+func (ew *entryWriter) emitLine(line []byte) {
+	t := ew.time
+	if !ew.timestamps || t.IsZero() {
+		t = time.Now()
+	}
+	ew.emit(LogEntry{
+		Stream: ew.stream,
+		Time:   t,
+		Log:    ew.prefix + string(line),
+	})
+}
@@ -0,0 +1,80 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestEntryWriter(t *testing.T) {
+	var entries []LogEntry
+	ew := &entryWriter{
+		stream:     "stdout",
+		timestamps: true,
+		prefix:     "ENV=foo ",
+		emit:       func(e LogEntry) { entries = append(entries, e) },
+	}
+
+	t1 := time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)
+	t2 := t1.Add(time.Second)
+	for _, s := range []string{
+		t1.Format(time.RFC3339Nano) + " first\n",
+		// A partial line, continued by a write without timestamp.
+		t2.Format(time.RFC3339Nano) + " sec",
+		"ond\nthird\n",
+		"last",
+	} {
+		n, err := ew.Write([]byte(s))
+		assert.NilError(t, err)
+		assert.Equal(t, n, len(s))
+	}
+	ew.flush()
+
+	assert.DeepEqual(t, entries, []LogEntry{
+		{Stream: "stdout", Time: t1, Log: "ENV=foo first"},
+		{Stream: "stdout", Time: t2, Log: "ENV=foo second"},
+		{Stream: "stdout", Time: t2, Log: "ENV=foo third"},
+		{Stream: "stdout", Time: t2, Log: "ENV=foo last"},
+	})
+}
+
+func TestEntryWriterWithoutTimestamps(t *testing.T) {
+	var entries []LogEntry
+	ew := &entryWriter{
+		stream: "stderr",
+		emit:   func(e LogEntry) { entries = append(entries, e) },
+	}
+
+	before := time.Now()
+	// Without timestamps, a leading RFC3339 timestamp is part of the line.
+	for _, s := range []string{"2026-01-02T03:04:05Z fo", "o\nbar\n"} {
+		_, err := ew.Write([]byte(s))
+		assert.NilError(t, err)
+	}
+	ew.flush()
+
+	assert.Equal(t, len(entries), 2)
+	assert.Equal(t, entries[0].Log, "2026-01-02T03:04:05Z foo")
+	assert.Equal(t, entries[1].Log, "bar")
+	for _, e := range entries {
+		assert.Equal(t, e.Stream, "stderr")
+		assert.Assert(t, !e.Time.Before(before))
+	}
+}
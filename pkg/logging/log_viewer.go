@@ -45,6 +45,7 @@ func RegisterLogViewer(driverName string, lvfn LogViewerFunc) {
 
 func init() {
 	RegisterLogViewer("json-file", viewLogsJSONFile)
+	RegisterLogViewer("local", viewLogsLocal)
 	RegisterLogViewer("journald", viewLogsJournald)
 	RegisterLogViewer("cri", viewLogsCRI)
 }
@@ -164,7 +165,7 @@ func (lv *ContainerLogViewer) PrintLogsTo(stdout, stderr io.Writer) error {
 		}
 
 	}
-	viewerFunc, err := getLogViewer(lv.loggingConfig.Driver)
+	viewerFunc, err := lv.viewerFunc()
 	if err != nil {
 		return err
 	}
@@ -172,6 +173,19 @@ func (lv *ContainerLogViewer) PrintLogsTo(stdout, stderr io.Writer) error {
 	return viewerFunc(lv.logViewingOptions, stdout, stderr, lv.stopChannel)
 }
 
+// Returns the LogViewerFunc for this LogViewer's logging driver, falling back
+// to the log cache for drivers without a viewer of their own.
+func (lv *ContainerLogViewer) viewerFunc() (LogViewerFunc, error) {
+	viewerFunc, err := getLogViewer(lv.loggingConfig.Driver)
+	if err != nil {
+		if !usesLogCache(lv.loggingConfig.Driver, lv.loggingConfig.Opts) {
+			return nil, fmt.Errorf("%w, and its log cache is disabled (%s=true)", err, CacheDisabled)
+		}
+		viewerFunc = viewLogsCache
+	}
+	return viewerFunc, nil
+}
+
 // Convenience wrapper for exec.LookPath.
 func checkExecutableAvailableInPath(executable string) bool {
 	_, err := exec.LookPath(executable)
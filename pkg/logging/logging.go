@@ -26,6 +26,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -39,7 +40,9 @@ import (
 	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
 
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
 	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
 const (
@@ -48,6 +51,7 @@ const (
 	LogPath    = "log-path"
 	MaxSize    = "max-size"
 	MaxFile    = "max-file"
+	Compress   = "compress"
 	Tag        = "tag"
 	Env        = "env"
 	Labels     = "labels"
@@ -88,8 +92,21 @@ var drivers = make(map[string]DriverFactory)
 var driversLogOptsValidateFunctions = make(map[string]LogOptsValidateFunc)
 
 func ValidateLogOpts(logDriver string, logOpts map[string]string) error {
+	if err := validateDeliveryLogOpts(logOpts); err != nil {
+		return err
+	}
+	if err := validateCacheLogOpts(logDriver, logOpts); err != nil {
+		return err
+	}
 	if value, ok := driversLogOptsValidateFunctions[logDriver]; ok && value != nil {
-		return value(logOpts)
+		// the delivery and cache log-opts apply to all the drivers, do not let the driver warn about them
+		driverLogOpts := make(map[string]string, len(logOpts))
+		for k, v := range logOpts {
+			if !strutil.InStringSlice(deliveryLogOpts, k) && !strutil.InStringSlice(cacheLogOpts, k) {
+				driverLogOpts[k] = v
+			}
+		}
+		return value(driverLogOpts)
 	}
 	return nil
 }
@@ -113,7 +130,11 @@ func GetDriver(name string, opts map[string]string, address string) (Driver, err
 	if !ok {
 		return nil, fmt.Errorf("unknown logging driver %q: %w", name, errdefs.ErrNotFound)
 	}
-	return driverFactory(opts, address)
+	driver, err := driverFactory(opts, address)
+	if err != nil {
+		return nil, err
+	}
+	return withLogCache(name, driver, opts), nil
 }
 
 func init() {
@@ -123,6 +144,9 @@ func init() {
 	RegisterDriver("json-file", func(opts map[string]string, address string) (Driver, error) {
 		return &JSONLogger{Opts: opts}, nil
 	}, JSONFileLogOptsValidate)
+	RegisterDriver("local", func(opts map[string]string, address string) (Driver, error) {
+		return &LocalLogger{Opts: opts}, nil
+	}, LocalLogOptsValidate)
 	RegisterDriver("journald", func(opts map[string]string, address string) (Driver, error) {
 		return &JournaldLogger{Opts: opts, Address: address}, nil
 	}, JournalLogOptsValidate)
@@ -382,9 +406,20 @@ func loggingProcessAdapter(ctx context.Context, driver Driver, dataStore, addres
 			log.G(ctx).Errorf("failed to get container task wait channel: %v", err)
 			return
 		}
-		<-exitCh
+		status := <-exitCh
 		stdoutR.Cancel()
 		stderrR.Cancel()
+		// alreadyExited does not know the exit time, nor the exit code
+		if !status.ExitTime().IsZero() {
+			eventutil.Record(ctx, dataStore, eventutil.JournalEvent{
+				Timestamp:  status.ExitTime(),
+				Namespace:  config.Namespace,
+				Type:       eventutil.TypeContainer,
+				Action:     "die",
+				ID:         config.ID,
+				Attributes: map[string]string{"exitCode": strconv.FormatUint(uint64(status.ExitCode()), 10)},
+			})
+		}
 	}()
 	wg.Wait()
 	return driver.PostProcess()
@@ -408,6 +443,10 @@ func loggerFunc(dataStore string) (logging.LoggerFunc, error) {
 			if err != nil {
 				return err
 			}
+			driver, err = withDeliveryMode(driver, logConfig.Opts)
+			if err != nil {
+				return err
+			}
 
 			loggerLock := getLockPath(dataStore, config.Namespace, config.ID)
 
@@ -0,0 +1,305 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	units "github.com/docker/go-units"
+
+	"github.com/containerd/log"
+)
+
+const (
+	// Mode is the delivery mode of the log messages, "blocking" (default) or "non-blocking".
+	Mode = "mode"
+	// MaxBufferSize is the size of the ring buffer of the non-blocking mode.
+	MaxBufferSize = "max-buffer-size"
+
+	modeBlocking    = "blocking"
+	modeNonBlocking = "non-blocking"
+
+	defaultMaxBufferSize = 1024 * 1024
+)
+
+// deliveryLogOpts are the log-opts that apply to all the drivers.
+var deliveryLogOpts = []string{
+	Mode,
+	MaxBufferSize,
+}
+
+// validateDeliveryLogOpts validates the mode and max-buffer-size log-opts.
+func validateDeliveryLogOpts(logOptMap map[string]string) error {
+	switch mode := logOptMap[Mode]; mode {
+	case "", modeBlocking:
+		if _, ok := logOptMap[MaxBufferSize]; ok {
+			return fmt.Errorf("log-opt %s is only supported with %s=%s", MaxBufferSize, Mode, modeNonBlocking)
+		}
+	case modeNonBlocking:
+		if _, err := parseMaxBufferSize(logOptMap); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown log-opt %s %q, must be %q or %q", Mode, mode, modeBlocking, modeNonBlocking)
+	}
+	return nil
+}
+
+func parseMaxBufferSize(logOptMap map[string]string) (int64, error) {
+	s, ok := logOptMap[MaxBufferSize]
+	if !ok {
+		return defaultMaxBufferSize, nil
+	}
+	size, err := units.RAMInBytes(s)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse log-opt %s %q: %w", MaxBufferSize, s, err)
+	}
+	if size <= 0 {
+		return 0, fmt.Errorf("log-opt %s must be positive, got %q", MaxBufferSize, s)
+	}
+	return size, nil
+}
+
+// withDeliveryMode wraps the driver in a NonBlockingLogger when the non-blocking mode is requested.
+func withDeliveryMode(driver Driver, logOptMap map[string]string) (Driver, error) {
+	if logOptMap[Mode] != modeNonBlocking {
+		return driver, nil
+	}
+	maxSize, err := parseMaxBufferSize(logOptMap)
+	if err != nil {
+		return nil, err
+	}
+	nb := &NonBlockingLogger{Driver: driver, MaxBufferSize: maxSize}
+	if _, ok := driver.(SyncDriver); ok {
+		return &nonBlockingSyncLogger{nb}, nil
+	}
+	return nb, nil
+}
+
+// droppedReportInterval is the interval between the reports of the dropped messages.
+const droppedReportInterval = 30 * time.Second
+
+// NonBlockingLogger decouples the container output from a driver through a ring buffer,
+// so that a slow driver never blocks the container.
+// When the buffer is full, the oldest messages are dropped.
+//
+// The messages are delivered to the wrapped driver in the background, and the
+// remaining ones are delivered by PostProcess.
+type NonBlockingLogger struct {
+	Driver
+	MaxBufferSize int64
+
+	startOnce sync.Once
+	ring      *ringBuffer
+	delivered chan error
+	stop      chan struct{}
+	reported  chan struct{}
+}
+
+// start starts the delivery of the messages to the wrapped driver, and the periodic
+// reports of the dropped messages.
+func (nb *NonBlockingLogger) start() {
+	nb.startOnce.Do(func() {
+		nb.ring = newRingBuffer(nb.MaxBufferSize)
+		nb.delivered = make(chan error, 1)
+		go func() {
+			nb.delivered <- nb.deliver()
+		}()
+		nb.stop = make(chan struct{})
+		nb.reported = make(chan struct{})
+		go func() {
+			defer close(nb.reported)
+			ticker := time.NewTicker(droppedReportInterval)
+			defer ticker.Stop()
+			var reported uint64
+			for {
+				select {
+				case <-ticker.C:
+					reported = nb.reportDropped(reported)
+				case <-nb.stop:
+					nb.reportDropped(reported)
+					return
+				}
+			}
+		}()
+	})
+}
+
+// reportDropped logs the messages dropped since the previous report, and returns the total.
+func (nb *NonBlockingLogger) reportDropped(reported uint64) uint64 {
+	dropped := nb.ring.droppedCount()
+	if dropped > reported {
+		log.L.Warnf("dropped %d log messages: the log driver could not keep up (%s=%d)", dropped-reported, MaxBufferSize, nb.MaxBufferSize)
+	}
+	return dropped
+}
+
+// Process buffers the messages, until stdout and stderr are closed.
+func (nb *NonBlockingLogger) Process(stdout <-chan string, stderr <-chan string) error {
+	nb.start()
+	var wg sync.WaitGroup
+	enqueue := func(stream string, dataChan <-chan string) {
+		defer wg.Done()
+		for line := range dataChan {
+			nb.ring.enqueue(logEntry{stream: stream, line: line})
+		}
+	}
+	wg.Add(2)
+	go enqueue(streamStdout, stdout)
+	go enqueue(streamStderr, stderr)
+	wg.Wait()
+	return nil
+}
+
+// deliver dequeues the messages until the ring buffer is closed and drained.
+func (nb *NonBlockingLogger) deliver() error {
+	if syncDriver, ok := nb.Driver.(SyncDriver); ok {
+		var errs []error
+		for {
+			entry, ok := nb.ring.dequeue()
+			if !ok {
+				return errors.Join(errs...)
+			}
+			if err := syncDriver.WriteLogEntry(entry.stream, entry.line); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	stdout := make(chan string)
+	stderr := make(chan string)
+	processed := make(chan error, 1)
+	go func() {
+		processed <- nb.Driver.Process(stdout, stderr)
+	}()
+	for {
+		entry, ok := nb.ring.dequeue()
+		if !ok {
+			break
+		}
+		if entry.stream == streamStdout {
+			stdout <- entry.line
+		} else {
+			stderr <- entry.line
+		}
+	}
+	close(stdout)
+	close(stderr)
+	return <-processed
+}
+
+// PostProcess delivers the remaining messages, reports the dropped messages,
+// and post-processes the wrapped driver.
+func (nb *NonBlockingLogger) PostProcess() error {
+	var errs []error
+	if nb.ring != nil {
+		nb.ring.close()
+		errs = append(errs, <-nb.delivered)
+		close(nb.stop)
+		<-nb.reported
+	}
+	errs = append(errs, nb.Driver.PostProcess())
+	return errors.Join(errs...)
+}
+
+// nonBlockingSyncLogger is the NonBlockingLogger of a SyncDriver.
+// It is a SyncDriver itself, so that the container output is buffered as soon as it is read.
+type nonBlockingSyncLogger struct {
+	*NonBlockingLogger
+}
+
+// WriteLogEntry buffers the message, implementing SyncDriver.
+func (nb *nonBlockingSyncLogger) WriteLogEntry(stream, line string) error {
+	nb.start()
+	nb.ring.enqueue(logEntry{stream: stream, line: line})
+	return nil
+}
+
+type logEntry struct {
+	stream string
+	line   string
+}
+
+// ringBuffer is a FIFO of log entries bounded by the total size of the lines.
+// Enqueuing never blocks: the oldest entries are dropped to make room for the new one.
+type ringBuffer struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queue   []logEntry
+	size    int64
+	maxSize int64
+	dropped uint64
+	closed  bool
+}
+
+func newRingBuffer(maxSize int64) *ringBuffer {
+	r := &ringBuffer{maxSize: maxSize}
+	r.cond = sync.NewCond(&r.mu)
+	return r
+}
+
+func (r *ringBuffer) enqueue(entry logEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	entrySize := int64(len(entry.line))
+	// an entry larger than the buffer is kept alone, rather than being dropped
+	for len(r.queue) > 0 && r.size+entrySize > r.maxSize {
+		r.size -= int64(len(r.queue[0].line))
+		r.queue[0] = logEntry{}
+		r.queue = r.queue[1:]
+		r.dropped++
+	}
+	r.queue = append(r.queue, entry)
+	r.size += entrySize
+	r.cond.Signal()
+}
+
+// dequeue blocks until an entry is available, and returns false once the buffer is closed and empty.
+func (r *ringBuffer) dequeue() (logEntry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for len(r.queue) == 0 && !r.closed {
+		r.cond.Wait()
+	}
+	if len(r.queue) == 0 {
+		return logEntry{}, false
+	}
+	entry := r.queue[0]
+	r.queue[0] = logEntry{}
+	r.queue = r.queue[1:]
+	r.size -= int64(len(entry.line))
+	return entry, true
+}
+
+func (r *ringBuffer) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	r.cond.Broadcast()
+}
+
+func (r *ringBuffer) droppedCount() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.dropped
+}
@@ -0,0 +1,142 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestValidateDeliveryLogOpts(t *testing.T) {
+	for _, tc := range []struct {
+		opts  map[string]string
+		valid bool
+	}{
+		{opts: map[string]string{}, valid: true},
+		{opts: map[string]string{Mode: "blocking"}, valid: true},
+		{opts: map[string]string{Mode: "non-blocking"}, valid: true},
+		{opts: map[string]string{Mode: "non-blocking", MaxBufferSize: "4m"}, valid: true},
+		{opts: map[string]string{Mode: "non-blocking", MaxBufferSize: "0"}, valid: false},
+		{opts: map[string]string{Mode: "non-blocking", MaxBufferSize: "foo"}, valid: false},
+		{opts: map[string]string{MaxBufferSize: "4m"}, valid: false},
+		{opts: map[string]string{Mode: "async"}, valid: false},
+	} {
+		err := ValidateLogOpts("json-file", tc.opts)
+		if tc.valid {
+			assert.NilError(t, err, "%v", tc.opts)
+		} else {
+			assert.Assert(t, err != nil, "%v", tc.opts)
+		}
+	}
+}
+
+func TestRingBufferDropsOldest(t *testing.T) {
+	r := newRingBuffer(10)
+	for i := 0; i < 5; i++ {
+		r.enqueue(logEntry{stream: streamStdout, line: fmt.Sprintf("%d\n", i)})
+	}
+	// 5 entries of 2 bytes fill the buffer, the next ones replace the oldest
+	r.enqueue(logEntry{stream: streamStderr, line: "5\n"})
+	r.enqueue(logEntry{stream: streamStdout, line: "6\n"})
+	assert.Equal(t, r.droppedCount(), uint64(2))
+	r.close()
+
+	var lines []string
+	for {
+		entry, ok := r.dequeue()
+		if !ok {
+			break
+		}
+		lines = append(lines, entry.line)
+	}
+	assert.DeepEqual(t, lines, []string{"2\n", "3\n", "4\n", "5\n", "6\n"})
+}
+
+// blockedDriver does not consume its messages until it is released.
+type blockedDriver struct {
+	MockDriver
+	release chan struct{}
+}
+
+func (b *blockedDriver) Process(stdout <-chan string, stderr <-chan string) error {
+	<-b.release
+	return b.MockDriver.Process(stdout, stderr)
+}
+
+func TestNonBlockingLogger(t *testing.T) {
+	inner := &blockedDriver{release: make(chan struct{})}
+	driver, err := withDeliveryMode(inner, map[string]string{Mode: modeNonBlocking, MaxBufferSize: "10"})
+	assert.NilError(t, err)
+
+	stdout := make(chan string)
+	stderr := make(chan string)
+	processed := make(chan error, 1)
+	go func() {
+		processed <- driver.Process(stdout, stderr)
+	}()
+
+	// The messages are accepted although the driver is blocked
+	sent := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			stdout <- fmt.Sprintf("%02d\n", i)
+		}
+		close(stdout)
+		close(stderr)
+		close(sent)
+	}()
+	select {
+	case <-sent:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the non-blocking logger blocked the container output")
+	}
+
+	close(inner.release)
+	assert.NilError(t, <-processed)
+	assert.NilError(t, driver.PostProcess())
+
+	// The oldest messages were dropped, the last ones are kept
+	received := inner.receivedStdout
+	dropped := driver.(*NonBlockingLogger).ring.droppedCount()
+	assert.Assert(t, dropped > 0)
+	assert.Equal(t, len(received)+int(dropped), 100)
+	assert.DeepEqual(t, received[len(received)-3:], []string{"97\n", "98\n", "99\n"})
+}
+
+func TestNonBlockingLoggerSyncDriver(t *testing.T) {
+	inner := &SyncMockDriver{}
+	driver, err := withDeliveryMode(inner, map[string]string{Mode: modeNonBlocking})
+	assert.NilError(t, err)
+	// The synchronous path of the wrapped driver is kept
+	syncDriver, ok := driver.(SyncDriver)
+	assert.Assert(t, ok)
+
+	assert.NilError(t, syncDriver.WriteLogEntry(streamStdout, "foo\n"))
+	assert.NilError(t, syncDriver.WriteLogEntry(streamStderr, "bar"))
+	assert.NilError(t, driver.PostProcess())
+	assert.DeepEqual(t, inner.receivedStdout, []string{"foo\n"})
+	assert.DeepEqual(t, inner.receivedStderr, []string{"bar"})
+
+	// The other drivers are not made synchronous
+	driver, err = withDeliveryMode(&MockDriver{}, map[string]string{Mode: modeNonBlocking})
+	assert.NilError(t, err)
+	_, ok = driver.(SyncDriver)
+	assert.Assert(t, !ok)
+}
@@ -0,0 +1,170 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/containerd/nerdctl/v2/pkg/logging/jsonfile"
+	"github.com/containerd/nerdctl/v2/pkg/logging/tail"
+)
+
+// compressedSuffix is the suffix of the rotated log files compressed by go-logrotate.
+const compressedSuffix = ".gz"
+
+// rotatedLogFiles returns the paths of the rotated segments of the log file, oldest first.
+// The segments are named "<log file>.<N>", or "<log file>.<N>.gz" when compressed (see go-logrotate).
+func rotatedLogFiles(logFilePath string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Dir(logFilePath))
+	if err != nil {
+		return nil, err
+	}
+	type segment struct {
+		path    string
+		order   int
+		modTime int64
+	}
+	var segments []segment
+	prefix := filepath.Base(logFilePath) + "."
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		order, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, prefix), compressedSuffix))
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// removed by the rotation meanwhile
+			continue
+		}
+		segments = append(segments, segment{
+			path:    filepath.Join(filepath.Dir(logFilePath), name),
+			order:   order,
+			modTime: info.ModTime().UnixNano(),
+		})
+	}
+	// The order restarts from 1 when the logger restarts, so the modification time prevails
+	sort.Slice(segments, func(i, j int) bool {
+		if segments[i].modTime != segments[j].modTime {
+			return segments[i].modTime < segments[j].modTime
+		}
+		return segments[i].order < segments[j].order
+	})
+	paths := make([]string, len(segments))
+	for i, s := range segments {
+		paths[i] = s.path
+	}
+	return paths, nil
+}
+
+// readRotatedLogFile returns the content of a rotated segment, decompressing it if needed.
+func readRotatedLogFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if !strings.HasSuffix(path, compressedSuffix) {
+		return io.ReadAll(f)
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// countTailLines returns the number of lines of the file, up to n.
+func countTailLines(f io.ReadSeeker, n uint) (uint, error) {
+	start, err := tail.FindTailLineStartIndex(f, n)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		return 0, err
+	}
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return 0, err
+	}
+	return uint(bytes.Count(content, []byte{'\n'})), nil
+}
+
+// viewRotatedJSONLogFiles writes the entries of the rotated segments of the json-file log file
+// that precede the entries shown from the current log file.
+func viewRotatedJSONLogFiles(lvopts LogViewOptions, jsonLogFilePath string, stdout, stderr io.Writer) error {
+	segments, err := rotatedLogFiles(jsonLogFilePath)
+	if err != nil || len(segments) == 0 {
+		return err
+	}
+	remaining := lvopts.Tail
+	if remaining > 0 {
+		f, err := os.Open(jsonLogFilePath)
+		if err != nil {
+			return err
+		}
+		n, err := countTailLines(f, remaining)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		if remaining -= n; remaining == 0 {
+			return nil
+		}
+	}
+	var contents [][]byte
+	for i := len(segments) - 1; i >= 0; i-- {
+		content, err := readRotatedLogFile(segments[i])
+		if err != nil {
+			if os.IsNotExist(err) {
+				// removed by the rotation meanwhile
+				continue
+			}
+			return err
+		}
+		if lvopts.Tail > 0 {
+			r := bytes.NewReader(content)
+			start, err := tail.FindTailLineStartIndex(r, remaining)
+			if err != nil {
+				return err
+			}
+			content = content[start:]
+			remaining -= min(remaining, uint(bytes.Count(content, []byte{'\n'})))
+		}
+		contents = append([][]byte{content}, contents...)
+		if lvopts.Tail > 0 && remaining == 0 {
+			break
+		}
+	}
+	for _, content := range contents {
+		if _, err := jsonfile.Decode(stdout, stderr, bytes.NewReader(content), lvopts.Timestamps, lvopts.Since, lvopts.Until); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fahedouch/go-logrotate"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/logging/jsonfile"
+)
+
+// writeRotatedJSONLog writes the lines to the json-file log file, rotating it every 3 lines,
+// and waits for the rotated segments to be compressed.
+func writeRotatedJSONLog(t *testing.T, logFilePath string, lines int) {
+	l := &logrotate.Logger{
+		Filename: logFilePath,
+		MaxBytes: 1 << 20,
+		Compress: true,
+	}
+	defer l.Close()
+	for i := 0; i < lines; i++ {
+		stdout := make(chan string, 1)
+		stderr := make(chan string)
+		stdout <- fmt.Sprintf("line%d\n", i)
+		close(stdout)
+		close(stderr)
+		assert.NilError(t, jsonfile.Encode(stdout, stderr, l))
+		if i%3 == 2 {
+			assert.NilError(t, l.Rotate())
+			waitCompressed(t, logFilePath)
+		}
+	}
+}
+
+func waitCompressed(t *testing.T, logFilePath string) {
+	for i := 0; i < 100; i++ {
+		segments, err := rotatedLogFiles(logFilePath)
+		assert.NilError(t, err)
+		compressed := true
+		for _, s := range segments {
+			compressed = compressed && strings.HasSuffix(s, compressedSuffix)
+		}
+		if compressed {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("the rotated log files were not compressed")
+}
+
+func TestViewCompressedRotatedJSONLog(t *testing.T) {
+	logFilePath := filepath.Join(t.TempDir(), "container-json.log")
+	writeRotatedJSONLog(t, logFilePath, 10)
+
+	segments, err := rotatedLogFiles(logFilePath)
+	assert.NilError(t, err)
+	assert.Equal(t, len(segments), 3)
+
+	testCases := []struct {
+		tail     uint
+		expected string
+	}{
+		{0, "line0\nline1\nline2\nline3\nline4\nline5\nline6\nline7\nline8\nline9\n"},
+		{1, "line9\n"},
+		{2, "line8\nline9\n"},
+		{5, "line5\nline6\nline7\nline8\nline9\n"},
+		{20, "line0\nline1\nline2\nline3\nline4\nline5\nline6\nline7\nline8\nline9\n"},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("tail %d", tc.tail), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			lvopts := LogViewOptions{Tail: tc.tail}
+			assert.NilError(t, viewLogsJSONFileDirect(lvopts, logFilePath, &stdout, &stderr, make(chan os.Signal)))
+			assert.Equal(t, stdout.String(), tc.expected)
+			assert.Equal(t, stderr.Len(), 0)
+		})
+	}
+}
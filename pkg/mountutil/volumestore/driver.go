@@ -0,0 +1,93 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volumestore
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/containerd/errdefs"
+)
+
+// DefaultDriver is the name of the driver used when none is specified.
+const DefaultDriver = "local"
+
+// Driver is the interface implemented by volume drivers.
+// A driver is instantiated with the options of a given volume (`nerdctl volume create --opt`).
+type Driver interface {
+	// Mount makes the volume content available at mountpoint.
+	// It is called when the first container using the volume starts.
+	Mount(mountpoint string) error
+	// Unmount reverts Mount. It is called when the last container using the volume stops,
+	// and before the volume is removed. It must not fail if the volume is not mounted.
+	Unmount(mountpoint string) error
+	// NeedsMount returns false if the volume is a plain directory, in which case
+	// Mount and Unmount are never called.
+	NeedsMount() bool
+}
+
+type DriverFactory func(opts map[string]string) (Driver, error)
+type DriverOptsValidateFunc func(opts map[string]string) error
+
+var drivers = make(map[string]DriverFactory)
+var driversOptsValidateFunctions = make(map[string]DriverOptsValidateFunc)
+
+// RegisterDriver registers a volume driver, similarly to logging.RegisterDriver.
+func RegisterDriver(name string, f DriverFactory, validateFunc DriverOptsValidateFunc) {
+	drivers[name] = f
+	driversOptsValidateFunctions[name] = validateFunc
+}
+
+func Drivers() []string {
+	var ss []string // nolint: prealloc
+	for f := range drivers {
+		ss = append(ss, f)
+	}
+	sort.Strings(ss)
+	return ss
+}
+
+// ValidateDriverOpts checks that the driver exists, and that it accepts the options.
+func ValidateDriverOpts(driver string, opts map[string]string) error {
+	if driver == "" {
+		driver = DefaultDriver
+	}
+	if _, ok := drivers[driver]; !ok {
+		return fmt.Errorf("unknown volume driver %q: %w", driver, errdefs.ErrNotFound)
+	}
+	if value, ok := driversOptsValidateFunctions[driver]; ok && value != nil {
+		return value(opts)
+	}
+	return nil
+}
+
+func GetDriver(name string, opts map[string]string) (Driver, error) {
+	if name == "" {
+		name = DefaultDriver
+	}
+	driverFactory, ok := drivers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown volume driver %q: %w", name, errdefs.ErrNotFound)
+	}
+	return driverFactory(opts)
+}
+
+func init() {
+	RegisterDriver(DefaultDriver, func(opts map[string]string) (Driver, error) {
+		return &LocalDriver{Opts: opts}, nil
+	}, LocalDriverOptsValidate)
+}
@@ -0,0 +1,70 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volumestore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/containerd/errdefs"
+)
+
+// Options of the local driver, compatible with `docker volume create --driver local`.
+// See https://docs.docker.com/reference/cli/docker/volume/create/#opt
+const (
+	LocalOptType   = "type"
+	LocalOptO      = "o"
+	LocalOptDevice = "device"
+)
+
+// LocalDriver stores volumes as directories under the data store.
+// When the "type" and "device" options are set, the device is mounted on the directory
+// while the volume is used by a container, e.g., for tmpfs or NFS volumes.
+type LocalDriver struct {
+	Opts map[string]string
+}
+
+func (d *LocalDriver) NeedsMount() bool {
+	return d.Opts[LocalOptType] != "" || d.Opts[LocalOptDevice] != ""
+}
+
+func LocalDriverOptsValidate(opts map[string]string) error {
+	for k := range opts {
+		switch k {
+		case LocalOptType, LocalOptO, LocalOptDevice:
+		default:
+			return fmt.Errorf("invalid option %q for the local volume driver: %w", k, errdefs.ErrInvalidArgument)
+		}
+	}
+	typ, device := opts[LocalOptType], opts[LocalOptDevice]
+	if typ == "" && device == "" {
+		if opts[LocalOptO] != "" {
+			return fmt.Errorf("option %q requires %q and %q: %w", LocalOptO, LocalOptType, LocalOptDevice, errdefs.ErrInvalidArgument)
+		}
+		return nil
+	}
+	if typ == "" {
+		return fmt.Errorf("missing required option %q: %w", LocalOptType, errdefs.ErrInvalidArgument)
+	}
+	if device == "" {
+		return fmt.Errorf("missing required option %q: %w", LocalOptDevice, errdefs.ErrInvalidArgument)
+	}
+	if typ == "none" && !strings.Contains(","+opts[LocalOptO]+",", ",bind,") && !strings.Contains(","+opts[LocalOptO]+",", ",rbind,") {
+		return fmt.Errorf("type \"none\" requires the \"bind\" or \"rbind\" option: %w", errdefs.ErrInvalidArgument)
+	}
+	return nil
+}
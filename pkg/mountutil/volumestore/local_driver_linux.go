@@ -0,0 +1,74 @@
+//go:build linux
+
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volumestore
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/containerd/containerd/v2/core/mount"
+)
+
+func (d *LocalDriver) Mount(mountpoint string) error {
+	if !d.NeedsMount() {
+		return nil
+	}
+	typ, device := d.Opts[LocalOptType], d.Opts[LocalOptDevice]
+	var options []string
+	if o := d.Opts[LocalOptO]; o != "" {
+		options = strings.Split(o, ",")
+	}
+	if typ == "nfs" || typ == "nfs4" {
+		// The kernel needs an IP address, not a hostname.
+		for i, opt := range options {
+			addr, ok := strings.CutPrefix(opt, "addr=")
+			if !ok {
+				continue
+			}
+			ipAddr, err := net.ResolveIPAddr("ip", addr)
+			if err != nil {
+				return fmt.Errorf("failed to resolve %q: %w", addr, err)
+			}
+			options[i] = "addr=" + ipAddr.String()
+		}
+	}
+	if typ == "none" {
+		// "none" is used for bind mounts, the actual type is conveyed by the "bind" option
+		typ = ""
+	}
+	m := mount.Mount{
+		Type:    typ,
+		Source:  device,
+		Options: options,
+	}
+	if err := m.Mount(mountpoint); err != nil {
+		return fmt.Errorf("failed to mount %q (type=%q) on %q: %w", device, d.Opts[LocalOptType], mountpoint, err)
+	}
+	return nil
+}
+
+func (d *LocalDriver) Unmount(mountpoint string) error {
+	if !d.NeedsMount() {
+		return nil
+	}
+	return mount.UnmountAll(mountpoint, unix.MNT_DETACH)
+}
@@ -0,0 +1,36 @@
+//go:build !linux
+
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volumestore
+
+import (
+	"fmt"
+
+	"github.com/containerd/errdefs"
+)
+
+func (d *LocalDriver) Mount(mountpoint string) error {
+	if !d.NeedsMount() {
+		return nil
+	}
+	return fmt.Errorf("mounting volumes with the \"type\" and \"device\" options: %w", errdefs.ErrNotImplemented)
+}
+
+func (d *LocalDriver) Unmount(mountpoint string) error {
+	return nil
+}
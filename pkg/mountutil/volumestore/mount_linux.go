@@ -0,0 +1,37 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volumestore
+
+import (
+	"path/filepath"
+
+	"github.com/containerd/containerd/v2/core/mount"
+)
+
+// isMountpoint returns whether something is mounted on dir.
+func isMountpoint(dir string) (bool, error) {
+	// mountinfo lists resolved paths
+	dir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return false, err
+	}
+	info, err := mount.Lookup(dir)
+	if err != nil {
+		return false, err
+	}
+	return info.Mountpoint == dir, nil
+}
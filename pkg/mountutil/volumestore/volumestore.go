@@ -24,6 +24,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
 
 	"github.com/containerd/log"
 
@@ -37,6 +38,7 @@ const (
 	volumeDirBasename  = "volumes"
 	dataDirName        = "_data"
 	volumeJSONFileName = "volume.json"
+	usersJSONFileName  = "users.json"
 )
 
 // ErrVolumeStore will wrap all errors here
@@ -50,7 +52,8 @@ type VolumeStore interface {
 	// Create will either return an existing volume, or create a new one
 	// NOTE that different labels will NOT create a new volume if there is one by that name already,
 	// but instead return the existing one with the (possibly different) labels
-	Create(name string, labels []string) (vol *native.Volume, err error)
+	// An empty driver means DefaultDriver.
+	Create(name string, labels []string, driver string, driverOpts map[string]string) (vol *native.Volume, err error)
 	// List returns all existing volumes.
 	// Note that list is expensive as it reads all volumes individual info
 	List(size bool) (map[string]native.Volume, error)
@@ -60,6 +63,11 @@ type VolumeStore interface {
 	Prune(filter func(volumes []*native.Volume) ([]string, error)) (err error)
 	// Count returns the number of volumes
 	Count() (count int, err error)
+	// Mount registers the container `id` as a user of the volume, and mounts the volume through its driver
+	// if this is the first user
+	Mount(name string, id string) error
+	// Unmount unregisters the container `id`, and unmounts the volume through its driver if there is no user left
+	Unmount(name string, id string) error
 
 	// Lock: see store implementation
 	Lock() error
@@ -158,10 +166,10 @@ func (vs *volumeStore) CreateWithoutLock(name string, labels []string) (vol *nat
 		return nil, err
 	}
 
-	return vs.rawCreate(name, labels)
+	return vs.rawCreate(name, labels, "", nil)
 }
 
-func (vs *volumeStore) Create(name string, labels []string) (vol *native.Volume, err error) {
+func (vs *volumeStore) Create(name string, labels []string, driver string, driverOpts map[string]string) (vol *native.Volume, err error) {
 	defer func() {
 		if err != nil {
 			err = errors.Join(ErrVolumeStore, err)
@@ -172,8 +180,12 @@ func (vs *volumeStore) Create(name string, labels []string) (vol *native.Volume,
 		return nil, err
 	}
 
+	if err = ValidateDriverOpts(driver, driverOpts); err != nil {
+		return nil, err
+	}
+
 	err = vs.Locker.WithLock(func() error {
-		vol, err = vs.rawCreate(name, labels)
+		vol, err = vs.rawCreate(name, labels, driver, driverOpts)
 		return err
 	})
 
@@ -263,7 +275,7 @@ func (vs *volumeStore) Remove(generator func() ([]string, []error, error)) (remo
 				// TODO: see above
 				warns = append(warns, fmt.Errorf("volume %q: %w", name, store.ErrNotFound))
 				continue
-			} else if err = vs.manager.Delete(name); err != nil {
+			} else if err = vs.rawDelete(name); err != nil {
 				return err
 			}
 
@@ -306,7 +318,7 @@ func (vs *volumeStore) Prune(filter func(vol []*native.Volume) ([]string, error)
 		}
 
 		for _, name := range toDelete {
-			err = vs.manager.Delete(name)
+			err = vs.rawDelete(name)
 			if err != nil {
 				return err
 			}
@@ -322,9 +334,12 @@ func (vs *volumeStore) rawGet(name string, size bool) (vol *native.Volume, err e
 		return nil, err
 	}
 
+	opts := volumeOpts(content)
 	vol = &native.Volume{
-		Name:   name,
-		Labels: labels(content),
+		Name:    name,
+		Driver:  opts.driver(),
+		Labels:  opts.Labels,
+		Options: opts.Options,
 	}
 
 	vol.Mountpoint, err = vs.manager.Location(name, dataDirName)
@@ -342,10 +357,15 @@ func (vs *volumeStore) rawGet(name string, size bool) (vol *native.Volume, err e
 	return vol, nil
 }
 
-func (vs *volumeStore) rawCreate(name string, labels []string) (vol *native.Volume, err error) {
+func (vs *volumeStore) rawCreate(name string, labels []string, driver string, driverOpts map[string]string) (vol *native.Volume, err error) {
 	volOpts := struct {
-		Labels map[string]string `json:"labels"`
-	}{}
+		Labels  map[string]string `json:"labels"`
+		Driver  string            `json:"driver,omitempty"`
+		Options map[string]string `json:"options,omitempty"`
+	}{
+		Driver:  driver,
+		Options: driverOpts,
+	}
 
 	if len(labels) > 0 {
 		volOpts.Labels = strutil.ConvertKVStringsToMap(labels)
@@ -370,7 +390,11 @@ func (vs *volumeStore) rawCreate(name string, labels []string) (vol *native.Volu
 
 	// At this point, we either have an existing volume, or created a new one successfully
 	vol = &native.Volume{
-		Name: name,
+		Name:   name,
+		Driver: volOpts.Driver,
+	}
+	if vol.Driver == "" {
+		vol.Driver = DefaultDriver
 	}
 
 	if err = vs.manager.GroupEnsure(name, dataDirName); err != nil {
@@ -384,14 +408,149 @@ func (vs *volumeStore) rawCreate(name string, labels []string) (vol *native.Volu
 	return vol, nil
 }
 
+func (vs *volumeStore) Mount(name string, id string) (err error) {
+	defer func() {
+		if err != nil {
+			err = errors.Join(ErrVolumeStore, err)
+		}
+	}()
+
+	return vs.Locker.WithLock(func() error {
+		driver, mountpoint, err := vs.rawDriver(name)
+		if err != nil || !driver.NeedsMount() {
+			return err
+		}
+		users, err := vs.rawUsers(name)
+		if err != nil {
+			return err
+		}
+		// Do not trust the users alone: they are stale when the volume was unmounted without
+		// the containers being stopped through the OCI hook (e.g., on reboot or crash).
+		mounted, err := isMountpoint(mountpoint)
+		if err != nil {
+			return err
+		}
+		if !mounted {
+			if len(users) > 0 {
+				log.L.Warnf("volume %q is not mounted, discarding its stale users %v", name, users)
+				users = nil
+			}
+			if err = driver.Mount(mountpoint); err != nil {
+				return err
+			}
+		}
+		if !slices.Contains(users, id) {
+			users = append(users, id)
+		}
+		return vs.rawSetUsers(name, users)
+	})
+}
+
+func (vs *volumeStore) Unmount(name string, id string) (err error) {
+	defer func() {
+		if err != nil {
+			err = errors.Join(ErrVolumeStore, err)
+		}
+	}()
+
+	return vs.Locker.WithLock(func() error {
+		driver, mountpoint, err := vs.rawDriver(name)
+		if err != nil || !driver.NeedsMount() {
+			return err
+		}
+		users, err := vs.rawUsers(name)
+		if err != nil {
+			return err
+		}
+		idx := slices.Index(users, id)
+		if idx < 0 {
+			return nil
+		}
+		users = slices.Delete(users, idx, idx+1)
+		if len(users) == 0 {
+			if err = driver.Unmount(mountpoint); err != nil {
+				return err
+			}
+		}
+		return vs.rawSetUsers(name, users)
+	})
+}
+
+func (vs *volumeStore) rawDriver(name string) (Driver, string, error) {
+	content, err := vs.manager.Get(name, volumeJSONFileName)
+	if err != nil {
+		return nil, "", err
+	}
+	opts := volumeOpts(content)
+	var driverOpts map[string]string
+	if opts.Options != nil {
+		driverOpts = *opts.Options
+	}
+	driver, err := GetDriver(opts.driver(), driverOpts)
+	if err != nil {
+		return nil, "", err
+	}
+	mountpoint, err := vs.manager.Location(name, dataDirName)
+	if err != nil {
+		return nil, "", err
+	}
+	return driver, mountpoint, nil
+}
+
+func (vs *volumeStore) rawUsers(name string) ([]string, error) {
+	var users []string
+	if doesExist, err := vs.manager.Exists(name, usersJSONFileName); err != nil || !doesExist {
+		return nil, err
+	}
+	content, err := vs.manager.Get(name, usersJSONFileName)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(content, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (vs *volumeStore) rawSetUsers(name string, users []string) error {
+	content, err := json.Marshal(users)
+	if err != nil {
+		return err
+	}
+	return vs.manager.Set(content, name, usersJSONFileName)
+}
+
+// rawDelete unmounts the volume if its driver requires it, so that the content of the device is not removed,
+// and then deletes it.
+func (vs *volumeStore) rawDelete(name string) error {
+	if driver, mountpoint, err := vs.rawDriver(name); err != nil {
+		log.L.WithError(err).Warnf("failed to get the driver of volume %q", name)
+	} else if driver.NeedsMount() {
+		if err = driver.Unmount(mountpoint); err != nil {
+			return err
+		}
+	}
+	return vs.manager.Delete(name)
+}
+
 // Private helpers
-func labels(b []byte) *map[string]string {
-	type volumeOpts struct {
-		Labels *map[string]string `json:"labels,omitempty"`
+type volumeJSON struct {
+	Labels  *map[string]string `json:"labels,omitempty"`
+	Driver  string             `json:"driver,omitempty"`
+	Options *map[string]string `json:"options,omitempty"`
+}
+
+func (vo volumeJSON) driver() string {
+	if vo.Driver == "" {
+		return DefaultDriver
 	}
-	var vo volumeOpts
+	return vo.Driver
+}
+
+func volumeOpts(b []byte) volumeJSON {
+	var vo volumeJSON
 	if err := json.Unmarshal(b, &vo); err != nil {
-		return nil
+		return volumeJSON{}
 	}
-	return vo.Labels
+	return vo
 }
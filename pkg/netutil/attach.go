@@ -0,0 +1,123 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package netutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containernetworking/cni/libcni"
+	"github.com/containernetworking/cni/pkg/invoke"
+	types100 "github.com/containernetworking/cni/pkg/types/100"
+	"github.com/containernetworking/cni/pkg/version"
+
+	"github.com/containerd/go-cni"
+)
+
+// Attach performs the CNI ADD operation of a single network on the interface set in rt.
+// Unlike go-cni, which names the interfaces after the position of the networks in the list,
+// this allows attaching networks to, and detaching networks from, a running container.
+func (e *CNIEnv) Attach(ctx context.Context, netw *NetworkConfig, rt *libcni.RuntimeConf) (*types100.Result, error) {
+	res, err := libcni.NewCNIConfig([]string{e.Path}, nil).AddNetworkList(ctx, netw.NetworkConfigList, rt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach network %q: %w", netw.Name, err)
+	}
+	return types100.NewResultFromResult(res)
+}
+
+// Detach performs the CNI DEL operation of a single network on the interface set in rt.
+func (e *CNIEnv) Detach(ctx context.Context, netw *NetworkConfig, rt *libcni.RuntimeConf) error {
+	if err := libcni.NewCNIConfig([]string{e.Path}, nil).DelNetworkList(ctx, netw.NetworkConfigList, rt); err != nil {
+		return fmt.Errorf("failed to detach network %q: %w", netw.Name, err)
+	}
+	return nil
+}
+
+// HasPortMap returns whether the network has the portmap plugin.
+func (netw *NetworkConfig) HasPortMap() bool {
+	return portMapPlugin(netw) != nil
+}
+
+func portMapPlugin(netw *NetworkConfig) *libcni.PluginConfig {
+	for _, plugin := range netw.Plugins {
+		if plugin.Network.Type == "portmap" {
+			return plugin
+		}
+	}
+	return nil
+}
+
+// SetPortMappings replaces the port mappings of the running container on the interface set in rt,
+// by running the DEL, ADD and CHECK operations of the portmap plugin of the network.
+// The result cached by the CNI ADD operation of the network is passed as the previous result,
+// so the other plugins of the network are not run.
+func (e *CNIEnv) SetPortMappings(ctx context.Context, netw *NetworkConfig, rt *libcni.RuntimeConf, ports []cni.PortMapping) error {
+	plugin := portMapPlugin(netw)
+	if plugin == nil {
+		return fmt.Errorf("network %q has no portmap plugin", netw.Name)
+	}
+	cniConfig := libcni.NewCNIConfig([]string{e.Path}, nil)
+	prevResult, err := cniConfig.GetNetworkListCachedResult(netw.NetworkConfigList, rt)
+	if err != nil {
+		return fmt.Errorf("failed to get the cached result of network %q: %w", netw.Name, err)
+	}
+	if prevResult == nil {
+		return fmt.Errorf("no cached result found for network %q of container %q", netw.Name, rt.ContainerID)
+	}
+	pluginPath, err := invoke.FindInPath(plugin.Network.Type, []string{e.Path})
+	if err != nil {
+		return err
+	}
+	conf, err := libcni.InjectConf(plugin, map[string]interface{}{
+		"name":       netw.Name,
+		"cniVersion": netw.CNIVersion,
+		"prevResult": prevResult,
+		"runtimeConfig": map[string]interface{}{
+			"portMappings": ports,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	args := func(action string) *invoke.Args {
+		return &invoke.Args{
+			Command:     action,
+			ContainerID: rt.ContainerID,
+			NetNS:       rt.NetNS,
+			PluginArgs:  rt.Args,
+			IfName:      rt.IfName,
+			Path:        e.Path,
+		}
+	}
+	if err := invoke.ExecPluginWithoutResult(ctx, pluginPath, conf.Bytes, args("DEL"), nil); err != nil {
+		return fmt.Errorf("failed to remove the port mappings of network %q: %w", netw.Name, err)
+	}
+	if len(ports) == 0 {
+		return nil
+	}
+	if _, err := invoke.ExecPluginWithResult(ctx, pluginPath, conf.Bytes, args("ADD"), nil); err != nil {
+		return fmt.Errorf("failed to add the port mappings of network %q: %w", netw.Name, err)
+	}
+	// CHECK was added in CNI spec version 0.4.0
+	if gtet, err := version.GreaterThanOrEqualTo(netw.CNIVersion, "0.4.0"); err != nil || !gtet {
+		return err
+	}
+	if err := invoke.ExecPluginWithoutResult(ctx, pluginPath, conf.Bytes, args("CHECK"), nil); err != nil {
+		return fmt.Errorf("failed to check the port mappings of network %q: %w", netw.Name, err)
+	}
+	return nil
+}
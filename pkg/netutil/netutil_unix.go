@@ -79,6 +79,42 @@ func (n *NetworkConfig) subnets() []*net.IPNet {
 	return subnets
 }
 
+// BridgeGateways returns the gateway addresses of the network, if it is a bridge network using host-local IPAM.
+func (n *NetworkConfig) BridgeGateways() []net.IP {
+	var gateways []net.IP
+	if len(n.Plugins) > 0 && n.Plugins[0].Network.Type == "bridge" {
+		var bridge bridgeConfig
+		if err := json.Unmarshal(n.Plugins[0].Bytes, &bridge); err != nil {
+			return gateways
+		}
+		if bridge.IPAM["type"] != "host-local" {
+			return gateways
+		}
+		var ipam hostLocalIPAMConfig
+		if err := mapstructure.Decode(bridge.IPAM, &ipam); err != nil {
+			return gateways
+		}
+		for _, irange := range ipam.Ranges {
+			if len(irange) == 0 {
+				continue
+			}
+			if gw := net.ParseIP(irange[0].Gateway); gw != nil {
+				gateways = append(gateways, gw)
+				continue
+			}
+			// host-local defaults to the first address of the subnet
+			_, subnet, err := net.ParseCIDR(irange[0].Subnet)
+			if err != nil {
+				continue
+			}
+			gw := append(net.IP(nil), subnet.IP...)
+			gw[len(gw)-1]++
+			gateways = append(gateways, gw)
+		}
+	}
+	return gateways
+}
+
 func (n *NetworkConfig) clean() error {
 	// Remove the bridge network interface on the host.
 	if len(n.Plugins) > 0 && n.Plugins[0].Network.Type == "bridge" {
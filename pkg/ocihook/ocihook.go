@@ -26,27 +26,36 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/containernetworking/cni/libcni"
 	types100 "github.com/containernetworking/cni/pkg/types/100"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	b4nndclient "github.com/rootless-containers/bypass4netns/pkg/api/daemon/client"
 	rlkclient "github.com/rootless-containers/rootlesskit/v3/pkg/api/client"
 
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/runtime/restart"
 	"github.com/containerd/go-cni"
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/bypass4netnsutil"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/dnsutil/embeddeddns"
 	"github.com/containerd/nerdctl/v2/pkg/dnsutil/hostsstore"
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
 	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
 	"github.com/containerd/nerdctl/v2/pkg/namestore"
 	"github.com/containerd/nerdctl/v2/pkg/netutil"
 	"github.com/containerd/nerdctl/v2/pkg/netutil/nettype"
 	"github.com/containerd/nerdctl/v2/pkg/ocihook/state"
 	"github.com/containerd/nerdctl/v2/pkg/portutil"
+	"github.com/containerd/nerdctl/v2/pkg/resolvconf"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
 	"github.com/containerd/nerdctl/v2/pkg/store"
 )
@@ -64,7 +73,12 @@ const (
 	NetworkNamespace = labels.Prefix + "network-namespace"
 )
 
-func Run(stdin io.Reader, stderr io.Writer, event, dataStore, cniPath, cniNetconfPath, bridgeIP string) error {
+// Run handles the OCI hook event.
+// dnsServerCmd is the command running the embedded DNS server of a network (see pkg/dnsutil/embeddeddns),
+// or nil when the embedded DNS server is disabled.
+// seccompTrackerCmd is the command tracking the processes of a container recording its seccomp profile
+// (see seccomputil.TrackProcesses).
+func Run(stdin io.Reader, stderr io.Writer, event, address, dataStore, cniPath, cniNetconfPath, bridgeIP string, dnsServerCmd, seccompTrackerCmd []string) error {
 	if stdin == nil || event == "" || dataStore == "" || cniPath == "" || cniNetconfPath == "" {
 		return errors.New("got insufficient args")
 	}
@@ -122,6 +136,9 @@ func Run(stdin io.Reader, stderr io.Writer, event, dataStore, cniPath, cniNetcon
 	if err != nil {
 		return err
 	}
+	opts.dnsServerCmd = dnsServerCmd
+	opts.seccompTrackerCmd = seccompTrackerCmd
+	opts.address = address
 
 	switch event {
 	case "createRuntime":
@@ -145,6 +162,12 @@ func newHandlerOpts(state *specs.State, dataStore, cniPath, cniNetconfPath, brid
 	}
 	o.extraHosts = extraHosts
 
+	if networkAliasesJSON := state.Annotations[labels.NetworkAliases]; networkAliasesJSON != "" {
+		if err := json.Unmarshal([]byte(networkAliasesJSON), &o.networkAliases); err != nil {
+			return nil, err
+		}
+	}
+
 	hs, err := loadSpec(o.state.Bundle)
 	if err != nil {
 		return nil, err
@@ -193,6 +216,7 @@ func newHandlerOpts(state *specs.State, dataStore, cniPath, cniNetconfPath, brid
 			cniOpts = append(cniOpts, cni.WithConfListBytes(netw.Bytes))
 			o.cniNames = append(o.cniNames, netstr)
 		}
+		o.cniEnv = e
 		o.cni, err = cni.New(cniOpts...)
 		if err != nil {
 			return nil, err
@@ -253,15 +277,20 @@ func newHandlerOpts(state *specs.State, dataStore, cniPath, cniNetconfPath, brid
 
 type handlerOpts struct {
 	state             *specs.State
+	address           string
 	dataStore         string
 	rootfs            string
 	ports             []cni.PortMapping
 	cni               cni.CNI
+	cniEnv            *netutil.CNIEnv
+	dnsServerCmd      []string
+	seccompTrackerCmd []string
 	cniNames          []string
 	fullID            string
 	rootlessKitClient rlkclient.Client
 	bypassClient      b4nndclient.Client
-	extraHosts        map[string]string // host:ip
+	extraHosts        map[string]string   // host:ip
+	networkAliases    map[string][]string // network:aliases
 	containerIP       string
 	containerMAC      string
 	containerIP6      string
@@ -332,39 +361,11 @@ func getPortMapOpts(opts *handlerOpts) ([]cni.NamespaceOpts, error) {
 		if !rootlessutil.IsRootlessChild() {
 			return []cni.NamespaceOpts{cni.WithCapabilityPortMap(opts.ports)}, nil
 		}
-		var (
-			childIP                            net.IP
-			portDriverDisallowsLoopbackChildIP bool
-		)
-		info, err := opts.rootlessKitClient.Info(context.TODO())
-		if err != nil {
-			log.L.WithError(err).Warn("cannot call RootlessKit Info API, make sure you have RootlessKit v0.14.1 or later")
-		} else {
-			childIP = info.NetworkDriver.ChildIP
-			portDriverDisallowsLoopbackChildIP = info.PortDriver.DisallowLoopbackChildIP // true for slirp4netns port driver
-		}
-		// For rootless, we need to modify the hostIP that is not bindable in the child namespace.
-		// https: //github.com/containerd/nerdctl/issues/88
-		//
 		// We must NOT modify opts.ports here, because we use the unmodified opts.ports for
 		// interaction with RootlessKit API.
-		ports := make([]cni.PortMapping, len(opts.ports))
-		for i, p := range opts.ports {
-			if hostIP := net.ParseIP(p.HostIP); hostIP != nil && !hostIP.IsUnspecified() {
-				// loopback address is always bindable in the child namespace, but other addresses are unlikely.
-				if !hostIP.IsLoopback() {
-					if !(childIP != nil && childIP.Equal(hostIP)) {
-						if portDriverDisallowsLoopbackChildIP {
-							p.HostIP = childIP.String()
-						} else {
-							p.HostIP = "127.0.0.1"
-						}
-					}
-				} else if portDriverDisallowsLoopbackChildIP {
-					p.HostIP = childIP.String()
-				}
-			}
-			ports[i] = p
+		ports, err := childPortMappingsRootless(context.TODO(), opts.rootlessKitClient, opts.ports)
+		if err != nil {
+			return nil, err
 		}
 		return []cni.NamespaceOpts{cni.WithCapabilityPortMap(ports)}, nil
 	}
@@ -476,55 +477,85 @@ func CleanupPortReserverProcess(namespace, id string) error {
 	return nil
 }
 
+// startPortReserverProcess reserves the host ports in rootful mode,
+// so that the ports appears on /proc/net/tcp.
+//
+// This also prevents other processes from binding to the same ports.
+//
+// See https://github.com/lima-vm/lima/issues/4085
+//
+// Similar patterns are used in Docker and Podman.
+// - https://github.com/moby/moby/pull/48132
+// - https://github.com/containers/podman/pull/23446
+func startPortReserverProcess(namespace, id string, ports []cni.PortMapping) (*os.Process, error) {
+	reserverCmd := exec.Command("sleep", "infinity")
+	for _, p := range ports {
+		protocol := p.Protocol
+		if !strings.HasSuffix(protocol, "4") && !strings.HasSuffix(protocol, "6") {
+			// e.g. "tcp" -> "tcp4"
+			protocol += "4"
+		}
+		hostAddr := net.JoinHostPort(p.HostIP, strconv.Itoa(int(p.HostPort)))
+		f, err := reserveSocket(protocol, hostAddr)
+		if err != nil {
+			log.L.WithError(err).Warnf("cannot reserve the port %s/%s", hostAddr, protocol)
+			continue
+		}
+		reserverCmd.ExtraFiles = append(reserverCmd.ExtraFiles, f)
+	}
+	if err := reserverCmd.Start(); err != nil {
+		return nil, fmt.Errorf("cannot start the port reserver process: %w", err)
+	}
+	reserverCmdPid := reserverCmd.Process.Pid
+	log.L.Debugf("started the port reserver process (pid=%d)", reserverCmdPid)
+	if err := writePidFile(portReserverPidFilePath(namespace, id), reserverCmdPid); err != nil {
+		log.L.Debugf("killing the port reserver process (pid=%d)", reserverCmdPid)
+		_ = reserverCmd.Process.Kill()
+		_ = os.RemoveAll(filepath.Dir(portReserverPidFilePath(namespace, id)))
+		return nil, fmt.Errorf("cannot write the pid file of the port reserver process: %w", err)
+	}
+	return reserverCmd.Process, nil
+}
+
+// ReplacePortReserverProcess replaces the port reserver process of a running container
+// after its port mappings were updated.
+func ReplacePortReserverProcess(namespace, id string, ports []cni.PortMapping) error {
+	// In rootless mode, port-reserver is handled by Rootlesskit.
+	if rootlessutil.IsRootlessChild() {
+		return nil
+	}
+	if err := CleanupPortReserverProcess(namespace, id); err != nil {
+		return err
+	}
+	if len(ports) == 0 {
+		return nil
+	}
+	_, err := startPortReserverProcess(namespace, id, ports)
+	return err
+}
+
 func applyNetworkSettings(opts *handlerOpts) (err error) {
 	portMapOpts, err := getPortMapOpts(opts)
 	if err != nil {
 		return err
 	}
 	if !rootlessutil.IsRootlessChild() && len(opts.ports) > 0 {
-		// When running in rootful mode, reserve the ports on the host
-		// so that the ports appears on /proc/net/tcp.
-		//
-		// This also prevents other processes from binding to the same ports.
-		//
+		// When running in rootful mode, reserve the ports on the host.
 		// Note that in rootless mode this is not necessary because
 		// RootlessKit's port driver already reserves the ports.
-		//
-		// See https://github.com/lima-vm/lima/issues/4085
-		//
-		// Similar patterns are used in Docker and Podman.
-		// - https://github.com/moby/moby/pull/48132
-		// - https://github.com/containers/podman/pull/23446
-		reserverCmd := exec.Command("sleep", "infinity")
-		for _, p := range opts.ports {
-			protocol := p.Protocol
-			if !strings.HasSuffix(protocol, "4") && !strings.HasSuffix(protocol, "6") {
-				// e.g. "tcp" -> "tcp4"
-				protocol += "4"
-			}
-			hostAddr := net.JoinHostPort(p.HostIP, strconv.Itoa(int(p.HostPort)))
-			f, err := reserveSocket(protocol, hostAddr)
-			if err != nil {
-				log.L.WithError(err).Warnf("cannot reserve the port %s/%s", hostAddr, protocol)
-				continue
-			}
-			reserverCmd.ExtraFiles = append(reserverCmd.ExtraFiles, f)
-		}
-		if err := reserverCmd.Start(); err != nil {
-			return fmt.Errorf("cannot start the port reserver process: %w", err)
+		namespace := opts.state.Annotations[labels.Namespace]
+		var reserver *os.Process
+		reserver, err = startPortReserverProcess(namespace, opts.state.ID, opts.ports)
+		if err != nil {
+			return err
 		}
-		reserverCmdPid := reserverCmd.Process.Pid
-		log.L.Debugf("started the port reserver process (pid=%d)", reserverCmdPid)
 		defer func() {
 			if err != nil {
-				log.L.Debugf("killing the port reserver process (pid=%d)", reserverCmdPid)
-				_ = reserverCmd.Process.Kill()
-				_ = os.RemoveAll(filepath.Dir(portReserverPidFilePath(opts.state.Annotations[labels.Namespace], opts.state.ID)))
+				log.L.Debugf("killing the port reserver process (pid=%d)", reserver.Pid)
+				_ = reserver.Kill()
+				_ = os.RemoveAll(filepath.Dir(portReserverPidFilePath(namespace, opts.state.ID)))
 			}
 		}()
-		if err := writePidFile(portReserverPidFilePath(opts.state.Annotations[labels.Namespace], opts.state.ID), reserverCmdPid); err != nil {
-			return fmt.Errorf("cannot write the pid file of the port reserver process: %w", err)
-		}
 	}
 	nsPath, err := getNetNSPath(opts.state)
 	if err != nil {
@@ -565,6 +596,8 @@ func applyNetworkSettings(opts *handlerOpts) (err error) {
 		Domainname: opts.state.Annotations[labels.Domainname],
 		ExtraHosts: opts.extraHosts,
 		Name:       opts.state.Annotations[labels.Name],
+		Aliases:    opts.networkAliases,
+		Ports:      opts.ports,
 	}
 
 	// When containerd gets bounced, containers that were previously running and that are restarted will go again
@@ -605,6 +638,12 @@ func applyNetworkSettings(opts *handlerOpts) (err error) {
 		return err
 	}
 
+	if opts.dnsServerCmd != nil {
+		if err := startEmbeddedDNS(opts, cniResRaw); err != nil {
+			return err
+		}
+	}
+
 	if rootlessutil.IsRootlessChild() {
 		if b4nnEnabled {
 			bm, err := bypass4netnsutil.NewBypass4netnsCNIBypassManager(opts.bypassClient, opts.rootlessKitClient, opts.state.Annotations)
@@ -637,6 +676,11 @@ func onCreateRuntime(opts *handlerOpts) error {
 		log.L.WithError(err).Error("failed re-acquiring name - see https://github.com/containerd/nerdctl/issues/2992")
 	}
 
+	// The tracker must be running before the process of the container is started
+	if err := startSeccompTracker(opts); err != nil {
+		return err
+	}
+
 	var netError error
 	if opts.cni != nil {
 		netError = applyNetworkSettings(opts)
@@ -651,6 +695,7 @@ func onCreateRuntime(opts *handlerOpts) error {
 	err = lf.Transform(func(lf *state.Store) error {
 		lf.StartedAt = time.Now()
 		lf.CreateError = netError != nil
+		lf.Networks = nil
 		return nil
 	})
 	if err != nil {
@@ -667,7 +712,10 @@ func onPostStop(opts *handlerOpts) error {
 	}
 
 	var shouldExit bool
+	var attachedNetworks map[string]string
+	var startedAt time.Time
 	err = lf.Transform(func(lf *state.Store) error {
+		startedAt = lf.StartedAt
 		// See https://github.com/containerd/nerdctl/issues/3357
 		// Check if we actually errored during runtimeCreate
 		// If that is the case, CreateError is set, and we are in postStop while the container will NOT be deleted (see ticket).
@@ -675,6 +723,9 @@ func onPostStop(opts *handlerOpts) error {
 		// Reset CreateError, and return.
 		shouldExit = lf.CreateError
 		lf.CreateError = false
+		// Networks modified by `nerdctl network connect` or `nerdctl network disconnect` after the task was created
+		attachedNetworks = lf.Networks
+		lf.Networks = nil
 		return nil
 	})
 	if err != nil {
@@ -684,8 +735,15 @@ func onPostStop(opts *handlerOpts) error {
 		return nil
 	}
 
+	if err := recordSeccomp(opts, startedAt); err != nil {
+		log.L.WithError(err).Errorf("failed to record the seccomp profile of container %s", opts.state.ID)
+	}
+
 	ctx := context.Background()
 	ns := opts.state.Annotations[labels.Namespace]
+	if err := unmountVolumes(opts, startedAt); err != nil {
+		log.L.WithError(err).Warnf("failed to unmount volumes of container %s", opts.state.ID)
+	}
 	if opts.cni != nil {
 		var err error
 		b4nnEnabled, b4nnBindEnabled, err := bypass4netnsutil.IsBypass4netnsEnabled(opts.state.Annotations)
@@ -730,7 +788,15 @@ func onPostStop(opts *handlerOpts) error {
 		namespaceOpts = append(namespaceOpts, ipAddressOpts...)
 		namespaceOpts = append(namespaceOpts, macAddressOpts...)
 		namespaceOpts = append(namespaceOpts, ip6AddressOpts...)
-		if err := opts.cni.Remove(ctx, opts.fullID, "", namespaceOpts...); err != nil {
+		if attachedNetworks != nil {
+			if err := detachNetworks(ctx, opts, attachedNetworks); err != nil {
+				return err
+			}
+			opts.cniNames = opts.cniNames[:0]
+			for netName := range attachedNetworks {
+				opts.cniNames = append(opts.cniNames, netName)
+			}
+		} else if err := opts.cni.Remove(ctx, opts.fullID, "", namespaceOpts...); err != nil {
 			log.L.WithError(err).Errorf("failed to call cni.Remove")
 			return err
 		}
@@ -767,6 +833,143 @@ func onPostStop(opts *handlerOpts) error {
 	return nil
 }
 
+// startEmbeddedDNS ensures that the embedded DNS servers of the bridge networks of the container are running.
+// Failing to start a server is fatal when the resolv.conf of the container points at it, as the container
+// could not resolve any name otherwise.
+func startEmbeddedDNS(opts *handlerOpts, results []*types100.Result) error {
+	var nameservers []string
+	if b, err := os.ReadFile(filepath.Join(opts.state.Annotations[labels.StateDir], "resolv.conf")); err == nil {
+		nameservers = resolvconf.GetNameservers(b, resolvconf.IP)
+	}
+	for i, cniName := range opts.cniNames {
+		netw, err := opts.cniEnv.NetworkByNameOrID(cniName)
+		if err != nil {
+			return fmt.Errorf("failed to load network %s: %w", cniName, err)
+		}
+		if len(netw.Plugins) == 0 || netw.Plugins[0].Network.Type != "bridge" || i >= len(results) {
+			continue
+		}
+		for _, ipCfg := range results[i].IPs {
+			if ipCfg.Gateway == nil {
+				continue
+			}
+			err := embeddeddns.EnsureServer(opts.dnsServerCmd, opts.dataStore, ipCfg.Gateway)
+			if err == nil {
+				continue
+			}
+			if slices.Contains(nameservers, ipCfg.Gateway.String()) {
+				return fmt.Errorf("failed to start the embedded DNS server of network %s: %w", cniName, err)
+			}
+			log.L.WithError(err).Warnf("failed to start the embedded DNS server of network %s", cniName)
+		}
+	}
+	return nil
+}
+
+// detachNetworks removes the networks of a container which networks were modified while running.
+// As the interfaces do not follow the naming of go-cni anymore, each network is detached individually.
+func detachNetworks(ctx context.Context, opts *handlerOpts, networks map[string]string) error {
+	var errs []error
+	for netName, ifName := range networks {
+		netw, err := opts.cniEnv.NetworkByNameOrID(netName)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		rt := &libcni.RuntimeConf{
+			ContainerID:    opts.fullID,
+			IfName:         ifName,
+			Args:           [][2]string{{"IgnoreUnknown", "1"}},
+			CapabilityArgs: map[string]interface{}{"portMappings": opts.ports},
+		}
+		if err := opts.cniEnv.Detach(ctx, netw, rt); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// unmountVolumes releases the volumes mounted by containerutil.MountVolumes.
+// The volumes are kept mounted when the task is going to be restarted by the containerd restart monitor,
+// as the restarted task does not go through containerutil.MountVolumes.
+// The volumes kept mounted are released by `nerdctl rm`.
+func unmountVolumes(opts *handlerOpts, startedAt time.Time) error {
+	mountsJSON := labels.GetMount(opts.state.Annotations)
+	if mountsJSON == "" {
+		return nil
+	}
+	if pending, err := restartPending(opts, startedAt); err != nil {
+		log.L.WithError(err).Warnf("failed to check whether container %s is going to be restarted", opts.state.ID)
+	} else if pending {
+		log.L.Debugf("container %s is going to be restarted, keeping its volumes mounted", opts.state.ID)
+		return nil
+	}
+	var mounts []struct {
+		Type string
+		Name string
+	}
+	if err := json.Unmarshal([]byte(mountsJSON), &mounts); err != nil {
+		return err
+	}
+	volStore, err := volumestore.New(opts.dataStore, opts.state.Annotations[labels.Namespace])
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, m := range mounts {
+		if m.Type != "volume" || m.Name == "" {
+			continue
+		}
+		if err := volStore.Unmount(m.Name, opts.state.ID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// restartPending returns whether the containerd restart monitor is going to restart the task started at startedAt.
+func restartPending(opts *handlerOpts, startedAt time.Time) (bool, error) {
+	if opts.address == "" {
+		return false, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, ctx, cancel, err := clientutil.NewClient(ctx, opts.state.Annotations[labels.Namespace], opts.address)
+	if err != nil {
+		return false, err
+	}
+	defer cancel()
+	defer client.Close()
+	container, err := client.LoadContainer(ctx, opts.state.ID)
+	if err != nil {
+		return false, err
+	}
+	l, err := container.Labels(ctx)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := l[restart.PolicyLabel]; !ok || l[restart.StatusLabel] != string(containerd.Running) {
+		return false, nil
+	}
+	if !strings.HasPrefix(l[restart.PolicyLabel], "on-failure") {
+		return restart.Reconcile(containerd.Status{Status: containerd.Stopped}, l), nil
+	}
+	// An `on-failure` container is only restarted after a failure: its volumes are kept mounted
+	// only once the exit status is known
+	exitStatus, ok, err := recordedExitStatus(opts, startedAt)
+	if err != nil || !ok {
+		return false, err
+	}
+	return restart.Reconcile(containerd.Status{Status: containerd.Stopped, ExitStatus: exitStatus}, l), nil
+}
+
+// recordedExitStatus returns the exit status of the task started at startedAt, as recorded by the logger
+// in the event journal. The restart monitor deletes the task only after noticing its exit, so the exit
+// status is usually recorded by then.
+func recordedExitStatus(opts *handlerOpts, startedAt time.Time) (uint32, bool, error) {
+	return eventutil.LastExitCode(opts.dataStore, opts.state.ID, startedAt)
+}
+
 // writePidFile writes the pid atomically to a file.
 // From https://github.com/containerd/containerd/blob/v1.7.0-rc.2/cmd/ctr/commands/commands.go#L265-L282
 func writePidFile(path string, pid int) error {
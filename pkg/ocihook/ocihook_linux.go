@@ -17,19 +17,120 @@
 package ocihook
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	cniutils "github.com/containernetworking/plugins/pkg/utils"
+	"github.com/opencontainers/runtime-spec/specs-go"
 
 	"github.com/containerd/containerd/v2/contrib/apparmor"
+	"github.com/containerd/containerd/v2/contrib/seccomp"
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/apparmorutil"
 	"github.com/containerd/nerdctl/v2/pkg/defaults"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/seccomputil"
 )
 
+// Files of the process tracker of a container recording its seccomp profile, in its state dir.
+const (
+	seccompTrackerOutput  = "seccomp-tracker.pids"
+	seccompTrackerPidFile = "seccomp-tracker.pid"
+	seccompTrackerLog     = "seccomp-tracker.log"
+)
+
+// startSeccompTracker starts the process tracker of the container, when the container was created with
+// --security-opt seccomp=record:PATH, and waits for it to be ready.
+func startSeccompTracker(opts *handlerOpts) error {
+	if opts.state.Annotations[labels.SeccompRecord] == "" {
+		return nil
+	}
+	if len(opts.seccompTrackerCmd) == 0 {
+		return errors.New("the command of the seccomp process tracker must be set")
+	}
+	stateDir := opts.state.Annotations[labels.StateDir]
+	logPath := filepath.Join(stateDir, seccompTrackerLog)
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	cmd := opts.seccompTrackerCmd
+	c := exec.Command(cmd[0], append(cmd[1:],
+		"--pid="+strconv.Itoa(opts.state.Pid),
+		"--output="+filepath.Join(stateDir, seccompTrackerOutput))...)
+	stdout, err := c.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	c.Stderr = logFile
+	// Detach from the OCI hook, which is waited for by the runtime
+	c.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := c.Start(); err != nil {
+		return fmt.Errorf("failed to start the seccomp process tracker: %w", err)
+	}
+	if line, _ := bufio.NewReader(stdout).ReadString('\n'); strings.TrimSpace(line) != "ready" {
+		_ = c.Process.Kill()
+		_ = c.Wait()
+		return fmt.Errorf("the seccomp process tracker failed to start, see %s", logPath)
+	}
+	if err := writePidFile(filepath.Join(stateDir, seccompTrackerPidFile), c.Process.Pid); err != nil {
+		_ = c.Process.Kill()
+		return err
+	}
+	return c.Process.Release()
+}
+
+// stopSeccompTracker stops the process tracker of the container, if any.
+func stopSeccompTracker(opts *handlerOpts) {
+	pidFile := filepath.Join(opts.state.Annotations[labels.StateDir], seccompTrackerPidFile)
+	b, err := os.ReadFile(pidFile)
+	if err != nil {
+		return
+	}
+	if pid, err := strconv.Atoi(strings.TrimSpace(string(b))); err == nil {
+		if proc, err := os.FindProcess(pid); err == nil {
+			_ = proc.Signal(syscall.SIGTERM)
+		}
+	}
+	_ = os.Remove(pidFile)
+}
+
+// recordSeccomp writes the seccomp profile recorded from the syscalls logged by the processes of the container
+// since it was started, when the container was created with --security-opt seccomp=record:PATH.
+func recordSeccomp(opts *handlerOpts, startedAt time.Time) error {
+	path := opts.state.Annotations[labels.SeccompRecord]
+	if path == "" {
+		return nil
+	}
+	stopSeccompTracker(opts)
+	b, err := os.ReadFile(filepath.Join(opts.state.Bundle, "config.json"))
+	if err != nil {
+		return err
+	}
+	var spec specs.Spec
+	if err := json.Unmarshal(b, &spec); err != nil {
+		return err
+	}
+	trackerOutput := filepath.Join(opts.state.Annotations[labels.StateDir], seccompTrackerOutput)
+	defer os.Remove(trackerOutput)
+	if err := seccomputil.Record(path, seccomp.DefaultProfile(&spec), startedAt, time.Now(), trackerOutput); err != nil {
+		return err
+	}
+	log.L.Infof("recorded the seccomp profile of container %s to %s", opts.state.ID, path)
+	return nil
+}
+
 func loadAppArmor() {
 	if !apparmorutil.CanLoadNewProfile() {
 		return
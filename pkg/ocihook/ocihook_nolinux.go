@@ -18,6 +18,18 @@
 
 package ocihook
 
+import "time"
+
+func startSeccompTracker(opts *handlerOpts) error {
+	//noop
+	return nil
+}
+
+func recordSeccomp(opts *handlerOpts, startedAt time.Time) error {
+	//noop
+	return nil
+}
+
 func loadAppArmor() {
 	//noop
 }
@@ -53,3 +53,11 @@ func unexposePortsRootless(ctx context.Context, rlkClient rlkclient.Client, port
 
 	return nil
 }
+
+func childPortMappingsRootless(ctx context.Context, rlkClient rlkclient.Client, ports []cni.PortMapping) ([]cni.PortMapping, error) {
+	pm, err := rootlessutil.NewRootlessCNIPortManager(rlkClient)
+	if err != nil {
+		return nil, err
+	}
+	return pm.ChildPortMappings(ctx, ports), nil
+}
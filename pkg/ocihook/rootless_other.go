@@ -34,3 +34,7 @@ func exposePortsRootless(ctx context.Context, rlkClient rlkclient.Client, ports
 func unexposePortsRootless(ctx context.Context, rlkClient rlkclient.Client, ports []cni.PortMapping) error {
 	return fmt.Errorf("cannot unexpose ports rootlessly on non-Linux hosts")
 }
+
+func childPortMappingsRootless(ctx context.Context, rlkClient rlkclient.Client, ports []cni.PortMapping) ([]cni.PortMapping, error) {
+	return nil, fmt.Errorf("cannot map ports rootlessly on non-Linux hosts")
+}
@@ -58,6 +58,9 @@ type Store struct {
 	// StartedAt reflects the time at which we received the oci-hook onCreateRuntime event
 	StartedAt   time.Time `json:"started_at"`
 	CreateError bool      `json:"create_error"`
+	// Networks maps the CNI networks of the running task to their interface name, once they have been modified
+	// by `nerdctl network connect` or `nerdctl network disconnect`. It is reset on onCreateRuntime.
+	Networks map[string]string `json:"networks,omitempty"`
 }
 
 // Load will populate the struct with existing in-store lifecycle information
@@ -0,0 +1,95 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package podutil provides helpers for pods, i.e., groups of containers that share
+// the network, IPC and UTS namespaces of an infra container.
+package podutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/errdefs"
+
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+// DefaultInfraImage is the image used for the infra container when --infra-image is not specified.
+const DefaultInfraImage = "registry.k8s.io/pause:3.10"
+
+// InfraContainerName returns the name of the infra container of the pod.
+func InfraContainerName(pod string) string {
+	return pod + "-infra"
+}
+
+// Infras returns the infra containers of all the pods in the namespace of ctx.
+func Infras(ctx context.Context, client *containerd.Client) ([]containerd.Container, error) {
+	return client.Containers(ctx, fmt.Sprintf("labels.%q==true", labels.PodInfra))
+}
+
+// Infra returns the infra container of the pod identified by its name or by a prefix of the infra container ID.
+func Infra(ctx context.Context, client *containerd.Client, nameOrID string) (containerd.Container, error) {
+	if nameOrID == "" {
+		return nil, fmt.Errorf("pod name must not be empty: %w", errdefs.ErrInvalidArgument)
+	}
+	infras, err := Infras(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	var matches []containerd.Container
+	for _, infra := range infras {
+		l, err := infra.Labels(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if l[labels.Pod] == nameOrID || infra.ID() == nameOrID {
+			return infra, nil
+		}
+		if strings.HasPrefix(infra.ID(), nameOrID) {
+			matches = append(matches, infra)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no such pod: %s: %w", nameOrID, errdefs.ErrNotFound)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("multiple pods found with provided prefix: %s", nameOrID)
+	}
+}
+
+// Members returns the containers of the pod, excluding its infra container.
+func Members(ctx context.Context, client *containerd.Client, pod string) ([]containerd.Container, error) {
+	containers, err := client.Containers(ctx, fmt.Sprintf("labels.%q==%s", labels.Pod, pod))
+	if err != nil {
+		return nil, err
+	}
+	var members []containerd.Container
+	for _, c := range containers {
+		l, err := c.Labels(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if l[labels.PodInfra] == "true" {
+			continue
+		}
+		members = append(members, c)
+	}
+	return members, nil
+}
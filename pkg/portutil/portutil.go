@@ -162,6 +162,60 @@ func ParseFlagP(s string) ([]cni.PortMapping, error) {
 	return mr, nil
 }
 
+// MatchFlagP returns the port mappings matching s, which is in the format of ParseFlagP.
+// The host IP and the host port can be omitted to match any, e.g., "80/tcp" matches
+// all the mappings of the container port 80/tcp.
+func MatchFlagP(ports []cni.PortMapping, s string) ([]cni.PortMapping, error) {
+	portProto, proto, ok := strings.Cut(s, "/")
+	if !ok {
+		proto = "tcp"
+	}
+	proto = strings.ToLower(proto)
+	switch proto {
+	case "tcp", "udp", "sctp":
+	default:
+		return nil, fmt.Errorf("invalid protocol %q", proto)
+	}
+	ip, hostPort, containerPort := splitParts(portProto)
+	var hostIP net.IP
+	if ip != "" {
+		if hostIP = net.ParseIP(ip); hostIP == nil {
+			return nil, fmt.Errorf("invalid ip address: %s", ip)
+		}
+	}
+	startPort, endPort, err := nat.ParsePortRange(containerPort)
+	if err != nil {
+		return nil, fmt.Errorf("invalid containerPort: %s", containerPort)
+	}
+	var startHostPort, endHostPort uint64
+	if hostPort != "" {
+		if startHostPort, endHostPort, err = nat.ParsePortRange(hostPort); err != nil {
+			return nil, fmt.Errorf("invalid hostPort: %s", hostPort)
+		}
+	}
+
+	var res []cni.PortMapping
+	for _, p := range ports {
+		if p.Protocol != proto || uint64(p.ContainerPort) < startPort || uint64(p.ContainerPort) > endPort {
+			continue
+		}
+		if hostPort != "" && (uint64(p.HostPort) < startHostPort || uint64(p.HostPort) > endHostPort) {
+			continue
+		}
+		if hostIP != nil {
+			pHostIP := net.ParseIP(p.HostIP)
+			if pHostIP == nil {
+				pHostIP = net.IPv4zero
+			}
+			if !pHostIP.Equal(hostIP) {
+				continue
+			}
+		}
+		res = append(res, p)
+	}
+	return res, nil
+}
+
 func StoreNetworkConfig(dataStore, namespace, id string, netConf networkstore.NetworkConfig) error {
 	ns, err := networkstore.New(dataStore, namespace, id)
 	if err != nil {
@@ -359,3 +359,37 @@ func TestParseFlagP(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchFlagP(t *testing.T) {
+	ports := []cni.PortMapping{
+		{HostPort: 8080, ContainerPort: 80, Protocol: "tcp", HostIP: "0.0.0.0"},
+		{HostPort: 8081, ContainerPort: 80, Protocol: "tcp", HostIP: "127.0.0.1"},
+		{HostPort: 8080, ContainerPort: 80, Protocol: "udp", HostIP: "0.0.0.0"},
+		{HostPort: 9090, ContainerPort: 90, Protocol: "tcp", HostIP: "0.0.0.0"},
+	}
+	tests := []struct {
+		s       string
+		want    []cni.PortMapping
+		wantErr bool
+	}{
+		{s: "80", want: ports[:2]},
+		{s: "80/udp", want: ports[2:3]},
+		{s: "8081:80", want: ports[1:2]},
+		{s: "0.0.0.0:8080:80/tcp", want: ports[:1]},
+		{s: "127.0.0.1:8080:80", want: nil},
+		{s: "80-90", want: []cni.PortMapping{ports[0], ports[1], ports[3]}},
+		{s: "80/foo", wantErr: true},
+		{s: "foo", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := MatchFlagP(ports, tt.s)
+			if tt.wantErr {
+				assert.Assert(t, err != nil)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
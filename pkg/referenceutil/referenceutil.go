@@ -106,6 +106,8 @@ func Parse(rawRef string) (*ImageReference, error) {
 		// The image must be loaded from the specified archive path first
 		// before parsing the image reference specified in its OCI image manifest.
 		return nil, ErrLoadOCIArchiveRequired
+	} else if IsOCILayout(rawRef) {
+		return nil, ErrOCILayoutReference
 	}
 	if decodedCID, err := decodeCid(rawRef); err == nil {
 		ir.Protocol = IPFSProtocol
@@ -301,3 +301,41 @@ func TestReferenceUtil(t *testing.T) {
 		assert.Equal(t, parsed.ExplicitTag, v.ExplicitTag, k)
 	}
 }
+
+func TestParseOCILayout(t *testing.T) {
+	dgst := digest.FromString("layout")
+	needles := map[string]struct {
+		Error  string
+		Path   string
+		Tag    string
+		Digest digest.Digest
+	}{
+		"oci-layout://./layout":                     {Path: "./layout"},
+		"oci-layout://./layout:v1":                  {Path: "./layout", Tag: "v1"},
+		"oci-layout:///srv/layout:1.0":              {Path: "/srv/layout", Tag: "1.0"},
+		"oci-layout:///srv/layout@" + dgst.String(): {Path: "/srv/layout", Digest: dgst},
+		`oci-layout://C:\layout`:                    {Path: `C:\layout`},
+		"oci-layout://./layout:-bad":                {Error: "invalid tag"},
+		"oci-layout://./layout@sha256:bad":          {Error: "invalid digest"},
+		"oci-layout://:v1":                          {Error: "missing the layout path"},
+		"./layout":                                  {Error: "not an OCI image layout reference"},
+	}
+
+	for k, v := range needles {
+		t.Run(k, func(t *testing.T) {
+			ref, err := ParseOCILayout(k)
+			if v.Error != "" {
+				assert.ErrorContains(t, err, v.Error)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, ref.Path, v.Path)
+			assert.Equal(t, ref.Tag, v.Tag)
+			assert.Equal(t, ref.Digest, v.Digest)
+			assert.Equal(t, ref.String(), k)
+		})
+	}
+
+	_, err := Parse("oci-layout://./layout:v1")
+	assert.ErrorIs(t, err, ErrOCILayoutReference)
+}
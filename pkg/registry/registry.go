@@ -0,0 +1,676 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package registry serves the images of a containerd namespace over the OCI Distribution API.
+// See https://github.com/opencontainers/distribution-spec/blob/v1.1.0/spec.md
+package registry
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/leases"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/idgen"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+)
+
+// maxManifestSize is the maximum size of the manifests and indexes accepted by the registry.
+const maxManifestSize = 4 << 20
+
+// uploadLeaseExpiration is how long an uploaded blob is kept around without being referenced by a manifest.
+const uploadLeaseExpiration = 24 * time.Hour
+
+// Options configures the registry.
+type Options struct {
+	// AllowPush accepts blob uploads and manifest pushes. The registry is read-only otherwise.
+	AllowPush bool
+	// Credentials maps user names to bcrypt password hashes. Basic auth is disabled when empty.
+	Credentials map[string][]byte
+}
+
+// NewHandler returns a registry serving the images and the content of the namespace of ctx.
+func NewHandler(ctx context.Context, client *containerd.Client, options Options) (http.Handler, error) {
+	namespace, err := namespaces.NamespaceRequired(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &server{
+		ctx:      namespaces.WithNamespace(context.WithoutCancel(ctx), namespace),
+		client:   client,
+		options:  options,
+		uploads:  map[string]*upload{},
+		verified: map[string][sha256.Size]byte{},
+	}, nil
+}
+
+type server struct {
+	// ctx is the namespaced context used for the uploads, which outlive the requests.
+	ctx     context.Context
+	client  *containerd.Client
+	options Options
+
+	mu      sync.Mutex
+	uploads map[string]*upload
+
+	// verified caches the SHA-256 of the password last verified for each user, as bcrypt is slow by design
+	// and clients authenticate every request
+	verifiedMu sync.Mutex
+	verified   map[string][sha256.Size]byte
+}
+
+type upload struct {
+	// ctx holds the lease of the upload
+	ctx  context.Context
+	repo string
+	// mu serializes the writes to w, as the chunks of an upload may be sent concurrently
+	mu sync.Mutex
+	w  content.Writer
+	// expiry discards the upload when its lease expires
+	expiry *time.Timer
+}
+
+var (
+	manifestRegexp = regexp.MustCompile(`^/v2/(.+)/manifests/([^/]+)$`)
+	uploadRegexp   = regexp.MustCompile(`^/v2/(.+)/blobs/uploads/?([^/]*)$`)
+	blobRegexp     = regexp.MustCompile(`^/v2/(.+)/blobs/([^/]+)$`)
+	tagsRegexp     = regexp.MustCompile(`^/v2/(.+)/tags/list$`)
+)
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="nerdctl"`)
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead && !s.options.AllowPush {
+		writeError(w, http.StatusMethodNotAllowed, "UNSUPPORTED", "the registry is read-only")
+		return
+	}
+	ctx := namespaces.WithNamespace(r.Context(), s.namespace())
+	log.G(ctx).Debugf("%s %s", r.Method, r.URL.Path)
+
+	path := r.URL.Path
+	switch {
+	case path == "/v2/" || path == "/v2":
+		w.WriteHeader(http.StatusOK)
+	case path == "/v2/_catalog":
+		s.serveCatalog(ctx, w, r)
+	case manifestRegexp.MatchString(path):
+		m := manifestRegexp.FindStringSubmatch(path)
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			s.getManifest(ctx, w, r, m[1], m[2])
+		case http.MethodPut:
+			s.putManifest(ctx, w, r, m[1], m[2])
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "UNSUPPORTED", r.Method+" is not supported on manifests")
+		}
+	case uploadRegexp.MatchString(path):
+		m := uploadRegexp.FindStringSubmatch(path)
+		switch {
+		case r.Method == http.MethodPost && m[2] == "":
+			s.startUpload(ctx, w, r, m[1])
+		case r.Method == http.MethodPatch && m[2] != "":
+			s.patchUpload(w, r, m[1], m[2])
+		case r.Method == http.MethodPut && m[2] != "":
+			s.finishUpload(w, r, m[1], m[2])
+		case r.Method == http.MethodGet && m[2] != "":
+			s.uploadStatus(w, m[1], m[2])
+		case r.Method == http.MethodDelete && m[2] != "":
+			s.cancelUpload(w, m[1], m[2])
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "UNSUPPORTED", r.Method+" is not supported on uploads")
+		}
+	case blobRegexp.MatchString(path):
+		m := blobRegexp.FindStringSubmatch(path)
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			s.getBlob(ctx, w, r, m[2])
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "UNSUPPORTED", r.Method+" is not supported on blobs")
+		}
+	case tagsRegexp.MatchString(path) && (r.Method == http.MethodGet || r.Method == http.MethodHead):
+		s.serveTags(ctx, w, tagsRegexp.FindStringSubmatch(path)[1])
+	default:
+		writeError(w, http.StatusNotFound, "UNSUPPORTED", "unsupported path")
+	}
+}
+
+func (s *server) namespace() string {
+	namespace, _ := namespaces.Namespace(s.ctx)
+	return namespace
+}
+
+// imageName returns the name of the local image for the repository and the tag of a request,
+// e.g., "library/alpine" and "3.20" for "docker.io/library/alpine:3.20".
+func imageName(repo, tag string) (string, error) {
+	parsed, err := referenceutil.Parse(repo + ":" + tag)
+	if err != nil {
+		return "", err
+	}
+	return parsed.String(), nil
+}
+
+func (s *server) getManifest(ctx context.Context, w http.ResponseWriter, r *http.Request, repo, ref string) {
+	var desc ocispec.Descriptor
+	if dgst, err := digest.Parse(ref); err == nil {
+		info, err := s.client.ContentStore().Info(ctx, dgst)
+		if err != nil || info.Size > maxManifestSize {
+			writeError(w, http.StatusNotFound, "MANIFEST_UNKNOWN", fmt.Sprintf("manifest %s is unknown", dgst))
+			return
+		}
+		b, err := content.ReadBlob(ctx, s.client.ContentStore(), ocispec.Descriptor{Digest: dgst, Size: info.Size})
+		if err != nil {
+			writeError(w, http.StatusNotFound, "MANIFEST_UNKNOWN", err.Error())
+			return
+		}
+		mediaType, err := manifestMediaType(b)
+		if err != nil {
+			writeError(w, http.StatusNotFound, "MANIFEST_UNKNOWN", fmt.Sprintf("%s is not a manifest", dgst))
+			return
+		}
+		desc = ocispec.Descriptor{MediaType: mediaType, Digest: dgst, Size: info.Size}
+	} else {
+		name, err := imageName(repo, ref)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "NAME_INVALID", err.Error())
+			return
+		}
+		img, err := s.client.ImageService().Get(ctx, name)
+		if err != nil {
+			writeError(w, http.StatusNotFound, "MANIFEST_UNKNOWN", fmt.Sprintf("image %q is unknown", name))
+			return
+		}
+		desc = img.Target
+	}
+	s.serveContent(ctx, w, r, desc, desc.MediaType, "MANIFEST_UNKNOWN")
+}
+
+func (s *server) getBlob(ctx context.Context, w http.ResponseWriter, r *http.Request, ref string) {
+	dgst, err := digest.Parse(ref)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "DIGEST_INVALID", err.Error())
+		return
+	}
+	info, err := s.client.ContentStore().Info(ctx, dgst)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "BLOB_UNKNOWN", fmt.Sprintf("blob %s is unknown", dgst))
+		return
+	}
+	s.serveContent(ctx, w, r, ocispec.Descriptor{Digest: dgst, Size: info.Size}, "application/octet-stream", "BLOB_UNKNOWN")
+}
+
+func (s *server) serveContent(ctx context.Context, w http.ResponseWriter, r *http.Request, desc ocispec.Descriptor, mediaType, unknownCode string) {
+	ra, err := s.client.ContentStore().ReaderAt(ctx, desc)
+	if err != nil {
+		writeError(w, http.StatusNotFound, unknownCode, err.Error())
+		return
+	}
+	defer ra.Close()
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Docker-Content-Digest", desc.Digest.String())
+	w.Header().Set("Etag", `"`+desc.Digest.String()+`"`)
+	http.ServeContent(w, r, "", time.Time{}, io.NewSectionReader(ra, 0, desc.Size))
+}
+
+func (s *server) serveTags(ctx context.Context, w http.ResponseWriter, repo string) {
+	parsed, err := referenceutil.Parse(repo)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "NAME_INVALID", err.Error())
+		return
+	}
+	imgs, err := s.client.ImageService().List(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "UNKNOWN", err.Error())
+		return
+	}
+	tags := []string{}
+	for _, img := range imgs {
+		if p, err := referenceutil.Parse(img.Name); err == nil && p.Name() == parsed.Name() && p.Tag != "" {
+			tags = append(tags, p.Tag)
+		}
+	}
+	if len(tags) == 0 {
+		writeError(w, http.StatusNotFound, "NAME_UNKNOWN", fmt.Sprintf("repository %q is unknown", repo))
+		return
+	}
+	sort.Strings(tags)
+	writeJSON(w, http.StatusOK, map[string]any{"name": repo, "tags": tags})
+}
+
+func (s *server) serveCatalog(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	imgs, err := s.client.ImageService().List(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "UNKNOWN", err.Error())
+		return
+	}
+	seen := map[string]bool{}
+	repos := []string{}
+	for _, img := range imgs {
+		p, err := referenceutil.Parse(img.Name)
+		if err != nil || p.Path == "" || seen[p.Name()] {
+			continue
+		}
+		seen[p.Name()] = true
+		repos = append(repos, p.Name())
+	}
+	sort.Strings(repos)
+	if n, err := strconv.Atoi(r.URL.Query().Get("n")); err == nil && n >= 0 && n < len(repos) {
+		repos = repos[:n]
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"repositories": repos})
+}
+
+func (s *server) putManifest(ctx context.Context, w http.ResponseWriter, r *http.Request, repo, ref string) {
+	b, err := io.ReadAll(io.LimitReader(r.Body, maxManifestSize+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "MANIFEST_INVALID", err.Error())
+		return
+	}
+	if len(b) > maxManifestSize {
+		writeError(w, http.StatusRequestEntityTooLarge, "SIZE_INVALID", "the manifest is too large")
+		return
+	}
+	mediaType := r.Header.Get("Content-Type")
+	if !images.IsManifestType(mediaType) && !images.IsIndexType(mediaType) {
+		if mediaType, err = manifestMediaType(b); err != nil {
+			writeError(w, http.StatusBadRequest, "MANIFEST_INVALID", err.Error())
+			return
+		}
+	}
+	desc := ocispec.Descriptor{MediaType: mediaType, Digest: digest.FromBytes(b), Size: int64(len(b))}
+	tag := ref
+	if dgst, err := digest.Parse(ref); err == nil {
+		if dgst != desc.Digest {
+			writeError(w, http.StatusBadRequest, "DIGEST_INVALID", fmt.Sprintf("the manifest digest is %s, not %s", desc.Digest, dgst))
+			return
+		}
+		tag = ""
+	}
+
+	children, err := manifestChildren(mediaType, b)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "MANIFEST_INVALID", err.Error())
+		return
+	}
+	cs := s.client.ContentStore()
+	labels := map[string]string{}
+	for key, child := range children {
+		if images.IsNonDistributable(child.MediaType) {
+			continue
+		}
+		if _, err := cs.Info(ctx, child.Digest); err != nil {
+			writeError(w, http.StatusBadRequest, "MANIFEST_BLOB_UNKNOWN", fmt.Sprintf("blob %s is unknown", child.Digest))
+			return
+		}
+		labels["containerd.io/gc.ref.content."+key] = child.Digest.String()
+	}
+
+	if tag == "" {
+		// Like a blob, a manifest pushed by digest is kept until an index or a tag refers to it
+		ctx, err = s.expiringLease(ctx)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "UNKNOWN", err.Error())
+			return
+		}
+	} else {
+		var done func(context.Context) error
+		ctx, done, err = s.client.WithLease(ctx)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "UNKNOWN", err.Error())
+			return
+		}
+		defer done(context.WithoutCancel(ctx))
+	}
+	if err := content.WriteBlob(ctx, cs, "nerdctl-registry-"+desc.Digest.String(), bytes.NewReader(b), desc, content.WithLabels(labels)); err != nil {
+		writeError(w, http.StatusInternalServerError, "UNKNOWN", err.Error())
+		return
+	}
+	if tag != "" {
+		name, err := imageName(repo, tag)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "NAME_INVALID", err.Error())
+			return
+		}
+		img := images.Image{Name: name, Target: desc, CreatedAt: time.Now()}
+		if _, err := s.client.ImageService().Update(ctx, img); err != nil {
+			if !errdefs.IsNotFound(err) {
+				writeError(w, http.StatusInternalServerError, "UNKNOWN", err.Error())
+				return
+			}
+			if _, err := s.client.ImageService().Create(ctx, img); err != nil {
+				writeError(w, http.StatusInternalServerError, "UNKNOWN", err.Error())
+				return
+			}
+		}
+		log.G(ctx).Infof("pushed %s (%s)", name, desc.Digest)
+	}
+	w.Header().Set("Location", fmt.Sprintf("/v2/%s/manifests/%s", repo, desc.Digest))
+	w.Header().Set("Docker-Content-Digest", desc.Digest.String())
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *server) startUpload(ctx context.Context, w http.ResponseWriter, r *http.Request, repo string) {
+	q := r.URL.Query()
+	if mount := q.Get("mount"); mount != "" {
+		// every blob of the namespace can be mounted, whatever the repository it comes from
+		if dgst, err := digest.Parse(mount); err == nil {
+			if _, err := s.client.ContentStore().Info(ctx, dgst); err == nil {
+				writeBlobCreated(w, repo, dgst)
+				return
+			}
+		}
+	}
+
+	id := idgen.GenerateID()
+	up, err := s.openUpload(repo, id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "UNKNOWN", err.Error())
+		return
+	}
+	if dgst := q.Get("digest"); dgst != "" {
+		// monolithic upload
+		s.commitUpload(w, r, up, repo, id, dgst)
+		return
+	}
+	s.mu.Lock()
+	s.uploads[id] = up
+	s.mu.Unlock()
+	writeUploadAccepted(w, repo, id, 0)
+}
+
+// expiringLease returns ctx with a new lease expiring after uploadLeaseExpiration, so that the content
+// written under it is not garbage collected before a manifest or an image refers to it.
+func (s *server) expiringLease(ctx context.Context) (context.Context, error) {
+	lease, err := s.client.LeasesService().Create(ctx, leases.WithRandomID(), leases.WithExpiration(uploadLeaseExpiration))
+	if err != nil {
+		return nil, err
+	}
+	return leases.WithLease(ctx, lease.ID), nil
+}
+
+// openUpload opens a content writer under its own expiring lease.
+// The upload is discarded when the lease expires, if it has not been finished or cancelled by then.
+func (s *server) openUpload(repo, id string) (*upload, error) {
+	ctx, err := s.expiringLease(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	cw, err := s.client.ContentStore().Writer(ctx, content.WithRef("nerdctl-registry-upload-"+id))
+	if err != nil {
+		return nil, err
+	}
+	up := &upload{ctx: ctx, repo: repo, w: cw}
+	up.expiry = time.AfterFunc(uploadLeaseExpiration, func() {
+		s.mu.Lock()
+		abandoned := s.uploads[id] == up
+		delete(s.uploads, id)
+		s.mu.Unlock()
+		if abandoned {
+			log.G(s.ctx).Debugf("upload %s expired", id)
+			s.abortUpload(up, id)
+		}
+	})
+	return up, nil
+}
+
+// abortUpload closes the writer of the upload and discards its content.
+func (s *server) abortUpload(up *upload, id string) {
+	up.expiry.Stop()
+	up.mu.Lock()
+	up.w.Close()
+	up.mu.Unlock()
+	if err := s.client.ContentStore().Abort(s.ctx, "nerdctl-registry-upload-"+id); err != nil && !errdefs.IsNotFound(err) {
+		log.G(s.ctx).WithError(err).Warnf("failed to abort upload %s", id)
+	}
+}
+
+func (s *server) lookupUpload(w http.ResponseWriter, repo, id string) *upload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	up, ok := s.uploads[id]
+	if !ok || up.repo != repo {
+		writeError(w, http.StatusNotFound, "BLOB_UPLOAD_UNKNOWN", fmt.Sprintf("upload %q is unknown", id))
+		return nil
+	}
+	return up
+}
+
+func (s *server) removeUpload(id string) {
+	s.mu.Lock()
+	delete(s.uploads, id)
+	s.mu.Unlock()
+}
+
+func (s *server) patchUpload(w http.ResponseWriter, r *http.Request, repo, id string) {
+	up := s.lookupUpload(w, repo, id)
+	if up == nil {
+		return
+	}
+	up.mu.Lock()
+	defer up.mu.Unlock()
+	st, err := up.w.Status()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "BLOB_UPLOAD_INVALID", err.Error())
+		return
+	}
+	// The chunks must be sent in order, starting at the offset of the upload
+	if contentRange := r.Header.Get("Content-Range"); contentRange != "" {
+		start, err := parseContentRangeStart(contentRange)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "BLOB_UPLOAD_INVALID", err.Error())
+			return
+		}
+		if start != st.Offset {
+			w.Header().Set("Location", fmt.Sprintf("/v2/%s/blobs/uploads/%s", repo, id))
+			w.Header().Set("Range", fmt.Sprintf("0-%d", max(st.Offset-1, 0)))
+			writeError(w, http.StatusRequestedRangeNotSatisfiable, "BLOB_UPLOAD_INVALID",
+				fmt.Sprintf("chunk starts at %d, expected %d", start, st.Offset))
+			return
+		}
+	}
+	if _, err := io.Copy(up.w, r.Body); err != nil {
+		writeError(w, http.StatusInternalServerError, "BLOB_UPLOAD_INVALID", err.Error())
+		return
+	}
+	if st, err = up.w.Status(); err != nil {
+		writeError(w, http.StatusInternalServerError, "BLOB_UPLOAD_INVALID", err.Error())
+		return
+	}
+	writeUploadAccepted(w, repo, id, st.Offset)
+}
+
+// parseContentRangeStart returns the start of the Content-Range of a chunk, which is "<start>-<end>".
+func parseContentRangeStart(contentRange string) (int64, error) {
+	startStr, endStr, ok := strings.Cut(contentRange, "-")
+	if !ok {
+		return 0, fmt.Errorf("invalid Content-Range %q", contentRange)
+	}
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid Content-Range %q: %w", contentRange, err)
+	}
+	if end, err := strconv.ParseInt(endStr, 10, 64); err != nil || end < start {
+		return 0, fmt.Errorf("invalid Content-Range %q", contentRange)
+	}
+	return start, nil
+}
+
+func (s *server) finishUpload(w http.ResponseWriter, r *http.Request, repo, id string) {
+	up := s.lookupUpload(w, repo, id)
+	if up == nil {
+		return
+	}
+	s.removeUpload(id)
+	s.commitUpload(w, r, up, repo, id, r.URL.Query().Get("digest"))
+}
+
+func (s *server) commitUpload(w http.ResponseWriter, r *http.Request, up *upload, repo, id, digestStr string) {
+	up.expiry.Stop()
+	up.mu.Lock()
+	defer up.mu.Unlock()
+	defer up.w.Close()
+	dgst, err := digest.Parse(digestStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "DIGEST_INVALID", err.Error())
+		return
+	}
+	if _, err := io.Copy(up.w, r.Body); err != nil {
+		writeError(w, http.StatusInternalServerError, "BLOB_UPLOAD_INVALID", err.Error())
+		return
+	}
+	if err := up.w.Commit(up.ctx, 0, dgst); err != nil && !errdefs.IsAlreadyExists(err) {
+		writeError(w, http.StatusBadRequest, "DIGEST_INVALID", err.Error())
+		return
+	}
+	log.G(s.ctx).Debugf("upload %s committed as %s", id, dgst)
+	writeBlobCreated(w, repo, dgst)
+}
+
+func (s *server) uploadStatus(w http.ResponseWriter, repo, id string) {
+	up := s.lookupUpload(w, repo, id)
+	if up == nil {
+		return
+	}
+	up.mu.Lock()
+	st, err := up.w.Status()
+	up.mu.Unlock()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "BLOB_UPLOAD_INVALID", err.Error())
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/v2/%s/blobs/uploads/%s", repo, id))
+	w.Header().Set("Range", fmt.Sprintf("0-%d", max(st.Offset-1, 0)))
+	w.Header().Set("Docker-Upload-UUID", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) cancelUpload(w http.ResponseWriter, repo, id string) {
+	up := s.lookupUpload(w, repo, id)
+	if up == nil {
+		return
+	}
+	s.removeUpload(id)
+	s.abortUpload(up, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeUploadAccepted(w http.ResponseWriter, repo, id string, offset int64) {
+	w.Header().Set("Location", fmt.Sprintf("/v2/%s/blobs/uploads/%s", repo, id))
+	w.Header().Set("Range", fmt.Sprintf("0-%d", max(offset-1, 0)))
+	w.Header().Set("Docker-Upload-UUID", id)
+	w.Header().Set("Content-Length", "0")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func writeBlobCreated(w http.ResponseWriter, repo string, dgst digest.Digest) {
+	w.Header().Set("Location", fmt.Sprintf("/v2/%s/blobs/%s", repo, dgst))
+	w.Header().Set("Docker-Content-Digest", dgst.String())
+	w.Header().Set("Content-Length", "0")
+	w.WriteHeader(http.StatusCreated)
+}
+
+// manifestMediaType returns the media type of a manifest or an index, which may be omitted from OCI manifests.
+func manifestMediaType(b []byte) (string, error) {
+	var m struct {
+		MediaType string          `json:"mediaType"`
+		Config    json.RawMessage `json:"config"`
+		Manifests json.RawMessage `json:"manifests"`
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return "", err
+	}
+	switch {
+	case images.IsManifestType(m.MediaType) || images.IsIndexType(m.MediaType):
+		return m.MediaType, nil
+	case m.MediaType != "":
+		return "", fmt.Errorf("unsupported media type %q", m.MediaType)
+	case m.Manifests != nil:
+		return ocispec.MediaTypeImageIndex, nil
+	case m.Config != nil:
+		return ocispec.MediaTypeImageManifest, nil
+	}
+	return "", errors.New("neither a manifest nor an index")
+}
+
+// manifestChildren returns the descriptors referred to by a manifest or an index,
+// keyed by the suffix of their garbage collection label ("m.0", "config", "l.0", ...).
+func manifestChildren(mediaType string, b []byte) (map[string]ocispec.Descriptor, error) {
+	children := map[string]ocispec.Descriptor{}
+	if images.IsIndexType(mediaType) {
+		var idx ocispec.Index
+		if err := json.Unmarshal(b, &idx); err != nil {
+			return nil, err
+		}
+		for i, desc := range idx.Manifests {
+			children[fmt.Sprintf("m.%d", i)] = desc
+		}
+		return children, nil
+	}
+	var m ocispec.Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	if m.Config.Digest == "" {
+		return nil, errors.New("the manifest has no config")
+	}
+	children["config"] = m.Config
+	for i, desc := range m.Layers {
+		children[fmt.Sprintf("l.%d", i)] = desc
+	}
+	return children, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.L.WithError(err).Debug("failed to write the response")
+	}
+}
+
+// writeError writes an error following the format of the OCI Distribution Spec:
+// https://github.com/opencontainers/distribution-spec/blob/v1.1.0/spec.md#error-codes
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	type errorInfo struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	writeJSON(w, status, map[string][]errorInfo{"errors": {{Code: code, Message: message}}})
+}
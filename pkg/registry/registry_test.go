@@ -0,0 +1,203 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/crypto/bcrypt"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+)
+
+func TestManifestMediaType(t *testing.T) {
+	for _, tc := range []struct {
+		manifest string
+		expected string
+		err      string
+	}{
+		{manifest: `{"mediaType":"` + images.MediaTypeDockerSchema2Manifest + `","config":{}}`, expected: images.MediaTypeDockerSchema2Manifest},
+		{manifest: `{"schemaVersion":2,"config":{},"layers":[]}`, expected: ocispec.MediaTypeImageManifest},
+		{manifest: `{"schemaVersion":2,"manifests":[]}`, expected: ocispec.MediaTypeImageIndex},
+		{manifest: `{"mediaType":"text/plain"}`, err: "unsupported media type"},
+		{manifest: `{"schemaVersion":2}`, err: "neither a manifest nor an index"},
+		{manifest: `not json`, err: "invalid character"},
+	} {
+		mediaType, err := manifestMediaType([]byte(tc.manifest))
+		if tc.err != "" {
+			assert.ErrorContains(t, err, tc.err, tc.manifest)
+			continue
+		}
+		assert.NilError(t, err, tc.manifest)
+		assert.Equal(t, mediaType, tc.expected, tc.manifest)
+	}
+}
+
+func TestManifestChildren(t *testing.T) {
+	const (
+		config = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		layer0 = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+		layer1 = "sha256:3333333333333333333333333333333333333333333333333333333333333333"
+	)
+	manifest := `{"schemaVersion":2,"config":{"digest":"` + config + `"},"layers":[{"digest":"` + layer0 + `"},{"digest":"` + layer1 + `"}]}`
+	children, err := manifestChildren(ocispec.MediaTypeImageManifest, []byte(manifest))
+	assert.NilError(t, err)
+	assert.Equal(t, len(children), 3)
+	assert.Equal(t, children["config"].Digest.String(), config)
+	assert.Equal(t, children["l.0"].Digest.String(), layer0)
+	assert.Equal(t, children["l.1"].Digest.String(), layer1)
+
+	index := `{"schemaVersion":2,"manifests":[{"digest":"` + layer0 + `"}]}`
+	children, err = manifestChildren(ocispec.MediaTypeImageIndex, []byte(index))
+	assert.NilError(t, err)
+	assert.Equal(t, len(children), 1)
+	assert.Equal(t, children["m.0"].Digest.String(), layer0)
+
+	_, err = manifestChildren(ocispec.MediaTypeImageManifest, []byte(`{"schemaVersion":2,"layers":[]}`))
+	assert.ErrorContains(t, err, "no config")
+}
+
+func TestImageName(t *testing.T) {
+	for repo, expected := range map[string]string{
+		"library/alpine":       "docker.io/library/alpine:3.20",
+		"alpine":               "docker.io/library/alpine:3.20",
+		"ghcr.io/foo/bar":      "ghcr.io/foo/bar:3.20",
+		"localhost:5000/image": "localhost:5000/image:3.20",
+	} {
+		name, err := imageName(repo, "3.20")
+		assert.NilError(t, err, repo)
+		assert.Equal(t, name, expected, repo)
+	}
+}
+
+func TestLoadHtpasswd(t *testing.T) {
+	dir := t.TempDir()
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	assert.NilError(t, err)
+
+	valid := filepath.Join(dir, "valid")
+	assert.NilError(t, os.WriteFile(valid, []byte("# comment\n\nalice:"+string(hash)+"\n"), 0o600))
+	credentials, err := LoadHtpasswd(valid)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, credentials, map[string][]byte{"alice": hash})
+
+	md5 := filepath.Join(dir, "md5")
+	assert.NilError(t, os.WriteFile(md5, []byte("alice:$apr1$x$y\n"), 0o600))
+	_, err = LoadHtpasswd(md5)
+	assert.ErrorContains(t, err, "not a bcrypt hash")
+
+	empty := filepath.Join(dir, "empty")
+	assert.NilError(t, os.WriteFile(empty, []byte("# no users\n"), 0o600))
+	_, err = LoadHtpasswd(empty)
+	assert.ErrorContains(t, err, "no users")
+}
+
+func TestServeHTTPRejects(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	assert.NilError(t, err)
+	s := &server{options: Options{Credentials: map[string][]byte{"alice": hash}}, verified: map[string][sha256.Size]byte{}}
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, rec.Code, http.StatusUnauthorized)
+	assert.Equal(t, rec.Header().Get("WWW-Authenticate"), `Basic realm="nerdctl"`)
+
+	req = httptest.NewRequest(http.MethodGet, "/v2/", nil)
+	req.SetBasicAuth("alice", "wrong")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, rec.Code, http.StatusUnauthorized)
+
+	// the registry is read-only without AllowPush
+	req = httptest.NewRequest(http.MethodPost, "/v2/foo/blobs/uploads/", nil)
+	req.SetBasicAuth("alice", "secret")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, rec.Code, http.StatusMethodNotAllowed)
+	assert.Assert(t, rec.Header().Get("Content-Type") == "application/json")
+}
+
+func TestAuthorizedCache(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	assert.NilError(t, err)
+	s := &server{options: Options{Credentials: map[string][]byte{"alice": hash}}, verified: map[string][sha256.Size]byte{}}
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/", nil)
+	req.SetBasicAuth("alice", "secret")
+	assert.Assert(t, s.authorized(req))
+	assert.Equal(t, len(s.verified), 1)
+	assert.Assert(t, s.authorized(req), "cached credentials")
+
+	req.SetBasicAuth("alice", "wrong")
+	assert.Assert(t, !s.authorized(req), "another password than the cached one")
+}
+
+// fakeWriter is a content.Writer appending to a buffer.
+type fakeWriter struct {
+	content.Writer
+	buf bytes.Buffer
+}
+
+func (w *fakeWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *fakeWriter) Status() (content.Status, error) {
+	return content.Status{Offset: int64(w.buf.Len())}, nil
+}
+
+func TestPatchUploadOutOfOrder(t *testing.T) {
+	cw := &fakeWriter{}
+	s := &server{uploads: map[string]*upload{"id": {ctx: context.Background(), repo: "foo", w: cw}}}
+
+	patch := func(contentRange, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/v2/foo/blobs/uploads/id", bytes.NewBufferString(body))
+		if contentRange != "" {
+			req.Header.Set("Content-Range", contentRange)
+		}
+		rec := httptest.NewRecorder()
+		s.patchUpload(rec, req, "foo", "id")
+		return rec
+	}
+
+	rec := patch("0-4", "hello")
+	assert.Equal(t, rec.Code, http.StatusAccepted)
+	assert.Equal(t, rec.Header().Get("Range"), "0-4")
+
+	rec = patch("10-14", "world")
+	assert.Equal(t, rec.Code, http.StatusRequestedRangeNotSatisfiable)
+	assert.Equal(t, rec.Header().Get("Range"), "0-4")
+	assert.Equal(t, cw.buf.String(), "hello")
+
+	rec = patch("5-4", "world")
+	assert.Equal(t, rec.Code, http.StatusBadRequest)
+
+	rec = patch("5-9", "world")
+	assert.Equal(t, rec.Code, http.StatusAccepted)
+	assert.Equal(t, cw.buf.String(), "helloworld")
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/containerd/errdefs"
 	"github.com/containerd/go-cni"
+	"github.com/containerd/log"
 )
 
 func NewRootlessCNIPortManager(client client.Client) (*RootlessCNIPortManager, error) {
@@ -81,3 +82,42 @@ func (rlcpm *RootlessCNIPortManager) UnexposePort(ctx context.Context, cpm cni.P
 	}
 	return pm.RemovePort(ctx, id)
 }
+
+// ChildPortMappings returns the port mappings to be set up by CNI in the child namespace.
+//
+// The host IPs that are not bindable in the child namespace are modified.
+// https://github.com/containerd/nerdctl/issues/88
+//
+// The unmodified port mappings must be used for interaction with RootlessKit API.
+func (rlcpm *RootlessCNIPortManager) ChildPortMappings(ctx context.Context, ports []cni.PortMapping) []cni.PortMapping {
+	var (
+		childIP                            net.IP
+		portDriverDisallowsLoopbackChildIP bool
+	)
+	info, err := rlcpm.Client.Info(ctx)
+	if err != nil {
+		log.G(ctx).WithError(err).Warn("cannot call RootlessKit Info API, make sure you have RootlessKit v0.14.1 or later")
+	} else {
+		childIP = info.NetworkDriver.ChildIP
+		portDriverDisallowsLoopbackChildIP = info.PortDriver.DisallowLoopbackChildIP // true for slirp4netns port driver
+	}
+	res := make([]cni.PortMapping, len(ports))
+	for i, p := range ports {
+		if hostIP := net.ParseIP(p.HostIP); hostIP != nil && !hostIP.IsUnspecified() {
+			// loopback address is always bindable in the child namespace, but other addresses are unlikely.
+			if !hostIP.IsLoopback() {
+				if !(childIP != nil && childIP.Equal(hostIP)) {
+					if portDriverDisallowsLoopbackChildIP {
+						p.HostIP = childIP.String()
+					} else {
+						p.HostIP = "127.0.0.1"
+					}
+				}
+			} else if portDriverDisallowsLoopbackChildIP {
+				p.HostIP = childIP.String()
+			}
+		}
+		res[i] = p
+	}
+	return res
+}
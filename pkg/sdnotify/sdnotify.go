@@ -0,0 +1,209 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package sdnotify implements the `--sdnotify` modes, which notify systemd
+// (sd_notify(3)) when a container started by an attached nerdctl process is ready.
+package sdnotify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/daemon"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+// Notification modes
+const (
+	Conmon    = "conmon"    // nerdctl reports READY=1 once the container is started
+	Container = "container" // the container notifies systemd through the forwarded NOTIFY_SOCKET
+	Healthy   = "healthy"   // nerdctl reports READY=1 once the container is healthy
+)
+
+const (
+	// ContainerSocketDir is the directory of the notification socket in the container.
+	ContainerSocketDir = "/run/notify"
+	// ContainerSocket is the value of NOTIFY_SOCKET in the container.
+	ContainerSocket = ContainerSocketDir + "/" + socketName
+
+	socketName = "notify.sock"
+
+	healthPollInterval = time.Second
+)
+
+// Validate checks that the mode is one of the supported notification modes.
+func Validate(mode string) error {
+	switch mode {
+	case Conmon, Container, Healthy:
+		return nil
+	}
+	return fmt.Errorf("invalid sdnotify mode %q, must be one of %q, %q or %q", mode, Conmon, Container, Healthy)
+}
+
+// SocketDir returns the host directory that is mounted on ContainerSocketDir for the "container" mode.
+func SocketDir(stateDir string) string {
+	return filepath.Join(stateDir, "notify")
+}
+
+// Notifier notifies systemd about a container according to its notification mode.
+// The zero value sends no notification.
+type Notifier struct {
+	container containerd.Container
+	mode      string
+	stop      func()
+}
+
+// Prepare prepares the notifications of the container, and must be called before its task is created.
+// In the "container" mode, it already listens on the socket of the container, so that the messages
+// sent by the container as soon as it starts are not lost.
+// Containers created without `--sdnotify` use the "conmon" mode.
+// The notifications are disabled when nerdctl is not run by systemd (NOTIFY_SOCKET is not set).
+// Stop must be called once the container exits, or when its task fails to start.
+func Prepare(ctx context.Context, container containerd.Container, lab map[string]string) *Notifier {
+	n := &Notifier{container: container}
+	if os.Getenv("NOTIFY_SOCKET") == "" {
+		return n
+	}
+	n.mode = lab[labels.SdNotify]
+	if n.mode == "" {
+		n.mode = Conmon
+	}
+	if n.mode == Container {
+		stop, err := forward(ctx, SocketDir(lab[labels.StateDir]))
+		if err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to forward the notification socket to container %s", container.ID())
+			n.mode = ""
+			return n
+		}
+		n.stop = stop
+	}
+	return n
+}
+
+// Start notifies systemd about the container, whose task has just been started.
+func (n *Notifier) Start(ctx context.Context) {
+	switch n.mode {
+	case Conmon:
+		notify(ctx, daemon.SdNotifyReady)
+	case Healthy:
+		ctx, cancel := context.WithCancel(ctx)
+		go notifyWhenHealthy(ctx, n.container)
+		n.stop = cancel
+	}
+}
+
+// Stop stops the notifications.
+func (n *Notifier) Stop() {
+	if n.stop != nil {
+		n.stop()
+	}
+}
+
+func notify(ctx context.Context, state string) {
+	if _, err := daemon.SdNotify(false, state); err != nil {
+		log.G(ctx).WithError(err).Warn("failed to notify systemd")
+	}
+}
+
+// forward listens on the socket of the container and forwards its messages to NOTIFY_SOCKET.
+// The messages are sent by nerdctl, which is in the cgroup of the systemd service, unlike the container.
+func forward(ctx context.Context, dir string) (func(), error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, socketName)
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	// The container may not run as root
+	if err := os.Chmod(path, 0o666); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, _, err := conn.ReadFromUnix(buf)
+			if err != nil {
+				return
+			}
+			if state := filterState(string(buf[:n])); state != "" {
+				notify(ctx, state)
+			}
+		}
+	}()
+	return func() {
+		conn.Close()
+		os.Remove(path)
+	}, nil
+}
+
+// filterState drops the MAINPID variable from the state sent by the container, as its PID is not meaningful to systemd.
+func filterState(state string) string {
+	var res []string
+	for _, l := range strings.Split(state, "\n") {
+		if l == "" || strings.HasPrefix(l, "MAINPID=") {
+			continue
+		}
+		res = append(res, l)
+	}
+	return strings.Join(res, "\n")
+}
+
+func notifyWhenHealthy(ctx context.Context, container containerd.Container) {
+	ticker := time.NewTicker(healthPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		lab, err := container.Labels(ctx)
+		if err != nil {
+			log.G(ctx).WithError(err).Debug("failed to get the container labels")
+			continue
+		}
+		s := lab[labels.HealthState]
+		if s == "" {
+			continue
+		}
+		state, err := healthcheck.HealthStateFromJSON(s)
+		if err != nil {
+			log.G(ctx).WithError(err).Debug("failed to parse the health state")
+			continue
+		}
+		if state.Status == healthcheck.Healthy {
+			notify(ctx, daemon.SdNotifyReady)
+			return
+		}
+	}
+}
@@ -0,0 +1,69 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package sdnotify
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+func TestValidate(t *testing.T) {
+	assert.NilError(t, Validate(Conmon))
+	assert.NilError(t, Validate(Container))
+	assert.NilError(t, Validate(Healthy))
+	assert.ErrorContains(t, Validate("ignore"), `invalid sdnotify mode "ignore"`)
+}
+
+func TestFilterState(t *testing.T) {
+	assert.Equal(t, filterState("READY=1"), "READY=1")
+	assert.Equal(t, filterState("READY=1\nMAINPID=1\nSTATUS=ok\n"), "READY=1\nSTATUS=ok")
+	assert.Equal(t, filterState("MAINPID=1"), "")
+}
+
+func TestPrepareContainerEarlyReady(t *testing.T) {
+	dir := t.TempDir()
+	systemdSocket := filepath.Join(dir, "systemd.sock")
+	systemd, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: systemdSocket, Net: "unixgram"})
+	assert.NilError(t, err)
+	defer systemd.Close()
+	t.Setenv("NOTIFY_SOCKET", systemdSocket)
+
+	stateDir := filepath.Join(dir, "state")
+	n := Prepare(context.Background(), nil, map[string]string{labels.SdNotify: Container, labels.StateDir: stateDir})
+	defer n.Stop()
+
+	// the container notifies before its task is reported as started
+	conn, err := net.Dial("unixgram", filepath.Join(SocketDir(stateDir), socketName))
+	assert.NilError(t, err)
+	_, err = conn.Write([]byte("READY=1\nMAINPID=1"))
+	assert.NilError(t, err)
+	conn.Close()
+	n.Start(context.Background())
+
+	assert.NilError(t, systemd.SetReadDeadline(time.Now().Add(5*time.Second)))
+	buf := make([]byte, 4096)
+	count, err := systemd.Read(buf)
+	assert.NilError(t, err)
+	assert.Equal(t, string(buf[:count]), "READY=1")
+}
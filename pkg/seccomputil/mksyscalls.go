@@ -0,0 +1,133 @@
+//go:build ignore
+
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// mksyscalls generates zsyscalls.go, the tables of the syscall names by number of the architectures
+// supported by the seccomp recorder, from the syscall numbers of golang.org/x/sys/unix.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var arches = []struct {
+	goarch string
+	// arch is the name of the specs.Arch constant
+	arch string
+	// renames maps the names of golang.org/x/sys/unix to the names of libseccomp
+	renames map[string]string
+}{
+	{goarch: "amd64", arch: "ArchX86_64"},
+	{goarch: "arm64", arch: "ArchAARCH64", renames: map[string]string{"fstatat": "newfstatat"}},
+}
+
+var sysnumRegexp = regexp.MustCompile(`^\s*SYS_([A-Z0-9_]+)\s*=\s*(\d+)`)
+
+func main() {
+	out, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", "golang.org/x/sys").Output()
+	if err != nil {
+		log.Fatal(err)
+	}
+	dir := filepath.Join(strings.TrimSpace(string(out)), "unix")
+
+	var b bytes.Buffer
+	b.WriteString(strings.TrimLeft(license, "\n"))
+	b.WriteString("\n// Code generated by mksyscalls.go; DO NOT EDIT.\n\npackage seccomputil\n\n")
+	b.WriteString("import \"github.com/opencontainers/runtime-spec/specs-go\"\n\n")
+	b.WriteString("var syscallNames = map[specs.Arch]map[int]string{\n")
+	for _, a := range arches {
+		names, err := readSysnum(filepath.Join(dir, "zsysnum_linux_"+a.goarch+".go"), a.renames)
+		if err != nil {
+			log.Fatal(err)
+		}
+		nums := make([]int, 0, len(names))
+		for num := range names {
+			nums = append(nums, num)
+		}
+		sort.Ints(nums)
+		fmt.Fprintf(&b, "\tspecs.%s: {\n", a.arch)
+		for _, num := range nums {
+			fmt.Fprintf(&b, "\t\t%d: %q,\n", num, names[num])
+		}
+		b.WriteString("\t},\n")
+	}
+	b.WriteString("}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("zsyscalls.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func readSysnum(path string, renames map[string]string) (map[int]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	names := map[int]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		m := sysnumRegexp.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		num, err := strconv.Atoi(m[2])
+		if err != nil {
+			return nil, err
+		}
+		name := strings.ToLower(m[1])
+		if renamed, ok := renames[name]; ok {
+			name = renamed
+		}
+		names[num] = name
+	}
+	return names, scanner.Err()
+}
+
+const license = `
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+`
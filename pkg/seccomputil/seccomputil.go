@@ -0,0 +1,260 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+//go:generate go run mksyscalls.go
+
+// Package seccomputil records least-privilege seccomp profiles from the syscalls logged by the kernel,
+// and compares profiles.
+package seccomputil
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/containerd/log"
+)
+
+// RecordPrefix is the prefix of the "seccomp" security option to record a profile, e.g., "record:/tmp/profile.json".
+const RecordPrefix = "record:"
+
+// trackerLost is written to the output of TrackProcesses when process events were lost.
+const trackerLost = "lost"
+
+// seccompRetLog is the "code" of the audit records of the syscalls logged by SCMP_ACT_LOG (SECCOMP_RET_LOG).
+const seccompRetLog = "0x7ffc0000"
+
+// auditArches maps the "arch" of the audit records (AUDIT_ARCH_*) to the seccomp architectures.
+var auditArches = map[string]specs.Arch{
+	"c000003e": specs.ArchX86_64,
+	"c00000b7": specs.ArchAARCH64,
+}
+
+var (
+	auditTimeRegexp  = regexp.MustCompile(`audit\((\d+)\.(\d+):\d+\)`)
+	auditFieldRegexp = regexp.MustCompile(`\b(pid|arch|syscall|code)=(\S+)`)
+	// auditLostRegexp matches the kernel messages reporting that audit records were dropped:
+	// by the rate limit of printk when auditd is not running, or by the audit backlog.
+	auditLostRegexp = regexp.MustCompile(`audit.*(callbacks suppressed|audit_lost=|limit exceeded|queue overflow)`)
+)
+
+// RecordingProfile returns a profile for the architectures of base that allows every syscall and logs it (SCMP_ACT_LOG).
+func RecordingProfile(base *specs.LinuxSeccomp) *specs.LinuxSeccomp {
+	return &specs.LinuxSeccomp{
+		DefaultAction: specs.ActLog,
+		Architectures: base.Architectures,
+	}
+}
+
+// GenerateProfile returns a profile that allows exactly the observed syscalls, in the structure of base:
+// the default action and the architectures of base are kept, and so are the rules of base
+// restricted to the observed syscalls, with their argument filters.
+// Rules of base that do not allow syscalls (e.g., clone3 failing with ENOSYS) are kept as is.
+// Observed syscalls that base does not refer to are allowed unconditionally.
+func GenerateProfile(base *specs.LinuxSeccomp, observed []string) *specs.LinuxSeccomp {
+	profile := &specs.LinuxSeccomp{
+		DefaultAction:   base.DefaultAction,
+		DefaultErrnoRet: base.DefaultErrnoRet,
+		Architectures:   base.Architectures,
+	}
+	referred := map[string]bool{}
+	for _, rule := range base.Syscalls {
+		for _, name := range rule.Names {
+			referred[name] = true
+		}
+		if rule.Action != specs.ActAllow {
+			profile.Syscalls = append(profile.Syscalls, rule)
+			continue
+		}
+		var names []string
+		for _, name := range rule.Names {
+			if slices.Contains(observed, name) {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+		rule.Names = names
+		profile.Syscalls = append(profile.Syscalls, rule)
+	}
+	var extra []string
+	for _, name := range observed {
+		if !referred[name] {
+			extra = append(extra, name)
+		}
+	}
+	if len(extra) > 0 {
+		slices.Sort(extra)
+		profile.Syscalls = append(profile.Syscalls, specs.LinuxSyscall{
+			Names:  extra,
+			Action: specs.ActAllow,
+		})
+	}
+	return profile
+}
+
+// ParseAuditLog returns the sorted names of the syscalls logged by SCMP_ACT_LOG between since and until
+// by the given processes (see TrackProcesses).
+// The records may be in the format of auditd ("type=SECCOMP msg=audit(...): ...")
+// or of the kernel log ("audit: type=1326 audit(...): ...").
+func ParseAuditLog(r io.Reader, since, until time.Time, pids map[int]bool) ([]string, error) {
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		m := auditTimeRegexp.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		sec, _ := strconv.ParseInt(m[1], 10, 64)
+		msec, _ := strconv.ParseInt(m[2], 10, 64)
+		if t := time.Unix(sec, msec*int64(time.Millisecond)); t.Before(since) || t.After(until) {
+			continue
+		}
+		fields := map[string]string{}
+		for _, f := range auditFieldRegexp.FindAllStringSubmatch(line, -1) {
+			fields[f[1]] = f[2]
+		}
+		if fields["code"] != seccompRetLog {
+			continue
+		}
+		if pid, err := strconv.Atoi(fields["pid"]); err != nil || !pids[pid] {
+			continue
+		}
+		arch, ok := auditArches[fields["arch"]]
+		if !ok {
+			log.L.Warnf("ignoring the syscalls of the unsupported architecture %q", fields["arch"])
+			continue
+		}
+		num, err := strconv.Atoi(fields["syscall"])
+		if err != nil {
+			continue
+		}
+		name, ok := syscallNames[arch][num]
+		if !ok {
+			log.L.Warnf("ignoring the unknown syscall %d of %s", num, arch)
+			continue
+		}
+		seen[name] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// AuditLost returns the messages of the kernel log (/dev/kmsg) reporting lost audit records between since and until.
+// bootTime is the time the timestamps of the kernel log are relative to.
+func AuditLost(kmsg io.Reader, bootTime, since, until time.Time) ([]string, error) {
+	var lost []string
+	scanner := bufio.NewScanner(kmsg)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		// "PRIORITY,SEQUENCE,TIMESTAMP_USEC,FLAGS;MESSAGE"
+		prefix, msg, ok := strings.Cut(scanner.Text(), ";")
+		if !ok || !auditLostRegexp.MatchString(msg) {
+			continue
+		}
+		fields := strings.Split(prefix, ",")
+		if len(fields) < 3 {
+			continue
+		}
+		usec, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		if t := bootTime.Add(time.Duration(usec) * time.Microsecond); t.Before(since) || t.After(until) {
+			continue
+		}
+		lost = append(lost, msg)
+	}
+	return lost, scanner.Err()
+}
+
+// ReadTrackedProcesses reads the output of TrackProcesses.
+// lost is true if process events were lost, in which case pids is incomplete.
+func ReadTrackedProcesses(r io.Reader) (pids map[int]bool, lost bool, err error) {
+	pids = map[int]bool{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == trackerLost {
+			lost = true
+			continue
+		}
+		pid, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid pid %q", line)
+		}
+		pids[pid] = true
+	}
+	return pids, lost, scanner.Err()
+}
+
+// LoadProfile reads a profile in the format of the OCI runtime spec, or in the format of Docker without includes and excludes.
+func LoadProfile(path string) (*specs.LinuxSeccomp, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var profile specs.LinuxSeccomp
+	if err := json.Unmarshal(b, &profile); err != nil {
+		return nil, fmt.Errorf("failed to parse the seccomp profile %q: %w", path, err)
+	}
+	return &profile, nil
+}
+
+// WriteProfile writes a profile in JSON.
+func WriteProfile(path string, profile *specs.LinuxSeccomp) error {
+	b, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
+
+// AllowedSyscalls returns the sorted names of the syscalls allowed by the rules of a profile, with argument filters or not.
+func AllowedSyscalls(profile *specs.LinuxSeccomp) []string {
+	var names []string
+	for _, rule := range profile.Syscalls {
+		if rule.Action != specs.ActAllow && rule.Action != specs.ActLog {
+			continue
+		}
+		for _, name := range rule.Names {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
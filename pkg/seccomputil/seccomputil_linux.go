@@ -0,0 +1,164 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package seccomputil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/sys/unix"
+
+	"github.com/containerd/log"
+)
+
+// auditLogGlob matches the log of auditd and its rotated logs (audit.log.1, ...).
+// auditd receives the audit records instead of the kernel log while it runs.
+const auditLogGlob = "/var/log/audit/audit.log*"
+
+// CollectSyscalls returns the names of the syscalls logged by SCMP_ACT_LOG between since and until by the given processes,
+// from the logs of auditd and from the kernel log, as auditd may not have been running the whole time.
+// It fails if the kernel reports that audit records were lost in the meantime, as the syscalls would be incomplete.
+func CollectSyscalls(since, until time.Time, pids map[int]bool) ([]string, error) {
+	seen := map[string]bool{}
+	collect := func(r io.Reader) error {
+		names, err := ParseAuditLog(r, since, until, pids)
+		for _, name := range names {
+			seen[name] = true
+		}
+		return err
+	}
+
+	auditLogs, err := filepath.Glob(auditLogGlob)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range auditLogs {
+		// Skip the logs rotated before the container started
+		if st, err := os.Stat(p); err != nil || st.ModTime().Before(since) {
+			continue
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return nil, err
+		}
+		err = collect(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	kmsg, err := readKmsg()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the kernel log: %w", err)
+	}
+	lost, err := AuditLost(bytes.NewReader(kmsg), bootTime(), since, until)
+	if err != nil {
+		return nil, err
+	}
+	if len(lost) > 0 {
+		return nil, fmt.Errorf("audit records were lost while recording, so the profile would be incomplete "+
+			"(run auditd, or raise the kernel.printk_ratelimit_burst sysctl): %s", strings.Join(lost, "; "))
+	}
+	if err := collect(bytes.NewReader(kmsg)); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// bootTime returns the time the timestamps of the kernel log are relative to.
+func bootTime() time.Time {
+	var ts unix.Timespec
+	if err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &ts); err != nil {
+		return time.Time{}
+	}
+	return time.Now().Add(-time.Duration(ts.Nano()))
+}
+
+// readKmsg returns the records of the kernel log buffer, one per line.
+// The raw syscalls avoid the blocking reads of the runtime poller at the end of the buffer.
+func readKmsg() ([]byte, error) {
+	fd, err := unix.Open("/dev/kmsg", unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer unix.Close(fd)
+	var b bytes.Buffer
+	buf := make([]byte, 8192)
+	for {
+		n, err := unix.Read(fd, buf)
+		switch {
+		case errors.Is(err, unix.EAGAIN):
+			return b.Bytes(), nil
+		case errors.Is(err, unix.EPIPE):
+			// the record was overwritten, continue with the next one
+			continue
+		case errors.Is(err, unix.EINTR):
+			continue
+		case err != nil:
+			return nil, err
+		}
+		b.Write(buf[:n])
+		if n > 0 && buf[n-1] != '\n' {
+			b.WriteByte('\n')
+		}
+	}
+}
+
+// Record writes to path a profile in the structure of base allowing the syscalls logged between since and until
+// by the processes tracked by TrackProcesses to trackerOutput.
+// An existing profile at path is removed when the syscalls cannot be collected, so that it is not mistaken for the new one.
+func Record(path string, base *specs.LinuxSeccomp, since, until time.Time, trackerOutput string) error {
+	observed, err := collectTrackedSyscalls(since, until, trackerOutput)
+	if err != nil {
+		if rmErr := os.Remove(path); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+			log.L.WithError(rmErr).Warnf("failed to remove the stale seccomp profile %s", path)
+		}
+		return err
+	}
+	return WriteProfile(path, GenerateProfile(base, observed))
+}
+
+func collectTrackedSyscalls(since, until time.Time, trackerOutput string) ([]string, error) {
+	f, err := os.Open(trackerOutput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the processes of the container: %w", err)
+	}
+	defer f.Close()
+	pids, lost, err := ReadTrackedProcesses(f)
+	if err != nil {
+		return nil, err
+	}
+	if lost {
+		return nil, errors.New("process events were lost while recording, so the profile would be incomplete")
+	}
+	return CollectSyscalls(since, until, pids)
+}
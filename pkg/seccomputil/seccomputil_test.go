@@ -0,0 +1,117 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package seccomputil
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"gotest.tools/v3/assert"
+)
+
+func TestParseAuditLog(t *testing.T) {
+	const auditLog = `type=SECCOMP msg=audit(1700000010.100:10): auid=4294967295 uid=0 gid=0 ses=4294967295 pid=100 comm="sh" exe="/bin/busybox" sig=0 arch=c000003e syscall=59 compat=0 ip=0x7f code=0x7ffc0000
+type=SECCOMP msg=audit(1700000010.200:11): auid=4294967295 uid=0 gid=0 ses=4294967295 pid=100 comm="sh" exe="/bin/busybox" sig=0 arch=c000003e syscall=1 compat=0 ip=0x7f code=0x7ffc0000
+type=SECCOMP msg=audit(1700000010.300:12): pid=100 comm="sh" arch=c000003e syscall=1 compat=0 ip=0x7f code=0x7ffc0000
+type=SECCOMP msg=audit(1700000010.400:13): pid=200 comm="other" arch=c000003e syscall=2 compat=0 ip=0x7f code=0x50001
+type=SYSCALL msg=audit(1700000010.500:14): arch=c000003e syscall=3 success=yes exit=0
+type=SECCOMP msg=audit(1700000005.000:15): pid=300 comm="before" arch=c000003e syscall=4 compat=0 ip=0x7f code=0x7ffc0000
+6,1234,5678901,-;audit: type=1326 audit(1700000011.000:16): auid=4294967295 uid=0 gid=0 ses=4294967295 pid=101 comm="sh" exe="/bin/busybox" sig=0 arch=c00000b7 syscall=79 compat=0 ip=0xffff code=0x7ffc0000
+6,1235,5678902,-;audit: type=1326 audit(1700000012.000:17): pid=102 comm="sh" arch=40000003 syscall=5 compat=1 ip=0x8 code=0x7ffc0000
+type=SECCOMP msg=audit(1700000030.000:18): pid=400 comm="after" arch=c000003e syscall=5 compat=0 ip=0x7f code=0x7ffc0000
+type=SECCOMP msg=audit(1700000013.000:19): pid=500 comm="host" arch=c000003e syscall=0 compat=0 ip=0x7f code=0x7ffc0000
+`
+	since := time.Unix(1700000010, 0)
+	until := time.Unix(1700000020, 0)
+	pids := map[int]bool{100: true, 101: true, 102: true, 200: true, 300: true, 400: true}
+	names, err := ParseAuditLog(strings.NewReader(auditLog), since, until, pids)
+	assert.NilError(t, err)
+	// open (2) is denied (SECCOMP_RET_ERRNO), close (3) is not a SECCOMP record,
+	// stat (4) and fstat (5) are out of the time range, the i386 syscall is unsupported,
+	// and read (0) is called by a process that is not tracked.
+	assert.DeepEqual(t, names, []string{"execve", "newfstatat", "write"})
+}
+
+func TestAuditLost(t *testing.T) {
+	const kmsg = `4,100,5000000,-;audit: audit_backlog=65 > audit_backlog_limit=64
+4,101,10000000,-;audit_printk_skb: 12 callbacks suppressed
+4,102,11000000,-;audit: audit_lost=3 audit_rate_limit=0 audit_backlog_limit=64
+6,103,12000000,-;audit: type=1326 audit(1700000012.000:17): pid=102 comm="sh" arch=c000003e syscall=5 compat=0 ip=0x8 code=0x7ffc0000
+4,104,30000000,-;audit: kauditd hold queue overflow
+`
+	bootTime := time.Unix(1700000000, 0)
+	lost, err := AuditLost(strings.NewReader(kmsg), bootTime, bootTime.Add(10*time.Second), bootTime.Add(20*time.Second))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, lost, []string{
+		"audit_printk_skb: 12 callbacks suppressed",
+		"audit: audit_lost=3 audit_rate_limit=0 audit_backlog_limit=64",
+	})
+}
+
+func TestReadTrackedProcesses(t *testing.T) {
+	pids, lost, err := ReadTrackedProcesses(strings.NewReader("100\n101\n"))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, pids, map[int]bool{100: true, 101: true})
+	assert.Assert(t, !lost)
+
+	pids, lost, err = ReadTrackedProcesses(strings.NewReader("100\nlost\n102\n"))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, pids, map[int]bool{100: true, 102: true})
+	assert.Assert(t, lost)
+
+	_, _, err = ReadTrackedProcesses(strings.NewReader("100\nfoo\n"))
+	assert.ErrorContains(t, err, "invalid pid")
+}
+
+func TestGenerateProfile(t *testing.T) {
+	base := &specs.LinuxSeccomp{
+		DefaultAction: specs.ActErrno,
+		Architectures: []specs.Arch{specs.ArchX86_64},
+		Syscalls: []specs.LinuxSyscall{
+			{Names: []string{"execve", "read", "write"}, Action: specs.ActAllow},
+			{Names: []string{"personality"}, Action: specs.ActAllow, Args: []specs.LinuxSeccompArg{{Index: 0, Value: 0, Op: specs.OpEqualTo}}},
+			{Names: []string{"clone3"}, Action: specs.ActErrno},
+			{Names: []string{"ptrace"}, Action: specs.ActAllow},
+		},
+	}
+	profile := GenerateProfile(base, []string{"clone3", "execve", "mount", "personality", "write"})
+	assert.DeepEqual(t, profile, &specs.LinuxSeccomp{
+		DefaultAction: specs.ActErrno,
+		Architectures: []specs.Arch{specs.ArchX86_64},
+		Syscalls: []specs.LinuxSyscall{
+			{Names: []string{"execve", "write"}, Action: specs.ActAllow},
+			{Names: []string{"personality"}, Action: specs.ActAllow, Args: []specs.LinuxSeccompArg{{Index: 0, Value: 0, Op: specs.OpEqualTo}}},
+			{Names: []string{"clone3"}, Action: specs.ActErrno},
+			{Names: []string{"mount"}, Action: specs.ActAllow},
+		},
+	})
+	assert.DeepEqual(t, AllowedSyscalls(profile), []string{"execve", "mount", "personality", "write"})
+}
+
+func TestRecordingProfile(t *testing.T) {
+	base := &specs.LinuxSeccomp{
+		DefaultAction: specs.ActErrno,
+		Architectures: []specs.Arch{specs.ArchAARCH64, specs.ArchARM},
+		Syscalls:      []specs.LinuxSyscall{{Names: []string{"read"}, Action: specs.ActAllow}},
+	}
+	profile := RecordingProfile(base)
+	assert.Equal(t, profile.DefaultAction, specs.ActLog)
+	assert.DeepEqual(t, profile.Architectures, base.Architectures)
+	assert.Equal(t, len(profile.Syscalls), 0)
+}
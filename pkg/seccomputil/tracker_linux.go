@@ -0,0 +1,186 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package seccomputil
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Process events connector (linux/cn_proc.h).
+const (
+	cnIdxProc         = 0x1
+	cnValProc         = 0x1
+	procCnMcastListen = 1
+
+	procEventFork = 0x00000001
+	procEventExec = 0x00000002
+	procEventExit = 0x80000000
+
+	// cnMsgLen is the size of struct cn_msg, without its data.
+	cnMsgLen = 20
+	// procEventHeaderLen is the size of struct proc_event, without its event_data.
+	procEventHeaderLen = 16
+)
+
+// procEvent is a process event: the pid and tgid of the process,
+// and for forks, the pid and tgid of the parent.
+type procEvent struct {
+	what       uint32
+	pid, tgid  int
+	parentTgid int
+}
+
+// TrackProcesses writes to w the pids of the processes of a container, one per line, as they appear:
+// the process pid (the init of the container), its descendants, and the processes executed in its cgroup
+// (`nerdctl exec`). It returns when the process pid exits or ctx is done.
+// ready is called once the process events are subscribed to, and pid must not fork before.
+//
+// The audit records of SCMP_ACT_LOG identify processes by their pid only, so these pids are
+// what distinguishes the syscalls of the container from the syscalls of other processes.
+func TrackProcesses(ctx context.Context, pid int, w io.Writer, ready func()) error {
+	cgroup, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return err
+	}
+	sock, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, unix.NETLINK_CONNECTOR)
+	if err != nil {
+		return fmt.Errorf("failed to open the process events connector: %w", err)
+	}
+	defer unix.Close(sock)
+	// Forks are bursty, make room so that events are not dropped
+	_ = unix.SetsockoptInt(sock, unix.SOL_SOCKET, unix.SO_RCVBUFFORCE, 8<<20)
+	// Wake up regularly to check ctx
+	if err := unix.SetsockoptTimeval(sock, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &unix.Timeval{Usec: 200000}); err != nil {
+		return err
+	}
+	if err := unix.Bind(sock, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: cnIdxProc}); err != nil {
+		return fmt.Errorf("failed to bind the process events connector: %w", err)
+	}
+	if err := unix.Sendto(sock, procListenMessage(), 0, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: cnIdxProc}); err != nil {
+		return fmt.Errorf("failed to subscribe to the process events: %w", err)
+	}
+
+	tracked := map[int]bool{pid: true}
+	if _, err := fmt.Fprintln(w, pid); err != nil {
+		return err
+	}
+	ready()
+
+	buf := make([]byte, 64*1024)
+	for ctx.Err() == nil {
+		n, _, err := unix.Recvfrom(sock, buf, 0)
+		switch {
+		case errors.Is(err, unix.EAGAIN), errors.Is(err, unix.EINTR):
+			continue
+		case errors.Is(err, unix.ENOBUFS):
+			if _, err := fmt.Fprintln(w, trackerLost); err != nil {
+				return err
+			}
+			continue
+		case err != nil:
+			return err
+		}
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			ev, ok := parseProcEvent(m.Data)
+			if !ok {
+				continue
+			}
+			var newPid int
+			switch ev.what {
+			case procEventFork:
+				if tracked[ev.parentTgid] && !tracked[ev.tgid] {
+					newPid = ev.tgid
+				}
+			case procEventExec:
+				// The processes of `nerdctl exec` are not descendants of the init of the container,
+				// they join its cgroup before they are executed.
+				if !tracked[ev.tgid] && sameCgroup(ev.tgid, cgroup) {
+					newPid = ev.tgid
+				}
+			case procEventExit:
+				if ev.pid == pid && ev.tgid == pid {
+					return nil
+				}
+			}
+			if newPid != 0 {
+				tracked[newPid] = true
+				if _, err := fmt.Fprintln(w, newPid); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// procListenMessage returns the netlink message subscribing to the process events.
+func procListenMessage() []byte {
+	b := make([]byte, unix.NLMSG_HDRLEN+cnMsgLen+4)
+	binary.NativeEndian.PutUint32(b[0:], uint32(len(b)))
+	binary.NativeEndian.PutUint16(b[4:], unix.NLMSG_DONE)
+	binary.NativeEndian.PutUint32(b[12:], uint32(os.Getpid()))
+	cn := b[unix.NLMSG_HDRLEN:]
+	binary.NativeEndian.PutUint32(cn[0:], cnIdxProc)
+	binary.NativeEndian.PutUint32(cn[4:], cnValProc)
+	binary.NativeEndian.PutUint16(cn[16:], 4)
+	binary.NativeEndian.PutUint32(cn[cnMsgLen:], procCnMcastListen)
+	return b
+}
+
+// parseProcEvent parses the struct cn_msg holding a struct proc_event.
+func parseProcEvent(b []byte) (procEvent, bool) {
+	if len(b) < cnMsgLen+procEventHeaderLen+16 {
+		return procEvent{}, false
+	}
+	if binary.NativeEndian.Uint32(b[0:]) != cnIdxProc || binary.NativeEndian.Uint32(b[4:]) != cnValProc {
+		return procEvent{}, false
+	}
+	ev := b[cnMsgLen:]
+	data := ev[procEventHeaderLen:]
+	e := procEvent{what: binary.NativeEndian.Uint32(ev[0:])}
+	switch e.what {
+	case procEventFork:
+		// parent_pid, parent_tgid, child_pid, child_tgid
+		e.parentTgid = int(binary.NativeEndian.Uint32(data[4:]))
+		e.pid = int(binary.NativeEndian.Uint32(data[8:]))
+		e.tgid = int(binary.NativeEndian.Uint32(data[12:]))
+	case procEventExec, procEventExit:
+		// process_pid, process_tgid, ...
+		e.pid = int(binary.NativeEndian.Uint32(data[0:]))
+		e.tgid = int(binary.NativeEndian.Uint32(data[4:]))
+	default:
+		return procEvent{}, false
+	}
+	return e, true
+}
+
+func sameCgroup(pid int, cgroup []byte) bool {
+	b, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	return err == nil && string(b) == string(cgroup)
+}
@@ -0,0 +1,129 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package statsutil
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// OpenMetricsContentType is the content type of the OpenMetrics text exposition format.
+const OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// MetricsSample is the statistics of a container, with the metadata exposed as labels of its series.
+type MetricsSample struct {
+	StatsEntry
+	Namespace string
+	Image     string
+	// Health is the health status of the container, empty when the container has no health check
+	Health       string
+	RestartCount int
+}
+
+type metricType string
+
+const (
+	gauge   metricType = "gauge"
+	counter metricType = "counter"
+)
+
+type metric struct {
+	name  string
+	typ   metricType
+	unit  string
+	help  string
+	value func(s *MetricsSample) float64
+}
+
+var metrics = []metric{
+	{"nerdctl_container_cpu_usage_percent", gauge, "percent", "CPU usage of the container",
+		func(s *MetricsSample) float64 { return s.CPUPercentage }},
+	{"nerdctl_container_memory_usage_bytes", gauge, "bytes", "Memory usage of the container",
+		func(s *MetricsSample) float64 { return s.Memory }},
+	{"nerdctl_container_memory_limit_bytes", gauge, "bytes", "Memory limit of the container",
+		func(s *MetricsSample) float64 { return s.MemoryLimit }},
+	{"nerdctl_container_memory_usage_percent", gauge, "percent", "Memory usage of the container, relative to its limit",
+		func(s *MetricsSample) float64 { return s.MemoryPercentage }},
+	{"nerdctl_container_network_receive_bytes", counter, "bytes", "Bytes received by the container",
+		func(s *MetricsSample) float64 { return s.NetworkRx }},
+	{"nerdctl_container_network_transmit_bytes", counter, "bytes", "Bytes transmitted by the container",
+		func(s *MetricsSample) float64 { return s.NetworkTx }},
+	{"nerdctl_container_block_read_bytes", counter, "bytes", "Bytes read from block devices by the container",
+		func(s *MetricsSample) float64 { return s.BlockRead }},
+	{"nerdctl_container_block_write_bytes", counter, "bytes", "Bytes written to block devices by the container",
+		func(s *MetricsSample) float64 { return s.BlockWrite }},
+	{"nerdctl_container_pids", gauge, "", "Number of processes of the container",
+		func(s *MetricsSample) float64 { return float64(s.PidsCurrent) }},
+	{"nerdctl_container_restarts", counter, "", "Number of restarts of the container by its restart policy",
+		func(s *MetricsSample) float64 { return float64(s.RestartCount) }},
+}
+
+// healthMetric is the name of the metric exposing the health status of the containers with a health check.
+const healthMetric = "nerdctl_container_health_status"
+
+// WriteOpenMetrics writes the samples in the OpenMetrics text format.
+// The samples of the containers whose statistics could not be collected are skipped.
+func WriteOpenMetrics(w io.Writer, samples []MetricsSample) error {
+	bw := bufio.NewWriter(w)
+	for _, m := range metrics {
+		fmt.Fprintf(bw, "# TYPE %s %s\n", m.name, m.typ)
+		if m.unit != "" {
+			fmt.Fprintf(bw, "# UNIT %s %s\n", m.name, m.unit)
+		}
+		fmt.Fprintf(bw, "# HELP %s %s.\n", m.name, m.help)
+		suffix := ""
+		if m.typ == counter {
+			suffix = "_total"
+		}
+		for i := range samples {
+			s := &samples[i]
+			if s.IsInvalid {
+				continue
+			}
+			fmt.Fprintf(bw, "%s%s{%s} %s\n", m.name, suffix, seriesLabels(s), formatValue(m.value(s)))
+		}
+	}
+	fmt.Fprintf(bw, "# TYPE %s gauge\n", healthMetric)
+	fmt.Fprintf(bw, "# HELP %s Health status of the container, set to 1 for the current status.\n", healthMetric)
+	for i := range samples {
+		s := &samples[i]
+		if s.IsInvalid || s.Health == "" {
+			continue
+		}
+		fmt.Fprintf(bw, "%s{%s,status=\"%s\"} 1\n", healthMetric, seriesLabels(s), escapeLabelValue(s.Health))
+	}
+	fmt.Fprintln(bw, "# EOF")
+	return bw.Flush()
+}
+
+func seriesLabels(s *MetricsSample) string {
+	return fmt.Sprintf("id=\"%s\",name=\"%s\",namespace=\"%s\",image=\"%s\"",
+		escapeLabelValue(s.ID), escapeLabelValue(s.Name), escapeLabelValue(s.Namespace), escapeLabelValue(s.Image))
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(v string) string {
+	return labelValueEscaper.Replace(v)
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,69 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package statsutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestWriteOpenMetrics(t *testing.T) {
+	samples := []MetricsSample{
+		{
+			StatsEntry: StatsEntry{
+				ID:            "0123456789ab",
+				Name:          `we"b`,
+				CPUPercentage: 12.5,
+				Memory:        1024,
+				NetworkRx:     2048,
+				PidsCurrent:   3,
+			},
+			Namespace:    "default",
+			Image:        "docker.io/library/nginx:alpine",
+			Health:       "healthy",
+			RestartCount: 2,
+		},
+		{
+			StatsEntry: StatsEntry{ID: "invalid", IsInvalid: true},
+			Namespace:  "default",
+			Health:     "unhealthy",
+		},
+	}
+	var b bytes.Buffer
+	assert.NilError(t, WriteOpenMetrics(&b, samples))
+	out := b.String()
+
+	labels := `id="0123456789ab",name="we\"b",namespace="default",image="docker.io/library/nginx:alpine"`
+	for _, line := range []string{
+		"# TYPE nerdctl_container_cpu_usage_percent gauge",
+		"nerdctl_container_cpu_usage_percent{" + labels + "} 12.5",
+		"# UNIT nerdctl_container_memory_usage_bytes bytes",
+		"nerdctl_container_memory_usage_bytes{" + labels + "} 1024",
+		"# TYPE nerdctl_container_network_receive_bytes counter",
+		"nerdctl_container_network_receive_bytes_total{" + labels + "} 2048",
+		"nerdctl_container_pids{" + labels + "} 3",
+		"nerdctl_container_restarts_total{" + labels + "} 2",
+		"nerdctl_container_health_status{" + labels + `,status="healthy"} 1`,
+	} {
+		assert.Assert(t, strings.Contains(out, line+"\n"), "missing %q in:\n%s", line, out)
+	}
+	assert.Assert(t, !strings.Contains(out, "invalid"), out)
+	assert.Assert(t, strings.HasSuffix(out, "# EOF\n"), out)
+}